@@ -5,12 +5,23 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"github.com/yourusername/secureopenvsx/internal/download"
 	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/lockfile"
 	"github.com/yourusername/secureopenvsx/internal/marketplace"
+	"github.com/yourusername/secureopenvsx/internal/mirror"
 	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/openvsx"
+	"github.com/yourusername/secureopenvsx/internal/policy"
+	"github.com/yourusername/secureopenvsx/internal/remediation"
+	"github.com/yourusername/secureopenvsx/internal/search"
+	"github.com/yourusername/secureopenvsx/internal/update"
 	"github.com/yourusername/secureopenvsx/internal/validation"
+	"github.com/yourusername/secureopenvsx/internal/vsixdiff"
+	"github.com/yourusername/secureopenvsx/internal/workspace"
 )
 
 // App struct holds the application state
@@ -65,6 +76,13 @@ func (a *App) AuditAllExtensions(path string) (*models.AuditReport, error) {
 	return report, err
 }
 
+// SetSignatureVerification turns package signature checks on or off for validation and audits
+func (a *App) SetSignatureVerification(enabled bool) {
+	log.Printf("[App] SetSignatureVerification called: %v", enabled)
+	a.validator.SetSignatureVerification(enabled, nil)
+	a.scanner.SetSignatureVerification(enabled, nil)
+}
+
 // DownloadOfficialExtension downloads the official version from Microsoft Marketplace
 func (a *App) DownloadOfficialExtension(extensionID string) (string, string, error) {
 	log.Printf("[App] DownloadOfficialExtension called for: %s", extensionID)
@@ -99,12 +117,25 @@ func (a *App) GetDefaultExtensionsPath() (string, error) {
 	return path, err
 }
 
-// SearchMarketplace searches for extensions using keywords or wildcards
-func (a *App) SearchMarketplace(searchTerm string) ([]*models.ExtensionMetadata, error) {
-	log.Printf("[App] SearchMarketplace called for: %s", searchTerm)
+// SearchMarketplace searches for extensions with sorting, paging and filters
+func (a *App) SearchMarketplace(opts models.SearchOptions) (*models.SearchResults, error) {
+	log.Printf("[App] SearchMarketplace called for: %q (sort %s, page %d)", opts.Query, opts.SortBy, opts.Page)
 	// Create marketplace client for search
 	client := marketplace.NewClient()
-	return client.SearchExtensions(searchTerm)
+	return client.Search(opts)
+}
+
+// SearchRegistries searches the Marketplace and OpenVSX together and merges the results by ID
+func (a *App) SearchRegistries(opts models.SearchOptions) (*models.DualSearchResults, error) {
+	log.Printf("[App] SearchRegistries called for: %q (sort %s, page %d)", opts.Query, opts.SortBy, opts.Page)
+	return search.Dual(opts, marketplace.NewClient(), openvsx.NewClient())
+}
+
+// GetExtensionDetails returns an extension's versions, changelog, README and license from both
+// registries (at most maxVersions versions each; 0 for all)
+func (a *App) GetExtensionDetails(extensionID string, maxVersions int) (*models.RegistryDetails, error) {
+	log.Printf("[App] GetExtensionDetails called for: %s", extensionID)
+	return search.Details(extensionID, maxVersions, marketplace.NewClient(), openvsx.NewClient())
 }
 
 // SearchMarketplaceExtension searches for an extension in marketplace by ID and validates it
@@ -157,7 +188,7 @@ func (a *App) ShowQuestionDialog(title, message string) (string, error) {
 // GetEditorProfiles returns all available editor profiles with default paths
 func (a *App) GetEditorProfiles() []models.EditorProfile {
 	log.Println("[App] GetEditorProfiles called")
-	return editor.GetDefaultEditorProfiles()
+	return editor.GetEditorProfiles()
 }
 
 // GetEditorStatus checks the availability and status of a specific editor
@@ -176,7 +207,7 @@ func (a *App) GetEditorStatus(editorType string) models.EditorStatus {
 // GetAllEditorStatuses returns the status of all known editors
 func (a *App) GetAllEditorStatuses() []models.EditorStatus {
 	log.Println("[App] GetAllEditorStatuses called")
-	profiles := editor.GetDefaultEditorProfiles()
+	profiles := editor.GetEditorProfiles()
 	statuses := make([]models.EditorStatus, 0, len(profiles))
 	for _, profile := range profiles {
 		statuses = append(statuses, editor.CheckEditorStatus(profile))
@@ -191,7 +222,49 @@ func (a *App) GetEditorExtensions(editorType string) ([]models.InstalledExtensio
 	if err != nil {
 		return nil, err
 	}
-	return a.scanner.ScanInstalledExtensions(profile.ExtensionsDir)
+	return a.scanner.ScanEditorExtensions(profile)
+}
+
+// GetInventory returns a matrix of installed extensions across editors.
+// An empty editorTypes list scans every known editor.
+func (a *App) GetInventory(editorTypes []string, checkTrust bool) (*models.Inventory, error) {
+	log.Printf("[App] GetInventory called: editors=%v, trust=%v", editorTypes, checkTrust)
+	profiles := editor.GetEditorProfiles()
+	if len(editorTypes) > 0 {
+		profiles = make([]models.EditorProfile, 0, len(editorTypes))
+		for _, editorType := range editorTypes {
+			profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+			if err != nil {
+				return nil, err
+			}
+			profiles = append(profiles, profile)
+		}
+	}
+	return a.scanner.BuildInventory(profiles, checkTrust)
+}
+
+// DiscoverEditorRoots returns every extensions directory found for each editor
+func (a *App) DiscoverEditorRoots() []models.EditorDiscovery {
+	log.Println("[App] DiscoverEditorRoots called")
+	return editor.DiscoverAllEditorRoots()
+}
+
+// AddCustomEditor registers a custom editor profile
+func (a *App) AddCustomEditor(profile models.EditorProfile) (models.EditorProfile, error) {
+	log.Printf("[App] AddCustomEditor called: id=%s, dir=%s", profile.ID, profile.ExtensionsDir)
+	return editor.AddCustomEditorProfile(profile)
+}
+
+// UpdateCustomEditor replaces an existing custom editor profile
+func (a *App) UpdateCustomEditor(profile models.EditorProfile) (models.EditorProfile, error) {
+	log.Printf("[App] UpdateCustomEditor called: id=%s", profile.ID)
+	return editor.UpdateCustomEditorProfile(profile)
+}
+
+// RemoveCustomEditor removes a custom editor profile
+func (a *App) RemoveCustomEditor(editorType string) error {
+	log.Printf("[App] RemoveCustomEditor called: id=%s", editorType)
+	return editor.RemoveCustomEditorProfile(models.EditorType(editorType))
 }
 
 // ========== CLI Status APIs ==========
@@ -215,6 +288,118 @@ func (a *App) InstallExtensionsViaCLI(cliCommand string, extensionIDs []string)
 	return report
 }
 
+// DownloadExtensionPackage downloads a version of an extension for a target platform from a
+// registry into dir, resuming a partial download and writing checksum and metadata sidecars
+func (a *App) DownloadExtensionPackage(extensionID, version, source, targetPlatform, dir string) (*models.DownloadInfo, error) {
+	log.Printf("[App] DownloadExtensionPackage called: ext=%s, version=%s, source=%s, platform=%s", extensionID, version, source, targetPlatform)
+	downloader := download.NewDownloader()
+	info, err := downloader.Resolve(extensionID, version, source, targetPlatform)
+	if err != nil {
+		return nil, err
+	}
+	if err := downloader.Download(info, dir); err != nil {
+		log.Printf("[App] DownloadExtensionPackage error: %v", err)
+		return nil, err
+	}
+	return info, nil
+}
+
+// InstallExtensionNative downloads an extension from the marketplace and installs it without the editor CLI
+func (a *App) InstallExtensionNative(editorType string, extensionID string) (*models.VSIXInstallResult, error) {
+	log.Printf("[App] InstallExtensionNative called: editor=%s, ext=%s", editorType, extensionID)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+
+	data, _, err := a.validator.DownloadOfficialExtension(extensionID)
+	if err != nil {
+		return nil, err
+	}
+	return editor.InstallVSIX(profile, data, "gallery", extensionID)
+}
+
+// InstallVSIXFile installs a local VSIX file into an editor without the editor CLI. The
+// extension is validated first; Suspicious and Unknown ones need allowUntrusted.
+func (a *App) InstallVSIXFile(editorType string, vsixPath string, allowUntrusted bool) (*models.VSIXInstallResult, error) {
+	log.Printf("[App] InstallVSIXFile called: editor=%s, file=%s, allowUntrusted=%v", editorType, vsixPath, allowUntrusted)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+	return editor.InstallVSIXFile(profile, vsixPath, a.validator, allowUntrusted)
+}
+
+// UninstallExtension removes an extension from an editor
+func (a *App) UninstallExtension(editorType string, extensionID string) (*models.UninstallResult, error) {
+	log.Printf("[App] UninstallExtension called: editor=%s, ext=%s", editorType, extensionID)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+	return editor.UninstallExtension(profile, extensionID)
+}
+
+// DisableExtension disables an extension in an editor's global storage
+func (a *App) DisableExtension(editorType string, extensionID string) error {
+	log.Printf("[App] DisableExtension called: editor=%s, ext=%s", editorType, extensionID)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return err
+	}
+	return editor.SetExtensionEnabled(profile, extensionID, false)
+}
+
+// EnableExtension re-enables a disabled extension in an editor's global storage
+func (a *App) EnableExtension(editorType string, extensionID string) error {
+	log.Printf("[App] EnableExtension called: editor=%s, ext=%s", editorType, extensionID)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return err
+	}
+	return editor.SetExtensionEnabled(profile, extensionID, true)
+}
+
+// ReconcileEditorExtensions compares an editor's extension folders with its extensions.json
+func (a *App) ReconcileEditorExtensions(editorType string) (*models.ReconcileReport, error) {
+	log.Printf("[App] ReconcileEditorExtensions called for: %s", editorType)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+	return editor.ReconcileExtensions(profile.ExtensionsDir)
+}
+
+// CleanEditorExtensions removes stale and orphaned extension folders from an editor
+func (a *App) CleanEditorExtensions(editorType string, dryRun bool) (*models.CleanResult, error) {
+	log.Printf("[App] CleanEditorExtensions called: editor=%s, dryRun=%v", editorType, dryRun)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+	return editor.CleanExtensions(profile.ExtensionsDir, dryRun)
+}
+
+// DiagnoseEditorExtensions checks an editor's extensions index against the folders on disk
+func (a *App) DiagnoseEditorExtensions(editorType string) (*models.DoctorReport, error) {
+	log.Printf("[App] DiagnoseEditorExtensions called: editor=%s", editorType)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+	return editor.DiagnoseExtensions(profile.ExtensionsDir)
+}
+
+// RepairEditorExtensions repairs an editor's extensions index
+func (a *App) RepairEditorExtensions(editorType string) (*models.DoctorReport, error) {
+	log.Printf("[App] RepairEditorExtensions called: editor=%s", editorType)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+	return editor.RepairExtensions(profile.ExtensionsDir)
+}
+
 // ========== Sync APIs ==========
 
 // SyncExtensions syncs selected extensions from source to target editors
@@ -244,6 +429,30 @@ func (a *App) DetectSyncConflicts(sourceEditor string, targetEditor string, exte
 	return editor.DetectConflicts(models.EditorType(sourceEditor), models.EditorType(targetEditor), extensionIDs)
 }
 
+// PreviewUserConfigSync shows how settings, keybindings and snippets would be merged into a target editor
+func (a *App) PreviewUserConfigSync(sourceEditor string, targetEditor string, options models.UserConfigSyncOptions) (*models.UserConfigSyncPlan, error) {
+	log.Printf("[App] PreviewUserConfigSync called: source=%s, target=%s", sourceEditor, targetEditor)
+	source, err := editor.GetEditorProfile(models.EditorType(sourceEditor))
+	if err != nil {
+		return nil, err
+	}
+	target, err := editor.GetEditorProfile(models.EditorType(targetEditor))
+	if err != nil {
+		return nil, err
+	}
+	return editor.PlanUserConfigSync(source, target, options)
+}
+
+// SyncUserConfig merges settings, keybindings and snippets into a target editor
+func (a *App) SyncUserConfig(sourceEditor string, targetEditor string, options models.UserConfigSyncOptions) (*models.UserConfigSyncPlan, error) {
+	log.Printf("[App] SyncUserConfig called: source=%s, target=%s", sourceEditor, targetEditor)
+	plan, err := a.PreviewUserConfigSync(sourceEditor, targetEditor, options)
+	if err != nil {
+		return nil, err
+	}
+	return plan, editor.ApplyUserConfigSync(plan)
+}
+
 // GetExtensionsIndex reads the extensions.json for a specific editor
 func (a *App) GetExtensionsIndex(editorType string) ([]models.ExtensionIndexEntry, error) {
 	log.Printf("[App] GetExtensionsIndex called for: %s", editorType)
@@ -254,6 +463,312 @@ func (a *App) GetExtensionsIndex(editorType string) ([]models.ExtensionIndexEntr
 	return editor.ReadExtensionsIndex(profile.ExtensionsDir)
 }
 
+// ========== Quarantine APIs ==========
+
+// QuarantineExtension moves an installed extension into the vsynx quarantine
+func (a *App) QuarantineExtension(editorType string, extensionID string) (*models.QuarantineRecord, error) {
+	log.Printf("[App] QuarantineExtension called: editor=%s, ext=%s", editorType, extensionID)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+	return editor.QuarantineExtension(profile, extensionID, "quarantined manually", "")
+}
+
+// ListQuarantined returns the quarantined extensions
+func (a *App) ListQuarantined() ([]models.QuarantineRecord, error) {
+	log.Println("[App] ListQuarantined called")
+	return editor.ListQuarantined()
+}
+
+// RestoreQuarantined moves a quarantined extension back into its editor
+func (a *App) RestoreQuarantined(quarantineID string) (*models.QuarantineRecord, error) {
+	log.Printf("[App] RestoreQuarantined called: %s", quarantineID)
+	return editor.RestoreQuarantined(quarantineID)
+}
+
+// DeleteQuarantined permanently deletes a quarantined extension
+func (a *App) DeleteQuarantined(quarantineID string) (*models.QuarantineRecord, error) {
+	log.Printf("[App] DeleteQuarantined called: %s", quarantineID)
+	return editor.DeleteQuarantined(quarantineID)
+}
+
+// RemediateExtension quarantines an installed extension and installs the official build in its place
+func (a *App) RemediateExtension(editorType string, extensionID string) (*models.RemediationResult, error) {
+	log.Printf("[App] RemediateExtension called: editor=%s, ext=%s", editorType, extensionID)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+	return remediation.Remediate(profile, extensionID, "", a.validator)
+}
+
+// UndoRemediation removes the official build and restores the quarantined extension
+func (a *App) UndoRemediation(quarantineID string) (*models.QuarantineRecord, error) {
+	log.Printf("[App] UndoRemediation called: %s", quarantineID)
+	return remediation.Undo(quarantineID)
+}
+
+// FixAllAuditFindings applies a fix action (quarantine, replace or uninstall) to every
+// suspicious and malicious extension in an audit report without validating again
+func (a *App) FixAllAuditFindings(editorType string, report *models.AuditReport, action string) (*models.AuditFixReport, error) {
+	log.Printf("[App] FixAllAuditFindings called: editor=%s, action=%s", editorType, action)
+	if report == nil {
+		return nil, fmt.Errorf("audit report is required")
+	}
+	fixAction, err := remediation.ParseFixAction(action)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+	return remediation.FixAudit(profile, report, fixAction, a.validator, nil), nil
+}
+
+// ========== Lockfile APIs ==========
+
+// PlanLockfile compares a vsynx.lock file with the installed extensions
+func (a *App) PlanLockfile(path string, prune bool) (*models.LockPlan, error) {
+	log.Printf("[App] PlanLockfile called: path=%s, prune=%v", path, prune)
+	lock, err := lockfile.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	installed, err := lockfile.InstalledVersions(lock)
+	if err != nil {
+		return nil, err
+	}
+	return lockfile.Plan(lock, installed, prune), nil
+}
+
+// ApplyLockfile converges the targeted editors to a vsynx.lock file
+func (a *App) ApplyLockfile(path string, prune bool) (*models.LockApplyReport, error) {
+	log.Printf("[App] ApplyLockfile called: path=%s, prune=%v", path, prune)
+	plan, err := a.PlanLockfile(path, prune)
+	if err != nil {
+		return nil, err
+	}
+	return lockfile.Apply(plan, a.validator), nil
+}
+
+// ========== Policy APIs ==========
+
+// GetPolicyStatus returns the organization policy, refreshing the cache when it is stale or refresh is set
+func (a *App) GetPolicyStatus(refresh bool) (*models.PolicyStatus, error) {
+	log.Printf("[App] GetPolicyStatus called: refresh=%v", refresh)
+	source, err := policy.LoadSource()
+	if err != nil {
+		return nil, err
+	}
+	return policy.Current(source, refresh)
+}
+
+// PlanPolicyEnforcement lists the actions needed to comply with the organization policy
+func (a *App) PlanPolicyEnforcement() (*models.EnforcementPlan, error) {
+	log.Println("[App] PlanPolicyEnforcement called")
+	status, err := a.GetPolicyStatus(false)
+	if err != nil {
+		return nil, err
+	}
+	return policy.PlanStatus(status)
+}
+
+// EnforcePolicy applies the organization policy to every detected editor
+func (a *App) EnforcePolicy() (*models.EnforcementReport, error) {
+	log.Println("[App] EnforcePolicy called")
+	plan, err := a.PlanPolicyEnforcement()
+	if err != nil {
+		return nil, err
+	}
+	logPath, err := policy.AuditLogPath()
+	if err != nil {
+		return nil, err
+	}
+	return policy.Enforce(plan, a.validator, logPath), nil
+}
+
+// GetEnforcementLog returns the policy enforcement audit log
+func (a *App) GetEnforcementLog() ([]models.EnforcementResult, error) {
+	log.Println("[App] GetEnforcementLog called")
+	logPath, err := policy.AuditLogPath()
+	if err != nil {
+		return nil, err
+	}
+	return policy.ReadAuditLog(logPath)
+}
+
+// ========== Workspace & Profile APIs ==========
+
+// CheckWorkspace compares an editor with a workspace's recommended extensions,
+// optionally installing the missing ones
+func (a *App) CheckWorkspace(dir string, editorType string, install bool) (*models.WorkspaceCheckReport, error) {
+	log.Printf("[App] CheckWorkspace called: dir=%s, editor=%s, install=%v", dir, editorType, install)
+	recs, source, err := workspace.LoadRecommendations(dir)
+	if err != nil {
+		return nil, err
+	}
+	profile, installed, err := workspaceEditorState(editorType)
+	if err != nil {
+		return nil, err
+	}
+	report := workspace.CheckRecommendations(recs, installed, a.validator)
+	report.Source = source
+	report.Editor = profile.ID
+	if install {
+		workspace.InstallMissing(profile, report, a.validator, false)
+	}
+	return report, nil
+}
+
+// ExportWorkspaceRecommendations writes an editor's enabled extensions to <dir>/.vscode/extensions.json
+func (a *App) ExportWorkspaceRecommendations(dir string, editorType string) (*models.WorkspaceRecommendations, error) {
+	log.Printf("[App] ExportWorkspaceRecommendations called: dir=%s, editor=%s", dir, editorType)
+	var existing *models.WorkspaceRecommendations
+	if _, err := os.Stat(workspace.RecommendationsPath(dir)); err == nil {
+		if existing, _, err = workspace.LoadRecommendations(dir); err != nil {
+			return nil, err
+		}
+	}
+	extensions, err := a.GetEditorExtensions(editorType)
+	if err != nil {
+		return nil, err
+	}
+	recs := workspace.RecommendationsFromInstalled(extensions, existing)
+	return recs, workspace.SaveRecommendations(dir, recs)
+}
+
+// ImportCodeProfile compares an editor with a .code-profile export, optionally installing the missing extensions
+func (a *App) ImportCodeProfile(path string, editorType string, install bool) (*models.WorkspaceCheckReport, error) {
+	log.Printf("[App] ImportCodeProfile called: path=%s, editor=%s, install=%v", path, editorType, install)
+	codeProfile, err := workspace.LoadCodeProfile(path)
+	if err != nil {
+		return nil, err
+	}
+	profile, installed, err := workspaceEditorState(editorType)
+	if err != nil {
+		return nil, err
+	}
+	report := workspace.CheckProfile(codeProfile, installed, a.validator)
+	report.Source = path
+	report.Editor = profile.ID
+	if install {
+		workspace.InstallMissing(profile, report, a.validator, false)
+	}
+	return report, nil
+}
+
+// ExportCodeProfile writes an editor's installed extensions to a .code-profile file
+func (a *App) ExportCodeProfile(path string, editorType string, name string) (*models.CodeProfile, error) {
+	log.Printf("[App] ExportCodeProfile called: path=%s, editor=%s", path, editorType)
+	extensions, err := a.GetEditorExtensions(editorType)
+	if err != nil {
+		return nil, err
+	}
+	codeProfile := workspace.ProfileFromInstalled(name, extensions)
+	return codeProfile, workspace.SaveCodeProfile(path, codeProfile)
+}
+
+// workspaceEditorState returns an editor profile and its installed extension versions
+func workspaceEditorState(editorType string) (models.EditorProfile, map[string]string, error) {
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return profile, nil, err
+	}
+	installed, err := editor.InstalledVersions(profile.ExtensionsDir)
+	return profile, installed, err
+}
+
+// ========== Mirror APIs ==========
+
+// MirrorExtensions downloads Legitimate extensions into a local offline repository
+func (a *App) MirrorExtensions(dir string, extensionIDs []string, sources []string, platforms []string, versions int) (*models.MirrorReport, error) {
+	log.Printf("[App] MirrorExtensions called: dir=%s, extensions=%d", dir, len(extensionIDs))
+	opts := mirror.Options{Sources: sources, Platforms: platforms, Versions: versions}
+	return mirror.Mirror(dir, extensionIDs, opts, download.NewDownloader(), a.validator)
+}
+
+// GetMirrorIndex returns the index of a local mirror repository
+func (a *App) GetMirrorIndex(dir string) (*models.MirrorIndex, error) {
+	log.Printf("[App] GetMirrorIndex called: %s", dir)
+	return mirror.LoadIndex(dir)
+}
+
+// InstallFromMirror installs an extension from a local mirror repository for the current platform
+func (a *App) InstallFromMirror(editorType, dir, extensionID, version string) (*models.VSIXInstallResult, error) {
+	log.Printf("[App] InstallFromMirror called: editor=%s, dir=%s, ext=%s", editorType, dir, extensionID)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+	index, err := mirror.LoadIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := mirror.Find(index, extensionID, version, download.CurrentPlatform())
+	if err != nil {
+		return nil, err
+	}
+	data, err := mirror.ReadPackage(dir, entry)
+	if err != nil {
+		return nil, err
+	}
+	return editor.InstallVSIX(profile, data, "gallery", extensionID)
+}
+
+// ========== Update APIs ==========
+
+// CheckOutdatedExtensions compares an editor's installed extensions with the newest
+// compatible versions on the Marketplace and OpenVSX
+func (a *App) CheckOutdatedExtensions(editorType string) (*models.OutdatedReport, error) {
+	log.Printf("[App] CheckOutdatedExtensions called for: %s", editorType)
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+	checker := update.NewChecker(marketplace.NewClient(), openvsx.NewClient())
+	return checker.Check(profile, update.Options{EngineVersion: editor.EngineVersion(profile)})
+}
+
+// UpdateExtensions validates, reviews and installs the available updates of the given
+// extensions (all outdated extensions if none are given). An update whose review needs
+// approval is skipped unless approved lists it as "publisher.name@version"; the skipped
+// result carries the review to show before asking again.
+func (a *App) UpdateExtensions(editorType string, extensionIDs []string, approved []string) (*models.UpdateReport, error) {
+	log.Printf("[App] UpdateExtensions called: editor=%s, extensions=%d, approved=%d", editorType, len(extensionIDs), len(approved))
+	profile, err := editor.GetEditorProfile(models.EditorType(editorType))
+	if err != nil {
+		return nil, err
+	}
+	engine := editor.EngineVersion(profile)
+	checker := update.NewChecker(marketplace.NewClient(), openvsx.NewClient())
+	report, err := checker.Check(profile, update.Options{EngineVersion: engine, ExtensionIDs: extensionIDs})
+	if err != nil {
+		return nil, err
+	}
+	opts := update.UpdateOptions{
+		EngineVersion: engine,
+		Review:        true,
+		Approve: func(ext models.OutdatedExtension, diff *models.PackageDiff) bool {
+			for _, entry := range approved {
+				if strings.EqualFold(entry, ext.ExtensionID+"@"+ext.UpdateVersion) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+	return update.Update(profile, report.Extensions, opts, download.NewDownloader(), a.validator), nil
+}
+
+// DiffExtensionVersions downloads two versions of an extension from a registry and
+// reports the security-relevant changes between them
+func (a *App) DiffExtensionVersions(extensionID, oldVersion, newVersion, source string) (*models.PackageDiff, error) {
+	log.Printf("[App] DiffExtensionVersions called: %s %s -> %s (%s)", extensionID, oldVersion, newVersion, source)
+	return vsixdiff.CompareVersions(download.NewDownloader(), extensionID, oldVersion, newVersion, source, download.UniversalPlatform)
+}
+
 // ========== CLI Installation APIs ==========
 
 // GetCLIInstallStatus checks if the vsynx CLI is installed and accessible
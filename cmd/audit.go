@@ -7,40 +7,229 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/fleet"
 	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/remediation"
 	"github.com/yourusername/secureopenvsx/internal/validation"
 )
 
+var (
+	auditEditor string
+	auditFix    string
+	auditYes    bool
+
+	auditReportTo    string
+	auditReportToken string
+	auditMachineKey  bool
+)
+
 var auditCmd = &cobra.Command{
 	Use:   "audit",
 	Short: "Audit all installed extensions",
 	Long: `Scans all installed VS Code extensions and validates each one.
-Provides a summary report showing trust levels and any issues found.`,
+Provides a summary report showing trust levels and any issues found.
+
+With --fix, suspicious and malicious extensions are fixed using the audit results:
+  quarantine  move the extension into the vsynx quarantine
+  replace     quarantine it and install the official marketplace build
+  uninstall   remove the extension
+Each fix is confirmed interactively unless --yes is given.
+
+With --report-to, the audit report is signed with this machine's key and posted,
+together with the machine identity, user and OS, to a fleet collector started with
+'vsynx server'. --machine-key prints this machine's public key for the collector's
+--enrolled file.
+
+With --verify-signatures, each extension's latest registry package is downloaded
+and its signature verified; the installed copy itself is not checked.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if auditMachineKey {
+			key, err := fleet.LoadMachineKey()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			identity := fleet.Identity(key)
+			fmt.Printf("%s  %s %s\n", identity.PublicKey, identity.Hostname, identity.MachineID)
+			return
+		}
+
+		var fixAction models.FixAction
+		if auditFix != "" {
+			action, err := remediation.ParseFixAction(auditFix)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if outputFormat == "json" && !auditYes {
+				fmt.Fprintln(os.Stderr, "Error: --fix with --output json requires --yes")
+				os.Exit(1)
+			}
+			fixAction = action
+		}
+
+		path := extensionsPath
+		if auditEditor != "" {
+			path = resolveEditorProfile(auditEditor).ExtensionsDir
+		}
+
 		scanner := validation.NewScanner()
+		configureSignatures(scanner)
 
-		report, err := scanner.AuditExtensions(extensionsPath)
+		report, err := scanner.AuditExtensions(path)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error auditing extensions: %v\n", err)
 			os.Exit(1)
 		}
 
-		if outputFormat == "json" {
-			data, _ := json.MarshalIndent(report, "", "  ")
-			fmt.Println(string(data))
+		// The collector receives the report as audited, before any fixes
+		var submitted *models.AuditSubmission
+		var submitErr error
+		if auditReportTo != "" {
+			submitted, submitErr = submitAuditReport(report)
+		}
+
+		exitCode := 0
+		if fixAction != "" {
+			exitCode = runAuditFix(report, fixAction)
 		} else {
-			printAuditReport(report)
+			if outputFormat == "json" {
+				data, _ := json.MarshalIndent(report, "", "  ")
+				fmt.Println(string(data))
+			} else {
+				printAuditReport(report)
+			}
+
+			// Exit with non-zero if any malicious extensions found
+			if report.MaliciousCount > 0 {
+				exitCode = 2
+			}
 		}
 
-		// Exit with non-zero if any malicious extensions found
-		if report.MaliciousCount > 0 {
-			os.Exit(2)
+		if submitErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", submitErr)
+			if exitCode == 0 {
+				exitCode = 1
+			}
+		} else if submitted != nil && outputFormat != "json" {
+			fmt.Printf("\n%s✓%s Report submitted to %s (machine %s)\n", colorGreen, colorReset, auditReportTo, submitted.Machine.MachineID)
+		}
+		if exitCode != 0 {
+			os.Exit(exitCode)
 		}
 	},
 }
 
+// submitAuditReport sends the audit report to the fleet collector given by --report-to
+func submitAuditReport(report *models.AuditReport) (*models.AuditSubmission, error) {
+	key, err := fleet.LoadMachineKey()
+	if err != nil {
+		return nil, err
+	}
+	token := auditReportToken
+	if token == "" {
+		token = os.Getenv(fleetTokenEnvVar)
+	}
+	return fleet.Submit(auditReportTo, report, key, token)
+}
+
+// runAuditFix fixes the flagged extensions of an audit report, prints both reports and
+// returns the exit code
+func runAuditFix(report *models.AuditReport, action models.FixAction) int {
+	editorID := auditEditor
+	if editorID == "" {
+		editorID = string(models.EditorVSCode)
+	}
+	profile := resolveEditorProfile(editorID)
+
+	var confirmFix func(models.ValidationResult) bool
+	if !auditYes {
+		confirmFix = func(result models.ValidationResult) bool {
+			return confirm(fmt.Sprintf("%s %s [%s%s%s]?", fixActionLabel(action), result.ExtensionID,
+				getTrustColor(result.TrustLevel), result.TrustLevel, colorReset))
+		}
+	}
+
+	if outputFormat != "json" {
+		printAuditReport(report)
+		fmt.Printf("\n=== Fixing Flagged Extensions (%s) ===\n\n", action)
+	}
+	fixValidator := validation.NewValidator()
+	configureSignatures(fixValidator)
+	fixReport := remediation.FixAudit(profile, report, action, fixValidator, confirmFix)
+
+	if outputFormat == "json" {
+		data, _ := json.MarshalIndent(struct {
+			Audit *models.AuditReport    `json:"audit"`
+			Fix   *models.AuditFixReport `json:"fix"`
+		}{report, fixReport}, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		printAuditFixReport(fixReport)
+	}
+
+	if fixReport.FailedCount > 0 {
+		return 1
+	}
+	// Malicious extensions that were left in place still fail the audit
+	for _, result := range fixReport.Results {
+		if result.TrustLevel == models.TrustLevelMalicious && result.Status != models.FixStatusFixed {
+			return 2
+		}
+	}
+	return 0
+}
+
+// fixActionLabel returns the verb used when confirming a fix
+func fixActionLabel(action models.FixAction) string {
+	switch action {
+	case models.FixActionQuarantine:
+		return "Quarantine"
+	case models.FixActionReplace:
+		return "Replace with the official build:"
+	default:
+		return "Uninstall"
+	}
+}
+
+func printAuditFixReport(report *models.AuditFixReport) {
+	fmt.Printf("\n=== Fix Report ===\n\n")
+	if len(report.Results) == 0 {
+		fmt.Println("No suspicious or malicious extensions to fix.")
+		return
+	}
+
+	for _, result := range report.Results {
+		switch result.Status {
+		case models.FixStatusFixed:
+			fmt.Printf("%s✓%s %s - %s", colorGreen, colorReset, result.ExtensionID, result.Action)
+			if result.OfficialVersion != "" {
+				fmt.Printf(" (official %s)", result.OfficialVersion)
+			}
+			if result.QuarantineID != "" {
+				fmt.Printf(" [quarantine: %s]", result.QuarantineID)
+			}
+			fmt.Println()
+		case models.FixStatusSkipped:
+			fmt.Printf("- %s - skipped\n", result.ExtensionID)
+		default:
+			fmt.Printf("%s✗%s %s - %s\n", colorRed, colorReset, result.ExtensionID, result.Error)
+		}
+	}
+
+	fmt.Printf("\nFixed: %d, Skipped: %d, Failed: %d\n", report.FixedCount, report.SkippedCount, report.FailedCount)
+}
+
 func init() {
 	rootCmd.AddCommand(auditCmd)
+
+	auditCmd.Flags().StringVar(&auditEditor, "editor", "", "Audit an editor's extensions (default: VS Code or --path)")
+	auditCmd.Flags().StringVar(&auditFix, "fix", "", "Fix flagged extensions: quarantine, replace or uninstall")
+	auditCmd.Flags().BoolVarP(&auditYes, "yes", "y", false, "Fix without asking for confirmation")
+	auditCmd.Flags().StringVar(&auditReportTo, "report-to", "", "Submit the signed audit report to a fleet collector URL")
+	auditCmd.Flags().StringVar(&auditReportToken, "report-token", "", "Collector bearer token (default: $"+fleetTokenEnvVar+")")
+	auditCmd.Flags().BoolVar(&auditMachineKey, "machine-key", false, "Print this machine's public key for the collector's --enrolled file and exit")
+	addSignatureFlags(auditCmd)
 }
 
 func printAuditReport(report *models.AuditReport) {
@@ -55,6 +244,12 @@ func printAuditReport(report *models.AuditReport) {
 	fmt.Printf("  %sMalicious: %d%s\n", colorRed, report.MaliciousCount, colorReset)
 	fmt.Printf("  Unknown: %d\n\n", report.UnknownCount)
 
+	if report.DisabledCount > 0 || report.PendingRemovalCount > 0 {
+		fmt.Println("Install State:")
+		fmt.Printf("  Disabled: %d\n", report.DisabledCount)
+		fmt.Printf("  Pending removal: %d\n\n", report.PendingRemovalCount)
+	}
+
 	// Show details for suspicious and malicious extensions
 	if report.SuspiciousCount > 0 || report.MaliciousCount > 0 {
 		fmt.Println("Extensions Requiring Attention:")
@@ -63,7 +258,11 @@ func printAuditReport(report *models.AuditReport) {
 		for _, result := range report.Results {
 			if result.TrustLevel == models.TrustLevelSuspicious || result.TrustLevel == models.TrustLevelMalicious {
 				trustColor := getTrustColor(result.TrustLevel)
-				fmt.Printf("\n%s [%s%s%s]\n", result.ExtensionID, trustColor, result.TrustLevel, colorReset)
+				fmt.Printf("\n%s [%s%s%s]", result.ExtensionID, trustColor, result.TrustLevel, colorReset)
+				if result.InstallState != "" && result.InstallState != models.InstallStateEnabled {
+					fmt.Printf(" (%s)", installStateLabel(result.InstallState))
+				}
+				fmt.Println()
 
 				if len(result.Differences) > 0 {
 					fmt.Println("  Issues:")
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+)
+
+var (
+	cleanEditor string
+	cleanDryRun bool
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove stale and orphaned extension folders",
+	Long: `Reconciles an editor's extension folders against extensions.json and reclaims
+disk space. Old versions left behind after updates and folders that are not
+listed in extensions.json are removed; the active version of every extension
+is never touched. Index entries pointing at missing folders are pruned.
+
+Use --dry-run to see what would be removed without changing anything.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveEditorProfile(cleanEditor)
+
+		result, err := editor.CleanExtensions(profile.ExtensionsDir, cleanDryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error cleaning extensions: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		title := "Clean Report"
+		verb := "Removed"
+		if cleanDryRun {
+			title = "Clean Preview"
+			verb = "Would remove"
+		}
+
+		fmt.Printf("\n=== %s: %s ===\n", title, profile.Name)
+		fmt.Printf("Extensions Dir: %s\n\n", result.ExtensionsDir)
+
+		if len(result.RemovedFolders) == 0 && len(result.PrunedEntries) == 0 && len(result.SkippedFolders) == 0 {
+			fmt.Println("Nothing to clean.")
+			return
+		}
+
+		for _, folder := range result.RemovedFolders {
+			fmt.Printf("%s %-50s %10s  (%s)\n", verb, folder.Folder, formatBytes(folder.SizeBytes), folder.Reason)
+		}
+		for _, entry := range result.PrunedEntries {
+			fmt.Printf("%s index entry %s (folder missing)\n", verb, entry)
+		}
+		for _, folder := range result.SkippedFolders {
+			fmt.Printf("%sSkipped%s %s (%s)\n", colorYellow, colorReset, folder.Folder, folder.Reason)
+		}
+		for _, folder := range result.PendingFolders {
+			fmt.Printf("%sPending%s %s (will be removed by the editor on next start)\n", colorYellow, colorReset, folder)
+		}
+
+		fmt.Printf("\nSpace reclaimed: %s\n", formatBytes(result.ReclaimedBytes))
+		if cleanDryRun {
+			fmt.Println("\nRun without --dry-run to apply.")
+		}
+	},
+}
+
+// formatBytes formats a byte count for display
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	rootCmd.AddCommand(cleanCmd)
+
+	cleanCmd.Flags().StringVar(&cleanEditor, "editor", "vscode", "Target editor (e.g., vscode, cursor, windsurf)")
+	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "Show what would be removed without changing anything")
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/download"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/vsixdiff"
+)
+
+var (
+	diffSource         string
+	diffTargetPlatform string
+	diffManifest       bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <extension-id> <old-version> <new-version>",
+	Short: "Compare two versions of an extension package",
+	Long: `Downloads two versions of an extension and compares the packages, to catch a
+trusted extension turning malicious in an update. It reports:
+  - a changed publisher
+  - new activation events, especially ones that start the extension on launch
+  - new npm dependencies and extension dependencies or pack entries
+  - new or changed contribution points and a changed entry point
+  - newly added native binaries and minified JavaScript
+
+Each change is rated none, low, medium or high risk. Either version may be a
+path to a local .vsix file instead. Exits with code 3 if the new version raises
+the risk to medium or higher, which "vsynx update --review" asks approval for.
+
+Examples:
+  vsynx diff ms-python.python 2024.0.1 2024.2.0
+  vsynx diff redhat.java 1.30.0 1.31.0 --source openvsx --manifest
+  vsynx diff pub.ext ./pub.ext-1.0.0.vsix ./pub.ext-1.1.0.vsix`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		extensionID := args[0]
+
+		diff, err := diffPackages(extensionID, args[1], args[2])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !strings.EqualFold(diff.ExtensionID, extensionID) {
+			fmt.Fprintf(os.Stderr, "Warning: packages contain %s, not %s\n", diff.ExtensionID, extensionID)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(diff, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			printPackageDiff(diff)
+			if diffManifest {
+				fmt.Printf("\n%s", diff.ManifestDiff)
+			}
+		}
+
+		if diff.RequiresApproval {
+			os.Exit(3)
+		}
+	},
+}
+
+// diffPackages compares two versions of an extension, each a registry version or a local .vsix
+func diffPackages(extensionID, oldVersion, newVersion string) (*models.PackageDiff, error) {
+	dir, err := os.MkdirTemp("", "vsynx-diff-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	downloader := download.NewDownloader()
+	var packages [2][]byte
+	for i, version := range []string{oldVersion, newVersion} {
+		if strings.HasSuffix(strings.ToLower(version), ".vsix") {
+			packages[i], err = os.ReadFile(version)
+		} else {
+			versionDir := filepath.Join(dir, fmt.Sprint(i))
+			if err = os.Mkdir(versionDir, 0755); err == nil {
+				packages[i], err = vsixdiff.FetchPackage(downloader, extensionID, version, diffSource, diffTargetPlatform, versionDir)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s %s: %w", extensionID, version, err)
+		}
+	}
+	return vsixdiff.Compare(packages[0], packages[1])
+}
+
+func printPackageDiff(diff *models.PackageDiff) {
+	fmt.Printf("\n=== Package Diff: %s %s -> %s ===\n\n", diff.ExtensionID, diff.OldVersion, diff.NewVersion)
+	if diff.Error != "" {
+		fmt.Printf("%s✗ %s%s\n", colorRed, diff.Error, colorReset)
+	}
+
+	if len(diff.Changes) == 0 && diff.Error == "" {
+		fmt.Println("No security-relevant changes.")
+	}
+	for _, change := range diff.Changes {
+		fmt.Printf("  %s%-8s%s %-22s %s\n", riskColor(change.Risk), change.Risk, colorReset, change.Kind, change.Detail)
+	}
+
+	fmt.Printf("\nFiles: %d added, %d changed, %d removed\n", diff.FilesAdded, diff.FilesChanged, diff.FilesRemoved)
+	fmt.Printf("Risk: %s%s%s", riskColor(diff.Risk), diff.Risk, colorReset)
+	if diff.RequiresApproval {
+		fmt.Print(" (requires approval)")
+	}
+	fmt.Println()
+}
+
+// riskColor returns the terminal color for a risk level
+func riskColor(risk models.RiskLevel) string {
+	switch risk {
+	case models.RiskHigh, models.RiskUnknown:
+		return colorRed
+	case models.RiskMedium:
+		return colorYellow
+	case models.RiskLow:
+		return colorReset
+	default:
+		return colorGreen
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVar(&diffSource, "source", "marketplace", "Registry to download from: marketplace or openvsx")
+	diffCmd.Flags().StringVar(&diffTargetPlatform, "target-platform", download.UniversalPlatform,
+		"Target platform: "+strings.Join(download.TargetPlatforms, ", "))
+	diffCmd.Flags().BoolVar(&diffManifest, "manifest", false, "Print the package.json diff")
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+var (
+	doctorEditor string
+	doctorFix    bool
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check and repair an editor's extensions index",
+	Long: `Checks an editor's extensions.json against the extension folders on disk and
+prints a repair plan. Detected problems include:
+  - entries whose folder no longer exists
+  - extension folders that are not listed in the index
+  - location paths that point elsewhere (e.g. after moving the home directory)
+  - extensions listed more than once
+  - invalid relativeLocation values and unparseable entries
+
+Use --fix to apply the plan. The previous index is backed up first.
+
+Exit codes:
+  0 - No problems found (or all problems fixed)
+  1 - Error
+  3 - Problems found (run with --fix to repair)`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveEditorProfile(doctorEditor)
+
+		var report *models.DoctorReport
+		var err error
+		if doctorFix {
+			report, err = editor.RepairExtensions(profile.ExtensionsDir)
+		} else {
+			report, err = editor.DiagnoseExtensions(profile.ExtensionsDir)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking extensions: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			printDoctorReport(profile, report)
+		}
+
+		if len(report.Issues) > 0 && !report.Fixed {
+			os.Exit(3)
+		}
+	},
+}
+
+// printDoctorReport prints the issues found and the repair plan
+func printDoctorReport(profile models.EditorProfile, report *models.DoctorReport) {
+	fmt.Printf("\n=== Doctor: %s ===\n", profile.Name)
+	fmt.Printf("Extensions Dir: %s\n", report.ExtensionsDir)
+	fmt.Printf("Index:          %s (%d entries, %d folders)\n\n", report.IndexPath, report.EntryCount, report.FolderCount)
+
+	if len(report.Issues) == 0 {
+		fmt.Printf("%s✓%s No problems found.\n", colorGreen, colorReset)
+		return
+	}
+
+	for _, issue := range report.Issues {
+		subject := issue.ExtensionID
+		if subject == "" {
+			subject = issue.Folder
+		}
+		if subject != "" {
+			fmt.Printf("%s✗%s [%s] %s: %s\n", colorRed, colorReset, issue.Kind, subject, issue.Detail)
+		} else {
+			fmt.Printf("%s✗%s [%s] %s\n", colorRed, colorReset, issue.Kind, issue.Detail)
+		}
+		fmt.Printf("    → %s\n", issue.Repair)
+	}
+
+	if report.Fixed {
+		fmt.Printf("\n%s✓%s Repaired %d issue(s).\n", colorGreen, colorReset, len(report.Issues))
+		if report.BackupPath != "" {
+			fmt.Printf("Previous index saved to %s\n", report.BackupPath)
+		}
+		return
+	}
+
+	fmt.Printf("\n%s%d issue(s) found.%s Run with --fix to apply the repairs above.\n", colorYellow, len(report.Issues), colorReset)
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().StringVar(&doctorEditor, "editor", "vscode", "Target editor (e.g., vscode, cursor, windsurf)")
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Apply the repair plan")
+}
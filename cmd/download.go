@@ -1,59 +1,89 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/yourusername/secureopenvsx/internal/validation"
+	"github.com/yourusername/secureopenvsx/internal/download"
+	"github.com/yourusername/secureopenvsx/internal/models"
 )
 
 var (
-	outputDir string
+	outputDir              string
+	downloadSource         string
+	downloadVersion        string
+	downloadTargetPlatform string
 )
 
 var downloadCmd = &cobra.Command{
 	Use:   "download [extension-id]",
-	Short: "Download official extension from Microsoft Marketplace",
-	Long: `Downloads the official VSIX package for an extension from the Microsoft Marketplace.
-The package is verified and can be used to replace a suspicious or malicious extension.`,
+	Short: "Download an extension package from a registry",
+	Long: `Downloads the VSIX package for an extension from the Microsoft Marketplace or OpenVSX.
+A specific version and target platform can be chosen. The package is streamed to disk;
+an interrupted download leaves a .part file that the next run resumes.
+
+The package is verified against the transfer size and, for OpenVSX, the published
+SHA256 checksum. The Marketplace publishes no checksum, so Marketplace downloads are
+only checked for size ("verification": "size-only"); run "vsynx validate
+--verify-signatures" to check their signature. A <file>.sha256 sidecar and <file>.json
+metadata are written next to it.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		extensionID := args[0]
 
-		validator := validation.NewValidator()
-
-		fmt.Printf("Downloading official extension: %s\n", extensionID)
-
-		data, hash, err := validator.DownloadOfficialExtension(extensionID)
+		downloader := download.NewDownloader()
+		info, err := downloader.Resolve(extensionID, downloadVersion, downloadSource, downloadTargetPlatform)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error downloading extension: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Determine output path
+		if outputFormat != "json" {
+			fmt.Printf("Downloading %s %s (%s, %s)\n", info.ExtensionID, info.Version, info.Source, info.TargetPlatform)
+		}
+
 		if outputDir == "" {
 			outputDir = "."
 		}
-
-		filename := fmt.Sprintf("%s.vsix", extensionID)
-		outputPath := filepath.Join(outputDir, filename)
-
-		// Write to file
-		if err := os.WriteFile(outputPath, data, 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing file: %v\n", err)
+		if err := downloader.Download(info, outputDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error downloading extension: %v\n", err)
 			os.Exit(1)
 		}
 
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(info, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
 		fmt.Printf("\n✓ Successfully downloaded extension\n")
-		fmt.Printf("  File: %s\n", outputPath)
-		fmt.Printf("  Size: %d bytes\n", len(data))
-		fmt.Printf("  SHA256: %s\n\n", hash)
+		fmt.Printf("  File: %s\n", info.File)
+		fmt.Printf("  Size: %s (%d bytes)\n", formatBytes(info.Size), info.Size)
+		if info.ResumedFrom > 0 {
+			fmt.Printf("  Resumed from: %d bytes\n", info.ResumedFrom)
+		}
+		fmt.Printf("  SHA256: %s\n", info.SHA256)
+		switch info.Verification {
+		case models.VerifiedSHA256:
+			fmt.Printf("  %s✓ Matches the checksum published by %s%s\n", colorGreen, info.Source, colorReset)
+		case models.VerifiedSizeOnly:
+			fmt.Printf("  %s⚠ Only the size was verified; %s publishes no checksum%s\n", colorYellow, info.Source, colorReset)
+		default:
+			fmt.Printf("  %s⚠ Not verified; %s publishes no checksum and the size was not sent%s\n", colorYellow, info.Source, colorReset)
+		}
+		fmt.Printf("  Checksum: %s%s\n", info.File, download.ChecksumSuffix)
+		fmt.Printf("  Metadata: %s%s\n\n", info.File, download.MetadataSuffix)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(downloadCmd)
 	downloadCmd.Flags().StringVarP(&outputDir, "output-dir", "d", ".", "Output directory for downloaded extension")
+	downloadCmd.Flags().StringVar(&downloadSource, "source", "marketplace", "Registry to download from: marketplace or openvsx")
+	downloadCmd.Flags().StringVar(&downloadVersion, "version", "", "Version to download (default: latest)")
+	downloadCmd.Flags().StringVar(&downloadTargetPlatform, "target-platform", download.UniversalPlatform,
+		"Target platform: "+strings.Join(download.TargetPlatforms, ", "))
 }
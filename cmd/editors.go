@@ -12,6 +12,14 @@ import (
 	"github.com/yourusername/secureopenvsx/internal/validation"
 )
 
+var (
+	customEditorName        string
+	customEditorDir         string
+	customEditorIndexFile   string
+	customEditorCLI         string
+	customEditorUserDataDir string
+)
+
 var editorsCmd = &cobra.Command{
 	Use:   "editors",
 	Short: "Manage and inspect code editors",
@@ -21,9 +29,10 @@ var editorsCmd = &cobra.Command{
 var editorsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all known editors",
-	Long:  `Lists all known VS Code family editors with their default extension paths.`,
+	Long: `Lists all known VS Code family editors with their default extension paths,
+followed by any custom editors registered with 'vsynx editors add'.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		profiles := editor.GetDefaultEditorProfiles()
+		profiles := editor.GetEditorProfiles()
 
 		if outputFormat == "json" {
 			data, _ := json.MarshalIndent(profiles, "", "  ")
@@ -40,7 +49,11 @@ var editorsListCmd = &cobra.Command{
 			if cli == "" {
 				cli = "(none)"
 			}
-			fmt.Printf("%-20s %-15s %-50s\n", p.ID, cli, p.ExtensionsDir)
+			id := string(p.ID)
+			if p.IsCustom {
+				id += " (custom)"
+			}
+			fmt.Printf("%-20s %-15s %-50s\n", id, cli, p.ExtensionsDir)
 		}
 		fmt.Println()
 	},
@@ -52,7 +65,7 @@ var editorsStatusCmd = &cobra.Command{
 	Long:  `Checks availability, extension count, and CLI status for a specific editor.`,
 	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		profiles := editor.GetDefaultEditorProfiles()
+		profiles := editor.GetEditorProfiles()
 
 		// If no editor specified, show all statuses
 		if len(args) == 0 {
@@ -135,7 +148,7 @@ var editorsExtensionsCmd = &cobra.Command{
 		}
 
 		scanner := validation.NewScanner()
-		extensions, err := scanner.ScanInstalledExtensions(profile.ExtensionsDir)
+		extensions, err := scanner.ScanEditorExtensions(profile)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error scanning extensions: %v\n", err)
 			os.Exit(1)
@@ -154,23 +167,187 @@ var editorsExtensionsCmd = &cobra.Command{
 			return
 		}
 
-		fmt.Printf("%-45s %-15s %-10s\n", "Extension ID", "Version", "Status")
-		fmt.Println(strings.Repeat("-", 75))
+		fmt.Printf("%-45s %-15s %-15s\n", "Extension ID", "Version", "Status")
+		fmt.Println(strings.Repeat("-", 80))
 
 		for _, ext := range extensions {
-			status := "Enabled"
-			if !ext.IsEnabled {
-				status = "Disabled"
-			}
-			fmt.Printf("%-45s %-15s %-10s\n", ext.ID, ext.Version, status)
+			status := installStateLabel(ext.State())
+			fmt.Printf("%-45s %-15s %-15s\n", ext.ID, ext.Version, status)
 		}
 		fmt.Println()
 	},
 }
 
+var editorsDiscoverCmd = &cobra.Command{
+	Use:   "discover [editor-id]",
+	Short: "Show every extensions directory found for each editor",
+	Long: `Lists every extensions directory found for each editor. Besides the default
+location this includes VSCODE_EXTENSIONS, extensions-dir in argv.json, portable
+installs, Flatpak and Snap sandboxes, remote server installs (~/.vscode-server,
+~/.cursor-server, ~/.windsurf-server) and, under WSL, the Windows host install.
+
+The active directory is the one vsynx uses for the editor.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var discoveries []models.EditorDiscovery
+		if len(args) == 1 {
+			profile, err := editor.GetEditorProfile(models.EditorType(args[0]))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Unknown editor: %s\n", args[0])
+				os.Exit(1)
+			}
+			discoveries = []models.EditorDiscovery{editor.DiscoverEditorRoots(profile)}
+		} else {
+			discoveries = editor.DiscoverAllEditorRoots()
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(discoveries, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Printf("\n=== Extension Roots ===\n")
+		for _, d := range discoveries {
+			fmt.Printf("\n%s (%s)\n", d.Editor.Name, d.Editor.ID)
+			for _, root := range d.Roots {
+				marker := " "
+				if root.Active {
+					marker = "*"
+				}
+				exists := fmt.Sprintf("%d extensions", root.ExtensionCount)
+				if !root.Exists {
+					exists = "not found"
+				}
+				fmt.Printf("  %s %-10s %-60s %s\n", marker, root.Source, root.Path, exists)
+			}
+		}
+		fmt.Println("\n* = active extensions directory")
+	},
+}
+
+var editorsAddCmd = &cobra.Command{
+	Use:   "add <editor-id>",
+	Short: "Register a custom editor",
+	Long: `Registers a custom VS Code compatible editor (e.g. Theia, Positron, Trae,
+code-server) so it can be used anywhere an editor ID is accepted.
+
+Custom editors are stored in editors.json in the vsynx config directory.
+If --cli is set, the command must accept VS Code's --install-extension flags.
+
+Example:
+  vsynx editors add positron --name Positron --extensions-dir ~/.positron/extensions`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, err := editor.AddCustomEditorProfile(models.EditorProfile{
+			ID:            models.EditorType(args[0]),
+			Name:          customEditorName,
+			ExtensionsDir: customEditorDir,
+			IndexFile:     customEditorIndexFile,
+			CLICommand:    customEditorCLI,
+			UserDataDir:   customEditorUserDataDir,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error adding editor: %v\n", err)
+			os.Exit(1)
+		}
+		printCustomEditor("Added", profile)
+	},
+}
+
+var editorsEditCmd = &cobra.Command{
+	Use:   "edit <editor-id>",
+	Short: "Change a custom editor",
+	Long:  `Updates the settings of a custom editor. Only the flags given are changed.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, err := editor.GetEditorProfile(models.EditorType(args[0]))
+		if err != nil || !profile.IsCustom {
+			fmt.Fprintf(os.Stderr, "Unknown custom editor: %s\n", args[0])
+			os.Exit(1)
+		}
+
+		flags := cmd.Flags()
+		if flags.Changed("name") {
+			profile.Name = customEditorName
+		}
+		if flags.Changed("extensions-dir") {
+			profile.ExtensionsDir = customEditorDir
+		}
+		if flags.Changed("index-file") {
+			profile.IndexFile = customEditorIndexFile
+		}
+		if flags.Changed("cli") {
+			profile.CLICommand = customEditorCLI
+		}
+		if flags.Changed("user-data-dir") {
+			profile.UserDataDir = customEditorUserDataDir
+		}
+
+		profile, err = editor.UpdateCustomEditorProfile(profile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating editor: %v\n", err)
+			os.Exit(1)
+		}
+		printCustomEditor("Updated", profile)
+	},
+}
+
+var editorsRemoveCmd = &cobra.Command{
+	Use:   "remove <editor-id>",
+	Short: "Remove a custom editor",
+	Long:  `Removes a custom editor from the vsynx config. Its extensions are left on disk.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := editor.RemoveCustomEditorProfile(models.EditorType(args[0])); err != nil {
+			fmt.Fprintf(os.Stderr, "Error removing editor: %v\n", err)
+			os.Exit(1)
+		}
+		if outputFormat != "json" {
+			fmt.Printf("%s✓%s Removed custom editor %s\n", colorGreen, colorReset, args[0])
+		}
+	},
+}
+
+// printCustomEditor prints a custom editor profile after it was saved
+func printCustomEditor(action string, profile models.EditorProfile) {
+	if outputFormat == "json" {
+		data, _ := json.MarshalIndent(profile, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Printf("%s✓%s %s custom editor %s\n\n", colorGreen, colorReset, action, profile.ID)
+	fmt.Printf("  Name:            %s\n", profile.Name)
+	fmt.Printf("  Extensions Dir:  %s\n", profile.ExtensionsDir)
+	fmt.Printf("  Index File:      %s\n", profile.IndexFile)
+	if profile.CLICommand != "" {
+		fmt.Printf("  CLI Command:     %s\n", profile.CLICommand)
+	}
+	if profile.UserDataDir != "" {
+		fmt.Printf("  User Data Dir:   %s\n", profile.UserDataDir)
+	}
+	if path, err := editor.CustomEditorsPath(); err == nil {
+		fmt.Printf("\nSaved to %s\n", path)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(editorsCmd)
 	editorsCmd.AddCommand(editorsListCmd)
 	editorsCmd.AddCommand(editorsStatusCmd)
 	editorsCmd.AddCommand(editorsExtensionsCmd)
+	editorsCmd.AddCommand(editorsDiscoverCmd)
+	editorsCmd.AddCommand(editorsAddCmd)
+	editorsCmd.AddCommand(editorsEditCmd)
+	editorsCmd.AddCommand(editorsRemoveCmd)
+
+	for _, cmd := range []*cobra.Command{editorsAddCmd, editorsEditCmd} {
+		cmd.Flags().StringVar(&customEditorName, "name", "", "Display name")
+		cmd.Flags().StringVar(&customEditorDir, "extensions-dir", "", "Extensions directory")
+		cmd.Flags().StringVar(&customEditorIndexFile, "index-file", "extensions.json", "Index file inside the extensions directory")
+		cmd.Flags().StringVar(&customEditorCLI, "cli", "", "CLI command that accepts --install-extension (optional)")
+		cmd.Flags().StringVar(&customEditorUserDataDir, "user-data-dir", "", "User data directory, used for enable/disable state (optional)")
+	}
+	editorsAddCmd.MarkFlagRequired("extensions-dir")
 }
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/policy"
+	"github.com/yourusername/secureopenvsx/internal/validation"
+)
+
+var (
+	enforceDryRun  bool
+	enforceRefresh bool
+)
+
+var enforceCmd = &cobra.Command{
+	Use:   "enforce",
+	Short: "Apply the organization policy to every detected editor",
+	Long: `Applies the organization policy (see 'vsynx policy') to every detected editor, or to
+the editors the policy lists: blocked extensions are uninstalled, missing mandatory
+extensions are installed and pinned extensions are moved to their pinned version.
+
+Every action is appended to the enforcement audit log (enforce.log in the vsynx
+config directory); view it with 'vsynx policy log'.
+
+Exit codes: 0 compliant or enforced, 1 an action failed, 3 actions pending (--dry-run).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		status := currentPolicy(enforceRefresh)
+		plan, err := policy.PlanStatus(status)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if enforceDryRun {
+			if outputFormat == "json" {
+				data, _ := json.MarshalIndent(plan, "", "  ")
+				fmt.Println(string(data))
+			} else {
+				printEnforcementPlan(plan)
+			}
+			if len(plan.Steps) > 0 {
+				os.Exit(3)
+			}
+			return
+		}
+
+		report, err := enforcePlan(plan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			printEnforcementPlan(plan)
+			printEnforcementReport(report)
+		}
+		if report.Failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// enforcePlan carries out an enforcement plan, recording each action in the audit log
+func enforcePlan(plan *models.EnforcementPlan) (*models.EnforcementReport, error) {
+	logPath, err := policy.AuditLogPath()
+	if err != nil {
+		return nil, err
+	}
+	return policy.Enforce(plan, validation.NewValidator(), logPath), nil
+}
+
+// describeEnforcementStep formats one enforcement step for display
+func describeEnforcementStep(step models.EnforcementStep) string {
+	var action string
+	switch step.Action {
+	case models.EnforcementUninstall:
+		action = fmt.Sprintf("uninstall %s@%s", step.ExtensionID, step.CurrentVersion)
+	case models.EnforcementInstall:
+		target := step.TargetVersion
+		if target == "" {
+			target = "latest"
+		}
+		action = fmt.Sprintf("install %s@%s", step.ExtensionID, target)
+	default:
+		action = fmt.Sprintf("pin %s %s → %s", step.ExtensionID, step.CurrentVersion, step.TargetVersion)
+	}
+	if step.Reason != "" {
+		action += " (" + step.Reason + ")"
+	}
+	return fmt.Sprintf("[%s] %s", step.Editor, action)
+}
+
+func printEnforcementPlan(plan *models.EnforcementPlan) {
+	fmt.Printf("\n=== Policy Enforcement Plan ===\n\n")
+	if plan.Policy != "" {
+		fmt.Printf("Policy: %s\n", plan.Policy)
+	}
+	fmt.Printf("Editors: %d\n", len(plan.Editors))
+	fmt.Printf("Compliant entries: %d\n\n", plan.Compliant)
+
+	if len(plan.Steps) == 0 {
+		fmt.Printf("%s✓%s All editors comply with the policy.\n", colorGreen, colorReset)
+		return
+	}
+	for _, step := range plan.Steps {
+		color := colorGreen
+		if step.Action == models.EnforcementUninstall {
+			color = colorRed
+		} else if step.Action == models.EnforcementPin {
+			color = colorYellow
+		}
+		fmt.Printf("  %s%s%s\n", color, describeEnforcementStep(step), colorReset)
+	}
+}
+
+func printEnforcementReport(report *models.EnforcementReport) {
+	if len(report.Results) == 0 {
+		return
+	}
+	fmt.Printf("\n=== Enforcement Results ===\n\n")
+	for _, result := range report.Results {
+		if result.Success {
+			fmt.Printf("%s✓%s %s\n", colorGreen, colorReset, describeEnforcementStep(result.Step))
+		} else {
+			fmt.Printf("%s✗%s %s - %s\n", colorRed, colorReset, describeEnforcementStep(result.Step), result.Error)
+		}
+	}
+	fmt.Printf("\nSucceeded: %d, Failed: %d\n", report.Succeeded, report.Failed)
+}
+
+func init() {
+	rootCmd.AddCommand(enforceCmd)
+
+	enforceCmd.Flags().BoolVar(&enforceDryRun, "dry-run", false, "Show the actions without applying them")
+	enforceCmd.Flags().BoolVar(&enforceRefresh, "refresh", false, "Fetch the policy even if the cache is fresh")
+}
@@ -8,25 +8,47 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/download"
 	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/mirror"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/validation"
 )
 
 var (
 	installEditor string
 	installCLI    string
 	installFile   string
+	installNative bool
+	installRepo   string
+	installAllow  bool
 )
 
 var installCmd = &cobra.Command{
 	Use:   "install [extension-id...]",
-	Short: "Install extensions via VS Code CLI",
+	Short: "Install extensions via VS Code CLI or natively",
 	Long: `Installs extensions using the VS Code family CLI tools.
 Supports installing to VS Code, VS Code Insiders, or VSCodium.
 
+With --native, vsynx installs the VSIX itself without needing the editor CLI,
+which also works for Windsurf, Cursor and Kiro. Arguments ending in .vsix are
+installed from disk; other arguments are downloaded from the Microsoft
+Marketplace by extension ID. The extension in a .vsix file is validated first:
+malicious ones are refused, and suspicious or unknown ones unless
+--allow-untrusted is given.
+
+With --repo, extensions are installed natively from a local mirror created by
+"vsynx mirror" instead of a registry (use publisher.name@version to pick a version).
+
 Note: This does NOT install from OpenVSX. Extensions are installed from
 the Microsoft Marketplace via the official VS Code CLI.`,
 	Args: cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		if installNative || installRepo != "" {
+			runNativeInstall(readInstallArgs(args))
+			return
+		}
+
 		// Determine which CLI to use
 		cliCommand := installCLI
 		if cliCommand == "" {
@@ -64,40 +86,7 @@ the Microsoft Marketplace via the official VS Code CLI.`,
 		}
 
 		// Collect extension IDs
-		var extensionIDs []string
-
-		// From command line args
-		extensionIDs = append(extensionIDs, args...)
-
-		// From file
-		if installFile != "" {
-			file, err := os.Open(installFile)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error opening file: %v\n", err)
-				os.Exit(1)
-			}
-			defer file.Close()
-
-			scanner := bufio.NewScanner(file)
-			for scanner.Scan() {
-				line := strings.TrimSpace(scanner.Text())
-				// Skip empty lines and comments
-				if line != "" && !strings.HasPrefix(line, "#") {
-					extensionIDs = append(extensionIDs, line)
-				}
-			}
-			if err := scanner.Err(); err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
-				os.Exit(1)
-			}
-		}
-
-		if len(extensionIDs) == 0 {
-			fmt.Fprintln(os.Stderr, "No extensions specified.")
-			fmt.Fprintln(os.Stderr, "Usage: vsynx install <extension-id> [extension-id...]")
-			fmt.Fprintln(os.Stderr, "   or: vsynx install --file extensions.txt")
-			os.Exit(1)
-		}
+		extensionIDs := readInstallArgs(args)
 
 		// Install extensions
 		report := editor.InstallMultipleExtensionsViaCLI(cliCommand, extensionIDs)
@@ -128,6 +117,148 @@ the Microsoft Marketplace via the official VS Code CLI.`,
 	},
 }
 
+// readInstallArgs collects extension IDs from the command line and --file
+func readInstallArgs(args []string) []string {
+	var extensionIDs []string
+
+	// From command line args
+	extensionIDs = append(extensionIDs, args...)
+
+	// From file
+	if installFile != "" {
+		extensionIDs = append(extensionIDs, readExtensionListFile(installFile)...)
+	}
+
+	if len(extensionIDs) == 0 {
+		fmt.Fprintln(os.Stderr, "No extensions specified.")
+		fmt.Fprintln(os.Stderr, "Usage: vsynx install <extension-id> [extension-id...]")
+		fmt.Fprintln(os.Stderr, "   or: vsynx install --file extensions.txt")
+		os.Exit(1)
+	}
+
+	return extensionIDs
+}
+
+// readExtensionListFile reads extension IDs from a file with one ID per line, skipping
+// blank lines and # comments
+func readExtensionListFile(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening file: %v\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	var extensionIDs []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// Skip empty lines and comments
+		if line != "" && !strings.HasPrefix(line, "#") {
+			extensionIDs = append(extensionIDs, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
+		os.Exit(1)
+	}
+	return extensionIDs
+}
+
+// runNativeInstall installs VSIX files or marketplace downloads directly into the editor's extensions directory
+func runNativeInstall(targets []string) {
+	profile := resolveEditorProfile(installEditor)
+
+	validator := validation.NewValidator()
+	var repoIndex *models.MirrorIndex
+	if installRepo != "" {
+		index, err := mirror.LoadIndex(installRepo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		repoIndex = index
+	}
+
+	report := models.InstallReport{
+		TargetEditor: profile.ID,
+		CLIUsed:      "native",
+		Results:      make([]models.InstallResult, 0, len(targets)),
+	}
+
+	for _, target := range targets {
+		result := models.InstallResult{ExtensionID: target}
+
+		var installed *models.VSIXInstallResult
+		var err error
+		if strings.HasSuffix(strings.ToLower(target), ".vsix") {
+			installed, err = editor.InstallVSIXFile(profile, target, validator, installAllow)
+		} else if repoIndex != nil {
+			installed, err = installFromRepo(profile, repoIndex, target)
+		} else {
+			var data []byte
+			data, _, err = validator.DownloadOfficialExtension(target)
+			if err == nil {
+				installed, err = editor.InstallVSIX(profile, data, "gallery", target)
+			}
+		}
+
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			report.TotalFailed++
+		} else {
+			result.ExtensionID = installed.ExtensionID
+			result.Success = true
+			result.Message = fmt.Sprintf("Installed %s to %s", installed.Version, installed.InstallPath)
+			report.TotalSuccess++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	if outputFormat == "json" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		if report.TotalFailed > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Printf("\n=== Native Install Report ===\n")
+	fmt.Printf("Editor: %s\n", profile.Name)
+	fmt.Printf("Extensions Dir: %s\n\n", profile.ExtensionsDir)
+
+	for _, result := range report.Results {
+		if result.Success {
+			fmt.Printf("%s✓%s %s - %s\n", colorGreen, colorReset, result.ExtensionID, result.Message)
+		} else {
+			fmt.Printf("%s✗%s %s - %s\n", colorRed, colorReset, result.ExtensionID, result.Error)
+		}
+	}
+
+	fmt.Printf("\nSuccess: %d, Failed: %d\n", report.TotalSuccess, report.TotalFailed)
+
+	if report.TotalFailed > 0 {
+		os.Exit(1)
+	}
+}
+
+// installFromRepo installs an extension (publisher.name or publisher.name@version) from the
+// local mirror for the current platform
+func installFromRepo(profile models.EditorProfile, index *models.MirrorIndex, target string) (*models.VSIXInstallResult, error) {
+	extensionID, version, _ := strings.Cut(target, "@")
+	entry, err := mirror.Find(index, extensionID, version, download.CurrentPlatform())
+	if err != nil {
+		return nil, err
+	}
+	data, err := mirror.ReadPackage(installRepo, entry)
+	if err != nil {
+		return nil, err
+	}
+	return editor.InstallVSIX(profile, data, "gallery", extensionID)
+}
+
 var installCLICmd = &cobra.Command{
 	Use:   "install-cli",
 	Short: "Install the vsynx CLI to your PATH",
@@ -176,7 +307,10 @@ func init() {
 	rootCmd.AddCommand(installCmd)
 	rootCmd.AddCommand(installCLICmd)
 
-	installCmd.Flags().StringVar(&installEditor, "editor", "vscode", "Target editor (vscode, vscode-insiders, vscodium)")
+	installCmd.Flags().StringVar(&installEditor, "editor", "vscode", "Target editor (vscode, vscode-insiders, vscodium; any editor with --native)")
 	installCmd.Flags().StringVar(&installCLI, "cli", "", "CLI command to use directly (code, code-insiders, codium)")
 	installCmd.Flags().StringVarP(&installFile, "file", "f", "", "File containing extension IDs (one per line)")
+	installCmd.Flags().BoolVar(&installNative, "native", false, "Install by unpacking the VSIX directly instead of using the editor CLI")
+	installCmd.Flags().StringVar(&installRepo, "repo", "", "Install natively from a local mirror directory (see vsynx mirror)")
+	installCmd.Flags().BoolVar(&installAllow, "allow-untrusted", false, "Install .vsix files whose extension is Suspicious or Unknown")
 }
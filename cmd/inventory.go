@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/validation"
+)
+
+var (
+	inventoryEditors   []string
+	inventorySkipTrust bool
+)
+
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Show installed extensions across all editors",
+	Long: `Scans every available editor and prints a matrix of extension ID by editor
+with the installed version in each cell. Extensions installed at different
+versions (version skew) and extensions present in only one editor are
+highlighted, together with each extension's trust level.
+
+Supports --output text (default), json and csv.
+
+Examples:
+  vsynx inventory
+  vsynx inventory --editors vscode,cursor --skip-trust
+  vsynx inventory --output csv > inventory.csv`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var profiles []models.EditorProfile
+		if len(inventoryEditors) > 0 {
+			for _, editorID := range inventoryEditors {
+				profile, err := editor.GetEditorProfile(models.EditorType(editorID))
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Unknown editor: %s\n", editorID)
+					os.Exit(1)
+				}
+				profiles = append(profiles, profile)
+			}
+		} else {
+			profiles = editor.GetEditorProfiles()
+		}
+
+		scanner := validation.NewScanner()
+		inventory, err := scanner.BuildInventory(profiles, !inventorySkipTrust)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error building inventory: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch outputFormat {
+		case "json":
+			data, _ := json.MarshalIndent(inventory, "", "  ")
+			fmt.Println(string(data))
+		case "csv":
+			if err := writeInventoryCSV(inventory); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing CSV: %v\n", err)
+				os.Exit(1)
+			}
+		default:
+			printInventory(inventory)
+		}
+	},
+}
+
+// printInventory prints the inventory matrix as a table
+func printInventory(inventory *models.Inventory) {
+	fmt.Printf("\n=== Extension Inventory (%d extensions, %d editors) ===\n\n", inventory.TotalExtensions, len(inventory.Editors))
+
+	if len(inventory.Editors) == 0 {
+		fmt.Println("No editors with an extensions directory were found.")
+		return
+	}
+
+	header := fmt.Sprintf("%-45s", "Extension ID")
+	for _, editorID := range inventory.Editors {
+		header += fmt.Sprintf(" %-16s", editorID)
+	}
+	if inventory.TrustChecked {
+		header += fmt.Sprintf(" %-12s", "Trust")
+	}
+	header += " Notes"
+	fmt.Println(header)
+	fmt.Println(strings.Repeat("-", len(header)+10))
+
+	for _, row := range inventory.Rows {
+		line := fmt.Sprintf("%-45s", row.ExtensionID)
+		for _, editorID := range inventory.Editors {
+			cell, ok := row.Editors[editorID]
+			text := "-"
+			if ok {
+				text = cell.Version
+				if cell.State != models.InstallStateEnabled {
+					text += "*"
+				}
+			}
+			text = fmt.Sprintf(" %-16s", text)
+			if ok && row.VersionSkew {
+				text = colorYellow + text + colorReset
+			}
+			line += text
+		}
+		if inventory.TrustChecked {
+			line += " " + getTrustColor(row.TrustLevel) + fmt.Sprintf("%-12s", row.TrustLevel) + colorReset
+		}
+
+		var notes []string
+		if row.VersionSkew {
+			notes = append(notes, "version skew")
+		}
+		if row.SingleEditor {
+			for editorID := range row.Editors {
+				notes = append(notes, fmt.Sprintf("only in %s", editorID))
+			}
+		}
+		line += " " + strings.Join(notes, ", ")
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	fmt.Printf("\nVersion skew: %d   Only in one editor: %d\n", inventory.VersionSkewCount, inventory.SingleEditorCount)
+	fmt.Println("* = disabled or pending removal")
+}
+
+// writeInventoryCSV writes the inventory matrix as CSV to stdout
+func writeInventoryCSV(inventory *models.Inventory) error {
+	w := csv.NewWriter(os.Stdout)
+
+	header := []string{"extension_id"}
+	for _, editorID := range inventory.Editors {
+		header = append(header, string(editorID))
+	}
+	header = append(header, "trust_level", "version_skew", "single_editor")
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range inventory.Rows {
+		record := []string{row.ExtensionID}
+		for _, editorID := range inventory.Editors {
+			record = append(record, row.Editors[editorID].Version)
+		}
+		record = append(record, string(row.TrustLevel),
+			fmt.Sprintf("%t", row.VersionSkew), fmt.Sprintf("%t", row.SingleEditor))
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+func init() {
+	rootCmd.AddCommand(inventoryCmd)
+
+	inventoryCmd.Flags().StringSliceVar(&inventoryEditors, "editors", nil, "Editors to include (default: all available)")
+	inventoryCmd.Flags().BoolVar(&inventorySkipTrust, "skip-trust", false, "Skip marketplace validation of each extension")
+}
@@ -46,16 +46,25 @@ func printExtensionsList(extensions []models.InstalledExtension) {
 	}
 
 	// Print table header
-	fmt.Printf("%-40s %-15s %-10s\n", "Extension ID", "Version", "Status")
-	fmt.Println(strings.Repeat("-", 70))
+	fmt.Printf("%-40s %-15s %-15s\n", "Extension ID", "Version", "Status")
+	fmt.Println(strings.Repeat("-", 75))
 
 	for _, ext := range extensions {
-		status := "Enabled"
-		if !ext.IsEnabled {
-			status = "Disabled"
-		}
-		fmt.Printf("%-40s %-15s %-10s\n", ext.ID, ext.Version, status)
+		status := installStateLabel(ext.State())
+		fmt.Printf("%-40s %-15s %-15s\n", ext.ID, ext.Version, status)
 	}
 
 	fmt.Println()
 }
+
+// installStateLabel returns a human-readable label for an install state
+func installStateLabel(state models.InstallState) string {
+	switch state {
+	case models.InstallStateDisabled:
+		return "Disabled"
+	case models.InstallStatePendingRemoval:
+		return "Pending removal"
+	default:
+		return "Enabled"
+	}
+}
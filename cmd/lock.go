@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/lockfile"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/validation"
+)
+
+var (
+	lockFile    string
+	lockFrom    string
+	lockEditors []string
+	lockNoHash  bool
+	lockForce   bool
+	lockPrune   bool
+	lockYes     bool
+)
+
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Create a vsynx.lock manifest from an editor",
+	Long: `Writes a vsynx.lock manifest pinning every extension installed in an editor
+to its current version. Commit the file and run 'vsynx apply' on other machines
+to get the same, verified set of extensions.
+
+Each package is downloaded to record its SHA256, which 'vsynx apply' then
+verifies before installing. --no-hash skips this, leaving the versions unpinned
+to a specific package.
+
+Example vsynx.lock:
+  {
+    "version": 1,
+    "editors": ["vscode", "cursor"],
+    "extensions": [
+      {"id": "ms-python.python", "version": "2024.2.0", "sha256": "...", "source": "marketplace"},
+      {"id": "golang.go", "version": "0.41.0", "source": "openvsx", "editors": ["vscode"]}
+    ]
+  }`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := os.Stat(lockFile); err == nil && !lockForce {
+			fmt.Fprintf(os.Stderr, "%s already exists (use --force to overwrite)\n", lockFile)
+			os.Exit(1)
+		}
+
+		profile := resolveEditorProfile(lockFrom)
+		versions, err := editor.InstalledVersions(profile.ExtensionsDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading extensions: %v\n", err)
+			os.Exit(1)
+		}
+
+		targets := []models.EditorType{profile.ID}
+		if len(lockEditors) > 0 {
+			targets = nil
+			for _, editorID := range lockEditors {
+				targets = append(targets, models.EditorType(editorID))
+			}
+		}
+
+		var fetcher lockfile.Fetcher
+		if !lockNoHash {
+			fetcher = validation.NewValidator()
+		}
+		lock, err := lockfile.Generate(versions, targets, fetcher)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating lockfile: %v\n", err)
+			os.Exit(1)
+		}
+		if err := lockfile.Save(lockFile, lock); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing lockfile: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("%s✓%s Wrote %s with %d extensions from %s\n", colorGreen, colorReset, lockFile, len(lock.Extensions), profile.Name)
+	},
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show what 'vsynx apply' would change",
+	Long: `Compares vsynx.lock with the extensions installed in each editor it targets
+and prints the installs, updates and downgrades needed to match it.
+
+Exit codes:
+  0 - All editors match the lockfile
+  1 - Error
+  3 - Changes needed`,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, plan := loadLockPlan()
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(plan, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			printLockPlan(plan)
+		}
+
+		if len(plan.Steps) > 0 {
+			os.Exit(3)
+		}
+	},
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Install the exact extensions listed in vsynx.lock",
+	Long: `Converges every editor targeted by vsynx.lock to it: missing extensions are
+installed and other versions are replaced with the pinned version. Each extension
+is validated (Malicious ones are refused), its package downloaded once from the
+entry's source, checked against the lockfile's SHA256 and installed natively.
+
+Extensions not listed in the lockfile are left alone unless --prune is given;
+removals are confirmed first unless --yes is set.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, plan := loadLockPlan()
+
+		if removals := lockRemovals(plan); len(removals) > 0 && !lockYes {
+			if outputFormat == "json" {
+				fmt.Fprintf(os.Stderr, "Error: --prune would remove %d extension(s); pass --yes to remove them with --output json\n", len(removals))
+				os.Exit(1)
+			}
+			fmt.Println("\nNot in the lockfile, will be removed:")
+			for _, step := range removals {
+				fmt.Printf("  %-16s %s\n", step.Editor, describeLockStep(step))
+			}
+			if !confirm(fmt.Sprintf("Remove %d extension(s)?", len(removals))) {
+				fmt.Println("Cancelled.")
+				return
+			}
+		}
+
+		if len(plan.Steps) == 0 {
+			if outputFormat == "json" {
+				data, _ := json.MarshalIndent(&models.LockApplyReport{Results: []models.LockApplyResult{}}, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+			fmt.Printf("%s✓%s All editors match %s.\n", colorGreen, colorReset, lockFile)
+			return
+		}
+
+		report := lockfile.Apply(plan, validation.NewValidator())
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			fmt.Printf("\n=== Apply Report ===\n\n")
+			for _, result := range report.Results {
+				step := result.Step
+				if result.Success {
+					fmt.Printf("%s✓%s %-10s %s %s\n", colorGreen, colorReset, step.Action, step.Editor, describeLockStep(step))
+				} else {
+					fmt.Printf("%s✗%s %-10s %s %s - %s\n", colorRed, colorReset, step.Action, step.Editor, describeLockStep(step), result.Error)
+				}
+			}
+			fmt.Printf("\nSuccess: %d, Failed: %d\n", report.Succeeded, report.Failed)
+		}
+
+		if report.Failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// loadLockPlan loads the lockfile and plans the changes against the installed extensions
+func loadLockPlan() (*models.Lockfile, *models.LockPlan) {
+	lock, err := lockfile.Load(lockFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	installed, err := lockfile.InstalledVersions(lock)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading installed extensions: %v\n", err)
+		os.Exit(1)
+	}
+	return lock, lockfile.Plan(lock, installed, lockPrune)
+}
+
+// lockRemovals returns the remove steps of a plan
+func lockRemovals(plan *models.LockPlan) []models.LockPlanStep {
+	var removals []models.LockPlanStep
+	for _, step := range plan.Steps {
+		if step.Action == models.LockActionRemove {
+			removals = append(removals, step)
+		}
+	}
+	return removals
+}
+
+// printLockPlan prints the planned changes grouped by action
+func printLockPlan(plan *models.LockPlan) {
+	fmt.Printf("\n=== Plan: %s ===\n\n", lockFile)
+
+	if len(plan.Steps) == 0 {
+		fmt.Printf("%s✓%s All editors match the lockfile (%d extensions up to date).\n", colorGreen, colorReset, plan.UpToDate)
+	}
+
+	for _, step := range plan.Steps {
+		symbol, color := "~", colorYellow
+		switch step.Action {
+		case models.LockActionInstall:
+			symbol, color = "+", colorGreen
+		case models.LockActionRemove:
+			symbol, color = "-", colorRed
+		}
+		fmt.Printf("%s%s %-10s%s %-16s %s\n", color, symbol, step.Action, colorReset, step.Editor, describeLockStep(step))
+	}
+
+	if len(plan.Unmanaged) > 0 {
+		editors := make([]string, 0, len(plan.Unmanaged))
+		for editorID := range plan.Unmanaged {
+			editors = append(editors, string(editorID))
+		}
+		sort.Strings(editors)
+
+		fmt.Println("\nNot in lockfile (kept; use --prune to remove):")
+		for _, editorID := range editors {
+			for _, id := range plan.Unmanaged[models.EditorType(editorID)] {
+				fmt.Printf("  %-16s %s\n", editorID, id)
+			}
+		}
+	}
+
+	if len(plan.Steps) > 0 {
+		fmt.Printf("\n%d change(s), %d up to date. Run 'vsynx apply' to apply.\n", len(plan.Steps), plan.UpToDate)
+	}
+}
+
+// describeLockStep formats the extension and version change of a plan step
+func describeLockStep(step models.LockPlanStep) string {
+	switch {
+	case step.Action == models.LockActionRemove:
+		return fmt.Sprintf("%s@%s", step.ExtensionID, step.CurrentVersion)
+	case step.CurrentVersion == "":
+		return fmt.Sprintf("%s@%s", step.ExtensionID, step.TargetVersion)
+	default:
+		return fmt.Sprintf("%s %s → %s", step.ExtensionID, step.CurrentVersion, step.TargetVersion)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(applyCmd)
+
+	for _, cmd := range []*cobra.Command{lockCmd, planCmd, applyCmd} {
+		cmd.Flags().StringVarP(&lockFile, "file", "f", lockfile.DefaultFileName, "Lockfile path")
+	}
+	for _, cmd := range []*cobra.Command{planCmd, applyCmd} {
+		cmd.Flags().BoolVar(&lockPrune, "prune", false, "Also remove extensions that are not in the lockfile")
+	}
+	applyCmd.Flags().BoolVarP(&lockYes, "yes", "y", false, "Remove extensions without asking for confirmation")
+
+	lockCmd.Flags().StringVar(&lockFrom, "from", "vscode", "Editor to read installed extensions from")
+	lockCmd.Flags().StringSliceVar(&lockEditors, "editors", nil, "Editors the lockfile targets (default: the --from editor)")
+	lockCmd.Flags().BoolVar(&lockNoHash, "no-hash", false, "Do not download packages to record their SHA256")
+	lockCmd.Flags().BoolVar(&lockForce, "force", false, "Overwrite an existing lockfile")
+}
@@ -8,10 +8,16 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/yourusername/secureopenvsx/internal/marketplace"
+	"github.com/yourusername/secureopenvsx/internal/models"
 )
 
 var (
-	searchLimit int
+	searchLimit    int
+	searchPage     int
+	searchSort     string
+	searchCategory string
+	searchTags     []string
+	searchVerified bool
 )
 
 var marketplaceCmd = &cobra.Command{
@@ -21,52 +27,92 @@ var marketplaceCmd = &cobra.Command{
 }
 
 var marketplaceSearchCmd = &cobra.Command{
-	Use:   "search <query>",
+	Use:   "search [query]",
 	Short: "Search for extensions",
-	Long:  `Searches the Microsoft Marketplace for extensions matching the given query.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Searches the Microsoft Marketplace for extensions matching the given query.
+Results can be sorted by installs, rating or last update, paged, and filtered by
+category, tag and verified publishers.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		query := args[0]
+		opts := models.SearchOptions{
+			SortBy:       searchSort,
+			Page:         searchPage,
+			PageSize:     searchLimit,
+			Category:     searchCategory,
+			Tags:         searchTags,
+			VerifiedOnly: searchVerified,
+		}
+		if len(args) > 0 {
+			opts.Query = args[0]
+		}
+		if opts.Query == "" && opts.Category == "" && len(opts.Tags) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: a query, --category or --tag is required")
+			os.Exit(1)
+		}
 
 		client := marketplace.NewClient()
-		results, err := client.SearchExtensions(query)
+		results, err := client.Search(opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error searching marketplace: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Apply limit
-		if searchLimit > 0 && len(results) > searchLimit {
-			results = results[:searchLimit]
-		}
-
 		if outputFormat == "json" {
 			data, _ := json.MarshalIndent(results, "", "  ")
 			fmt.Println(string(data))
 			return
 		}
 
-		fmt.Printf("\n=== Marketplace Search: \"%s\" (%d results) ===\n\n", query, len(results))
+		totalNote := ""
+		if results.TotalUnfiltered {
+			totalNote = " before the verified filter"
+		}
+		fmt.Printf("\n=== Marketplace Search: \"%s\" (page %d, %d of %d results%s) ===\n\n",
+			opts.Query, results.Page, len(results.Extensions), results.Total, totalNote)
 
-		if len(results) == 0 {
+		if len(results.Extensions) == 0 {
 			fmt.Println("No extensions found.")
+			if results.Total > results.Page*results.PageSize {
+				fmt.Printf("More results: --page %d\n", results.Page+1)
+			}
 			return
 		}
 
-		fmt.Printf("%-45s %-25s %-12s %s\n", "Extension ID", "Publisher", "Version", "Verified")
-		fmt.Println(strings.Repeat("-", 100))
+		fmt.Printf("%-45s %-12s %10s %-12s %s\n", "Extension ID", "Version", "Installs", "Rating", "Verified")
+		fmt.Println(strings.Repeat("-", 90))
 
-		for _, ext := range results {
+		for _, ext := range results.Extensions {
 			verified := ""
 			if ext.IsVerifiedPublisher {
 				verified = "✓"
 			}
-			fmt.Printf("%-45s %-25s %-12s %s\n", ext.ID, ext.Publisher, ext.Version, verified)
+			rating := ""
+			if ext.RatingCount > 0 {
+				rating = fmt.Sprintf("%.1f (%s)", ext.AverageRating, formatCount(ext.RatingCount))
+			}
+			fmt.Printf("%-45s %-12s %10s %-12s %s\n", ext.ID, ext.Version, formatCount(ext.InstallCount), rating, verified)
+		}
+
+		if results.Total > results.Page*results.PageSize {
+			// Pages are counted before the verified filter, so a later page may show fewer results
+			fmt.Printf("\nMore results: --page %d\n", results.Page+1)
 		}
 		fmt.Println()
 	},
 }
 
+// formatCount formats a large count compactly, e.g. 1.2M
+func formatCount(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fK", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
 var marketplaceOpenCmd = &cobra.Command{
 	Use:   "open <extension-id>",
 	Short: "Get marketplace URL for an extension",
@@ -94,5 +140,10 @@ func init() {
 	marketplaceCmd.AddCommand(marketplaceSearchCmd)
 	marketplaceCmd.AddCommand(marketplaceOpenCmd)
 
-	marketplaceSearchCmd.Flags().IntVarP(&searchLimit, "limit", "l", 20, "Maximum number of results to display")
+	marketplaceSearchCmd.Flags().IntVarP(&searchLimit, "limit", "l", models.DefaultSearchPageSize, "Results per page")
+	marketplaceSearchCmd.Flags().IntVar(&searchPage, "page", 1, "Page of results to show")
+	marketplaceSearchCmd.Flags().StringVar(&searchSort, "sort", models.SortRelevance, "Sort by installs, rating, updated or relevance")
+	marketplaceSearchCmd.Flags().StringVar(&searchCategory, "category", "", "Only extensions in this category (e.g. \"Programming Languages\")")
+	marketplaceSearchCmd.Flags().StringSliceVar(&searchTags, "tag", nil, "Only extensions with this tag (repeatable)")
+	marketplaceSearchCmd.Flags().BoolVar(&searchVerified, "verified", false, "Only extensions from verified publishers")
 }
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/download"
+	"github.com/yourusername/secureopenvsx/internal/mirror"
+	"github.com/yourusername/secureopenvsx/internal/validation"
+)
+
+var (
+	mirrorFile        string
+	mirrorDest        string
+	mirrorSources     []string
+	mirrorPlatforms   []string
+	mirrorVersions    int
+	mirrorAllVersions bool
+	mirrorPreRelease  bool
+	mirrorSuspicious  bool
+	mirrorUnknown     bool
+)
+
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror [extension-id...]",
+	Short: "Mirror extensions into a local offline repository",
+	Long: `Downloads extensions into a local repository that can be carried into an
+air-gapped network. Each extension is validated first: malicious extensions are
+refused, and so are suspicious and unknown ones unless --allow-suspicious or
+--allow-unknown is given. Every package is verified against its transfer size,
+published checksum and VSIX manifest, and the repository index (index.json) records
+what was mirrored. Pre-releases are skipped unless --pre-release is given, including
+OpenVSX ones, which are only recognised from the downloaded package.
+
+Packages are laid out as <publisher>/<name>/<version>/<file>.vsix with .sha256 and
+.json sidecars. Re-running keeps packages that are already in the repository.
+
+Install from the repository with "vsynx install --repo <dir> <extension-id>".`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		extensionIDs := append([]string{}, args...)
+		if mirrorFile != "" {
+			extensionIDs = append(extensionIDs, readExtensionListFile(mirrorFile)...)
+		}
+		if len(extensionIDs) == 0 {
+			fmt.Fprintln(os.Stderr, "No extensions specified.")
+			fmt.Fprintln(os.Stderr, "Usage: vsynx mirror --file list.txt --dest ./repo")
+			os.Exit(1)
+		}
+
+		opts := mirror.Options{
+			Sources:    mirrorSources,
+			Platforms:  mirrorPlatforms,
+			Versions:   mirrorVersions,
+			PreRelease: mirrorPreRelease,
+
+			AllowSuspicious: mirrorSuspicious,
+			AllowUnknown:    mirrorUnknown,
+		}
+		if mirrorAllVersions {
+			opts.Versions = 0
+		}
+
+		report, err := mirror.Mirror(mirrorDest, extensionIDs, opts, download.NewDownloader(), validation.NewValidator())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			fmt.Printf("\n=== Mirror Report ===\n")
+			fmt.Printf("Repository: %s\n", report.Dir)
+			fmt.Printf("Extensions: %d\n\n", report.Extensions)
+
+			for _, entry := range report.Added {
+				fmt.Printf("%s✓%s %s %s (%s) from %s - %s, %s\n", colorGreen, colorReset,
+					entry.ExtensionID, entry.Version, entry.TargetPlatform, entry.Source,
+					formatBytes(entry.Size), entry.TrustLevel)
+			}
+			for _, failure := range report.Failed {
+				target := failure.ExtensionID
+				if failure.Version != "" {
+					target += " " + failure.Version
+				}
+				if failure.TargetPlatform != "" {
+					target += " (" + failure.TargetPlatform + ")"
+				}
+				fmt.Printf("%s✗%s %s - %s\n", colorRed, colorReset, target, failure.Error)
+			}
+
+			fmt.Printf("\nAdded: %d, Already mirrored: %d, Failed: %d\n", len(report.Added), report.Existing, len(report.Failed))
+		}
+
+		if len(report.Failed) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mirrorCmd)
+	mirrorCmd.Flags().StringVarP(&mirrorFile, "file", "f", "", "File containing extension IDs (one per line)")
+	mirrorCmd.Flags().StringVar(&mirrorDest, "dest", "", "Repository directory to mirror into")
+	mirrorCmd.Flags().StringSliceVar(&mirrorSources, "source", []string{"marketplace"}, "Registries to mirror from, in order of preference (marketplace, openvsx)")
+	mirrorCmd.Flags().StringSliceVar(&mirrorPlatforms, "target-platform", []string{download.UniversalPlatform},
+		"Target platforms to mirror: "+strings.Join(download.TargetPlatforms, ", "))
+	mirrorCmd.Flags().IntVar(&mirrorVersions, "versions", 1, "Number of latest versions to mirror per extension")
+	mirrorCmd.Flags().BoolVar(&mirrorAllVersions, "all-versions", false, "Mirror every published version")
+	mirrorCmd.Flags().BoolVar(&mirrorPreRelease, "pre-release", false, "Include pre-release versions")
+	mirrorCmd.Flags().BoolVar(&mirrorSuspicious, "allow-suspicious", false, "Also mirror extensions classified Suspicious")
+	mirrorCmd.Flags().BoolVar(&mirrorUnknown, "allow-unknown", false, "Also mirror extensions whose trust level is Unknown")
+	mirrorCmd.MarkFlagRequired("dest")
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/policy"
+)
+
+var (
+	policyURL           string
+	policyGit           string
+	policyGitFile       string
+	policyGitRef        string
+	policyPublicKey     string
+	policyInterval      string
+	policyAllowUnsigned bool
+	policyAllowHTTP     bool
+	policySignKey       string
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Configure and inspect the organization extension policy",
+	Long: `An organization policy lists blocked, mandatory and pinned extensions. vsynx fetches it
+from a URL or a git repository, verifies its ed25519 signature and caches it in the
+vsynx config directory. Apply it with 'vsynx enforce' or 'vsynx watch --enforce'.
+
+Policy format (policy.json):
+  {
+    "version": 1,
+    "serial": 42,
+    "name": "acme",
+    "editors": ["vscode", "cursor"],
+    "blocked":   [{"id": "evil.*", "reason": "known malware publisher"}],
+    "mandatory": [{"id": "acme.linter"}, {"id": "acme.fmt", "version": "2.0.0"}],
+    "pinned":    [{"id": "ms-python.python", "version": "2024.2.1"}]
+  }
+
+The signature is the base64 ed25519 signature of the file, published next to it
+as <file>.sig. Create keys with 'vsynx policy keygen' and sign with 'vsynx policy sign'.
+Raise the serial with every revision: a policy with a lower serial than the cached
+one is refused, so an older signed policy cannot be replayed.`,
+}
+
+var policySetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set where the policy is fetched from",
+	Run: func(cmd *cobra.Command, args []string) {
+		source := &models.PolicySource{
+			URL:           policyURL,
+			Git:           policyGit,
+			GitFile:       policyGitFile,
+			GitRef:        policyGitRef,
+			PublicKey:     readKeyArg(policyPublicKey),
+			Interval:      policyInterval,
+			AllowUnsigned: policyAllowUnsigned,
+			AllowHTTP:     policyAllowHTTP,
+		}
+		if source.Git == "" {
+			source.GitFile = ""
+		}
+		if err := policy.SaveSource(source); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		status, err := policy.Fetch(source)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Policy source saved, but the first fetch failed: %v\n", err)
+			os.Exit(1)
+		}
+		printPolicyStatus(status)
+	},
+}
+
+var policyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the current policy, refreshing it when the cache is stale",
+	Run: func(cmd *cobra.Command, args []string) {
+		printPolicyStatus(currentPolicy(false))
+	},
+}
+
+var policyFetchCmd = &cobra.Command{
+	Use:   "fetch",
+	Short: "Fetch and verify the policy now",
+	Run: func(cmd *cobra.Command, args []string) {
+		printPolicyStatus(currentPolicy(true))
+	},
+}
+
+var policyKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate an ed25519 key pair for signing policies",
+	Run: func(cmd *cobra.Command, args []string) {
+		publicKey, privateKey, err := policy.GenerateKey()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(map[string]string{"publicKey": publicKey, "privateKey": privateKey}, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		fmt.Printf("Public key:  %s\n", publicKey)
+		fmt.Printf("Private key: %s\n", privateKey)
+		fmt.Println("\nKeep the private key secret; distribute the public key with 'vsynx policy set --public-key'.")
+	},
+}
+
+var policySignCmd = &cobra.Command{
+	Use:   "sign <policy.json>",
+	Short: "Validate and sign a policy file, writing <policy.json>.sig",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		privateKey := readKeyArg(policySignKey)
+		if privateKey == "" {
+			privateKey = os.Getenv("VSYNX_POLICY_KEY")
+		}
+		if privateKey == "" {
+			fmt.Fprintln(os.Stderr, "Error: --key or $VSYNX_POLICY_KEY is required")
+			os.Exit(1)
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		signature, err := policy.Sign(data, privateKey)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(args[0]+".sig", []byte(signature+"\n"), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing signature: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s✓%s Signed %s\n", colorGreen, colorReset, args[0]+".sig")
+	},
+}
+
+var policyLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the enforcement audit log",
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := policy.AuditLogPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		entries, err := policy.ReadAuditLog(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(entries, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Printf("\n=== Enforcement Log ===\n\n")
+		if len(entries) == 0 {
+			fmt.Println("No enforcement actions recorded.")
+			return
+		}
+		for _, entry := range entries {
+			mark := colorGreen + "✓" + colorReset
+			if !entry.Success {
+				mark = colorRed + "✗" + colorReset
+			}
+			fmt.Printf("%s %s %s\n", entry.Time.Format("2006-01-02 15:04:05"), mark, describeEnforcementStep(entry.Step))
+			if entry.Error != "" {
+				fmt.Printf("    %s\n", entry.Error)
+			}
+		}
+		fmt.Printf("\nLog: %s\n", path)
+	},
+}
+
+// currentPolicy loads the configured policy source and returns the current policy
+func currentPolicy(refresh bool) *models.PolicyStatus {
+	source, err := policy.LoadSource()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	status, err := policy.Current(source, refresh)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if status.Stale {
+		fmt.Fprintf(os.Stderr, "%sWarning: using the cached policy; refresh failed: %s%s\n", colorYellow, status.Error, colorReset)
+	}
+	return status
+}
+
+// readKeyArg returns a key given inline or, when the value names a file, the file's contents
+func readKeyArg(value string) string {
+	if value == "" {
+		return ""
+	}
+	if data, err := os.ReadFile(value); err == nil {
+		return strings.TrimSpace(string(data))
+	}
+	return strings.TrimSpace(value)
+}
+
+// printPolicyStatus prints the policy source and the cached policy
+func printPolicyStatus(status *models.PolicyStatus) {
+	if outputFormat == "json" {
+		data, _ := json.MarshalIndent(status, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Printf("\n=== Organization Policy ===\n\n")
+	if status.Source.URL != "" {
+		fmt.Printf("Source: %s\n", status.Source.URL)
+	} else {
+		fmt.Printf("Source: %s (%s)\n", status.Source.Git, status.Source.GitFile)
+	}
+	if status.Signed {
+		fmt.Printf("Signature: %s✓ verified%s\n", colorGreen, colorReset)
+	} else {
+		fmt.Printf("Signature: %snot signed%s\n", colorYellow, colorReset)
+	}
+	fmt.Printf("SHA256: %s\n", status.SHA256)
+	fmt.Printf("Fetched: %s\n", status.FetchedAt.Format("2006-01-02 15:04:05"))
+
+	p := status.Policy
+	if p.Name != "" {
+		fmt.Printf("Name: %s\n", p.Name)
+	}
+	if len(p.Editors) > 0 {
+		editors := make([]string, len(p.Editors))
+		for i, editorID := range p.Editors {
+			editors[i] = string(editorID)
+		}
+		fmt.Printf("Editors: %s\n", strings.Join(editors, ", "))
+	}
+
+	fmt.Printf("\nBlocked (%d):\n", len(p.Blocked))
+	for _, rule := range p.Blocked {
+		if rule.Reason != "" {
+			fmt.Printf("  %s - %s\n", rule.ID, rule.Reason)
+		} else {
+			fmt.Printf("  %s\n", rule.ID)
+		}
+	}
+	fmt.Printf("\nMandatory (%d):\n", len(p.Mandatory))
+	for _, ext := range p.Mandatory {
+		fmt.Printf("  %s\n", policyExtensionLabel(ext))
+	}
+	fmt.Printf("\nPinned (%d):\n", len(p.Pinned))
+	for _, ext := range p.Pinned {
+		fmt.Printf("  %s\n", policyExtensionLabel(ext))
+	}
+}
+
+// policyExtensionLabel formats a mandatory or pinned extension
+func policyExtensionLabel(ext models.PolicyExtension) string {
+	if ext.Version == "" {
+		return ext.ID + " (latest)"
+	}
+	return ext.ID + "@" + ext.Version
+}
+
+func init() {
+	rootCmd.AddCommand(policyCmd)
+	policyCmd.AddCommand(policySetCmd)
+	policyCmd.AddCommand(policyShowCmd)
+	policyCmd.AddCommand(policyFetchCmd)
+	policyCmd.AddCommand(policyKeygenCmd)
+	policyCmd.AddCommand(policySignCmd)
+	policyCmd.AddCommand(policyLogCmd)
+
+	policySetCmd.Flags().StringVar(&policyURL, "url", "", "HTTP(S) URL of the policy file")
+	policySetCmd.Flags().StringVar(&policyGit, "git", "", "Git repository URL or path holding the policy")
+	policySetCmd.Flags().StringVar(&policyGitFile, "git-file", "policy.json", "Policy file path inside the git repository")
+	policySetCmd.Flags().StringVar(&policyGitRef, "git-ref", "", "Branch or tag to fetch (default: the remote HEAD)")
+	policySetCmd.Flags().StringVar(&policyPublicKey, "public-key", "", "Base64 ed25519 public key, or a file containing it")
+	policySetCmd.Flags().StringVar(&policyInterval, "interval", "1h", "How often to refresh the cached policy")
+	policySetCmd.Flags().BoolVar(&policyAllowUnsigned, "allow-unsigned", false, "Accept an unsigned policy (testing only)")
+	policySetCmd.Flags().BoolVar(&policyAllowHTTP, "allow-http", false, "Accept an unencrypted http:// or git:// location (testing only)")
+
+	policySignCmd.Flags().StringVar(&policySignKey, "key", "", "Base64 private key, or a file containing it (default: $VSYNX_POLICY_KEY)")
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/validation"
+	"github.com/yourusername/secureopenvsx/internal/workspace"
+)
+
+var profileName string
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Import and export VS Code .code-profile files",
+}
+
+var profileImportCmd = &cobra.Command{
+	Use:   "import <file.code-profile>",
+	Short: "Check an editor against a .code-profile export",
+	Long: `Reads the extensions of a .code-profile export, validates each of them and reports
+which are missing from the editor. With --install, missing extensions are validated and
+installed natively at the profile's version when it records one. Only Legitimate ones are
+installed; Unknown ones need --allow-unknown, and suspicious or malicious ones are skipped.
+
+Exit codes:
+  0 - Editor has every extension of the profile
+  1 - Error or failed install
+  3 - Missing or untrusted extensions found`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		codeProfile, err := workspace.LoadCodeProfile(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		profile, installed := loadWorkspaceEditor()
+		report := workspace.CheckProfile(codeProfile, installed, workspaceValidator())
+		report.Source = args[0]
+		report.Editor = profile.ID
+
+		finishWorkspaceCheck(profile, report)
+	},
+}
+
+var profileExportCmd = &cobra.Command{
+	Use:   "export <file.code-profile>",
+	Short: "Export an editor's extensions as a .code-profile",
+	Long:  `Writes the installed extensions of an editor to a .code-profile file that VS Code can import.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveEditorProfile(workspaceEditor)
+		extensions, err := validation.NewScanner().ScanEditorExtensions(profile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Error scanning extensions: %v\n", err)
+			os.Exit(1)
+		}
+
+		name := profileName
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(args[0]), ".code-profile")
+		}
+		codeProfile := workspace.ProfileFromInstalled(name, extensions)
+		if err := workspace.SaveCodeProfile(args[0], codeProfile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("%s✓%s Exported %d extensions from %s to %s\n", colorGreen, colorReset,
+			len(codeProfile.Extensions), profile.Name, args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileImportCmd)
+	profileCmd.AddCommand(profileExportCmd)
+
+	for _, cmd := range []*cobra.Command{profileImportCmd, profileExportCmd} {
+		cmd.Flags().StringVarP(&workspaceEditor, "editor", "e", "vscode", "Editor to check or export")
+	}
+	profileImportCmd.Flags().BoolVar(&workspaceInstall, "install", false, "Install missing extensions natively")
+	profileImportCmd.Flags().BoolVar(&workspaceSkipTrust, "skip-trust", false, "Skip marketplace trust validation in the report (installs are always validated)")
+	profileImportCmd.Flags().BoolVar(&workspaceAllowUnknown, "allow-unknown", false, "Also install extensions whose trust level is Unknown")
+	profileExportCmd.Flags().StringVar(&profileName, "name", "", "Profile name (default: the file name)")
+}
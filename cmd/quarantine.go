@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+var quarantineEditor string
+
+var quarantineCmd = &cobra.Command{
+	Use:   "quarantine",
+	Short: "Manage quarantined extensions",
+	Long: `Quarantined extensions are moved out of the editor's extensions directory into the
+vsynx config directory, together with their extensions.json entries, so they can be
+restored exactly as they were.`,
+}
+
+var quarantineAddCmd = &cobra.Command{
+	Use:   "add <extension-id>",
+	Short: "Quarantine an installed extension",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveEditorProfile(quarantineEditor)
+		record, err := editor.QuarantineExtension(profile, args[0], "quarantined manually", "")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		printQuarantineResult(record, "Quarantined")
+	},
+}
+
+var quarantineListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List quarantined extensions",
+	Run: func(cmd *cobra.Command, args []string) {
+		records, err := editor.ListQuarantined()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(records, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Printf("\n=== Quarantined Extensions ===\n\n")
+		if len(records) == 0 {
+			fmt.Println("No extensions are quarantined.")
+			return
+		}
+		for _, record := range records {
+			fmt.Printf("%s\n", record.ID)
+			fmt.Printf("  Extension: %s (%s)\n", record.ExtensionID, record.Editor)
+			if record.TrustLevel != "" {
+				fmt.Printf("  Trust: %s%s%s\n", getTrustColor(record.TrustLevel), record.TrustLevel, colorReset)
+			}
+			if record.Reason != "" {
+				fmt.Printf("  Reason: %s\n", record.Reason)
+			}
+			fmt.Printf("  Quarantined: %s\n\n", record.QuarantinedAt.Format("2006-01-02 15:04:05"))
+		}
+	},
+}
+
+var quarantineRestoreCmd = &cobra.Command{
+	Use:   "restore <quarantine-id>",
+	Short: "Move a quarantined extension back into its editor",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		record, err := editor.RestoreQuarantined(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		printQuarantineResult(record, "Restored")
+	},
+}
+
+var quarantineDeleteCmd = &cobra.Command{
+	Use:   "delete <quarantine-id>",
+	Short: "Permanently delete a quarantined extension",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		record, err := editor.DeleteQuarantined(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		printQuarantineResult(record, "Deleted")
+	},
+}
+
+// printQuarantineResult prints the outcome of a quarantine operation
+func printQuarantineResult(record *models.QuarantineRecord, verb string) {
+	if outputFormat == "json" {
+		data, _ := json.MarshalIndent(record, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Printf("%s✓%s %s %s (%s)\n", colorGreen, colorReset, verb, record.ExtensionID, record.Editor)
+	fmt.Printf("  Quarantine ID: %s\n", record.ID)
+}
+
+func init() {
+	rootCmd.AddCommand(quarantineCmd)
+	quarantineCmd.AddCommand(quarantineAddCmd)
+	quarantineCmd.AddCommand(quarantineListCmd)
+	quarantineCmd.AddCommand(quarantineRestoreCmd)
+	quarantineCmd.AddCommand(quarantineDeleteCmd)
+
+	quarantineAddCmd.Flags().StringVarP(&quarantineEditor, "editor", "e", "vscode", "Editor the extension is installed in")
+}
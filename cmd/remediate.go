@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/remediation"
+	"github.com/yourusername/secureopenvsx/internal/validation"
+)
+
+var (
+	remediateEditor string
+	remediateUndo   string
+)
+
+var remediateCmd = &cobra.Command{
+	Use:   "remediate <extension-id>",
+	Short: "Replace an installed extension with the official marketplace build",
+	Long: `Moves the installed extension into the quarantine, downloads the official build
+from the Microsoft Marketplace, verifies it and installs it natively into the same
+editor, updating extensions.json.
+
+The official build must carry a Marketplace signature that verifies against a
+trusted root (see --signature-roots) and be the version the Marketplace lists;
+otherwise nothing is changed.
+
+The operation is reversible: 'vsynx remediate --undo <quarantine-id>' removes the
+official build and restores the quarantined extension.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if remediateUndo != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if remediateUndo != "" {
+			record, err := remediation.Undo(remediateUndo)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			printQuarantineResult(record, "Restored")
+			return
+		}
+
+		extensionID := strings.TrimSpace(args[0])
+		profile := resolveEditorProfile(remediateEditor)
+		validator := validation.NewValidator()
+		configureSignatures(validator)
+
+		result, err := remediation.Remediate(profile, extensionID, "", validator)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Printf("%s✓%s Replaced %s in %s with the official build\n", colorGreen, colorReset, result.ExtensionID, profile.Name)
+		if result.TrustLevel != "" {
+			fmt.Printf("  Trust: %s%s%s\n", getTrustColor(result.TrustLevel), result.TrustLevel, colorReset)
+		}
+		fmt.Printf("  Previous: %s\n", strings.Join(result.PreviousVersions, ", "))
+		fmt.Printf("  Official: %s\n", result.OfficialVersion)
+		fmt.Printf("  SHA256: %s\n", result.SHA256)
+		fmt.Printf("  Quarantine ID: %s\n", result.QuarantineID)
+		fmt.Printf("\nUndo with: vsynx remediate --undo %s\n", result.QuarantineID)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(remediateCmd)
+
+	remediateCmd.Flags().StringVarP(&remediateEditor, "editor", "e", "vscode", "Editor the extension is installed in")
+	remediateCmd.Flags().StringVar(&remediateUndo, "undo", "", "Reverse a remediation by quarantine ID")
+	addSignatureRootsFlag(remediateCmd)
+}
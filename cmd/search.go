@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/marketplace"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/openvsx"
+	"github.com/yourusername/secureopenvsx/internal/search"
+)
+
+var (
+	dualSearchLimit    int
+	dualSearchPage     int
+	dualSearchSort     string
+	dualSearchCategory string
+	dualSearchVerified bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the Marketplace and OpenVSX together",
+	Long: `Searches the Microsoft Marketplace and the OpenVSX registry concurrently and merges
+the results by extension ID. Each row shows the latest version in each registry and
+whether the extension is published in one or both, with matching versions. An
+extension that appears on only one registry's page is looked up by ID on the other
+before it is labelled. Tags are not supported because OpenVSX cannot filter by them;
+use 'vsynx marketplace search --tag' instead.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := models.SearchOptions{
+			Query:        args[0],
+			SortBy:       dualSearchSort,
+			Page:         dualSearchPage,
+			PageSize:     dualSearchLimit,
+			Category:     dualSearchCategory,
+			VerifiedOnly: dualSearchVerified,
+		}
+
+		results, err := search.Dual(opts, marketplace.NewClient(), openvsx.NewClient())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error searching registries: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(results, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		totalNote := ""
+		if results.TotalsUnfiltered {
+			totalNote = " before the verified filter"
+		}
+		fmt.Printf("\n=== Registry Search: \"%s\" (Marketplace %d, OpenVSX %d results%s) ===\n\n",
+			opts.Query, results.MarketplaceTotal, results.OpenVSXTotal, totalNote)
+
+		if results.MarketplaceError != "" {
+			fmt.Printf("%s✗ Marketplace unavailable: %s%s\n\n", colorYellow, results.MarketplaceError, colorReset)
+		}
+		if results.OpenVSXError != "" {
+			fmt.Printf("%s✗ OpenVSX unavailable: %s%s\n\n", colorYellow, results.OpenVSXError, colorReset)
+		}
+
+		if len(results.Rows) == 0 {
+			fmt.Println("No extensions found.")
+			return
+		}
+
+		fmt.Printf("%-45s %-14s %-14s %s\n", "Extension ID", "Marketplace", "OpenVSX", "Status")
+		fmt.Println(strings.Repeat("-", 95))
+
+		for _, row := range results.Rows {
+			mpVersion, ovsxVersion := "-", "-"
+			if row.Marketplace != nil {
+				mpVersion = row.Marketplace.Version
+			}
+			if row.OpenVSX != nil {
+				ovsxVersion = row.OpenVSX.Version
+			}
+			fmt.Printf("%-45s %-14s %-14s %s\n", row.ExtensionID, mpVersion, ovsxVersion, presenceStatus(row))
+		}
+		fmt.Println()
+	},
+}
+
+// presenceStatus describes in which registries a search row was found
+func presenceStatus(row models.DualSearchRow) string {
+	switch row.Presence {
+	case models.PresenceBoth:
+		if row.VersionsMatch {
+			return colorGreen + "✓ both, versions match" + colorReset
+		}
+		return colorYellow + "✓ both, versions differ" + colorReset
+	case models.PresenceMarketplaceOnly:
+		return colorYellow + "Marketplace only" + colorReset
+	default:
+		return colorYellow + "OpenVSX only" + colorReset
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().IntVarP(&dualSearchLimit, "limit", "l", models.DefaultSearchPageSize, "Results per page from each registry")
+	searchCmd.Flags().IntVar(&dualSearchPage, "page", 1, "Page of results to show")
+	searchCmd.Flags().StringVar(&dualSearchSort, "sort", models.SortRelevance, "Sort by installs, rating, updated or relevance")
+	searchCmd.Flags().StringVar(&dualSearchCategory, "category", "", "Only extensions in this category")
+	searchCmd.Flags().BoolVar(&dualSearchVerified, "verified", false, "Only extensions from verified publishers")
+}
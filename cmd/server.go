@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/fleet"
+)
+
+const (
+	// fleetTokenEnvVar holds the bearer token shared by 'audit --report-to' and 'server' for submitting reports
+	fleetTokenEnvVar = "VSYNX_REPORT_TOKEN"
+	// fleetReadTokenEnvVar holds the bearer token required to read reports from 'server'
+	fleetReadTokenEnvVar = "VSYNX_READ_TOKEN"
+	// defaultServerPort is the collector port used when --listen is not given
+	defaultServerPort = "8080"
+)
+
+var (
+	serverListen    string
+	serverDataDir   string
+	serverToken     string
+	serverReadToken string
+	serverEnrolled  string
+)
+
+var serverCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Run a fleet collector for audit reports",
+	Long: `Starts an HTTP collector that receives signed audit reports from
+'vsynx audit --report-to <url>' and keeps the latest report of each machine.
+
+Endpoints:
+  POST /api/v1/reports          submit a signed audit report (write token)
+  GET  /api/v1/summary          which machines have which flagged extension (read token)
+  GET  /api/v1/machines         latest report summary per machine (read token)
+  GET  /api/v1/machines/{id}    latest full report of one machine (read token)
+
+Submitting and reading use separate bearer tokens: --token (or $VSYNX_REPORT_TOKEN),
+shared with the machines, and --read-token (or $VSYNX_READ_TOKEN) for administrators.
+Without both tokens the collector only listens on localhost.
+
+Reports are verified against the machine's public key, but a key proves only that
+reports come from the same machine, not which machine it is. With --enrolled, only
+machines whose public key (printed by 'vsynx audit --machine-key') is listed in the
+file may submit.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dataDir := serverDataDir
+		if dataDir == "" {
+			path, err := config.Path("fleet")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			dataDir = path
+		}
+		token := serverToken
+		if token == "" {
+			token = os.Getenv(fleetTokenEnvVar)
+		}
+		readToken := serverReadToken
+		if readToken == "" {
+			readToken = os.Getenv(fleetReadTokenEnvVar)
+		}
+
+		listen := serverListen
+		authenticated := token != "" && readToken != ""
+		if listen == "" {
+			listen = net.JoinHostPort("127.0.0.1", defaultServerPort)
+			if authenticated {
+				listen = ":" + defaultServerPort
+			}
+		}
+		if !authenticated && !isLoopbackAddress(listen) {
+			fmt.Fprintf(os.Stderr, "Error: listening on %s requires both --token and --read-token\n", listen)
+			os.Exit(1)
+		}
+
+		server, err := fleet.NewServer(dataDir, token, readToken)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if serverEnrolled != "" {
+			enrolled, err := fleet.LoadEnrolledMachines(serverEnrolled)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			server.Enrolled = enrolled
+		}
+
+		fmt.Printf("Fleet collector listening on %s\n", listen)
+		fmt.Printf("Reports stored in %s\n", dataDir)
+		if !authenticated {
+			fmt.Printf("%sWarning: no read or write token set; only local clients can reach the collector%s\n", colorYellow, colorReset)
+		}
+		if server.Enrolled == nil {
+			fmt.Printf("%sWarning: no --enrolled file; reports from any machine key are accepted%s\n", colorYellow, colorReset)
+		} else {
+			fmt.Printf("Enrolled machines: %d\n", len(server.Enrolled))
+		}
+		// Timeouts keep slow or stalled clients from holding connections open
+		httpServer := &http.Server{
+			Addr:              listen,
+			Handler:           server.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       time.Minute,
+			WriteTimeout:      time.Minute,
+			IdleTimeout:       2 * time.Minute,
+		}
+		log.Fatal(httpServer.ListenAndServe())
+	},
+}
+
+// isLoopbackAddress reports whether a listen address only accepts local connections
+func isLoopbackAddress(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func init() {
+	rootCmd.AddCommand(serverCmd)
+
+	serverCmd.Flags().StringVar(&serverListen, "listen", "", "Address to listen on (default: 127.0.0.1:"+defaultServerPort+", or :"+defaultServerPort+" when both tokens are set)")
+	serverCmd.Flags().StringVar(&serverDataDir, "data", "", "Directory for stored reports (default: fleet/ in the vsynx config directory)")
+	serverCmd.Flags().StringVar(&serverToken, "token", "", "Bearer token required to submit reports (default: $"+fleetTokenEnvVar+")")
+	serverCmd.Flags().StringVar(&serverReadToken, "read-token", "", "Bearer token required to read reports (default: $"+fleetReadTokenEnvVar+")")
+	serverCmd.Flags().StringVar(&serverEnrolled, "enrolled", "", "File of machine public keys allowed to submit reports")
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/registry"
+)
+
+var (
+	registryDir             string
+	registryListen          string
+	registryBaseURL         string
+	registryAllowSuspicious bool
+)
+
+var serveRegistryCmd = &cobra.Command{
+	Use:   "serve-registry",
+	Short: "Serve a mirrored repository as an OpenVSX-compatible registry",
+	Long: `Serves a repository created by 'vsynx mirror' over HTTP using the subset of the
+OpenVSX REST API that vsynx's own OpenVSX client consumes:
+
+  GET /api/-/search?query=&offset=&size=
+  GET /api/{namespace}/{name}
+  GET /api/{namespace}/{name}/versions
+  GET /api/{namespace}/{name}/{version}
+  GET /api/{namespace}/{name}/{platform}/{version}
+  GET /api/{namespace}/{name}/[{platform}/]{version}/file/{file}
+
+Only packages that were classified Legitimate when mirrored and still match their
+index checksum are served. Changes to the repository index are picked up automatically.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		server, err := registry.NewServer(registryDir, registryAllowSuspicious)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		server.BaseURL = registryBaseURL
+
+		fmt.Printf("Registry listening on %s\n", registryListen)
+		fmt.Printf("Serving %d packages from %s\n", server.Served(), registryDir)
+		for _, skipped := range server.Skipped() {
+			fmt.Printf("%sNot served%s %s %s (%s) - %s\n", colorYellow, colorReset,
+				skipped.ExtensionID, skipped.Version, skipped.TargetPlatform, skipped.Error)
+		}
+		log.Fatal(http.ListenAndServe(registryListen, server.Handler()))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveRegistryCmd)
+
+	serveRegistryCmd.Flags().StringVar(&registryDir, "dir", "", "Mirror repository to serve")
+	serveRegistryCmd.Flags().StringVar(&registryListen, "listen", ":8081", "Address to listen on")
+	serveRegistryCmd.Flags().StringVar(&registryBaseURL, "base-url", "", "External URL of the registry used in links (default: from the request)")
+	serveRegistryCmd.Flags().BoolVar(&registryAllowSuspicious, "allow-suspicious", false, "Also serve packages classified Suspicious")
+	serveRegistryCmd.MarkFlagRequired("dir")
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/marketplace"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/openvsx"
+	"github.com/yourusername/secureopenvsx/internal/search"
+)
+
+// changelogPreviewLines is how much of the changelog show prints without --changelog
+const changelogPreviewLines = 30
+
+var (
+	showVersions  int
+	showReadme    bool
+	showChangelog bool
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <extension-id>",
+	Short: "Show extension details from both registries",
+	Long: `Shows an extension as published on the Microsoft Marketplace and OpenVSX: its
+versions with their release dates in each registry, pre-release flags, engine
+compatibility and target platforms, the license and repository, and the changelog
+and README packaged in the latest VSIX. OpenVSX dates, engines and pre-release
+flags are only looked up for its 20 newest versions.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		extensionID := args[0]
+
+		details, err := search.Details(extensionID, showVersions, marketplace.NewClient(), openvsx.NewClient())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(details, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		printExtensionDetails(details)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	showCmd.Flags().IntVar(&showVersions, "versions", 20, "Number of most recent versions to list (0 for all)")
+	showCmd.Flags().BoolVar(&showReadme, "readme", false, "Print the README")
+	showCmd.Flags().BoolVar(&showChangelog, "changelog", false, "Print the full changelog")
+}
+
+// detailsVersionRow is one version in the merged version table
+type detailsVersionRow struct {
+	version     string
+	marketplace *models.ExtensionVersion
+	openvsx     *models.ExtensionVersion
+}
+
+func printExtensionDetails(details *models.RegistryDetails) {
+	// Prefer the Marketplace listing for the overview, as the validator does
+	primary := details.Marketplace
+	if primary == nil {
+		primary = details.OpenVSX
+	}
+	meta := primary.Metadata
+
+	fmt.Printf("\n=== %s ===\n\n", details.ExtensionID)
+	if meta.DisplayName != "" {
+		fmt.Printf("Name:        %s\n", meta.DisplayName)
+	}
+	publisher := meta.Publisher
+	if meta.IsVerifiedPublisher {
+		publisher += " " + colorGreen + "✓ verified" + colorReset
+	}
+	fmt.Printf("Publisher:   %s\n", publisher)
+	if meta.Description != "" {
+		fmt.Printf("Description: %s\n", meta.Description)
+	}
+	repository := detailsText(details, func(d *models.ExtensionDetails) string { return d.Metadata.RepositoryURL })
+	if repository == "" {
+		repository = "unknown"
+	}
+	fmt.Printf("Repository:  %s\n", repository)
+	fmt.Printf("License:     %s\n", detailsLicense(details))
+	if meta.InstallCount > 0 {
+		fmt.Printf("Installs:    %s\n", formatCount(meta.InstallCount))
+	}
+	fmt.Println()
+
+	for _, registry := range []struct {
+		name    string
+		details *models.ExtensionDetails
+		err     string
+	}{
+		{"Microsoft Marketplace", details.Marketplace, details.MarketplaceError},
+		{"OpenVSX Registry", details.OpenVSX, details.OpenVSXError},
+	} {
+		switch {
+		case registry.details != nil:
+			fmt.Printf("%s: %s (%d versions)\n", registry.name, registry.details.Metadata.Version, registry.details.TotalVersions)
+		case registry.err != "":
+			fmt.Printf("%s: %s✗ %s%s\n", registry.name, colorYellow, registry.err, colorReset)
+		}
+	}
+	fmt.Println()
+
+	rows := mergeDetailVersions(details)
+	fmt.Printf("%-20s %-12s %-12s %-12s %-12s %s\n", "Version", "Marketplace", "OpenVSX", "Pre-release", "Engine", "Platforms")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, row := range rows {
+		var info models.ExtensionVersion
+		if row.marketplace != nil {
+			info = *row.marketplace
+		} else {
+			info = *row.openvsx
+		}
+		preRelease := ""
+		if info.PreRelease {
+			preRelease = "yes"
+		}
+		platforms := "universal"
+		if len(info.TargetPlatforms) > 0 {
+			platforms = strings.Join(info.TargetPlatforms, ", ")
+		}
+		fmt.Printf("%-20s %-12s %-12s %-12s %-12s %s\n",
+			row.version, versionDate(row.marketplace), versionDate(row.openvsx), preRelease, info.EngineVSCode, platforms)
+	}
+	fmt.Println()
+
+	if changelog := detailsText(details, func(d *models.ExtensionDetails) string { return d.Changelog }); changelog != "" {
+		fmt.Println("Changelog:")
+		lines := strings.Split(strings.TrimSpace(changelog), "\n")
+		if !showChangelog && len(lines) > changelogPreviewLines {
+			lines = append(lines[:changelogPreviewLines], "...", "(use --changelog for the full changelog)")
+		}
+		for _, line := range lines {
+			fmt.Printf("  %s\n", line)
+		}
+		fmt.Println()
+	}
+
+	if showReadme {
+		if readme := detailsText(details, func(d *models.ExtensionDetails) string { return d.Readme }); readme != "" {
+			fmt.Println("README:")
+			fmt.Println(strings.TrimSpace(readme))
+			fmt.Println()
+		} else {
+			fmt.Println("No README published.")
+		}
+	}
+}
+
+// mergeDetailVersions lists the versions of both registries, newest first
+func mergeDetailVersions(details *models.RegistryDetails) []detailsVersionRow {
+	index := map[string]int{}
+	var rows []detailsVersionRow
+	add := func(d *models.ExtensionDetails, marketplace bool) {
+		if d == nil {
+			return
+		}
+		for i := range d.Versions {
+			v := &d.Versions[i]
+			j, seen := index[v.Version]
+			if !seen {
+				j = len(rows)
+				index[v.Version] = j
+				rows = append(rows, detailsVersionRow{version: v.Version})
+			}
+			if marketplace {
+				rows[j].marketplace = v
+			} else {
+				rows[j].openvsx = v
+			}
+		}
+	}
+	add(details.Marketplace, true)
+	add(details.OpenVSX, false)
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return editor.CompareVersions(rows[i].version, rows[j].version) > 0
+	})
+	if showVersions > 0 && len(rows) > showVersions {
+		rows = rows[:showVersions]
+	}
+	return rows
+}
+
+// versionDate formats the release date of a version, or "-" if the registry lacks it
+func versionDate(v *models.ExtensionVersion) string {
+	if v == nil {
+		return "-"
+	}
+	if v.LastUpdated.IsZero() {
+		return "?"
+	}
+	return v.LastUpdated.Format(time.DateOnly)
+}
+
+// detailsText returns a text asset from the Marketplace listing, or from OpenVSX if the
+// Marketplace has none
+func detailsText(details *models.RegistryDetails, text func(*models.ExtensionDetails) string) string {
+	for _, d := range []*models.ExtensionDetails{details.Marketplace, details.OpenVSX} {
+		if d != nil && text(d) != "" {
+			return text(d)
+		}
+	}
+	return ""
+}
+
+// detailsLicense describes the license of an extension
+func detailsLicense(details *models.RegistryDetails) string {
+	license := detailsText(details, func(d *models.ExtensionDetails) string { return d.License })
+	licenseURL := detailsText(details, func(d *models.ExtensionDetails) string { return d.LicenseURL })
+	switch {
+	case license != "" && licenseURL != "":
+		return license + " (" + licenseURL + ")"
+	case license != "":
+		return license
+	case licenseURL != "":
+		return licenseURL
+	default:
+		return "unknown"
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -17,6 +18,12 @@ var (
 	syncExts      string
 	syncAll       bool
 	syncOverwrite bool
+
+	syncSettings    bool
+	syncKeybindings bool
+	syncSnippets    bool
+	syncConfig      bool
+	syncYes         bool
 )
 
 var syncCmd = &cobra.Command{
@@ -24,7 +31,11 @@ var syncCmd = &cobra.Command{
 	Short: "Sync extensions between editors",
 	Long: `Commands for syncing VS Code extensions between different editors.
 Supports copying extensions from a source editor (e.g., vscode) to target editors
-(e.g., windsurf, cursor, kiro).`,
+(e.g., windsurf, cursor, kiro).
+
+With --settings, --keybindings and --snippets (or --config for all three), the user
+configuration is merged too. Comments in the target files are kept, settings that only
+apply to another editor are left out, and a diff is shown before anything is written.`,
 }
 
 var syncRunCmd = &cobra.Command{
@@ -41,8 +52,12 @@ Use --overwrite to replace existing extensions in target editors.`,
 			fmt.Fprintln(os.Stderr, "Error: --to is required")
 			os.Exit(1)
 		}
-		if syncExts == "" && !syncAll {
-			fmt.Fprintln(os.Stderr, "Error: --ext or --all is required")
+		if syncExts == "" && !syncAll && !syncConfigOptions().Any() {
+			fmt.Fprintln(os.Stderr, "Error: --ext, --all or a configuration flag (--settings, --keybindings, --snippets, --config) is required")
+			os.Exit(1)
+		}
+		if syncConfigOptions().Any() && outputFormat == "json" && !syncYes {
+			fmt.Fprintln(os.Stderr, "Error: pass --yes to write configuration changes with --output json (use sync preview to see them)")
 			os.Exit(1)
 		}
 
@@ -77,17 +92,25 @@ Use --overwrite to replace existing extensions in target editors.`,
 			}
 		}
 
-		request := models.SyncRequest{
-			SourceEditor:       models.EditorType(syncFrom),
-			TargetEditors:      targetTypes,
-			ExtensionIDs:       extensionIDs,
-			OverwriteConflicts: syncOverwrite,
+		report := &models.SyncReport{SourceEditor: models.EditorType(syncFrom), Results: []models.SyncResult{}}
+		if syncExts != "" || syncAll {
+			request := models.SyncRequest{
+				SourceEditor:       models.EditorType(syncFrom),
+				TargetEditors:      targetTypes,
+				ExtensionIDs:       extensionIDs,
+				OverwriteConflicts: syncOverwrite,
+			}
+
+			var err error
+			report, err = editor.SyncExtensions(request)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error syncing extensions: %v\n", err)
+				os.Exit(1)
+			}
 		}
 
-		report, err := editor.SyncExtensions(request)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error syncing extensions: %v\n", err)
-			os.Exit(1)
+		if options := syncConfigOptions(); options.Any() {
+			report.UserConfig = runUserConfigSync(targetTypes, options)
 		}
 
 		if outputFormat == "json" {
@@ -96,6 +119,10 @@ Use --overwrite to replace existing extensions in target editors.`,
 			return
 		}
 
+		if len(report.Results) == 0 {
+			return
+		}
+
 		fmt.Printf("\n=== Sync Report ===\n")
 		fmt.Printf("Source: %s\n", report.SourceEditor)
 		fmt.Printf("Extensions: %d\n\n", len(extensionIDs))
@@ -145,8 +172,8 @@ var syncPreviewCmd = &cobra.Command{
 			fmt.Fprintln(os.Stderr, "Error: --to is required")
 			os.Exit(1)
 		}
-		if syncExts == "" && !syncAll {
-			fmt.Fprintln(os.Stderr, "Error: --ext or --all is required")
+		if syncExts == "" && !syncAll && !syncConfigOptions().Any() {
+			fmt.Fprintln(os.Stderr, "Error: --ext, --all or a configuration flag (--settings, --keybindings, --snippets, --config) is required")
 			os.Exit(1)
 		}
 
@@ -177,32 +204,44 @@ var syncPreviewCmd = &cobra.Command{
 		}
 
 		type PreviewResult struct {
-			Target    string   `json:"target"`
-			NewCount  int      `json:"newCount"`
-			Conflicts []string `json:"conflicts"`
-			Overwrite int      `json:"overwriteCount"`
+			Target     string                     `json:"target"`
+			NewCount   int                        `json:"newCount"`
+			Conflicts  []string                   `json:"conflicts"`
+			Overwrite  int                        `json:"overwriteCount"`
+			UserConfig *models.UserConfigSyncPlan `json:"userConfig,omitempty"`
 		}
 
 		var results []PreviewResult
 
 		for _, target := range targets {
 			target = strings.TrimSpace(target)
-			conflicts, err := editor.DetectConflicts(
-				models.EditorType(syncFrom),
-				models.EditorType(target),
-				extensionIDs,
-			)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error checking conflicts for %s: %v\n", target, err)
-				continue
+			result := PreviewResult{Target: target, Conflicts: []string{}}
+
+			if syncExts != "" || syncAll {
+				conflicts, err := editor.DetectConflicts(
+					models.EditorType(syncFrom),
+					models.EditorType(target),
+					extensionIDs,
+				)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error checking conflicts for %s: %v\n", target, err)
+					continue
+				}
+				result.NewCount = len(extensionIDs) - len(conflicts)
+				result.Conflicts = conflicts
+				result.Overwrite = len(conflicts)
 			}
 
-			results = append(results, PreviewResult{
-				Target:    target,
-				NewCount:  len(extensionIDs) - len(conflicts),
-				Conflicts: conflicts,
-				Overwrite: len(conflicts),
-			})
+			if options := syncConfigOptions(); options.Any() {
+				plan, err := planUserConfigSync(models.EditorType(target), options)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error planning configuration sync for %s: %v\n", target, err)
+					continue
+				}
+				result.UserConfig = plan
+			}
+
+			results = append(results, result)
 		}
 
 		if outputFormat == "json" {
@@ -213,14 +252,23 @@ var syncPreviewCmd = &cobra.Command{
 
 		fmt.Printf("\n=== Sync Preview ===\n")
 		fmt.Printf("Source: %s\n", syncFrom)
-		fmt.Printf("Extensions to sync: %d\n\n", len(extensionIDs))
+		syncingExtensions := syncExts != "" || syncAll
+		if syncingExtensions {
+			fmt.Printf("Extensions to sync: %d\n", len(extensionIDs))
+		}
+		fmt.Println()
 
 		for _, r := range results {
 			fmt.Printf("Target: %s\n", r.Target)
-			fmt.Printf("  New (to install): %d\n", r.NewCount)
-			fmt.Printf("  Conflicts (overwrite): %d\n", r.Overwrite)
-			if len(r.Conflicts) > 0 {
-				fmt.Printf("  Conflicting IDs: %s\n", strings.Join(r.Conflicts, ", "))
+			if syncingExtensions {
+				fmt.Printf("  New (to install): %d\n", r.NewCount)
+				fmt.Printf("  Conflicts (overwrite): %d\n", r.Overwrite)
+				if len(r.Conflicts) > 0 {
+					fmt.Printf("  Conflicting IDs: %s\n", strings.Join(r.Conflicts, ", "))
+				}
+			}
+			if r.UserConfig != nil {
+				printUserConfigPlan(r.UserConfig)
 			}
 			fmt.Println()
 		}
@@ -307,6 +355,115 @@ var syncConflictsCmd = &cobra.Command{
 	},
 }
 
+// syncConfigOptions returns the user configuration selected by the sync flags
+func syncConfigOptions() models.UserConfigSyncOptions {
+	return models.UserConfigSyncOptions{
+		Settings:    syncSettings || syncConfig,
+		Keybindings: syncKeybindings || syncConfig,
+		Snippets:    syncSnippets || syncConfig,
+	}
+}
+
+// planUserConfigSync plans the configuration sync from --from to one target editor
+func planUserConfigSync(target models.EditorType, options models.UserConfigSyncOptions) (*models.UserConfigSyncPlan, error) {
+	sourceProfile, err := editor.GetEditorProfile(models.EditorType(syncFrom))
+	if err != nil {
+		return nil, fmt.Errorf("invalid source editor: %w", err)
+	}
+	targetProfile, err := editor.GetEditorProfile(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target editor: %w", err)
+	}
+	return editor.PlanUserConfigSync(sourceProfile, targetProfile, options)
+}
+
+// runUserConfigSync shows the configuration diff for each target and writes it once confirmed
+func runUserConfigSync(targets []models.EditorType, options models.UserConfigSyncOptions) []models.UserConfigSyncPlan {
+	plans := make([]models.UserConfigSyncPlan, 0, len(targets))
+	failed := false
+	for _, target := range targets {
+		plan, err := planUserConfigSync(target, options)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error planning configuration sync for %s: %v\n", target, err)
+			failed = true
+			continue
+		}
+
+		if outputFormat != "json" {
+			fmt.Printf("\n=== Configuration: %s → %s ===\n", plan.SourceEditor, plan.TargetEditor)
+			printUserConfigPlan(plan)
+		}
+
+		apply := plan.Pending() > 0 && (syncYes || (outputFormat != "json" && confirm(fmt.Sprintf("Write these changes to %s?", target))))
+		if apply {
+			if err := editor.ApplyUserConfigSync(plan); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing configuration for %s: %v\n", target, err)
+				failed = true
+			} else if outputFormat != "json" {
+				fmt.Printf("%s✓%s Wrote %d file(s)\n", colorGreen, colorReset, plan.Pending())
+			}
+		} else if plan.Pending() > 0 && outputFormat != "json" {
+			fmt.Println("Skipped.")
+		}
+		plans = append(plans, *plan)
+	}
+	if failed {
+		os.Exit(1)
+	}
+	return plans
+}
+
+// printUserConfigPlan prints each planned configuration change with its diff
+func printUserConfigPlan(plan *models.UserConfigSyncPlan) {
+	if len(plan.Changes) == 0 {
+		fmt.Println("  No settings, keybindings or snippets found in the source editor")
+		return
+	}
+	for _, change := range plan.Changes {
+		fmt.Printf("  %s %s (%s)\n", change.Kind, change.TargetPath, change.Action)
+		if len(change.Added) > 0 {
+			fmt.Printf("    Added: %s\n", strings.Join(change.Added, ", "))
+		}
+		if len(change.Changed) > 0 {
+			fmt.Printf("    Changed: %s\n", strings.Join(change.Changed, ", "))
+		}
+		if len(change.Filtered) > 0 {
+			fmt.Printf("    Left out (editor-specific): %s\n", strings.Join(change.Filtered, ", "))
+		}
+		printDiff(change.Diff)
+	}
+}
+
+// printDiff prints a unified diff with added and removed lines colored
+func printDiff(diff string) {
+	if diff == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---"):
+			fmt.Printf("    %s\n", line)
+		case strings.HasPrefix(line, "+"):
+			fmt.Printf("    %s%s%s\n", colorGreen, line, colorReset)
+		case strings.HasPrefix(line, "-"):
+			fmt.Printf("    %s%s%s\n", colorRed, line, colorReset)
+		default:
+			fmt.Printf("    %s\n", line)
+		}
+	}
+}
+
+// stdinReader is shared by every prompt so input buffered by one answer is not lost
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// confirm asks a yes/no question on the terminal, defaulting to no
+func confirm(prompt string) bool {
+	fmt.Printf("%s [y/N] ", prompt)
+	answer, _ := stdinReader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 func init() {
 	rootCmd.AddCommand(syncCmd)
 	syncCmd.AddCommand(syncRunCmd)
@@ -321,5 +478,13 @@ func init() {
 		cmd.Flags().BoolVar(&syncAll, "all", false, "Sync all extensions from source")
 	}
 
+	for _, cmd := range []*cobra.Command{syncRunCmd, syncPreviewCmd} {
+		cmd.Flags().BoolVar(&syncSettings, "settings", false, "Also merge settings.json")
+		cmd.Flags().BoolVar(&syncKeybindings, "keybindings", false, "Also merge keybindings.json")
+		cmd.Flags().BoolVar(&syncSnippets, "snippets", false, "Also merge user snippets")
+		cmd.Flags().BoolVar(&syncConfig, "config", false, "Also merge settings, keybindings and snippets")
+	}
+
 	syncRunCmd.Flags().BoolVar(&syncOverwrite, "overwrite", false, "Overwrite existing extensions in target")
+	syncRunCmd.Flags().BoolVarP(&syncYes, "yes", "y", false, "Write configuration changes without asking")
 }
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+var (
+	uninstallEditor string
+)
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall <extension-id...>",
+	Short: "Uninstall extensions from an editor",
+	Long: `Removes extensions from an editor's extensions directory and extensions.json.
+All installed versions of each extension are removed. Folders that cannot be
+deleted right away are marked in the editor's .obsolete file so the editor
+removes them on its next start.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveEditorProfile(uninstallEditor)
+
+		results := make([]*models.UninstallResult, 0, len(args))
+		failed := 0
+		for _, extID := range args {
+			result, err := editor.UninstallExtension(profile, extID)
+			if err != nil {
+				failed++
+				if outputFormat != "json" {
+					fmt.Printf("%s✗%s %s - %v\n", colorRed, colorReset, extID, err)
+				}
+				continue
+			}
+			results = append(results, result)
+
+			if outputFormat != "json" {
+				fmt.Printf("%s✓%s %s - removed %s\n", colorGreen, colorReset, extID, strings.Join(result.RemovedFolders, ", "))
+				if len(result.PendingFolders) > 0 {
+					fmt.Printf("  Pending removal on next editor start: %s\n", strings.Join(result.PendingFolders, ", "))
+				}
+			}
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(results, "", "  ")
+			fmt.Println(string(data))
+		}
+
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+var disableCmd = &cobra.Command{
+	Use:   "disable <extension-id...>",
+	Short: "Disable extensions in an editor",
+	Long: `Disables extensions globally by updating the editor's global storage.
+Close the editor first: it keeps this state in memory and overwrites it on exit.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runSetEnabled(args, false)
+	},
+}
+
+var enableCmd = &cobra.Command{
+	Use:   "enable <extension-id...>",
+	Short: "Enable previously disabled extensions in an editor",
+	Long: `Re-enables extensions by removing them from the editor's disabled list.
+Close the editor first: it keeps this state in memory and overwrites it on exit.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runSetEnabled(args, true)
+	},
+}
+
+// runSetEnabled enables or disables each extension and reports the outcome
+func runSetEnabled(extensionIDs []string, enabled bool) {
+	profile := resolveEditorProfile(uninstallEditor)
+
+	action := "Disabled"
+	if enabled {
+		action = "Enabled"
+	}
+
+	failed := 0
+	for _, extID := range extensionIDs {
+		if err := editor.SetExtensionEnabled(profile, extID, enabled); err != nil {
+			failed++
+			fmt.Printf("%s✗%s %s - %v\n", colorRed, colorReset, extID, err)
+			continue
+		}
+		fmt.Printf("%s✓%s %s %s in %s\n", colorGreen, colorReset, action, extID, profile.Name)
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// resolveEditorProfile looks up an editor profile and applies the global --path override
+func resolveEditorProfile(editorID string) models.EditorProfile {
+	profile, err := editor.GetEditorProfile(models.EditorType(editorID))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unknown editor: %s\n", editorID)
+		os.Exit(1)
+	}
+	if extensionsPath != "" {
+		profile.ExtensionsDir = extensionsPath
+	}
+	return profile
+}
+
+func init() {
+	rootCmd.AddCommand(uninstallCmd)
+	rootCmd.AddCommand(disableCmd)
+	rootCmd.AddCommand(enableCmd)
+
+	for _, cmd := range []*cobra.Command{uninstallCmd, disableCmd, enableCmd} {
+		cmd.Flags().StringVar(&uninstallEditor, "editor", "vscode", "Target editor (e.g., vscode, cursor, windsurf)")
+	}
+}
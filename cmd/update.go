@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/download"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/marketplace"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/openvsx"
+	"github.com/yourusername/secureopenvsx/internal/update"
+	"github.com/yourusername/secureopenvsx/internal/validation"
+)
+
+var (
+	outdatedEditors       []string
+	updateEngine          string
+	updateEditor          string
+	updateAll             bool
+	updateNative          bool
+	updateAllowSuspicious bool
+	updateYes             bool
+	updateReview          bool
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List installed extensions with newer versions available",
+	Long: `Compares the installed version of every extension, per editor, with the newest
+version on the Microsoft Marketplace and on OpenVSX.
+
+Only versions the editor can run are considered: the package must support the
+editor's VS Code version (engines.vscode) and be built for this platform, and
+pre-releases are only offered for extensions installed from the pre-release
+channel. The VS Code version is read from the editor install; use --engine when
+it cannot be found. Exits with code 3 if any extension is outdated.
+
+Examples:
+  vsynx outdated
+  vsynx outdated --editors vscode,cursor --output json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var profiles []models.EditorProfile
+		if len(outdatedEditors) > 0 {
+			for _, editorID := range outdatedEditors {
+				profiles = append(profiles, resolveEditorProfile(editorID))
+			}
+		} else {
+			for _, profile := range editor.GetEditorProfiles() {
+				if editor.CheckEditorStatus(profile).DirExists {
+					profiles = append(profiles, profile)
+				}
+			}
+		}
+
+		checker := update.NewChecker(marketplace.NewClient(), openvsx.NewClient())
+		reports := []*models.OutdatedReport{}
+		outdated := 0
+		for _, profile := range profiles {
+			report, err := checker.Check(profile, updateCheckOptions(profile, nil))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error checking %s: %v\n", profile.Name, err)
+				os.Exit(1)
+			}
+			reports = append(reports, report)
+			outdated += report.OutdatedCount
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(reports, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			if len(reports) == 0 {
+				fmt.Println("No editors with an extensions directory were found.")
+			}
+			for _, report := range reports {
+				printOutdatedReport(report)
+			}
+		}
+
+		if outdated > 0 {
+			os.Exit(3)
+		}
+	},
+}
+
+var updateCmd = &cobra.Command{
+	Use:   "update [extension-id...]",
+	Short: "Update installed extensions after validating the new version",
+	Long: `Updates extensions of an editor to the newest compatible version found by
+"vsynx outdated". Each extension is validated before its update is installed:
+Malicious extensions are refused and Suspicious ones are skipped unless
+--allow-suspicious is given. The new package is downloaded and checked to be the
+expected extension, version and VS Code engine before it is installed.
+
+Editors with a CLI (VS Code, VS Code Insiders, VSCodium) install the verified
+package through it; other editors, or --native, unpack it directly.
+
+With --review, the installed extension folder is compared with the update as
+"vsynx diff" does, whichever registry it was installed from. An update that raises the risk to medium or higher,
+such as a new publisher, startup activation or native binary, is only installed
+after you approve it; with --yes or --output json it is skipped.
+
+Examples:
+  vsynx update ms-python.python
+  vsynx update --all --editor cursor
+  vsynx update --all --review
+  vsynx update --all --yes --output json`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if updateAll == (len(args) > 0) {
+			fmt.Fprintln(os.Stderr, "Error: give extension IDs or --all")
+			os.Exit(1)
+		}
+
+		profile := resolveEditorProfile(updateEditor)
+		opts := updateCheckOptions(profile, args)
+		checker := update.NewChecker(marketplace.NewClient(), openvsx.NewClient())
+		report, err := checker.Check(profile, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var outdated []models.OutdatedExtension
+		for _, ext := range report.Extensions {
+			if ext.Outdated {
+				outdated = append(outdated, ext)
+			}
+		}
+		if len(outdated) == 0 {
+			if outputFormat == "json" {
+				data, _ := json.MarshalIndent(&models.UpdateReport{Editor: profile.ID, Results: []models.UpdateResult{}}, "", "  ")
+				fmt.Println(string(data))
+			} else {
+				fmt.Printf("All checked extensions in %s are up to date.\n", profile.Name)
+			}
+			return
+		}
+
+		if outputFormat != "json" && !updateYes {
+			fmt.Printf("\nUpdates available in %s:\n", profile.Name)
+			for _, ext := range outdated {
+				fmt.Printf("  %s %s -> %s (%s)\n", ext.ExtensionID, ext.InstalledVersion, ext.UpdateVersion, ext.UpdateSource)
+			}
+			if !confirm(fmt.Sprintf("Install %d update(s)?", len(outdated))) {
+				fmt.Println("Cancelled.")
+				return
+			}
+		}
+
+		updateOpts := update.UpdateOptions{
+			EngineVersion: opts.EngineVersion,
+			// A --path override is only known to vsynx, not to the editor CLI
+			Native:          updateNative || extensionsPath != "",
+			AllowSuspicious: updateAllowSuspicious,
+			Review:          updateReview,
+		}
+		if updateReview && outputFormat != "json" && !updateYes {
+			updateOpts.Approve = func(ext models.OutdatedExtension, diff *models.PackageDiff) bool {
+				printPackageDiff(diff)
+				return confirm(fmt.Sprintf("Approve %s %s despite the increased risk?", ext.ExtensionID, ext.UpdateVersion))
+			}
+		}
+		result := update.Update(profile, outdated, updateOpts, download.NewDownloader(), validation.NewValidator())
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			printUpdateReport(profile, result)
+		}
+		if result.FailedCount > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// updateCheckOptions builds the update check options for an editor from the flags
+func updateCheckOptions(profile models.EditorProfile, extensionIDs []string) update.Options {
+	engine := updateEngine
+	if engine == "" {
+		engine = editor.EngineVersion(profile)
+	}
+	return update.Options{
+		EngineVersion: engine,
+		Platform:      download.CurrentPlatform(),
+		ExtensionIDs:  extensionIDs,
+	}
+}
+
+func printOutdatedReport(report *models.OutdatedReport) {
+	engine := report.EngineVersion
+	if engine == "" {
+		engine = "unknown, engine not checked"
+	}
+	fmt.Printf("\n=== Outdated Extensions: %s (VS Code %s, %s) ===\n\n", report.EditorName, engine, report.Platform)
+
+	if len(report.Extensions) == 0 {
+		fmt.Println("No extensions installed.")
+		return
+	}
+
+	fmt.Printf("%-45s %-14s %-14s %-14s %s\n", "Extension ID", "Installed", "Marketplace", "OpenVSX", "Status")
+	fmt.Println(strings.Repeat("-", 110))
+	for _, ext := range report.Extensions {
+		installed := ext.InstalledVersion
+		if ext.PreReleaseChannel {
+			installed += " (pre)"
+		}
+		status := colorGreen + "✓ up to date" + colorReset
+		switch {
+		case ext.Outdated:
+			status = fmt.Sprintf("%s↑ %s from %s%s", colorYellow, ext.UpdateVersion, ext.UpdateSource, colorReset)
+		case ext.MarketplaceError != "" && ext.OpenVSXError != "":
+			status = "? not found on either registry"
+		case ext.MarketplaceLatest == "" && ext.OpenVSXLatest == "":
+			status = "? no compatible version"
+		}
+		fmt.Printf("%-45s %-14s %-14s %-14s %s\n", ext.ExtensionID, installed,
+			dashIfEmpty(ext.MarketplaceLatest), dashIfEmpty(ext.OpenVSXLatest), status)
+	}
+	fmt.Printf("\nOutdated: %d of %d\n", report.OutdatedCount, len(report.Extensions))
+}
+
+func printUpdateReport(profile models.EditorProfile, report *models.UpdateReport) {
+	fmt.Printf("\n=== Update Report: %s ===\n\n", profile.Name)
+	for _, result := range report.Results {
+		switch result.Status {
+		case models.UpdateStatusUpdated:
+			fmt.Printf("%s✓%s %s %s -> %s (%s, %s)", colorGreen, colorReset, result.ExtensionID, result.FromVersion, result.ToVersion, result.Source, result.Method)
+			if result.Review != nil {
+				fmt.Printf(" [risk %s%s%s]", riskColor(result.Review.Risk), result.Review.Risk, colorReset)
+			}
+			fmt.Println()
+		case models.UpdateStatusSkipped:
+			fmt.Printf("%s-%s %s skipped: %s\n", colorYellow, colorReset, result.ExtensionID, result.Error)
+		default:
+			fmt.Printf("%s✗%s %s %s -> %s: %s\n", colorRed, colorReset, result.ExtensionID, result.FromVersion, result.ToVersion, result.Error)
+		}
+	}
+	fmt.Printf("\nUpdated: %d, Skipped: %d, Failed: %d\n", report.UpdatedCount, report.SkippedCount, report.FailedCount)
+}
+
+// dashIfEmpty returns "-" for an empty value
+func dashIfEmpty(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+	rootCmd.AddCommand(updateCmd)
+
+	outdatedCmd.Flags().StringSliceVar(&outdatedEditors, "editors", nil, "Editors to check (default: all editors with an extensions directory)")
+	for _, cmd := range []*cobra.Command{outdatedCmd, updateCmd} {
+		cmd.Flags().StringVar(&updateEngine, "engine", "", "VS Code version of the editor (default: read from the editor install)")
+	}
+
+	updateCmd.Flags().StringVar(&updateEditor, "editor", "vscode", "Editor to update (e.g., vscode, cursor, windsurf)")
+	updateCmd.Flags().BoolVar(&updateAll, "all", false, "Update every outdated extension")
+	updateCmd.Flags().BoolVar(&updateNative, "native", false, "Install by unpacking the VSIX instead of using the editor CLI")
+	updateCmd.Flags().BoolVar(&updateAllowSuspicious, "allow-suspicious", false, "Also update extensions classified as Suspicious")
+	updateCmd.Flags().BoolVarP(&updateYes, "yes", "y", false, "Do not ask for confirmation")
+	updateCmd.Flags().BoolVar(&updateReview, "review", false, "Diff each update against the installed version and ask approval when the risk increases")
+}
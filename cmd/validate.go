@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -15,12 +16,17 @@ var validateCmd = &cobra.Command{
 	Short: "Validate an extension by ID",
 	Long: `Validate an extension by querying the Microsoft Marketplace and OpenVSX registry.
 Compares metadata such as publisher, version, repository URL, and hash.
-Classifies the extension as Legitimate, Suspicious, or Malicious.`,
+Classifies the extension as Legitimate, Suspicious, or Malicious.
+
+With --verify-signatures, the latest package is downloaded from each registry and
+its signature verified. This checks what the registries publish, not the package
+installed on this machine.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		extensionID := args[0]
 
 		validator := validation.NewValidator()
+		configureSignatures(validator)
 		result, err := validator.ValidateExtension(extensionID)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error validating extension: %v\n", err)
@@ -41,8 +47,57 @@ Classifies the extension as Legitimate, Suspicious, or Malicious.`,
 	},
 }
 
+var (
+	verifySignatures   bool
+	signatureRootsFile string
+	openvsxKeyFile     string
+	openvsxKeyURL      string
+)
+
 func init() {
 	rootCmd.AddCommand(validateCmd)
+	addSignatureFlags(validateCmd)
+}
+
+// addSignatureFlags registers the package signature verification flags on cmd
+func addSignatureFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&verifySignatures, "verify-signatures", false,
+		"Download the latest registry packages and verify their signatures (the installed package is not checked)")
+	addSignatureRootsFlag(cmd)
+	cmd.Flags().StringVar(&openvsxKeyFile, "openvsx-key", "", "Pinned OpenVSX signing key file (PEM or base64)")
+	cmd.Flags().StringVar(&openvsxKeyURL, "openvsx-key-url", validation.DefaultOpenVSXKeyURL,
+		"Only fetch OpenVSX signing keys published below this URL when no key is pinned")
+}
+
+// addSignatureRootsFlag registers the Marketplace signature roots flag on cmd
+func addSignatureRootsFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&signatureRootsFile, "signature-roots", "",
+		"PEM file of trusted roots for Marketplace signatures (default: system roots; an untrusted chain is reported as unverified)")
+}
+
+// signatureConfigurer is a validator or scanner that can verify package signatures
+type signatureConfigurer interface {
+	SetSignatureVerification(enabled bool, roots *x509.CertPool)
+	SetOpenVSXKey(key []byte, keyURL string)
+}
+
+// configureSignatures applies the signature verification flags to target
+func configureSignatures(target signatureConfigurer) {
+	var roots *x509.CertPool
+	var key []byte
+	var err error
+	if signatureRootsFile != "" {
+		roots, err = validation.LoadSignatureRoots(signatureRootsFile)
+	}
+	if err == nil && openvsxKeyFile != "" {
+		key, err = validation.LoadOpenVSXKey(openvsxKeyFile)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	target.SetSignatureVerification(verifySignatures, roots)
+	target.SetOpenVSXKey(key, openvsxKeyURL)
 }
 
 func printValidationResult(result *models.ValidationResult) {
@@ -76,6 +131,30 @@ func printValidationResult(result *models.ValidationResult) {
 		fmt.Printf("  Download: %s\n\n", result.OpenVSXData.DownloadURL)
 	}
 
+	// Package signatures
+	if len(result.SignatureChecks) > 0 {
+		overall := string(result.SignatureStatus)
+		if overall == "" {
+			overall = "not verified"
+		}
+		fmt.Printf("Signature: %s\n", overall)
+		for _, check := range result.SignatureChecks {
+			status := string(check.Status)
+			if status == "" {
+				status = "not verified"
+			}
+			line := fmt.Sprintf("  %s %s: %s", check.Source, check.Version, status)
+			if check.Signer != "" {
+				line += " (" + check.Signer + ")"
+			}
+			if check.Detail != "" {
+				line += " - " + check.Detail
+			}
+			fmt.Println(line)
+		}
+		fmt.Println()
+	}
+
 	// Differences
 	if len(result.Differences) > 0 {
 		fmt.Println("Differences Found:")
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/policy"
+	"github.com/yourusername/secureopenvsx/internal/validation"
+	"github.com/yourusername/secureopenvsx/internal/watch"
+)
+
+var (
+	watchEditors    []string
+	watchQuarantine bool
+	watchNotify     bool
+	watchWebhook    string
+	watchLogFile    string
+	watchSettle     time.Duration
+	watchEnforce    bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Audit extensions as they are installed",
+	Long: `Watches the extensions directory of every editor and validates each extension
+folder that appears or changes, a few seconds after the install finishes.
+
+Every result is appended as a JSON line to the watch log (watch.log in the vsynx
+config directory by default). Suspicious and malicious extensions can trigger a
+desktop notification (--notify) or a webhook POST with the event as JSON (--webhook),
+and with --quarantine they are moved out of the extensions directory. Use
+'vsynx quarantine list' and 'vsynx quarantine restore' to review them.
+
+With --enforce, the organization policy (see 'vsynx policy') is applied at startup,
+after every install and whenever the policy refresh interval elapses.
+
+Press Ctrl+C to stop.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles := watchProfiles()
+
+		options := watch.Options{Settle: watchSettle, Quarantine: watchQuarantine}
+		if watchNotify {
+			options.Notifiers = append(options.Notifiers, watch.DesktopNotifier{})
+		}
+		if watchWebhook != "" {
+			options.Notifiers = append(options.Notifiers, watch.NewWebhookNotifier(watchWebhook))
+		}
+
+		logPath := watchLogFile
+		if logPath == "" {
+			var err error
+			if logPath, err = config.Path("watch.log"); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating log directory: %v\n", err)
+			os.Exit(1)
+		}
+		logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening watch log: %v\n", err)
+			os.Exit(1)
+		}
+		defer logFile.Close()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		events := make(chan models.WatchEvent)
+		done := make(chan error, 1)
+		watcher := watch.New(profiles, validation.NewValidator(), options)
+		go func() {
+			done <- watcher.Run(ctx, events)
+			close(events)
+		}()
+
+		if outputFormat != "json" {
+			fmt.Printf("\n=== Watching Extensions ===\n\n")
+			for _, profile := range profiles {
+				fmt.Printf("  %s: %s\n", profile.Name, profile.ExtensionsDir)
+			}
+			fmt.Printf("\nLog: %s\n", logPath)
+			if watchQuarantine {
+				fmt.Println("Suspicious and malicious extensions will be quarantined.")
+			}
+			fmt.Println("Press Ctrl+C to stop.")
+			fmt.Println()
+		}
+
+		var source *models.PolicySource
+		var backoff *policy.Backoff
+		var enforceTick <-chan time.Time
+		if watchEnforce {
+			if source, err = policy.LoadSource(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			interval, _ := policy.Interval(source)
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			enforceTick = ticker.C
+			backoff = policy.NewBackoff(interval)
+			runWatchEnforcement(source, backoff)
+		}
+
+	loop:
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					break loop
+				}
+				data, _ := json.Marshal(event)
+				logFile.Write(append(data, '\n'))
+
+				if outputFormat == "json" {
+					fmt.Println(string(data))
+				} else {
+					printWatchEvent(event)
+				}
+				if watchEnforce {
+					runWatchEnforcement(source, backoff)
+				}
+			case <-enforceTick:
+				runWatchEnforcement(source, backoff)
+			}
+		}
+
+		if err := <-done; err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// watchProfiles returns the editors selected with --editors, or every editor with an extensions directory
+func watchProfiles() []models.EditorProfile {
+	if extensionsPath != "" {
+		return []models.EditorProfile{resolveEditorProfile("vscode")}
+	}
+
+	var profiles []models.EditorProfile
+	if len(watchEditors) > 0 {
+		for _, editorID := range watchEditors {
+			profiles = append(profiles, resolveEditorProfile(strings.TrimSpace(editorID)))
+		}
+		return profiles
+	}
+
+	for _, profile := range editor.GetEditorProfiles() {
+		if info, err := os.Stat(profile.ExtensionsDir); err == nil && info.IsDir() {
+			profiles = append(profiles, profile)
+		}
+	}
+	if len(profiles) == 0 {
+		fmt.Fprintln(os.Stderr, "No editor extensions directories found to watch.")
+		os.Exit(1)
+	}
+	return profiles
+}
+
+// runWatchEnforcement applies the current policy and prints the actions taken. After a
+// failure, further attempts wait for the backoff instead of running on every event.
+func runWatchEnforcement(source *models.PolicySource, backoff *policy.Backoff) {
+	if !backoff.Ready(time.Now()) {
+		return
+	}
+	status, err := policy.Current(source, false)
+	var plan *models.EnforcementPlan
+	if err == nil {
+		plan, err = policy.PlanStatus(status)
+	}
+	if err != nil {
+		backoff.Record(true, time.Now())
+		fmt.Fprintf(os.Stderr, "Policy enforcement failed: %v\n", err)
+		return
+	}
+	if len(plan.Steps) == 0 {
+		backoff.Record(status.Stale, time.Now())
+		return
+	}
+
+	report, err := enforcePlan(plan)
+	if err != nil {
+		backoff.Record(true, time.Now())
+		fmt.Fprintf(os.Stderr, "Policy enforcement failed: %v\n", err)
+		return
+	}
+	backoff.Record(status.Stale || report.Failed > 0, time.Now())
+	for _, result := range report.Results {
+		if outputFormat == "json" {
+			data, _ := json.Marshal(result)
+			fmt.Println(string(data))
+			continue
+		}
+		mark := colorGreen + "✓" + colorReset
+		if !result.Success {
+			mark = colorRed + "✗" + colorReset
+		}
+		fmt.Printf("%s  %s policy: %s", result.Time.Format("15:04:05"), mark, describeEnforcementStep(result.Step))
+		if result.Error != "" {
+			fmt.Printf(" - %s", result.Error)
+		}
+		fmt.Println()
+	}
+}
+
+// printWatchEvent prints one watch result
+func printWatchEvent(event models.WatchEvent) {
+	trustColor := getTrustColor(event.TrustLevel)
+	fmt.Printf("%s  %-10s %s@%s %s[%s]%s", event.Time.Format("15:04:05"), event.Editor,
+		event.ExtensionID, event.Version, trustColor, event.TrustLevel, colorReset)
+	if event.Action == models.WatchActionQuarantined {
+		fmt.Printf(" %squarantined%s (%s)", colorRed, colorReset, event.QuarantineID)
+	}
+	if event.Error != "" {
+		fmt.Printf(" - %s", event.Error)
+	}
+	fmt.Println()
+	for _, issue := range event.Issues {
+		fmt.Printf("    - %s\n", issue)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().StringSliceVar(&watchEditors, "editors", nil, "Editors to watch (default: every editor with an extensions directory)")
+	watchCmd.Flags().BoolVar(&watchQuarantine, "quarantine", false, "Move suspicious and malicious extensions into quarantine")
+	watchCmd.Flags().BoolVar(&watchNotify, "notify", false, "Show a desktop notification for suspicious and malicious extensions")
+	watchCmd.Flags().StringVar(&watchWebhook, "webhook", "", "POST suspicious and malicious events as JSON to this URL")
+	watchCmd.Flags().StringVar(&watchLogFile, "log", "", "Watch log file (default: watch.log in the vsynx config directory)")
+	watchCmd.Flags().BoolVar(&watchEnforce, "enforce", false, "Apply the organization policy at startup, after installs and on each refresh")
+	watchCmd.Flags().DurationVar(&watchSettle, "settle", watch.DefaultSettle, "How long a directory must be quiet before validating")
+}
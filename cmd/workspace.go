@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/validation"
+	"github.com/yourusername/secureopenvsx/internal/workspace"
+)
+
+var (
+	workspaceEditor       string
+	workspaceInstall      bool
+	workspaceSkipTrust    bool
+	workspaceAllowUnknown bool
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Check and export workspace extension recommendations",
+	Long:  `Works with the recommendations teams keep in .vscode/extensions.json or a .code-workspace file.`,
+}
+
+var workspaceCheckCmd = &cobra.Command{
+	Use:   "check [dir]",
+	Short: "Check an editor against a workspace's recommended extensions",
+	Long: `Reads .vscode/extensions.json in a repository checkout (or a .code-workspace file),
+validates every recommended extension and reports which are missing from the editor
+and which unwanted extensions are installed. With --install, missing extensions are
+validated and installed natively if Legitimate, even with --skip-trust; Unknown ones
+need --allow-unknown, and suspicious or malicious ones are skipped.
+
+Exit codes:
+  0 - Editor matches the recommendations
+  1 - Error or failed install
+  3 - Missing, unwanted or untrusted extensions found`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		recs, source, err := workspace.LoadRecommendations(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		profile, installed := loadWorkspaceEditor()
+		report := workspace.CheckRecommendations(recs, installed, workspaceValidator())
+		report.Source = source
+		report.Editor = profile.ID
+
+		finishWorkspaceCheck(profile, report)
+	},
+}
+
+var workspaceExportCmd = &cobra.Command{
+	Use:   "export [dir]",
+	Short: "Write an editor's extensions to .vscode/extensions.json",
+	Long: `Recommends every enabled extension of an editor in <dir>/.vscode/extensions.json.
+An existing unwantedRecommendations list is kept.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		var existing *models.WorkspaceRecommendations
+		if _, err := os.Stat(workspace.RecommendationsPath(dir)); err == nil {
+			existing, _, err = workspace.LoadRecommendations(dir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		profile := resolveEditorProfile(workspaceEditor)
+		extensions, err := validation.NewScanner().ScanEditorExtensions(profile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Error scanning extensions: %v\n", err)
+			os.Exit(1)
+		}
+
+		recs := workspace.RecommendationsFromInstalled(extensions, existing)
+		if err := workspace.SaveRecommendations(dir, recs); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("%s✓%s Wrote %d recommendations from %s to %s\n", colorGreen, colorReset,
+			len(recs.Recommendations), profile.Name, workspace.RecommendationsPath(dir))
+	},
+}
+
+// loadWorkspaceEditor resolves the --editor profile and its installed extension versions
+func loadWorkspaceEditor() (models.EditorProfile, map[string]string) {
+	profile := resolveEditorProfile(workspaceEditor)
+	installed, err := editor.InstalledVersions(profile.ExtensionsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading extensions: %v\n", err)
+		os.Exit(1)
+	}
+	return profile, installed
+}
+
+// workspaceValidator returns the validator used for trust checks, or nil with --skip-trust
+func workspaceValidator() workspace.Validator {
+	if workspaceSkipTrust {
+		return nil
+	}
+	return validation.NewValidator()
+}
+
+// finishWorkspaceCheck installs missing extensions if requested, prints the report and exits
+func finishWorkspaceCheck(profile models.EditorProfile, report *models.WorkspaceCheckReport) {
+	failed := 0
+	if workspaceInstall {
+		for _, result := range workspace.InstallMissing(profile, report, validation.NewValidator(), workspaceAllowUnknown) {
+			if !result.Success {
+				failed++
+			}
+		}
+	}
+
+	if outputFormat == "json" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		printWorkspaceReport(profile, report)
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+	if report.MissingCount > 0 || report.UnwantedInstalled > 0 || report.UntrustedCount > 0 {
+		os.Exit(3)
+	}
+}
+
+// printWorkspaceReport prints the state of each recommended and unwanted extension
+func printWorkspaceReport(profile models.EditorProfile, report *models.WorkspaceCheckReport) {
+	fmt.Printf("\n=== Workspace Check ===\n")
+	fmt.Printf("Source: %s\n", report.Source)
+	fmt.Printf("Editor: %s\n\n", profile.Name)
+
+	for _, item := range report.Extensions {
+		trust := ""
+		if item.TrustLevel != "" {
+			trust = fmt.Sprintf(" %s[%s]%s", getTrustColor(item.TrustLevel), item.TrustLevel, colorReset)
+		}
+		switch {
+		case item.Unwanted:
+			fmt.Printf("%s!%s %s %s - unwanted but installed\n", colorYellow, colorReset, item.ExtensionID, item.InstalledVersion)
+		case item.Installed:
+			fmt.Printf("%s✓%s %s %s%s\n", colorGreen, colorReset, item.ExtensionID, item.InstalledVersion, trust)
+		default:
+			fmt.Printf("%s✗%s %s - not installed%s\n", colorRed, colorReset, item.ExtensionID, trust)
+		}
+	}
+
+	if len(report.Installs) > 0 {
+		fmt.Println("\nInstalls:")
+		for _, result := range report.Installs {
+			if result.Success {
+				fmt.Printf("%s✓%s %s - %s\n", colorGreen, colorReset, result.ExtensionID, result.Message)
+			} else {
+				fmt.Printf("%s✗%s %s - %s\n", colorRed, colorReset, result.ExtensionID, result.Error)
+			}
+		}
+	}
+
+	fmt.Printf("\nMissing: %d, Unwanted installed: %d", report.MissingCount, report.UnwantedInstalled)
+	if report.TrustChecked {
+		fmt.Printf(", Untrusted: %d", report.UntrustedCount)
+	}
+	fmt.Println()
+	if report.MissingCount > 0 && !workspaceInstall {
+		fmt.Println("Run again with --install to install missing extensions.")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(workspaceCheckCmd)
+	workspaceCmd.AddCommand(workspaceExportCmd)
+
+	for _, cmd := range []*cobra.Command{workspaceCheckCmd, workspaceExportCmd} {
+		cmd.Flags().StringVarP(&workspaceEditor, "editor", "e", "vscode", "Editor to check or export")
+	}
+	workspaceCheckCmd.Flags().BoolVar(&workspaceInstall, "install", false, "Install missing recommended extensions natively")
+	workspaceCheckCmd.Flags().BoolVar(&workspaceSkipTrust, "skip-trust", false, "Skip marketplace trust validation in the report (installs are always validated)")
+	workspaceCheckCmd.Flags().BoolVar(&workspaceAllowUnknown, "allow-unknown", false, "Also install extensions whose trust level is Unknown")
+}
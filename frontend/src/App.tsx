@@ -12,14 +12,16 @@ import {
   GetEditorProfiles,
   GetAllEditorStatuses,
   GetEditorExtensions,
-  GetCLIStatus,
   InstallExtensionViaCLI,
   SyncExtensions,
   DetectSyncConflicts,
   GetCLIInstallStatus,
   InstallCLI,
   UninstallCLI,
+  AddCustomEditor,
+  RemoveCustomEditor,
 } from './wailsjs/go/main/App'
+import { models } from './wailsjs/go/models'
 
 interface ExtensionMetadata {
   id: string
@@ -87,17 +89,6 @@ interface EditorStatus {
   isAvailable?: boolean
 }
 
-interface CLIStatus {
-  vscodeAvailable?: boolean
-  vscodePath?: string
-  insidersAvailable?: boolean
-  insidersPath?: string
-  codiumAvailable?: boolean
-  codiumPath?: string
-  anyAvailable?: boolean
-  preferredCli?: string
-}
-
 interface SyncResult {
   targetEditor: string
   success?: boolean
@@ -117,6 +108,13 @@ interface SyncReport {
   totalErrors?: number
 }
 
+// Whether an editor's CLI command was found on this machine
+const hasCLI = (status: EditorStatus): boolean => !!(status.cliAvailable && status.editor.cliCommand)
+
+// Display name of an editor ID, falling back to the ID
+const editorName = (statuses: EditorStatus[], editorId: string): string =>
+  statuses.find(s => s.editor.id === editorId)?.editor.name || editorId
+
 function App() {
   const [extensions, setExtensions] = useState<Extension[]>([])
   // Cache audit reports per editor so user can switch between editors
@@ -134,7 +132,6 @@ function App() {
   const [editorProfiles, setEditorProfiles] = useState<EditorProfile[]>([])
   const [editorStatuses, setEditorStatuses] = useState<EditorStatus[]>([])
   const [selectedEditor, setSelectedEditor] = useState<string>('vscode')
-  const [cliStatus, setCLIStatus] = useState<CLIStatus | null>(null)
   
   // Sync-related state
   const [syncSourceEditor, setSyncSourceEditor] = useState<string>('vscode')
@@ -183,19 +180,13 @@ function App() {
       const profiles = await GetEditorProfiles()
       setEditorProfiles(profiles || [])
       
-      const statuses = await GetAllEditorStatuses()
-      setEditorStatuses(statuses || [])
-      
-      const cli = await GetCLIStatus()
-      setCLIStatus(cli)
+      const statuses: EditorStatus[] = await GetAllEditorStatuses() || []
+      setEditorStatuses(statuses)
       
-      // Set install target to first available CLI
-      if (cli?.vscodeAvailable) {
-        setInstallTargetEditor('vscode')
-      } else if (cli?.insidersAvailable) {
-        setInstallTargetEditor('vscode-insiders')
-      } else if (cli?.codiumAvailable) {
-        setInstallTargetEditor('vscodium')
+      // Keep the install target if its CLI is still available, else take the first editor with one
+      const cliEditors = statuses.filter(hasCLI)
+      if (cliEditors.length > 0 && !cliEditors.some(s => s.editor.id === installTargetEditor)) {
+        setInstallTargetEditor(cliEditors[0].editor.id)
       }
     } catch (error) {
       console.error('[Frontend] Failed to load editor data:', error)
@@ -302,7 +293,8 @@ function App() {
     setMarketplaceSearchResult(null)
     setSelectedSearchResult(null)
     try {
-      const results = await SearchMarketplace(marketplaceSearchQuery.trim())
+      const page = await SearchMarketplace(models.SearchOptions.createFrom({ query: marketplaceSearchQuery.trim() }))
+      const results = page?.extensions
       console.log('[Frontend] Marketplace search results:', results)
       setMarketplaceSearchResults(results || [])
       setView('search')
@@ -360,7 +352,8 @@ function App() {
     // Debounce search
     debounceTimeoutRef.current = setTimeout(async () => {
       try {
-        const results = await SearchMarketplace(value.trim())
+        const page = await SearchMarketplace(models.SearchOptions.createFrom({ query: value.trim(), pageSize: 8 }))
+        const results = page?.extensions
         // Only show suggestions if not suppressed (e.g., user clicked search button)
         if (!suppressSuggestionsRef.current) {
           setSuggestions(results?.slice(0, 8) || [])
@@ -496,11 +489,6 @@ function App() {
 
   // Install extension via CLI handler
   const handleInstallViaCLI = async (extensionId: string) => {
-    if (!cliStatus?.anyAvailable) {
-      setError('No VS Code CLI available. Please install VS Code and ensure "code" command is in PATH.')
-      return
-    }
-    
     const cliCommand = getCLICommand(installTargetEditor)
     if (!cliCommand) {
       setError(`CLI not available for ${installTargetEditor}`)
@@ -521,15 +509,10 @@ function App() {
     }
   }
 
-  // Get CLI command for an editor
+  // Get CLI command for an editor, built-in or custom, from the backend editor list
   const getCLICommand = (editorId: string): string | null => {
-    if (!cliStatus) return null
-    switch (editorId) {
-      case 'vscode': return cliStatus.vscodeAvailable ? 'code' : null
-      case 'vscode-insiders': return cliStatus.insidersAvailable ? 'code-insiders' : null
-      case 'vscodium': return cliStatus.codiumAvailable ? 'codium' : null
-      default: return null
-    }
+    const status = editorStatuses.find(s => s.editor.id === editorId)
+    return status && hasCLI(status) ? status.editor.cliCommand! : null
   }
 
   // Check if an editor has CLI available
@@ -555,10 +538,6 @@ function App() {
   // Execute Install + Sync workflow
   const handleInstallAndSync = async () => {
     if (!selectedSearchResult) return
-    if (!cliStatus?.anyAvailable) {
-      setError('No VS Code CLI available')
-      return
-    }
     if (installSyncTargets.length === 0) {
       setError('Please select at least one target editor')
       return
@@ -990,7 +969,6 @@ function App() {
             loading={loading}
             detailsLoading={detailsLoading}
             installing={installing}
-            cliStatus={cliStatus}
             installTargetEditor={installTargetEditor}
             setInstallTargetEditor={setInstallTargetEditor}
             suggestions={suggestions}
@@ -1057,6 +1035,8 @@ function App() {
           />
         ) : view === 'settings' ? (
           <SettingsView
+            editorProfiles={editorProfiles}
+            onEditorsChanged={loadEditorData}
             setVsynxCliStatus={setVsynxCliStatus}
             cliInstalling={cliInstalling}
             setCliInstalling={setCliInstalling}
@@ -1319,7 +1299,6 @@ function MarketplaceSearchView({
   loading,
   detailsLoading,
   installing,
-  cliStatus,
   installTargetEditor,
   setInstallTargetEditor,
   suggestions,
@@ -1576,7 +1555,7 @@ function MarketplaceSearchView({
                 Install Extension
               </h4>
               
-              {cliStatus?.anyAvailable ? (
+              {editorStatuses.some(hasCLI) ? (
                 <div className="space-y-3">
                   <div className="flex flex-wrap items-center gap-3">
                     <div className="flex items-center gap-2">
@@ -1586,9 +1565,9 @@ function MarketplaceSearchView({
                         onChange={(e) => setInstallTargetEditor(e.target.value)}
                         className="px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-blue-500"
                       >
-                        {cliStatus.vscodeAvailable && <option value="vscode">VS Code</option>}
-                        {cliStatus.insidersAvailable && <option value="vscode-insiders">VS Code Insiders</option>}
-                        {cliStatus.codiumAvailable && <option value="vscodium">VSCodium</option>}
+                        {editorStatuses.filter(hasCLI).map((status: EditorStatus) => (
+                          <option key={status.editor.id} value={status.editor.id}>{status.editor.name}</option>
+                        ))}
                       </select>
                     </div>
                     <button
@@ -1604,7 +1583,7 @@ function MarketplaceSearchView({
                       ) : (
                         <>
                           <Download className="w-4 h-4" />
-                          <span>Install to {installTargetEditor === 'vscode' ? 'VS Code' : installTargetEditor === 'vscode-insiders' ? 'Insiders' : 'VSCodium'}</span>
+                          <span>Install to {editorName(editorStatuses, installTargetEditor)}</span>
                         </>
                       )}
                     </button>
@@ -1626,7 +1605,7 @@ function MarketplaceSearchView({
                 <div className="flex items-center gap-2 text-gray-500">
                   <AlertTriangle className="w-5 h-5 text-yellow-500" />
                   <span className="text-sm">
-                    VS Code CLI not found. Install VS Code and run "Shell Command: Install 'code' command in PATH" from the command palette.
+                    No editor CLI found. Install VS Code and run "Shell Command: Install 'code' command in PATH" from the command palette, or add an editor with its CLI command in Settings.
                   </span>
                 </div>
               )}
@@ -1720,8 +1699,7 @@ function MarketplaceSearchView({
                 <p className="text-sm font-medium text-gray-700 mb-2">Install to:</p>
                 <div className="p-3 bg-blue-50 rounded-lg">
                   <p className="font-medium text-blue-800">
-                    {installTargetEditor === 'vscode' ? 'VS Code' : 
-                     installTargetEditor === 'vscode-insiders' ? 'VS Code Insiders' : 'VSCodium'}
+                    {editorName(editorStatuses, installTargetEditor)}
                   </p>
                   <p className="text-xs text-blue-600">Source for sync</p>
                 </div>
@@ -2269,6 +2247,8 @@ function SyncView({
 
 // Settings View Component
 function SettingsView({ 
+  editorProfiles,
+  onEditorsChanged,
   setVsynxCliStatus, 
   cliInstalling, 
   setCliInstalling 
@@ -2276,6 +2256,8 @@ function SettingsView({
   const [localCliStatus, setLocalCliStatus] = useState<any>(null)
   const [statusLoading, setStatusLoading] = useState(true)
   const [actionMessage, setActionMessage] = useState<string | null>(null)
+  const [newEditor, setNewEditor] = useState({ id: '', name: '', extensionsDir: '', cliCommand: '' })
+  const [editorMessage, setEditorMessage] = useState<string | null>(null)
 
   // Load CLI status on mount
   useEffect(() => {
@@ -2324,6 +2306,32 @@ function SettingsView({
     if (setCliInstalling) setCliInstalling(false)
   }
 
+  const handleAddEditor = async () => {
+    setEditorMessage(null)
+    try {
+      const profile = await AddCustomEditor(models.EditorProfile.createFrom(newEditor))
+      setEditorMessage(`Added ${profile.name}`)
+      setNewEditor({ id: '', name: '', extensionsDir: '', cliCommand: '' })
+      onEditorsChanged()
+    } catch (err: any) {
+      setEditorMessage(`Error: ${err.message || err}`)
+    }
+  }
+
+  const handleRemoveEditor = async (profile: EditorProfile) => {
+    if (!confirm(`Remove ${profile.name}? Its extensions stay on disk.`)) return
+    setEditorMessage(null)
+    try {
+      await RemoveCustomEditor(profile.id)
+      setEditorMessage(`Removed ${profile.name}`)
+      onEditorsChanged()
+    } catch (err: any) {
+      setEditorMessage(`Error: ${err.message || err}`)
+    }
+  }
+
+  const customEditors = (editorProfiles || []).filter((p: EditorProfile) => p.isCustom)
+
   return (
     <div className="h-full overflow-y-auto p-6 bg-gray-50">
       <div className="container mx-auto max-w-4xl">
@@ -2346,6 +2354,81 @@ function SettingsView({
           </p>
         </div>
 
+        {/* Custom Editors Section */}
+        <div className="bg-white rounded-lg shadow-lg p-6 mb-6">
+          <h3 className="text-lg font-bold mb-4 flex items-center">
+            <Monitor className="w-5 h-5 mr-2 text-blue-600" />
+            Custom Editors
+          </h3>
+          <p className="text-gray-600 mb-4">
+            Register other VS Code compatible editors (Theia, Positron, code-server, ...).
+            Editors with a CLI command can be used as install targets.
+          </p>
+
+          {customEditors.length > 0 && (
+            <div className="space-y-2 mb-4">
+              {customEditors.map((profile: EditorProfile) => (
+                <div key={profile.id} className="flex items-center justify-between p-3 bg-gray-50 rounded-lg">
+                  <div>
+                    <p className="font-medium">{profile.name} <span className="text-xs text-gray-500">({profile.id})</span></p>
+                    <p className="text-xs text-gray-500">{profile.extensionsDir}{profile.cliCommand && ` · CLI: ${profile.cliCommand}`}</p>
+                  </div>
+                  <button
+                    onClick={() => handleRemoveEditor(profile)}
+                    className="flex items-center space-x-1 px-3 py-1 text-sm bg-red-50 text-red-700 rounded-lg hover:bg-red-100"
+                  >
+                    <XCircle className="w-4 h-4" />
+                    <span>Remove</span>
+                  </button>
+                </div>
+              ))}
+            </div>
+          )}
+
+          <div className="grid grid-cols-1 md:grid-cols-2 gap-3">
+            <input
+              type="text"
+              value={newEditor.id}
+              onChange={(e) => setNewEditor({ ...newEditor, id: e.target.value })}
+              placeholder="ID (e.g. positron)"
+              className="px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-blue-500"
+            />
+            <input
+              type="text"
+              value={newEditor.name}
+              onChange={(e) => setNewEditor({ ...newEditor, name: e.target.value })}
+              placeholder="Name (e.g. Positron)"
+              className="px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-blue-500"
+            />
+            <input
+              type="text"
+              value={newEditor.extensionsDir}
+              onChange={(e) => setNewEditor({ ...newEditor, extensionsDir: e.target.value })}
+              placeholder="Extensions directory"
+              className="px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-blue-500"
+            />
+            <input
+              type="text"
+              value={newEditor.cliCommand}
+              onChange={(e) => setNewEditor({ ...newEditor, cliCommand: e.target.value })}
+              placeholder="CLI command (optional)"
+              className="px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-blue-500"
+            />
+          </div>
+          <button
+            onClick={handleAddEditor}
+            disabled={!newEditor.id || !newEditor.extensionsDir}
+            className="mt-3 flex items-center space-x-2 px-4 py-2 bg-blue-600 text-white rounded-lg hover:bg-blue-700 disabled:opacity-50 disabled:cursor-not-allowed"
+          >
+            <CheckCircle className="w-4 h-4" />
+            <span>Add Editor</span>
+          </button>
+
+          {editorMessage && (
+            <div className="mt-3 p-3 bg-gray-100 rounded-lg text-sm">{editorMessage}</div>
+          )}
+        </div>
+
         {/* CLI Installation Section */}
         <div className="bg-white rounded-lg shadow-lg p-6 mb-6">
           <h3 className="text-lg font-bold mb-4 flex items-center">
@@ -24,7 +24,7 @@ vi.mock('../wailsjs/go/main/App', () => ({
     maliciousCount: 0,
     results: [],
   }),
-  SearchMarketplace: vi.fn().mockResolvedValue([]),
+  SearchMarketplace: vi.fn().mockResolvedValue({ extensions: [], total: 0, page: 1, pageSize: 20 }),
   GetEditorProfiles: vi.fn().mockResolvedValue([
     { id: 'vscode', name: 'VS Code', extensionsDir: '/mock/.vscode/extensions' },
     { id: 'windsurf', name: 'Windsurf', extensionsDir: '/mock/.windsurf/extensions' },
@@ -43,8 +43,18 @@ vi.mock('../wailsjs/go/main/App', () => ({
     results: [],
   }),
   InstallExtensionViaCLI: vi.fn().mockResolvedValue(null),
+  AddCustomEditor: vi.fn().mockImplementation((profile) => Promise.resolve(profile)),
+  RemoveCustomEditor: vi.fn().mockResolvedValue(null),
 }))
 
+// Mock the generated Wails models; createFrom returns the plain object
+vi.mock('../wailsjs/go/models', () => {
+  const model = { createFrom: (source: any = {}) => source }
+  return {
+    models: { EditorProfile: model, SearchOptions: model },
+  }
+})
+
 // Mock window.matchMedia
 Object.defineProperty(window, 'matchMedia', {
   writable: true,
@@ -0,0 +1,42 @@
+package asset
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	// MaxSize caps the README, changelog, license and manifest text read from a registry
+	MaxSize = 1 << 20
+	// TruncatedNote is appended to an asset cut off at MaxSize, so it is not shown as complete
+	TruncatedNote = "\n\n[truncated by vsynx: the file is larger than 1 MiB]\n"
+)
+
+// Fetch downloads a text asset published by a registry. Assets larger than MaxSize are
+// cut off at MaxSize and end with TruncatedNote.
+func Fetch(client *http.Client, assetURL, userAgent string) (string, error) {
+	req, err := http.NewRequest("GET", assetURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read asset: %w", err)
+	}
+	if len(data) > MaxSize {
+		return string(data[:MaxSize]) + TruncatedNote, nil
+	}
+	return string(data), nil
+}
@@ -0,0 +1,43 @@
+package asset
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/readme":
+			if r.Header.Get("User-Agent") != "Test/1.0" {
+				t.Errorf("User-Agent = %q", r.Header.Get("User-Agent"))
+			}
+			w.Write([]byte("# Ext"))
+		case "/huge":
+			w.Write([]byte(strings.Repeat("x", MaxSize+100)))
+		case "/exact":
+			w.Write([]byte(strings.Repeat("x", MaxSize)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	text, err := Fetch(srv.Client(), srv.URL+"/readme", "Test/1.0")
+	if err != nil || text != "# Ext" {
+		t.Errorf("Fetch(readme) = %q, %v", text, err)
+	}
+	text, err = Fetch(srv.Client(), srv.URL+"/huge", "Test/1.0")
+	if err != nil || len(text) != MaxSize+len(TruncatedNote) || !strings.HasSuffix(text, TruncatedNote) {
+		t.Errorf("Fetch(huge) returned %d bytes, %v; want %d bytes ending with the truncation note", len(text), err, MaxSize)
+	}
+	text, err = Fetch(srv.Client(), srv.URL+"/exact", "Test/1.0")
+	if err != nil || len(text) != MaxSize || strings.HasSuffix(text, TruncatedNote) {
+		t.Errorf("Fetch(exact) returned %d bytes, %v; want %d bytes without a truncation note", len(text), err, MaxSize)
+	}
+	if _, err := Fetch(srv.Client(), srv.URL+"/missing", "Test/1.0"); err == nil {
+		t.Error("Fetch(missing) should fail")
+	}
+}
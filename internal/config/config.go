@@ -0,0 +1,32 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DirEnvVar overrides the location of the vsynx configuration directory
+const DirEnvVar = "VSYNX_CONFIG_DIR"
+
+// Dir returns the vsynx configuration directory (e.g. ~/.config/vsynx on Linux).
+// It does not create the directory.
+func Dir() (string, error) {
+	if dir := os.Getenv(DirEnvVar); dir != "" {
+		return dir, nil
+	}
+	userConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine config directory: %w", err)
+	}
+	return filepath.Join(userConfigDir, "vsynx"), nil
+}
+
+// Path returns the path of a file inside the vsynx configuration directory
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDirOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(DirEnvVar, dir)
+
+	got, err := Dir()
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+	if got != dir {
+		t.Errorf("Dir() = %s, want %s", got, dir)
+	}
+
+	path, err := Path("editors.json")
+	if err != nil {
+		t.Fatalf("Path failed: %v", err)
+	}
+	if path != filepath.Join(dir, "editors.json") {
+		t.Errorf("Path() = %s, want %s", path, filepath.Join(dir, "editors.json"))
+	}
+}
+
+func TestDirDefault(t *testing.T) {
+	t.Setenv(DirEnvVar, "")
+
+	got, err := Dir()
+	if err != nil {
+		t.Skipf("No user config directory available: %v", err)
+	}
+	if filepath.Base(got) != "vsynx" {
+		t.Errorf("Dir() = %s, want a vsynx directory", got)
+	}
+}
@@ -0,0 +1,350 @@
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/marketplace"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/openvsx"
+)
+
+const (
+	// PartialSuffix marks an incomplete download that the next attempt resumes
+	PartialSuffix = ".part"
+	// ChecksumSuffix is the sha256sum-style sidecar written next to a download
+	ChecksumSuffix = ".sha256"
+	// MetadataSuffix is the metadata JSON written next to a download
+	MetadataSuffix = ".json"
+
+	// UniversalPlatform selects the platform-neutral package
+	UniversalPlatform = "universal"
+)
+
+// TargetPlatforms lists the platforms the registries publish platform-specific packages for
+var TargetPlatforms = []string{
+	UniversalPlatform,
+	"win32-x64", "win32-arm64",
+	"linux-x64", "linux-arm64", "linux-armhf",
+	"alpine-x64", "alpine-arm64",
+	"darwin-x64", "darwin-arm64",
+	"web",
+}
+
+// ValidatePlatform checks that platform is a known target platform
+func ValidatePlatform(platform string) error {
+	for _, p := range TargetPlatforms {
+		if p == platform {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown target platform %q (expected one of: %s)", platform, strings.Join(TargetPlatforms, ", "))
+}
+
+// Downloader resolves extension packages on a registry and streams them to disk
+type Downloader struct {
+	marketplaceClient *marketplace.Client
+	openvsxClient     *openvsx.Client
+	httpClient        *http.Client
+}
+
+// NewDownloader creates a downloader. Package transfers have no overall timeout so
+// large packages are not cut off; only waiting for the response headers is bounded.
+func NewDownloader() *Downloader {
+	return &Downloader{
+		marketplaceClient: marketplace.NewClient(),
+		openvsxClient:     openvsx.NewClient(),
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				ResponseHeaderTimeout: 30 * time.Second,
+				// Byte ranges must refer to the package itself, not a compressed encoding
+				DisableCompression: true,
+			},
+		},
+	}
+}
+
+// Resolve finds the package URL and published checksum of an extension version on a
+// source registry. An empty version resolves the latest and an empty platform the
+// universal package.
+func (d *Downloader) Resolve(extensionID, version, source, targetPlatform string) (*models.DownloadInfo, error) {
+	if targetPlatform == "" {
+		targetPlatform = UniversalPlatform
+	}
+	if err := ValidatePlatform(targetPlatform); err != nil {
+		return nil, err
+	}
+
+	info := &models.DownloadInfo{
+		ExtensionID:    extensionID,
+		Version:        version,
+		Source:         source,
+		TargetPlatform: targetPlatform,
+	}
+
+	switch source {
+	case "", "marketplace":
+		info.Source = "marketplace"
+		if info.Version == "" {
+			metadata, err := d.marketplaceClient.FetchMetadata(extensionID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+			}
+			info.Version = metadata.Version
+		}
+		packageURL, err := marketplace.PlatformPackageURL(extensionID, info.Version, targetPlatform)
+		if err != nil {
+			return nil, err
+		}
+		info.URL = packageURL
+	case "openvsx":
+		metadata, err := d.openvsxClient.FetchPlatformMetadata(extensionID, version, targetPlatform)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+		}
+		if metadata.DownloadURL == "" {
+			return nil, fmt.Errorf("no download URL available for %s@%s", extensionID, metadata.Version)
+		}
+		info.Version = metadata.Version
+		info.URL = metadata.DownloadURL
+		if metadata.TargetPlatform != "" {
+			info.TargetPlatform = metadata.TargetPlatform
+		}
+		if metadata.SHA256URL != "" {
+			data, err := d.openvsxClient.DownloadExtension(metadata.SHA256URL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch published checksum: %w", err)
+			}
+			info.ExpectedSHA256 = parseChecksum(data)
+		}
+	default:
+		return nil, fmt.Errorf("unknown source registry: %s", source)
+	}
+
+	return info, nil
+}
+
+// ListVersions returns the published versions of an extension on a source registry, newest first
+func (d *Downloader) ListVersions(extensionID, source string) ([]models.ExtensionVersion, error) {
+	switch source {
+	case "", "marketplace":
+		return d.marketplaceClient.ListVersions(extensionID)
+	case "openvsx":
+		return d.openvsxClient.ListVersions(extensionID)
+	default:
+		return nil, fmt.Errorf("unknown source registry: %s", source)
+	}
+}
+
+// CurrentPlatform returns the target platform of the running system, e.g. "linux-x64"
+func CurrentPlatform() string {
+	system := map[string]string{"windows": "win32", "darwin": "darwin", "linux": "linux"}[runtime.GOOS]
+	arch := map[string]string{"amd64": "x64", "arm64": "arm64", "arm": "armhf"}[runtime.GOARCH]
+	if system == "" || arch == "" {
+		return UniversalPlatform
+	}
+	return system + "-" + arch
+}
+
+// FileName returns the VSIX file name of a download, e.g. "ms-python.python-2024.1.0@linux-x64.vsix"
+func FileName(info *models.DownloadInfo) string {
+	name := fmt.Sprintf("%s-%s", info.ExtensionID, info.Version)
+	if info.TargetPlatform != "" && info.TargetPlatform != UniversalPlatform {
+		name += "@" + info.TargetPlatform
+	}
+	return name + ".vsix"
+}
+
+// Download streams the package described by info into dir, resuming a partial file left
+// by an earlier attempt. The package is verified against the transfer size and, when the
+// registry publishes one, its checksum; info.Verification records which of the two was
+// checked, if any. Then a .sha256 sidecar and metadata JSON are written next to it.
+func (d *Downloader) Download(info *models.DownloadInfo, dir string) error {
+	if info.URL == "" {
+		return fmt.Errorf("download URL is empty")
+	}
+	// The file name is built from registry data, so keep it inside dir
+	if !editor.ValidExtensionID(info.ExtensionID) || !editor.ValidVersion(info.Version) || !editor.ValidTargetPlatform(info.TargetPlatform) {
+		return fmt.Errorf("refusing unsafe download name %s@%s (%s)", info.ExtensionID, info.Version, info.TargetPlatform)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	path := filepath.Join(dir, FileName(info))
+	log.Printf("[Download] Fetching %s to %s", info.URL, path)
+	if err := d.fetch(info, path, true); err != nil {
+		return err
+	}
+	info.File = path
+	info.DownloadedAt = time.Now()
+
+	checksum := fmt.Sprintf("%s  %s\n", info.SHA256, filepath.Base(path))
+	if err := os.WriteFile(path+ChecksumSuffix, []byte(checksum), 0644); err != nil {
+		return fmt.Errorf("failed to write checksum file: %w", err)
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal download metadata: %w", err)
+	}
+	if err := os.WriteFile(path+MetadataSuffix, data, 0644); err != nil {
+		return fmt.Errorf("failed to write download metadata: %w", err)
+	}
+
+	log.Printf("[Download] Downloaded %s (%d bytes, sha256 %s)", path, info.Size, info.SHA256)
+	return nil
+}
+
+// fetch streams info.URL into path through a partial file, resuming from its current
+// size when the server honours byte ranges. A partial file that cannot be resumed is
+// discarded and, when retry is set, the download starts over once.
+func (d *Downloader) fetch(info *models.DownloadInfo, path string, retry bool) error {
+	partPath := path + PartialSuffix
+	part, err := os.OpenFile(partPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open partial download: %w", err)
+	}
+	defer part.Close()
+
+	// Hash what is already on disk so the final digest covers the whole package
+	hash := sha256.New()
+	offset, err := io.Copy(hash, part)
+	if err != nil {
+		return fmt.Errorf("failed to read partial download: %w", err)
+	}
+
+	req, err := http.NewRequest("GET", info.URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create download request: %w", err)
+	}
+	req.Header.Set("User-Agent", marketplace.UserAgent)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download extension: %w", err)
+	}
+	defer resp.Body.Close()
+
+	total := int64(-1)
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			return d.restart(info, path, part, retry, "server returned an unexpected byte range")
+		}
+		total = size
+		log.Printf("[Download] Resuming %s at byte %d", filepath.Base(path), offset)
+	case resp.StatusCode == http.StatusOK:
+		// The server sent the whole package; drop whatever was on disk
+		if offset > 0 {
+			if err := part.Truncate(0); err != nil {
+				return fmt.Errorf("failed to reset partial download: %w", err)
+			}
+			if _, err := part.Seek(0, io.SeekStart); err != nil {
+				return fmt.Errorf("failed to reset partial download: %w", err)
+			}
+			hash.Reset()
+			offset = 0
+		}
+		total = resp.ContentLength
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		return d.restart(info, path, part, retry, "partial download is larger than the package")
+	default:
+		return fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+
+	written, err := io.Copy(io.MultiWriter(part, hash), resp.Body)
+	if err != nil {
+		return fmt.Errorf("download interrupted after %d bytes (run again to resume): %w", offset+written, err)
+	}
+
+	size := offset + written
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if total >= 0 && size != total {
+		part.Close()
+		os.Remove(partPath)
+		return fmt.Errorf("size mismatch: received %d bytes, expected %d", size, total)
+	}
+	if info.ExpectedSHA256 != "" && !strings.EqualFold(sum, info.ExpectedSHA256) {
+		part.Close()
+		os.Remove(partPath)
+		return fmt.Errorf("SHA256 mismatch: got %s, registry published %s", sum, info.ExpectedSHA256)
+	}
+
+	if err := part.Close(); err != nil {
+		return fmt.Errorf("failed to write download: %w", err)
+	}
+	if err := os.Rename(partPath, path); err != nil {
+		return fmt.Errorf("failed to move download into place: %w", err)
+	}
+
+	info.Size = size
+	info.SHA256 = sum
+	info.ResumedFrom = offset
+	switch {
+	case info.ExpectedSHA256 != "":
+		info.Verification = models.VerifiedSHA256
+	case total >= 0:
+		info.Verification = models.VerifiedSizeOnly
+	default:
+		info.Verification = models.Unverified
+	}
+	return nil
+}
+
+// restart discards a partial download that cannot be resumed and starts over once
+func (d *Downloader) restart(info *models.DownloadInfo, path string, part *os.File, retry bool, reason string) error {
+	part.Close()
+	if err := os.Remove(path + PartialSuffix); err != nil {
+		return fmt.Errorf("failed to remove partial download: %w", err)
+	}
+	if !retry {
+		return fmt.Errorf("cannot resume download: %s", reason)
+	}
+	log.Printf("[Download] Restarting %s: %s", filepath.Base(path), reason)
+	return d.fetch(info, path, false)
+}
+
+var contentRangePattern = regexp.MustCompile(`^bytes (\d+)-\d+/(\d+|\*)$`)
+
+// parseContentRange returns the first byte and the total size of a Content-Range header.
+// The size is -1 when the server does not know it.
+func parseContentRange(header string) (start, size int64, ok bool) {
+	m := contentRangePattern.FindStringSubmatch(strings.TrimSpace(header))
+	if m == nil {
+		return 0, 0, false
+	}
+	start, _ = strconv.ParseInt(m[1], 10, 64)
+	size = -1
+	if m[2] != "*" {
+		size, _ = strconv.ParseInt(m[2], 10, 64)
+	}
+	return start, size, true
+}
+
+// parseChecksum extracts the hex digest from a published checksum file ("<hash>" or
+// "<hash>  <file>")
+func parseChecksum(data []byte) string {
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
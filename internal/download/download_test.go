@@ -0,0 +1,270 @@
+package download
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// testPackage returns deterministic package bytes and their SHA256
+func testPackage() ([]byte, string) {
+	data := bytes.Repeat([]byte("vsix-package-data-"), 4096)
+	sum := sha256.Sum256(data)
+	return data, hex.EncodeToString(sum[:])
+}
+
+// rangeServer serves data with byte range support and records the Range headers it saw
+func rangeServer(t *testing.T, data []byte, ranges *[]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "pkg.vsix", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testInfo(url, expected string) *models.DownloadInfo {
+	return &models.DownloadInfo{
+		ExtensionID:    "pub.ext",
+		Version:        "1.2.3",
+		Source:         "openvsx",
+		TargetPlatform: "linux-x64",
+		URL:            url,
+		ExpectedSHA256: expected,
+	}
+}
+
+func TestDownloadWritesSidecars(t *testing.T) {
+	data, sum := testPackage()
+	var ranges []string
+	srv := rangeServer(t, data, &ranges)
+	dir := t.TempDir()
+
+	d := &Downloader{httpClient: srv.Client()}
+	info := testInfo(srv.URL, sum)
+	if err := d.Download(info, dir); err != nil {
+		t.Fatalf("Download() error: %v", err)
+	}
+
+	path := filepath.Join(dir, "pub.ext-1.2.3@linux-x64.vsix")
+	if info.File != path {
+		t.Errorf("File = %s, want %s", info.File, path)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("downloaded package differs (err %v)", err)
+	}
+	if info.Size != int64(len(data)) || info.SHA256 != sum {
+		t.Errorf("Size/SHA256 = %d/%s, want %d/%s", info.Size, info.SHA256, len(data), sum)
+	}
+	if info.Verification != models.VerifiedSHA256 {
+		t.Errorf("Verification = %s, want %s", info.Verification, models.VerifiedSHA256)
+	}
+	if _, err := os.Stat(path + PartialSuffix); !os.IsNotExist(err) {
+		t.Error("partial file should be gone after a complete download")
+	}
+
+	checksum, _ := os.ReadFile(path + ChecksumSuffix)
+	if want := sum + "  pub.ext-1.2.3@linux-x64.vsix\n"; string(checksum) != want {
+		t.Errorf("checksum file = %q, want %q", checksum, want)
+	}
+
+	var meta models.DownloadInfo
+	raw, _ := os.ReadFile(path + MetadataSuffix)
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		t.Fatalf("metadata JSON: %v", err)
+	}
+	if meta.SHA256 != sum || meta.TargetPlatform != "linux-x64" || meta.Version != "1.2.3" {
+		t.Errorf("metadata = %+v", meta)
+	}
+}
+
+func TestDownloadWithoutChecksumIsSizeOnly(t *testing.T) {
+	data, _ := testPackage()
+	var ranges []string
+	srv := rangeServer(t, data, &ranges)
+
+	d := &Downloader{httpClient: srv.Client()}
+	info := testInfo(srv.URL, "")
+	if err := d.Download(info, t.TempDir()); err != nil {
+		t.Fatalf("Download() error: %v", err)
+	}
+	if info.Verification != models.VerifiedSizeOnly {
+		t.Errorf("Verification = %s, want %s", info.Verification, models.VerifiedSizeOnly)
+	}
+}
+
+func TestDownloadWithoutSizeOrChecksumIsUnverified(t *testing.T) {
+	data, _ := testPackage()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Flushing before the end makes the response chunked, without a Content-Length
+		w.Write(data[:100])
+		w.(http.Flusher).Flush()
+		w.Write(data[100:])
+	}))
+	defer srv.Close()
+
+	d := &Downloader{httpClient: srv.Client()}
+	info := testInfo(srv.URL, "")
+	if err := d.Download(info, t.TempDir()); err != nil {
+		t.Fatalf("Download() error: %v", err)
+	}
+	if info.Verification != models.Unverified {
+		t.Errorf("Verification = %s, want %s", info.Verification, models.Unverified)
+	}
+}
+
+func TestDownloadRejectsUnsafeNames(t *testing.T) {
+	dir := t.TempDir()
+	d := &Downloader{httpClient: http.DefaultClient}
+	for _, mutate := range []func(*models.DownloadInfo){
+		func(info *models.DownloadInfo) { info.Version = "../../x" },
+		func(info *models.DownloadInfo) { info.ExtensionID = "../pub.ext" },
+		func(info *models.DownloadInfo) { info.TargetPlatform = "linux/../../x" },
+	} {
+		info := testInfo("http://127.0.0.1:1/pkg.vsix", "")
+		mutate(info)
+		if err := d.Download(info, dir); err == nil || !strings.Contains(err.Error(), "unsafe") {
+			t.Errorf("Download(%s@%s %s) error = %v, want unsafe name", info.ExtensionID, info.Version, info.TargetPlatform, err)
+		}
+	}
+}
+
+func TestDownloadResumesPartialFile(t *testing.T) {
+	data, sum := testPackage()
+	var ranges []string
+	srv := rangeServer(t, data, &ranges)
+	dir := t.TempDir()
+
+	info := testInfo(srv.URL, sum)
+	path := filepath.Join(dir, FileName(info))
+	if err := os.WriteFile(path+PartialSuffix, data[:1000], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Downloader{httpClient: srv.Client()}
+	if err := d.Download(info, dir); err != nil {
+		t.Fatalf("Download() error: %v", err)
+	}
+
+	if len(ranges) != 1 || ranges[0] != "bytes=1000-" {
+		t.Errorf("Range headers = %v, want [bytes=1000-]", ranges)
+	}
+	if info.ResumedFrom != 1000 {
+		t.Errorf("ResumedFrom = %d, want 1000", info.ResumedFrom)
+	}
+	got, _ := os.ReadFile(path)
+	if !bytes.Equal(got, data) {
+		t.Error("resumed package differs from the original")
+	}
+}
+
+func TestDownloadRestartsWithoutRangeSupport(t *testing.T) {
+	data, sum := testPackage()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+
+	info := testInfo(srv.URL, sum)
+	path := filepath.Join(dir, FileName(info))
+	os.WriteFile(path+PartialSuffix, []byte("stale bytes"), 0644)
+
+	d := &Downloader{httpClient: srv.Client()}
+	if err := d.Download(info, dir); err != nil {
+		t.Fatalf("Download() error: %v", err)
+	}
+	if info.ResumedFrom != 0 || info.SHA256 != sum {
+		t.Errorf("ResumedFrom/SHA256 = %d/%s, want 0/%s", info.ResumedFrom, info.SHA256, sum)
+	}
+}
+
+func TestDownloadRejectsChecksumMismatch(t *testing.T) {
+	data, _ := testPackage()
+	var ranges []string
+	srv := rangeServer(t, data, &ranges)
+	dir := t.TempDir()
+
+	info := testInfo(srv.URL, strings.Repeat("0", 64))
+	d := &Downloader{httpClient: srv.Client()}
+	err := d.Download(info, dir)
+	if err == nil || !strings.Contains(err.Error(), "SHA256 mismatch") {
+		t.Fatalf("Download() error = %v, want SHA256 mismatch", err)
+	}
+
+	path := filepath.Join(dir, FileName(info))
+	for _, p := range []string{path, path + PartialSuffix, path + ChecksumSuffix} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s should not exist after a failed verification", filepath.Base(p))
+		}
+	}
+}
+
+func TestDownloadRejectsSizeMismatch(t *testing.T) {
+	data, _ := testPackage()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Claim a larger package than the bytes actually sent
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes 100-%d/%d", len(data)-1, len(data)+50))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(data[100:])
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+
+	info := testInfo(srv.URL, "")
+	path := filepath.Join(dir, FileName(info))
+	os.WriteFile(path+PartialSuffix, data[:100], 0644)
+
+	d := &Downloader{httpClient: srv.Client()}
+	err := d.Download(info, dir)
+	if err == nil || !strings.Contains(err.Error(), "size mismatch") {
+		t.Fatalf("Download() error = %v, want size mismatch", err)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	info := &models.DownloadInfo{ExtensionID: "pub.ext", Version: "1.0.0", TargetPlatform: UniversalPlatform}
+	if got := FileName(info); got != "pub.ext-1.0.0.vsix" {
+		t.Errorf("FileName() = %s", got)
+	}
+	info.TargetPlatform = "darwin-arm64"
+	if got := FileName(info); got != "pub.ext-1.0.0@darwin-arm64.vsix" {
+		t.Errorf("FileName() = %s", got)
+	}
+}
+
+func TestValidatePlatform(t *testing.T) {
+	for _, p := range []string{"universal", "linux-x64", "darwin-arm64", "win32-x64"} {
+		if err := ValidatePlatform(p); err != nil {
+			t.Errorf("ValidatePlatform(%s) error: %v", p, err)
+		}
+	}
+	if err := ValidatePlatform("amiga-m68k"); err == nil {
+		t.Error("expected error for unknown platform")
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	start, size, ok := parseContentRange("bytes 100-199/200")
+	if !ok || start != 100 || size != 200 {
+		t.Errorf("parseContentRange = %d, %d, %v", start, size, ok)
+	}
+	if _, size, ok := parseContentRange("bytes 0-9/*"); !ok || size != -1 {
+		t.Errorf("unknown size = %d, %v", size, ok)
+	}
+	if _, _, ok := parseContentRange("items 0-9/10"); ok {
+		t.Error("expected malformed header to be rejected")
+	}
+}
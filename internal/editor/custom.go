@@ -0,0 +1,230 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// customEditorsFile is the config file holding user-defined editor profiles
+const customEditorsFile = "editors.json"
+
+// customEditorIDPattern keeps custom editor IDs usable as command-line arguments
+var customEditorIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// GetEditorProfiles returns the built-in editor profiles followed by the custom profiles
+// registered in the vsynx config. An unreadable config only drops the custom profiles.
+func GetEditorProfiles() []models.EditorProfile {
+	profiles := GetDefaultEditorProfiles()
+	custom, err := LoadCustomEditorProfiles()
+	if err != nil {
+		return profiles
+	}
+	return append(profiles, custom...)
+}
+
+// CustomEditorsPath returns the path of the custom editor profiles config file
+func CustomEditorsPath() (string, error) {
+	return config.Path(customEditorsFile)
+}
+
+// LoadCustomEditorProfiles reads the custom editor profiles from the config file.
+// A missing config file means there are no custom profiles.
+func LoadCustomEditorProfiles() ([]models.EditorProfile, error) {
+	path, err := CustomEditorsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return []models.EditorProfile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom editors: %w", err)
+	}
+
+	var profiles []models.EditorProfile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for i := range profiles {
+		profiles[i].IsCustom = true
+	}
+	return profiles, nil
+}
+
+// AddCustomEditorProfile validates a new custom editor profile and saves it to the config
+func AddCustomEditorProfile(profile models.EditorProfile) (models.EditorProfile, error) {
+	profile, err := normalizeCustomProfile(profile)
+	if err != nil {
+		return models.EditorProfile{}, err
+	}
+
+	profiles, err := LoadCustomEditorProfiles()
+	if err != nil {
+		return models.EditorProfile{}, err
+	}
+	if findCustomProfile(profiles, profile.ID) >= 0 {
+		return models.EditorProfile{}, fmt.Errorf("custom editor already exists: %s", profile.ID)
+	}
+
+	profiles = append(profiles, profile)
+	if err := saveCustomEditorProfiles(profiles); err != nil {
+		return models.EditorProfile{}, err
+	}
+	return profile, nil
+}
+
+// UpdateCustomEditorProfile replaces an existing custom editor profile with the same ID
+func UpdateCustomEditorProfile(profile models.EditorProfile) (models.EditorProfile, error) {
+	profile, err := normalizeCustomProfile(profile)
+	if err != nil {
+		return models.EditorProfile{}, err
+	}
+
+	profiles, err := LoadCustomEditorProfiles()
+	if err != nil {
+		return models.EditorProfile{}, err
+	}
+	i := findCustomProfile(profiles, profile.ID)
+	if i < 0 {
+		return models.EditorProfile{}, fmt.Errorf("custom editor not found: %s", profile.ID)
+	}
+
+	profiles[i] = profile
+	if err := saveCustomEditorProfiles(profiles); err != nil {
+		return models.EditorProfile{}, err
+	}
+	return profile, nil
+}
+
+// RemoveCustomEditorProfile deletes a custom editor profile from the config.
+// Extensions installed in the editor are left untouched.
+func RemoveCustomEditorProfile(editorID models.EditorType) error {
+	profiles, err := LoadCustomEditorProfiles()
+	if err != nil {
+		return err
+	}
+	i := findCustomProfile(profiles, editorID)
+	if i < 0 {
+		if isBuiltinEditor(editorID) {
+			return fmt.Errorf("%s is a built-in editor and cannot be removed", editorID)
+		}
+		return fmt.Errorf("custom editor not found: %s", editorID)
+	}
+
+	profiles = append(profiles[:i], profiles[i+1:]...)
+	return saveCustomEditorProfiles(profiles)
+}
+
+// normalizeCustomProfile validates a custom profile and fills in defaults
+func normalizeCustomProfile(profile models.EditorProfile) (models.EditorProfile, error) {
+	profile.ID = models.EditorType(strings.ToLower(strings.TrimSpace(string(profile.ID))))
+	if !customEditorIDPattern.MatchString(string(profile.ID)) {
+		return profile, fmt.Errorf("invalid editor ID %q: use lowercase letters, digits, '.', '_' or '-'", profile.ID)
+	}
+	if isBuiltinEditor(profile.ID) {
+		return profile, fmt.Errorf("%s is a built-in editor", profile.ID)
+	}
+
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" {
+		profile.Name = string(profile.ID)
+	}
+
+	if strings.TrimSpace(profile.ExtensionsDir) == "" {
+		return profile, fmt.Errorf("extensions directory is required")
+	}
+	extensionsDir, err := expandUserPath(profile.ExtensionsDir)
+	if err != nil {
+		return profile, err
+	}
+	profile.ExtensionsDir = extensionsDir
+
+	if profile.UserDataDir != "" {
+		userDataDir, err := expandUserPath(profile.UserDataDir)
+		if err != nil {
+			return profile, err
+		}
+		profile.UserDataDir = userDataDir
+	}
+
+	if profile.IndexFile == "" {
+		profile.IndexFile = defaultIndexFile
+	}
+	if profile.IndexFile != filepath.Base(profile.IndexFile) || profile.IndexFile == "." || profile.IndexFile == ".." {
+		return profile, fmt.Errorf("index file must be a file name inside the extensions directory: %s", profile.IndexFile)
+	}
+
+	// Custom editors with a CLI are assumed to accept VS Code's --install-extension flags
+	profile.CLICommand = strings.TrimSpace(profile.CLICommand)
+	profile.IsVSCodeFamily = profile.CLICommand != ""
+	profile.IsCustom = true
+	return profile, nil
+}
+
+// saveCustomEditorProfiles writes the custom editor profiles to the config file
+func saveCustomEditorProfiles(profiles []models.EditorProfile) error {
+	path, err := CustomEditorsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal custom editors: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write custom editors: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save custom editors: %w", err)
+	}
+	return nil
+}
+
+// findCustomProfile returns the index of the profile with the given ID, or -1
+func findCustomProfile(profiles []models.EditorProfile, editorID models.EditorType) int {
+	for i, p := range profiles {
+		if p.ID == editorID {
+			return i
+		}
+	}
+	return -1
+}
+
+// isBuiltinEditor reports whether editorID belongs to a built-in profile
+func isBuiltinEditor(editorID models.EditorType) bool {
+	_, ok := builtinLocations[editorID]
+	return ok || editorID == models.EditorCustom
+}
+
+// expandUserPath expands a leading ~ and makes the path absolute
+func expandUserPath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve home directory: %w", err)
+		}
+		path = filepath.Join(homeDir, path[1:])
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	return absPath, nil
+}
@@ -0,0 +1,137 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+func TestCustomEditorProfiles(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	extensionsDir := t.TempDir()
+
+	added, err := AddCustomEditorProfile(models.EditorProfile{
+		ID:            "Positron",
+		Name:          "Positron",
+		ExtensionsDir: extensionsDir,
+	})
+	if err != nil {
+		t.Fatalf("AddCustomEditorProfile failed: %v", err)
+	}
+	if added.ID != "positron" || !added.IsCustom || added.IndexFile != "extensions.json" {
+		t.Errorf("Unexpected normalized profile: %+v", added)
+	}
+
+	profile, err := GetEditorProfile("positron")
+	if err != nil {
+		t.Fatalf("Custom profile not found: %v", err)
+	}
+	if profile.ExtensionsDir != extensionsDir {
+		t.Errorf("ExtensionsDir = %s, want %s", profile.ExtensionsDir, extensionsDir)
+	}
+	if found := FindProfileByExtensionsDir(extensionsDir); found == nil || found.ID != "positron" {
+		t.Errorf("FindProfileByExtensionsDir did not find the custom profile, got %+v", found)
+	}
+
+	if _, err := AddCustomEditorProfile(models.EditorProfile{ID: "positron", ExtensionsDir: extensionsDir}); err == nil {
+		t.Error("Expected an error adding a duplicate custom editor")
+	}
+
+	profile.CLICommand = "positron"
+	updated, err := UpdateCustomEditorProfile(profile)
+	if err != nil {
+		t.Fatalf("UpdateCustomEditorProfile failed: %v", err)
+	}
+	if !updated.IsVSCodeFamily {
+		t.Error("Custom editor with a CLI should be treated as VS Code family")
+	}
+
+	if err := RemoveCustomEditorProfile("positron"); err != nil {
+		t.Fatalf("RemoveCustomEditorProfile failed: %v", err)
+	}
+	if _, err := GetEditorProfile("positron"); err == nil {
+		t.Error("Expected removed custom editor to be unknown")
+	}
+}
+
+func TestCustomEditorProfileValidation(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+
+	tests := []struct {
+		name    string
+		profile models.EditorProfile
+	}{
+		{"built-in ID", models.EditorProfile{ID: models.EditorVSCode, ExtensionsDir: "/tmp/ext"}},
+		{"invalid ID", models.EditorProfile{ID: "my editor", ExtensionsDir: "/tmp/ext"}},
+		{"missing extensions dir", models.EditorProfile{ID: "theia"}},
+		{"index file with path", models.EditorProfile{ID: "theia", ExtensionsDir: "/tmp/ext", IndexFile: "../extensions.json"}},
+		{"index file parent", models.EditorProfile{ID: "theia", ExtensionsDir: "/tmp/ext", IndexFile: ".."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := AddCustomEditorProfile(tt.profile); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+
+	if err := RemoveCustomEditorProfile(models.EditorVSCode); err == nil {
+		t.Error("Expected an error removing a built-in editor")
+	}
+}
+
+func TestCustomEditorIndexFile(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	extensionsDir := t.TempDir()
+	profile, err := AddCustomEditorProfile(models.EditorProfile{ID: "theia", ExtensionsDir: extensionsDir, IndexFile: "index.json"})
+	if err != nil {
+		t.Fatalf("AddCustomEditorProfile failed: %v", err)
+	}
+
+	entries := []models.ExtensionIndexEntry{{Identifier: models.ExtensionIdentifier{ID: "pub.ext"}, Version: "1.0.0", RelativeLocation: "pub.ext-1.0.0"}}
+	if err := WriteExtensionsIndex(extensionsDir, entries); err != nil {
+		t.Fatalf("WriteExtensionsIndex failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(extensionsDir, "index.json")); err != nil {
+		t.Errorf("Index was not written to the profile's index file: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(extensionsDir, "extensions.json")); !os.IsNotExist(err) {
+		t.Errorf("extensions.json should not be written, got %v", err)
+	}
+
+	read, err := ReadExtensionsIndex(extensionsDir)
+	if err != nil || len(read) != 1 || read[0].Identifier.ID != "pub.ext" {
+		t.Fatalf("ReadExtensionsIndex = %+v, %v", read, err)
+	}
+	if !CheckEditorStatus(profile).IndexFileExists {
+		t.Error("CheckEditorStatus did not find the profile's index file")
+	}
+
+	// extensions.json is not the index of this editor
+	os.Remove(filepath.Join(extensionsDir, "index.json"))
+	os.WriteFile(filepath.Join(extensionsDir, "extensions.json"), []byte("[]"), 0644)
+	if _, err := ReadExtensionsIndex(extensionsDir); err == nil {
+		t.Error("Expected an error when only extensions.json exists")
+	}
+	if indexFileExists(extensionsDir) {
+		t.Error("indexFileExists should ignore extensions.json for this editor")
+	}
+}
+
+func TestExpandUserPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	got, err := expandUserPath("~/.trae/extensions")
+	if err != nil {
+		t.Fatalf("expandUserPath failed: %v", err)
+	}
+	if want := filepath.Join(home, ".trae", "extensions"); got != want {
+		t.Errorf("expandUserPath = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,332 @@
+package editor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/jsonc"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// editorLocations describes where a built-in editor keeps its files
+type editorLocations struct {
+	dotFolder    string // holds extensions/ and argv.json, e.g. .vscode
+	appFolder    string // user data folder name, e.g. Code
+	cli          string // launcher used to locate portable installs
+	serverFolder string // remote (SSH/WSL/container) server install in the home directory
+	flatpakID    string
+	flatpakData  string // folder below the Flatpak app's data directory
+	snapName     string
+	useEnv       bool // honours VSCODE_EXTENSIONS and VSCODE_PORTABLE
+}
+
+// builtinLocations maps each built-in editor to its install locations
+var builtinLocations = map[models.EditorType]editorLocations{
+	models.EditorVSCode: {
+		dotFolder: ".vscode", appFolder: "Code", cli: "code", serverFolder: ".vscode-server",
+		flatpakID: "com.visualstudio.code", flatpakData: "vscode", snapName: "code", useEnv: true,
+	},
+	models.EditorVSCodeInsiders: {
+		dotFolder: ".vscode-insiders", appFolder: "Code - Insiders", cli: "code-insiders",
+		serverFolder: ".vscode-server-insiders", snapName: "code-insiders",
+	},
+	models.EditorVSCodium: {
+		dotFolder: ".vscode-oss", appFolder: "VSCodium", cli: "codium", serverFolder: ".vscodium-server",
+		flatpakID: "com.vscodium.codium", flatpakData: "codium", snapName: "codium",
+	},
+	models.EditorWindsurf: {
+		dotFolder: ".windsurf", appFolder: "Windsurf", cli: "windsurf", serverFolder: ".windsurf-server",
+	},
+	models.EditorCursor: {
+		dotFolder: ".cursor", appFolder: "Cursor", cli: "cursor", serverFolder: ".cursor-server",
+	},
+	models.EditorKiro: {
+		dotFolder: ".kiro", appFolder: "Kiro", cli: "kiro",
+	},
+}
+
+// DiscoverEditorRoots returns every extensions directory found for an editor: the one in
+// use locally, plus Flatpak/Snap sandboxes, remote server installs and, under WSL, the
+// Windows host install. The root matching the profile's ExtensionsDir is marked active.
+func DiscoverEditorRoots(profile models.EditorProfile) models.EditorDiscovery {
+	homeDir, _ := os.UserHomeDir()
+
+	var roots []models.ExtensionRoot
+	if loc, ok := builtinLocations[profile.ID]; ok && !profile.IsCustom {
+		roots = append(roots, localExtensionRoots(homeDir, loc)...)
+		roots = append(roots, remoteExtensionRoots(homeDir, loc)...)
+	}
+
+	discovery := models.EditorDiscovery{Editor: profile, Roots: []models.ExtensionRoot{}}
+	activeFound := false
+	for _, root := range roots {
+		if !root.Exists && root.Source != models.RootSourceDefault && !isExplicitSource(root.Source) {
+			continue
+		}
+		if !activeFound && samePath(filepath.Clean(root.Path), filepath.Clean(profile.ExtensionsDir)) {
+			root.Active = true
+			activeFound = true
+		}
+		discovery.Roots = append(discovery.Roots, root)
+	}
+
+	// Custom profiles and --path overrides point somewhere discovery does not know about
+	if !activeFound {
+		discovery.Roots = append([]models.ExtensionRoot{newExtensionRoot(profile.ExtensionsDir, models.RootSourceCustom)}, discovery.Roots...)
+		discovery.Roots[0].Active = true
+	}
+
+	for i := range discovery.Roots {
+		if discovery.Roots[i].Exists {
+			discovery.Roots[i].ExtensionCount = countExtensionFolders(discovery.Roots[i].Path)
+		}
+	}
+	return discovery
+}
+
+// DiscoverAllEditorRoots runs DiscoverEditorRoots for every known editor
+func DiscoverAllEditorRoots() []models.EditorDiscovery {
+	profiles := GetEditorProfiles()
+	discoveries := make([]models.EditorDiscovery, 0, len(profiles))
+	for _, profile := range profiles {
+		discoveries = append(discoveries, DiscoverEditorRoots(profile))
+	}
+	return discoveries
+}
+
+// discoverExtensionsDir picks the extensions directory a built-in editor uses on this machine.
+// Explicit configuration (VSCODE_EXTENSIONS, argv.json, portable mode) wins; otherwise the
+// first existing of the default, Flatpak and Snap locations, falling back to the default.
+func discoverExtensionsDir(homeDir string, editorType models.EditorType) string {
+	loc := builtinLocations[editorType]
+	roots := localExtensionRoots(homeDir, loc)
+
+	for _, root := range roots {
+		if isExplicitSource(root.Source) {
+			return root.Path
+		}
+	}
+	for _, root := range roots {
+		if root.Exists {
+			return root.Path
+		}
+	}
+	return getDefaultExtensionsDir(homeDir, loc.dotFolder)
+}
+
+// discoverUserDataDir picks the user data directory of a built-in editor, following
+// portable and Flatpak installs
+func discoverUserDataDir(homeDir string, editorType models.EditorType) string {
+	loc := builtinLocations[editorType]
+
+	if dataDir := findPortableDataDir(loc); dataDir != "" {
+		return filepath.Join(dataDir, "user-data")
+	}
+
+	defaultDir := getDefaultUserDataDir(homeDir, loc.appFolder)
+	if loc.flatpakID != "" && !isDir(defaultDir) {
+		flatpakDir := filepath.Join(homeDir, ".var", "app", loc.flatpakID, "config", loc.appFolder)
+		if isDir(flatpakDir) {
+			return flatpakDir
+		}
+	}
+	return defaultDir
+}
+
+// localExtensionRoots lists the local extensions directories of an editor in precedence order
+func localExtensionRoots(homeDir string, loc editorLocations) []models.ExtensionRoot {
+	var roots []models.ExtensionRoot
+
+	if loc.useEnv {
+		if dir := os.Getenv("VSCODE_EXTENSIONS"); dir != "" {
+			roots = append(roots, newExtensionRoot(dir, models.RootSourceEnv))
+		}
+	}
+	if dir := readArgvExtensionsDir(filepath.Join(homeDir, loc.dotFolder, "argv.json")); dir != "" {
+		roots = append(roots, newExtensionRoot(dir, models.RootSourceArgv))
+	}
+	if dataDir := findPortableDataDir(loc); dataDir != "" {
+		roots = append(roots, newExtensionRoot(filepath.Join(dataDir, "extensions"), models.RootSourcePortable))
+	}
+
+	roots = append(roots, newExtensionRoot(getDefaultExtensionsDir(homeDir, loc.dotFolder), models.RootSourceDefault))
+
+	if runtime.GOOS == "linux" {
+		if loc.flatpakID != "" {
+			dir := filepath.Join(homeDir, ".var", "app", loc.flatpakID, "data", loc.flatpakData, "extensions")
+			roots = append(roots, newExtensionRoot(dir, models.RootSourceFlatpak))
+		}
+		if loc.snapName != "" {
+			dir := filepath.Join(homeDir, "snap", loc.snapName, "current", loc.dotFolder, "extensions")
+			roots = append(roots, newExtensionRoot(dir, models.RootSourceSnap))
+		}
+	}
+
+	return roots
+}
+
+// remoteExtensionRoots lists extensions directories of remote-host installs: the editor's
+// server in this home directory and, under WSL, the editor installed on the Windows host
+func remoteExtensionRoots(homeDir string, loc editorLocations) []models.ExtensionRoot {
+	var roots []models.ExtensionRoot
+
+	if loc.serverFolder != "" {
+		dir := filepath.Join(homeDir, loc.serverFolder, "extensions")
+		roots = append(roots, newExtensionRoot(dir, models.RootSourceRemote))
+	}
+	if windowsHome := wslWindowsHome(); windowsHome != "" {
+		dir := filepath.Join(windowsHome, loc.dotFolder, "extensions")
+		roots = append(roots, newExtensionRoot(dir, models.RootSourceWSLHost))
+	}
+
+	return roots
+}
+
+// newExtensionRoot creates an ExtensionRoot, checking whether the directory exists
+func newExtensionRoot(path string, source models.ExtensionRootSource) models.ExtensionRoot {
+	if expanded, err := expandUserPath(path); err == nil {
+		path = expanded
+	}
+	return models.ExtensionRoot{Path: path, Source: source, Exists: isDir(path)}
+}
+
+// isExplicitSource reports whether a root was configured by the user rather than probed
+func isExplicitSource(source models.ExtensionRootSource) bool {
+	return source == models.RootSourceEnv || source == models.RootSourceArgv || source == models.RootSourcePortable
+}
+
+// readArgvExtensionsDir returns the extensions-dir set in an editor's argv.json, if any
+func readArgvExtensionsDir(argvPath string) string {
+	data, err := os.ReadFile(argvPath)
+	if err != nil {
+		return ""
+	}
+	var argv map[string]any
+	if err := json.Unmarshal(jsonc.Standardize(data), &argv); err != nil {
+		return ""
+	}
+	dir, _ := argv["extensions-dir"].(string)
+	return dir
+}
+
+// findPortableDataDir returns the data folder of a portable install, or "" if the editor is not portable
+func findPortableDataDir(loc editorLocations) string {
+	if loc.useEnv {
+		if dir := os.Getenv("VSCODE_PORTABLE"); dir != "" {
+			return dir
+		}
+	}
+	if loc.cli == "" {
+		return ""
+	}
+
+	portableMu.Lock()
+	defer portableMu.Unlock()
+	dataDir, ok := portableDataDirs[loc.cli]
+	if !ok {
+		dataDir = portableDataDirOfCLI(loc.cli)
+		portableDataDirs[loc.cli] = dataDir
+	}
+	return dataDir
+}
+
+var (
+	portableMu       sync.Mutex
+	portableDataDirs = map[string]string{} // CLI name -> portable data folder, "" if none
+)
+
+// portableDataDirOfCLI locates an editor's launcher and returns the portable data folder
+// next to its install, or "". Profiles are built often, so findPortableDataDir caches this
+// once per process.
+func portableDataDirOfCLI(cli string) string {
+	cliPath, err := findCLI(cli)
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(cliPath); err == nil {
+		cliPath = resolved
+	}
+
+	var dataDir string
+	if runtime.GOOS == "darwin" {
+		// <Editor>.app/Contents/Resources/app/bin/code keeps its data next to the bundle
+		if i := strings.Index(cliPath, ".app"+string(filepath.Separator)); i >= 0 {
+			dataDir = filepath.Join(filepath.Dir(cliPath[:i+len(".app")]), "code-portable-data")
+		}
+	} else {
+		// <install>/bin/code keeps its data in <install>/data
+		dataDir = filepath.Join(filepath.Dir(filepath.Dir(cliPath)), "data")
+	}
+
+	if dataDir != "" && isDir(dataDir) {
+		return dataDir
+	}
+	return ""
+}
+
+var (
+	wslHomeOnce sync.Once
+	wslHome     string
+)
+
+// wslWindowsHome returns the Windows user profile as a /mnt path when running under WSL
+func wslWindowsHome() string {
+	wslHomeOnce.Do(func() {
+		if runtime.GOOS != "linux" || !isWSL() {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, "cmd.exe", "/c", "echo %USERPROFILE%").Output()
+		if err != nil {
+			return
+		}
+		wslHome = windowsToWSLPath(strings.TrimSpace(string(out)))
+	})
+	return wslHome
+}
+
+// isWSL reports whether the process runs inside the Windows Subsystem for Linux
+func isWSL() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	_, err := os.Stat("/proc/sys/fs/binfmt_misc/WSLInterop")
+	return err == nil
+}
+
+// windowsToWSLPath converts a Windows path like C:\Users\me to /mnt/c/Users/me
+func windowsToWSLPath(path string) string {
+	if len(path) < 3 || path[1] != ':' || (path[2] != '\\' && path[2] != '/') {
+		return ""
+	}
+	return "/mnt/" + strings.ToLower(path[:1]) + strings.ReplaceAll(path[2:], `\`, "/")
+}
+
+// countExtensionFolders counts the extension folders in a directory
+func countExtensionFolders(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			count++
+		}
+	}
+	return count
+}
+
+// isDir reports whether path is an existing directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,159 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+func TestDiscoverExtensionsDirDefault(t *testing.T) {
+	t.Setenv("VSCODE_EXTENSIONS", "")
+	t.Setenv("VSCODE_PORTABLE", "")
+	homeDir := t.TempDir()
+
+	got := discoverExtensionsDir(homeDir, models.EditorCursor)
+	if want := filepath.Join(homeDir, ".cursor", "extensions"); got != want {
+		t.Errorf("discoverExtensionsDir = %s, want %s", got, want)
+	}
+}
+
+func TestDiscoverExtensionsDirEnv(t *testing.T) {
+	envDir := t.TempDir()
+	t.Setenv("VSCODE_EXTENSIONS", envDir)
+	homeDir := t.TempDir()
+
+	if got := discoverExtensionsDir(homeDir, models.EditorVSCode); got != envDir {
+		t.Errorf("discoverExtensionsDir = %s, want %s", got, envDir)
+	}
+	// The variable only applies to VS Code itself
+	if got := discoverExtensionsDir(homeDir, models.EditorCursor); got == envDir {
+		t.Error("VSCODE_EXTENSIONS should not change Cursor's extensions directory")
+	}
+}
+
+func TestDiscoverExtensionsDirArgv(t *testing.T) {
+	t.Setenv("VSCODE_EXTENSIONS", "")
+	t.Setenv("VSCODE_PORTABLE", "")
+	homeDir := t.TempDir()
+	customDir := filepath.Join(homeDir, "ext")
+
+	argv := `{
+	// Added by the user
+	"extensions-dir": "` + filepath.ToSlash(customDir) + `",
+}`
+	os.MkdirAll(filepath.Join(homeDir, ".vscode"), 0755)
+	if err := os.WriteFile(filepath.Join(homeDir, ".vscode", "argv.json"), []byte(argv), 0644); err != nil {
+		t.Fatalf("Failed to write argv.json: %v", err)
+	}
+
+	if got := discoverExtensionsDir(homeDir, models.EditorVSCode); got != customDir {
+		t.Errorf("discoverExtensionsDir = %s, want %s", got, customDir)
+	}
+}
+
+func TestDiscoverExtensionsDirFlatpak(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Flatpak is Linux only")
+	}
+	t.Setenv("VSCODE_EXTENSIONS", "")
+	t.Setenv("VSCODE_PORTABLE", "")
+	homeDir := t.TempDir()
+	flatpakDir := filepath.Join(homeDir, ".var", "app", "com.visualstudio.code", "data", "vscode", "extensions")
+	os.MkdirAll(flatpakDir, 0755)
+
+	if got := discoverExtensionsDir(homeDir, models.EditorVSCode); got != flatpakDir {
+		t.Errorf("discoverExtensionsDir = %s, want %s", got, flatpakDir)
+	}
+
+	// A regular install takes precedence over the sandbox
+	defaultDir := filepath.Join(homeDir, ".vscode", "extensions")
+	os.MkdirAll(defaultDir, 0755)
+	if got := discoverExtensionsDir(homeDir, models.EditorVSCode); got != defaultDir {
+		t.Errorf("discoverExtensionsDir = %s, want %s", got, defaultDir)
+	}
+}
+
+func TestDiscoverEditorRoots(t *testing.T) {
+	t.Setenv("VSCODE_EXTENSIONS", "")
+	t.Setenv("VSCODE_PORTABLE", "")
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+
+	os.MkdirAll(filepath.Join(homeDir, ".windsurf", "extensions", "pub.ext-1.0.0"), 0755)
+	os.MkdirAll(filepath.Join(homeDir, ".windsurf-server", "extensions", "pub.ext-1.0.0"), 0755)
+	os.MkdirAll(filepath.Join(homeDir, ".windsurf-server", "extensions", "pub.other-2.0.0"), 0755)
+
+	profile, err := GetEditorProfile(models.EditorWindsurf)
+	if err != nil {
+		t.Fatalf("GetEditorProfile failed: %v", err)
+	}
+	discovery := DiscoverEditorRoots(profile)
+
+	roots := map[models.ExtensionRootSource]models.ExtensionRoot{}
+	for _, root := range discovery.Roots {
+		roots[root.Source] = root
+	}
+	if root := roots[models.RootSourceDefault]; !root.Active || root.ExtensionCount != 1 {
+		t.Errorf("Expected active default root with 1 extension, got %+v", root)
+	}
+	if root := roots[models.RootSourceRemote]; root.Active || root.ExtensionCount != 2 {
+		t.Errorf("Expected inactive remote root with 2 extensions, got %+v", root)
+	}
+}
+
+func TestDiscoverEditorRootsOverride(t *testing.T) {
+	profile := models.EditorProfile{ID: models.EditorVSCode, ExtensionsDir: t.TempDir()}
+	discovery := DiscoverEditorRoots(profile)
+
+	if len(discovery.Roots) == 0 || !discovery.Roots[0].Active || discovery.Roots[0].Path != profile.ExtensionsDir {
+		t.Errorf("Expected the overridden directory to be the active root, got %+v", discovery.Roots)
+	}
+}
+
+func TestFindPortableDataDirCached(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("portable layout differs on this platform")
+	}
+	install := t.TempDir()
+	cli := "vsynx-portable-test"
+	if err := os.MkdirAll(filepath.Join(install, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(install, "bin", cli), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	dataDir := filepath.Join(install, "data")
+	if err := os.Mkdir(dataDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", filepath.Join(install, "bin"))
+	t.Cleanup(func() { delete(portableDataDirs, cli) })
+
+	loc := editorLocations{cli: cli}
+	if got := findPortableDataDir(loc); got != dataDir {
+		t.Fatalf("findPortableDataDir = %q, want %q", got, dataDir)
+	}
+	// Later lookups are answered from the cache without searching PATH again
+	t.Setenv("PATH", "")
+	if got := findPortableDataDir(loc); got != dataDir {
+		t.Errorf("cached findPortableDataDir = %q, want %q", got, dataDir)
+	}
+}
+
+func TestWindowsToWSLPath(t *testing.T) {
+	tests := map[string]string{
+		`C:\Users\me`:   "/mnt/c/Users/me",
+		`D:/data`:       "/mnt/d/data",
+		"%USERPROFILE%": "",
+		"":              "",
+	}
+	for input, want := range tests {
+		if got := windowsToWSLPath(input); got != want {
+			t.Errorf("windowsToWSLPath(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
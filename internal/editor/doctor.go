@@ -0,0 +1,271 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// DiagnoseExtensions checks extensions.json against the folders on disk and plans a repair
+// for every problem found. Nothing is changed on disk.
+func DiagnoseExtensions(extensionsDir string) (*models.DoctorReport, error) {
+	report, _, err := diagnoseExtensions(extensionsDir)
+	return report, err
+}
+
+// RepairExtensions diagnoses an extensions directory and applies the repair plan.
+// The previous index is kept next to it as a .vsynx-backup file.
+func RepairExtensions(extensionsDir string) (*models.DoctorReport, error) {
+	report, repaired, err := diagnoseExtensions(extensionsDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Issues) == 0 {
+		return report, nil
+	}
+
+	if data, err := os.ReadFile(report.IndexPath); err == nil {
+		backupPath := report.IndexPath + ".vsynx-backup"
+		if err := os.WriteFile(backupPath, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to back up extensions index: %w", err)
+		}
+		report.BackupPath = backupPath
+	}
+
+	if err := WriteExtensionsIndex(extensionsDir, repaired); err != nil {
+		return nil, err
+	}
+	report.Fixed = true
+	return report, nil
+}
+
+// diagnoseExtensions returns the doctor report together with the repaired index
+func diagnoseExtensions(extensionsDir string) (*models.DoctorReport, []models.ExtensionIndexEntry, error) {
+	folders, err := listExtensionFolders(extensionsDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	obsolete, err := ReadObsoleteExtensions(extensionsDir)
+	if err != nil {
+		obsolete = map[string]bool{}
+	}
+
+	entries, indexPath, issues := readIndexLenient(extensionsDir)
+	report := &models.DoctorReport{
+		ExtensionsDir: extensionsDir,
+		IndexPath:     indexPath,
+		EntryCount:    len(entries),
+		FolderCount:   len(folders),
+		Issues:        issues,
+	}
+
+	folderByName := map[string]extensionFolder{}
+	foldersByID := map[string][]extensionFolder{}
+	for _, folder := range folders {
+		folderByName[strings.ToLower(folder.name)] = folder
+		if !IsObsolete(obsolete, folder.name) {
+			id := strings.ToLower(folder.id)
+			foldersByID[id] = append(foldersByID[id], folder)
+		}
+	}
+
+	repaired := make([]models.ExtensionIndexEntry, 0, len(entries))
+	kept := map[string]int{}
+
+	for _, entry := range entries {
+		id := strings.ToLower(entry.Identifier.ID)
+		folderName := entry.RelativeLocation
+
+		if !isValidRelativeLocation(folderName) {
+			candidate := ""
+			if entry.Location.Path != "" {
+				if _, ok := folderByName[strings.ToLower(path.Base(entry.Location.Path))]; ok {
+					candidate = path.Base(entry.Location.Path)
+				}
+			}
+			if candidate == "" {
+				if folder := bestFolderFor(foldersByID[id], entry.Version); folder != nil {
+					candidate = folder.name
+				}
+			}
+			issue := models.IndexIssue{
+				Kind:        models.IndexIssueBadRelativeLocation,
+				ExtensionID: entry.Identifier.ID,
+				Detail:      fmt.Sprintf("relativeLocation %q is not a folder name", entry.RelativeLocation),
+				Repair:      "remove entry",
+			}
+			if candidate != "" {
+				issue.Repair = fmt.Sprintf("set relativeLocation to %s", candidate)
+				issue.Folder = candidate
+			}
+			report.Issues = append(report.Issues, issue)
+			if candidate == "" {
+				continue
+			}
+			folderName = candidate
+		}
+
+		if _, ok := folderByName[strings.ToLower(folderName)]; !ok {
+			issue := models.IndexIssue{
+				Kind:        models.IndexIssueMissingFolder,
+				ExtensionID: entry.Identifier.ID,
+				Folder:      folderName,
+				Detail:      fmt.Sprintf("folder %s does not exist", folderName),
+				Repair:      "remove entry",
+			}
+			replacement := bestFolderFor(foldersByID[id], entry.Version)
+			if replacement == nil {
+				report.Issues = append(report.Issues, issue)
+				continue
+			}
+			issue.Repair = fmt.Sprintf("point entry at %s (version %s)", replacement.name, replacement.version)
+			report.Issues = append(report.Issues, issue)
+			folderName = replacement.name
+			entry.Version = replacement.version
+		}
+		entry.RelativeLocation = folderName
+
+		expected := buildExtensionLocation(filepath.Join(extensionsDir, folderName))
+		if entry.Location.Path == "" || !samePath(entry.Location.Path, expected.Path) {
+			detail := fmt.Sprintf("location.path is %s", entry.Location.Path)
+			if entry.Location.Path == "" {
+				detail = "location.path is missing"
+			}
+			report.Issues = append(report.Issues, models.IndexIssue{
+				Kind:        models.IndexIssueWrongLocation,
+				ExtensionID: entry.Identifier.ID,
+				Folder:      folderName,
+				Detail:      detail,
+				Repair:      fmt.Sprintf("set location.path to %s", expected.Path),
+			})
+			entry.Location = expected
+		}
+
+		if i, seen := kept[id]; seen {
+			keep := repaired[i]
+			if CompareVersions(entry.Version, keep.Version) > 0 {
+				keep = entry
+			}
+			report.Issues = append(report.Issues, models.IndexIssue{
+				Kind:        models.IndexIssueDuplicateID,
+				ExtensionID: entry.Identifier.ID,
+				Detail:      fmt.Sprintf("listed more than once (versions %s and %s)", repaired[i].Version, entry.Version),
+				Repair:      fmt.Sprintf("keep only version %s", keep.Version),
+			})
+			repaired[i] = keep
+			continue
+		}
+		kept[id] = len(repaired)
+		repaired = append(repaired, entry)
+	}
+
+	// Folders of extensions that have no entry at all are added back to the index.
+	// Other versions of indexed extensions are left for 'vsynx clean'.
+	for _, onDisk := range folders {
+		id := strings.ToLower(onDisk.id)
+		if _, indexed := kept[id]; indexed || IsObsolete(obsolete, onDisk.name) {
+			continue
+		}
+		folder := bestFolderFor(foldersByID[id], "")
+		kept[id] = len(repaired)
+		report.Issues = append(report.Issues, models.IndexIssue{
+			Kind:        models.IndexIssueUnindexedFolder,
+			ExtensionID: folder.id,
+			Folder:      folder.name,
+			Detail:      fmt.Sprintf("folder %s is not listed in the index", folder.name),
+			Repair:      "add entry to the index (or remove the folder with 'vsynx clean')",
+		})
+		repaired = append(repaired, models.ExtensionIndexEntry{
+			Identifier:       models.ExtensionIdentifier{ID: strings.ToLower(folder.id)},
+			Version:          folder.version,
+			Location:         buildExtensionLocation(filepath.Join(extensionsDir, folder.name)),
+			RelativeLocation: folder.name,
+			Metadata: map[string]any{
+				"installedTimestamp": time.Now().UnixMilli(),
+				"source":             "resource",
+			},
+		})
+	}
+
+	return report, repaired, nil
+}
+
+// readIndexLenient reads the extensions index, skipping entries that cannot be parsed.
+// Problems with the file itself are returned as issues instead of errors.
+func readIndexLenient(extensionsDir string) ([]models.ExtensionIndexEntry, string, []models.IndexIssue) {
+	issues := []models.IndexIssue{}
+
+	names := indexFiles(extensionsDir)
+	var indexPath string
+	var data []byte
+	err := os.ErrNotExist
+	for _, name := range names {
+		indexPath = filepath.Join(extensionsDir, name)
+		if data, err = os.ReadFile(indexPath); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		issues = append(issues, models.IndexIssue{
+			Kind:   models.IndexIssueMissingIndex,
+			Detail: fmt.Sprintf("no %s found", names[0]),
+			Repair: "rebuild the index from the folders on disk",
+		})
+		return nil, filepath.Join(extensionsDir, names[0]), issues
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		issues = append(issues, models.IndexIssue{
+			Kind:   models.IndexIssueMalformedIndex,
+			Detail: fmt.Sprintf("%s is not valid JSON: %v", filepath.Base(indexPath), err),
+			Repair: "rebuild the index from the folders on disk",
+		})
+		return nil, indexPath, issues
+	}
+
+	entries := make([]models.ExtensionIndexEntry, 0, len(raw))
+	for i, item := range raw {
+		var entry models.ExtensionIndexEntry
+		if err := json.Unmarshal(item, &entry); err != nil || entry.Identifier.ID == "" {
+			detail := fmt.Sprintf("entry %d has no identifier", i)
+			if err != nil {
+				detail = fmt.Sprintf("entry %d cannot be parsed: %v", i, err)
+			}
+			issues = append(issues, models.IndexIssue{
+				Kind:   models.IndexIssueMalformedEntry,
+				Detail: detail,
+				Repair: "remove entry",
+			})
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, indexPath, issues
+}
+
+// isValidRelativeLocation reports whether rel is a plain folder name inside the extensions directory
+func isValidRelativeLocation(rel string) bool {
+	return rel != "" && rel != "." && rel != ".." && !strings.ContainsAny(rel, `/\`)
+}
+
+// bestFolderFor picks the folder matching version, or the highest version if none matches
+func bestFolderFor(folders []extensionFolder, version string) *extensionFolder {
+	var best *extensionFolder
+	for i, folder := range folders {
+		if version != "" && folder.version == version {
+			return &folders[i]
+		}
+		if best == nil || CompareVersions(folder.version, best.version) > 0 {
+			best = &folders[i]
+		}
+	}
+	return best
+}
@@ -0,0 +1,115 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+func TestDiagnoseExtensions(t *testing.T) {
+	extensionsDir := t.TempDir()
+	writeTestExtensionFolder(t, extensionsDir, "pub.ext-1.0.0", "pub", "ext", "1.0.0")
+	writeTestExtensionFolder(t, extensionsDir, "pub.ext-1.1.0", "pub", "ext", "1.1.0")
+	writeTestExtensionFolder(t, extensionsDir, "pub.moved-2.0.0", "pub", "moved", "2.0.0")
+	writeTestExtensionFolder(t, extensionsDir, "pub.unlisted-0.1.0", "pub", "unlisted", "0.1.0")
+
+	WriteExtensionsIndex(extensionsDir, []models.ExtensionIndexEntry{
+		{Identifier: models.ExtensionIdentifier{ID: "pub.ext"}, Version: "1.0.0", RelativeLocation: "pub.ext-1.0.0",
+			Location: buildExtensionLocation(filepath.Join(extensionsDir, "pub.ext-1.0.0"))},
+		{Identifier: models.ExtensionIdentifier{ID: "pub.ext"}, Version: "1.1.0", RelativeLocation: "pub.ext-1.1.0",
+			Location: buildExtensionLocation(filepath.Join(extensionsDir, "pub.ext-1.1.0"))},
+		{Identifier: models.ExtensionIdentifier{ID: "pub.moved"}, Version: "2.0.0", RelativeLocation: "pub.moved-2.0.0",
+			Location: models.ExtensionLocation{Mid: 1, Path: "/home/olduser/.vscode/extensions/pub.moved-2.0.0", Scheme: "file"}},
+		{Identifier: models.ExtensionIdentifier{ID: "pub.gone"}, Version: "3.0.0", RelativeLocation: "pub.gone-3.0.0",
+			Location: buildExtensionLocation(filepath.Join(extensionsDir, "pub.gone-3.0.0"))},
+	})
+
+	report, err := DiagnoseExtensions(extensionsDir)
+	if err != nil {
+		t.Fatalf("DiagnoseExtensions failed: %v", err)
+	}
+
+	kinds := map[models.IndexIssueKind]string{}
+	for _, issue := range report.Issues {
+		kinds[issue.Kind] = issue.ExtensionID
+	}
+	expected := map[models.IndexIssueKind]string{
+		models.IndexIssueDuplicateID:     "pub.ext",
+		models.IndexIssueWrongLocation:   "pub.moved",
+		models.IndexIssueMissingFolder:   "pub.gone",
+		models.IndexIssueUnindexedFolder: "pub.unlisted",
+	}
+	for kind, id := range expected {
+		if kinds[kind] != id {
+			t.Errorf("Expected %s issue for %s, got %q", kind, id, kinds[kind])
+		}
+	}
+	if len(report.Issues) != len(expected) {
+		t.Errorf("Expected %d issues, got %+v", len(expected), report.Issues)
+	}
+
+	// Diagnosing must not touch the index
+	index, _ := ReadExtensionsIndex(extensionsDir)
+	if len(index) != 4 {
+		t.Errorf("Index should be unchanged, got %d entries", len(index))
+	}
+}
+
+func TestRepairExtensions(t *testing.T) {
+	extensionsDir := t.TempDir()
+	writeTestExtensionFolder(t, extensionsDir, "pub.ext-1.0.0", "pub", "ext", "1.0.0")
+	writeTestExtensionFolder(t, extensionsDir, "pub.other-0.2.0", "pub", "other", "0.2.0")
+
+	index := `[
+		{"identifier": {"id": "pub.ext"}, "version": "1.0.0", "relativeLocation": "../pub.ext-1.0.0",
+		 "location": {"$mid": 1, "path": "/old/home/pub.ext-1.0.0", "scheme": "file"}},
+		{"identifier": {"id": "pub.gone"}, "version": "1.0.0", "relativeLocation": "pub.gone-1.0.0"},
+		"not an entry"
+	]`
+	indexPath := filepath.Join(extensionsDir, "extensions.json")
+	if err := os.WriteFile(indexPath, []byte(index), 0644); err != nil {
+		t.Fatalf("Failed to write index: %v", err)
+	}
+
+	report, err := RepairExtensions(extensionsDir)
+	if err != nil {
+		t.Fatalf("RepairExtensions failed: %v", err)
+	}
+	if !report.Fixed {
+		t.Error("Report should be marked fixed")
+	}
+	if _, err := os.Stat(report.BackupPath); err != nil {
+		t.Errorf("Expected index backup at %s: %v", report.BackupPath, err)
+	}
+
+	repaired, err := ReadExtensionsIndex(extensionsDir)
+	if err != nil {
+		t.Fatalf("Repaired index is unreadable: %v", err)
+	}
+	if len(repaired) != 2 {
+		t.Fatalf("Expected 2 entries after repair, got %+v", repaired)
+	}
+	for _, entry := range repaired {
+		want := buildExtensionLocation(filepath.Join(extensionsDir, entry.RelativeLocation))
+		if entry.Location.Path != want.Path {
+			t.Errorf("%s location = %s, want %s", entry.Identifier.ID, entry.Location.Path, want.Path)
+		}
+	}
+	if entry := FindExtensionEntry(repaired, "pub.ext"); entry == nil || entry.RelativeLocation != "pub.ext-1.0.0" {
+		t.Errorf("Expected pub.ext to point at pub.ext-1.0.0, got %+v", entry)
+	}
+	if FindExtensionEntry(repaired, "pub.other") == nil {
+		t.Error("Expected unindexed pub.other to be added")
+	}
+
+	// A second pass finds nothing left to fix
+	report, err = DiagnoseExtensions(extensionsDir)
+	if err != nil {
+		t.Fatalf("DiagnoseExtensions failed: %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("Expected no issues after repair, got %+v", report.Issues)
+	}
+}
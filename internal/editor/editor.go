@@ -12,7 +12,8 @@ import (
 	"github.com/yourusername/secureopenvsx/internal/models"
 )
 
-// GetDefaultEditorProfiles returns all known editor profiles with default paths
+// GetDefaultEditorProfiles returns the built-in editor profiles with the paths each editor
+// uses on this machine (see discoverExtensionsDir)
 func GetDefaultEditorProfiles() []models.EditorProfile {
 	homeDir, _ := os.UserHomeDir()
 
@@ -20,8 +21,9 @@ func GetDefaultEditorProfiles() []models.EditorProfile {
 		{
 			ID:             models.EditorVSCode,
 			Name:           "VS Code",
-			ExtensionsDir:  getDefaultExtensionsDir(homeDir, ".vscode"),
+			ExtensionsDir:  discoverExtensionsDir(homeDir, models.EditorVSCode),
 			IndexFile:      "extensions.json",
+			UserDataDir:    discoverUserDataDir(homeDir, models.EditorVSCode),
 			CLICommand:     "code",
 			IsVSCodeFamily: true,
 			IsCustom:       false,
@@ -29,8 +31,9 @@ func GetDefaultEditorProfiles() []models.EditorProfile {
 		{
 			ID:             models.EditorVSCodeInsiders,
 			Name:           "VS Code Insiders",
-			ExtensionsDir:  getDefaultExtensionsDir(homeDir, ".vscode-insiders"),
+			ExtensionsDir:  discoverExtensionsDir(homeDir, models.EditorVSCodeInsiders),
 			IndexFile:      "extensions.json",
+			UserDataDir:    discoverUserDataDir(homeDir, models.EditorVSCodeInsiders),
 			CLICommand:     "code-insiders",
 			IsVSCodeFamily: true,
 			IsCustom:       false,
@@ -38,8 +41,9 @@ func GetDefaultEditorProfiles() []models.EditorProfile {
 		{
 			ID:             models.EditorVSCodium,
 			Name:           "VSCodium",
-			ExtensionsDir:  getDefaultExtensionsDir(homeDir, ".vscode-oss"),
+			ExtensionsDir:  discoverExtensionsDir(homeDir, models.EditorVSCodium),
 			IndexFile:      "extensions.json",
+			UserDataDir:    discoverUserDataDir(homeDir, models.EditorVSCodium),
 			CLICommand:     "codium",
 			IsVSCodeFamily: true,
 			IsCustom:       false,
@@ -47,8 +51,9 @@ func GetDefaultEditorProfiles() []models.EditorProfile {
 		{
 			ID:             models.EditorWindsurf,
 			Name:           "Windsurf",
-			ExtensionsDir:  getDefaultExtensionsDir(homeDir, ".windsurf"),
+			ExtensionsDir:  discoverExtensionsDir(homeDir, models.EditorWindsurf),
 			IndexFile:      "extensions.json",
+			UserDataDir:    discoverUserDataDir(homeDir, models.EditorWindsurf),
 			CLICommand:     "",
 			IsVSCodeFamily: false,
 			IsCustom:       false,
@@ -56,8 +61,9 @@ func GetDefaultEditorProfiles() []models.EditorProfile {
 		{
 			ID:             models.EditorCursor,
 			Name:           "Cursor",
-			ExtensionsDir:  getDefaultExtensionsDir(homeDir, ".cursor"),
+			ExtensionsDir:  discoverExtensionsDir(homeDir, models.EditorCursor),
 			IndexFile:      "extensions.json",
+			UserDataDir:    discoverUserDataDir(homeDir, models.EditorCursor),
 			CLICommand:     "",
 			IsVSCodeFamily: false,
 			IsCustom:       false,
@@ -65,8 +71,9 @@ func GetDefaultEditorProfiles() []models.EditorProfile {
 		{
 			ID:             models.EditorKiro,
 			Name:           "Kiro",
-			ExtensionsDir:  getDefaultExtensionsDir(homeDir, ".kiro"),
+			ExtensionsDir:  discoverExtensionsDir(homeDir, models.EditorKiro),
 			IndexFile:      "extensions.json",
+			UserDataDir:    discoverUserDataDir(homeDir, models.EditorKiro),
 			CLICommand:     "",
 			IsVSCodeFamily: false,
 			IsCustom:       false,
@@ -81,9 +88,27 @@ func getDefaultExtensionsDir(homeDir, editorFolder string) string {
 	return filepath.Join(homeDir, editorFolder, "extensions")
 }
 
-// GetEditorProfile returns a specific editor profile by ID
+// getDefaultUserDataDir returns the default user data directory for an editor
+func getDefaultUserDataDir(homeDir, appFolder string) string {
+	switch runtime.GOOS {
+	case "windows":
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, appFolder)
+		}
+		return filepath.Join(homeDir, "AppData", "Roaming", appFolder)
+	case "darwin":
+		return filepath.Join(homeDir, "Library", "Application Support", appFolder)
+	default:
+		if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+			return filepath.Join(configHome, appFolder)
+		}
+		return filepath.Join(homeDir, ".config", appFolder)
+	}
+}
+
+// GetEditorProfile returns a specific editor profile by ID, including custom profiles
 func GetEditorProfile(editorType models.EditorType) (models.EditorProfile, error) {
-	profiles := GetDefaultEditorProfiles()
+	profiles := GetEditorProfiles()
 	for _, p := range profiles {
 		if p.ID == editorType {
 			return p, nil
@@ -92,6 +117,26 @@ func GetEditorProfile(editorType models.EditorType) (models.EditorProfile, error
 	return models.EditorProfile{}, fmt.Errorf("unknown editor type: %s", editorType)
 }
 
+// FindProfileByExtensionsDir returns the editor profile whose extensions directory is dir, if any
+func FindProfileByExtensionsDir(dir string) *models.EditorProfile {
+	dir = filepath.Clean(dir)
+	for _, p := range GetEditorProfiles() {
+		if samePath(filepath.Clean(p.ExtensionsDir), dir) {
+			profile := p
+			return &profile
+		}
+	}
+	return nil
+}
+
+// samePath compares two cleaned paths, ignoring case on Windows
+func samePath(a, b string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
 // CheckEditorStatus checks the availability and status of an editor
 func CheckEditorStatus(profile models.EditorProfile) models.EditorStatus {
 	status := models.EditorStatus{
@@ -108,14 +153,10 @@ func CheckEditorStatus(profile models.EditorProfile) models.EditorStatus {
 	}
 
 	// Check if index file exists
-	indexPath := filepath.Join(profile.ExtensionsDir, profile.IndexFile)
-	if _, err := os.Stat(indexPath); err == nil {
-		status.IndexFileExists = true
-	} else {
-		// Try fallback to extension.json (singular)
-		fallbackPath := filepath.Join(profile.ExtensionsDir, "extension.json")
-		if _, err := os.Stat(fallbackPath); err == nil {
+	for _, name := range profileIndexFiles(profile) {
+		if _, err := os.Stat(filepath.Join(profile.ExtensionsDir, name)); err == nil {
 			status.IndexFileExists = true
+			break
 		}
 	}
 
@@ -129,14 +170,7 @@ func CheckEditorStatus(profile models.EditorProfile) models.EditorStatus {
 	}
 
 	// Count extensions
-	entries, err := os.ReadDir(profile.ExtensionsDir)
-	if err == nil {
-		for _, entry := range entries {
-			if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
-				status.ExtensionCount++
-			}
-		}
-	}
+	status.ExtensionCount = countExtensionFolders(profile.ExtensionsDir)
 
 	status.IsAvailable = status.DirExists
 	return status
@@ -190,19 +224,41 @@ func GetCLIStatus() models.CLIStatus {
 	return status
 }
 
-// ReadExtensionsIndex reads and parses the extensions.json file
+// defaultIndexFile is the extensions index VS Code keeps in its extensions directory
+const defaultIndexFile = "extensions.json"
+
+// indexFiles returns the index file names of an extensions directory, preferred first:
+// the index file of the editor profile using the directory, or extensions.json
+func indexFiles(extensionsDir string) []string {
+	if profile := FindProfileByExtensionsDir(extensionsDir); profile != nil {
+		return profileIndexFiles(*profile)
+	}
+	return profileIndexFiles(models.EditorProfile{})
+}
+
+// profileIndexFiles returns the index file names of an editor profile, preferred first.
+// extensions.json falls back to the singular extension.json; a name that is not a plain
+// file name is ignored.
+func profileIndexFiles(profile models.EditorProfile) []string {
+	name := profile.IndexFile
+	if name == "" || name == defaultIndexFile || name != filepath.Base(name) || name == "." || name == ".." {
+		return []string{defaultIndexFile, "extension.json"}
+	}
+	return []string{name}
+}
+
+// ReadExtensionsIndex reads and parses the extensions index of an extensions directory
 func ReadExtensionsIndex(extensionsDir string) ([]models.ExtensionIndexEntry, error) {
-	// Try extensions.json first
-	indexPath := filepath.Join(extensionsDir, "extensions.json")
-	data, err := os.ReadFile(indexPath)
-	if err != nil {
-		// Try fallback to extension.json
-		indexPath = filepath.Join(extensionsDir, "extension.json")
-		data, err = os.ReadFile(indexPath)
-		if err != nil {
-			return nil, fmt.Errorf("no extensions index file found in %s", extensionsDir)
+	var data []byte
+	err := os.ErrNotExist
+	for _, name := range indexFiles(extensionsDir) {
+		if data, err = os.ReadFile(filepath.Join(extensionsDir, name)); err == nil {
+			break
 		}
 	}
+	if err != nil {
+		return nil, fmt.Errorf("no extensions index file found in %s", extensionsDir)
+	}
 
 	var entries []models.ExtensionIndexEntry
 	if err := json.Unmarshal(data, &entries); err != nil {
@@ -212,9 +268,9 @@ func ReadExtensionsIndex(extensionsDir string) ([]models.ExtensionIndexEntry, er
 	return entries, nil
 }
 
-// WriteExtensionsIndex writes the extensions.json file
+// WriteExtensionsIndex writes the extensions index of an extensions directory
 func WriteExtensionsIndex(extensionsDir string, entries []models.ExtensionIndexEntry) error {
-	indexPath := filepath.Join(extensionsDir, "extensions.json")
+	indexPath := filepath.Join(extensionsDir, indexFiles(extensionsDir)[0])
 
 	data, err := json.MarshalIndent(entries, "", "  ")
 	if err != nil {
@@ -235,6 +291,26 @@ func WriteExtensionsIndex(extensionsDir string, entries []models.ExtensionIndexE
 	return nil
 }
 
+// buildExtensionLocation builds the location block for an extension folder
+func buildExtensionLocation(folderPath string) models.ExtensionLocation {
+	// Normalize path for the platform
+	absPath, err := filepath.Abs(folderPath)
+	if err != nil {
+		absPath = folderPath
+	}
+	// Convert to forward slashes and ensure it starts with /
+	normalizedPath := filepath.ToSlash(absPath)
+	if !strings.HasPrefix(normalizedPath, "/") {
+		normalizedPath = "/" + normalizedPath
+	}
+
+	return models.ExtensionLocation{
+		Mid:    1,
+		Path:   normalizedPath,
+		Scheme: "file",
+	}
+}
+
 // FindExtensionEntry finds an extension entry by ID in the index
 func FindExtensionEntry(entries []models.ExtensionIndexEntry, extensionID string) *models.ExtensionIndexEntry {
 	extensionID = strings.ToLower(extensionID)
@@ -246,6 +322,56 @@ func FindExtensionEntry(entries []models.ExtensionIndexEntry, extensionID string
 	return nil
 }
 
+// InstalledExtensionFolder returns the folder of an extension's installed version as
+// recorded in the index
+func InstalledExtensionFolder(extensionsDir, extensionID string) (string, error) {
+	index, err := ReadExtensionsIndex(extensionsDir)
+	if err != nil {
+		return "", err
+	}
+	entry := FindExtensionEntry(index, extensionID)
+	if entry == nil {
+		return "", fmt.Errorf("%s is not in the extensions index", extensionID)
+	}
+	return extensionFolderPath(extensionsDir, entryFolderName(*entry))
+}
+
+// InstalledVersions returns the installed version of each extension keyed by lowercase ID.
+// The index decides the version; without an index the highest version on disk is used.
+// A missing extensions directory means nothing is installed.
+func InstalledVersions(extensionsDir string) (map[string]string, error) {
+	versions := map[string]string{}
+	if !isDir(extensionsDir) {
+		return versions, nil
+	}
+
+	index, err := ReadExtensionsIndex(extensionsDir)
+	if err == nil {
+		for _, entry := range index {
+			id := strings.ToLower(entry.Identifier.ID)
+			if current, ok := versions[id]; !ok || CompareVersions(entry.Version, current) > 0 {
+				versions[id] = entry.Version
+			}
+		}
+		return versions, nil
+	}
+	if indexFileExists(extensionsDir) {
+		return nil, err
+	}
+
+	folders, err := listExtensionFolders(extensionsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range folders {
+		id := strings.ToLower(folder.id)
+		if current, ok := versions[id]; !ok || CompareVersions(folder.version, current) > 0 {
+			versions[id] = folder.version
+		}
+	}
+	return versions, nil
+}
+
 // InstallExtensionViaCLI installs an extension using the VS Code CLI
 func InstallExtensionViaCLI(cliCommand string, extensionID string) error {
 	cliPath, err := findCLI(cliCommand)
@@ -336,3 +336,23 @@ func TestGetDefaultExtensionsDir(t *testing.T) {
 		t.Errorf("getDefaultExtensionsDir() = %s, want %s", result, expected)
 	}
 }
+
+func TestReadEngineVersion(t *testing.T) {
+	vscode := t.TempDir()
+	os.WriteFile(filepath.Join(vscode, "product.json"), []byte(`{"nameShort": "Code"}`), 0644)
+	os.WriteFile(filepath.Join(vscode, "package.json"), []byte(`{"name": "code-oss-dev", "version": "1.86.2"}`), 0644)
+	if got := readEngineVersion(vscode); got != "1.86.2" {
+		t.Errorf("VS Code engine = %q, want 1.86.2", got)
+	}
+
+	fork := t.TempDir()
+	os.WriteFile(filepath.Join(fork, "product.json"), []byte(`{"nameShort": "Cursor", "vscodeVersion": "1.85.1"}`), 0644)
+	os.WriteFile(filepath.Join(fork, "package.json"), []byte(`{"version": "0.42.0"}`), 0644)
+	if got := readEngineVersion(fork); got != "1.85.1" {
+		t.Errorf("fork engine = %q, want 1.85.1", got)
+	}
+
+	if got := readEngineVersion(t.TempDir()); got != "" {
+		t.Errorf("missing install engine = %q, want empty", got)
+	}
+}
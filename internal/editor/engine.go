@@ -0,0 +1,71 @@
+package editor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// EngineVersion returns the VS Code API version an editor implements (e.g. "1.86.2"), read
+// from the product.json and package.json of the install that provides its CLI. Forks such
+// as Cursor and Windsurf report the VS Code version they are based on. It returns "" when
+// the install cannot be found.
+func EngineVersion(profile models.EditorProfile) string {
+	cli := profile.CLICommand
+	if loc, ok := builtinLocations[profile.ID]; ok && loc.cli != "" {
+		cli = loc.cli
+	}
+	if cli == "" {
+		return ""
+	}
+
+	cliPath, err := findCLI(cli)
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(cliPath); err == nil {
+		cliPath = resolved
+	}
+
+	var appDir string
+	if runtime.GOOS == "darwin" {
+		// <Editor>.app/Contents/Resources/app/bin/code
+		if i := strings.Index(cliPath, ".app"+string(filepath.Separator)); i >= 0 {
+			appDir = filepath.Join(cliPath[:i+len(".app")], "Contents", "Resources", "app")
+		}
+	} else {
+		// <install>/bin/code next to <install>/resources/app
+		appDir = filepath.Join(filepath.Dir(filepath.Dir(cliPath)), "resources", "app")
+	}
+	if appDir == "" {
+		return ""
+	}
+	return readEngineVersion(appDir)
+}
+
+// readEngineVersion reads the VS Code version from an editor's resources/app folder
+func readEngineVersion(appDir string) string {
+	var product struct {
+		VSCodeVersion string `json:"vscodeVersion"`
+	}
+	if data, err := os.ReadFile(filepath.Join(appDir, "product.json")); err == nil {
+		if json.Unmarshal(data, &product) == nil && product.VSCodeVersion != "" {
+			return product.VSCodeVersion
+		}
+	}
+
+	// VS Code and VSCodium keep their version in package.json
+	var pkg struct {
+		Version string `json:"version"`
+	}
+	if data, err := os.ReadFile(filepath.Join(appDir, "package.json")); err == nil {
+		if json.Unmarshal(data, &pkg) == nil {
+			return pkg.Version
+		}
+	}
+	return ""
+}
@@ -0,0 +1,40 @@
+package editor
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// extensionIDPattern matches publisher.name extension IDs
+	extensionIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*\.[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	// namePattern matches a publisher or extension name
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	// versionPattern matches dotted numeric versions with an optional pre-release or build suffix
+	versionPattern = regexp.MustCompile(`^\d+(\.\d+){1,3}([-+][0-9A-Za-z.+-]+)?$`)
+	// targetPlatformPattern matches target platforms such as linux-x64
+	targetPlatformPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+// ValidExtensionID reports whether id is a publisher.name extension ID that is safe to
+// use in a file name
+func ValidExtensionID(id string) bool {
+	return extensionIDPattern.MatchString(id) && !strings.Contains(id, "..")
+}
+
+// ValidVersion reports whether version is shaped like an extension version and is safe
+// to use in a file name
+func ValidVersion(version string) bool {
+	return versionPattern.MatchString(version) && !strings.Contains(version, "..")
+}
+
+// ValidTargetPlatform reports whether platform is shaped like a target platform; empty
+// means the universal package
+func ValidTargetPlatform(platform string) bool {
+	return platform == "" || targetPlatformPattern.MatchString(platform)
+}
+
+// validName reports whether a publisher or extension name is safe to use in a file name
+func validName(name string) bool {
+	return namePattern.MatchString(name) && !strings.Contains(name, "..")
+}
@@ -0,0 +1,97 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// obsoleteFile is the marker file VS Code uses to track extension folders pending removal.
+// It maps extension keys (the folder name, e.g. publisher.name-1.0.0) to true; the editor
+// ignores listed folders and deletes them on its next start.
+const obsoleteFile = ".obsolete"
+
+// ReadObsoleteExtensions reads the .obsolete marker file of an extensions directory
+func ReadObsoleteExtensions(extensionsDir string) (map[string]bool, error) {
+	data, err := os.ReadFile(filepath.Join(extensionsDir, obsoleteFile))
+	if os.IsNotExist(err) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", obsoleteFile, err)
+	}
+
+	obsolete := map[string]bool{}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return obsolete, nil
+	}
+	if err := json.Unmarshal(data, &obsolete); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", obsoleteFile, err)
+	}
+	return obsolete, nil
+}
+
+// IsObsolete reports whether a folder is marked for removal (keys are compared case-insensitively)
+func IsObsolete(obsolete map[string]bool, folderName string) bool {
+	for key, marked := range obsolete {
+		if marked && strings.EqualFold(key, folderName) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeObsoleteExtensions writes the .obsolete marker file, removing it when empty
+func writeObsoleteExtensions(extensionsDir string, obsolete map[string]bool) error {
+	path := filepath.Join(extensionsDir, obsoleteFile)
+	if len(obsolete) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", obsoleteFile, err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(obsolete)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", obsoleteFile, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", obsoleteFile, err)
+	}
+	return nil
+}
+
+// markObsolete adds folders to the .obsolete marker file
+func markObsolete(extensionsDir string, folderNames ...string) error {
+	obsolete, err := ReadObsoleteExtensions(extensionsDir)
+	if err != nil {
+		return err
+	}
+	for _, name := range folderNames {
+		obsolete[name] = true
+	}
+	return writeObsoleteExtensions(extensionsDir, obsolete)
+}
+
+// unmarkObsolete removes folders from the .obsolete marker file
+func unmarkObsolete(extensionsDir string, folderNames ...string) error {
+	obsolete, err := ReadObsoleteExtensions(extensionsDir)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, name := range folderNames {
+		for key := range obsolete {
+			if strings.EqualFold(key, name) {
+				delete(obsolete, key)
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return writeObsoleteExtensions(extensionsDir, obsolete)
+}
@@ -0,0 +1,269 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+const (
+	// quarantineFolder is the config folder that quarantined extensions are moved to
+	quarantineFolder = "quarantine"
+	// quarantineRecordFile describes a quarantined extension inside its quarantine folder
+	quarantineRecordFile = "quarantine.json"
+)
+
+// QuarantineDir returns the folder holding quarantined extensions
+func QuarantineDir() (string, error) {
+	return config.Path(quarantineFolder)
+}
+
+// ReadExtensionIdentity returns the extension ID and version of an installed extension folder
+func ReadExtensionIdentity(folderPath string) (string, string, error) {
+	pkg, err := readExtensionPackageJSON(folderPath)
+	if err != nil {
+		return "", "", err
+	}
+	if pkg.Publisher == "" || pkg.Name == "" {
+		return "", "", fmt.Errorf("package.json in %s has no publisher or name", folderPath)
+	}
+	return pkg.Publisher + "." + pkg.Name, pkg.Version, nil
+}
+
+// QuarantineExtension moves every installed version of an extension out of the editor's
+// extensions directory into the vsynx quarantine and removes it from extensions.json.
+// The folders and index entries are recorded so RestoreQuarantined can put them back.
+func QuarantineExtension(profile models.EditorProfile, extensionID string, reason string, trustLevel models.TrustLevel) (*models.QuarantineRecord, error) {
+	// The ID may come from an untrusted package.json and names the quarantine folder
+	if !ValidExtensionID(extensionID) {
+		return nil, fmt.Errorf("invalid extension ID %q", extensionID)
+	}
+
+	index, err := ReadExtensionsIndex(profile.ExtensionsDir)
+	if err != nil && indexFileExists(profile.ExtensionsDir) {
+		return nil, err
+	}
+
+	now := time.Now()
+	record := &models.QuarantineRecord{
+		ExtensionID:   extensionID,
+		Editor:        profile.ID,
+		ExtensionsDir: profile.ExtensionsDir,
+		Folders:       []string{},
+		Reason:        reason,
+		TrustLevel:    trustLevel,
+		QuarantinedAt: now,
+	}
+
+	folders := map[string]bool{}
+	newIndex := make([]models.ExtensionIndexEntry, 0, len(index))
+	for _, entry := range index {
+		if strings.EqualFold(entry.Identifier.ID, extensionID) {
+			record.IndexEntries = append(record.IndexEntries, entry)
+			if folder := entryFolderName(entry); folder != "" {
+				folderPath, err := extensionFolderPath(profile.ExtensionsDir, folder)
+				if err != nil {
+					return nil, fmt.Errorf("refusing to quarantine %s: %w", extensionID, err)
+				}
+				if isDir(folderPath) {
+					folders[folder] = true
+				}
+			}
+			continue
+		}
+		newIndex = append(newIndex, entry)
+	}
+	for _, folder := range findExtensionFolders(profile.ExtensionsDir, extensionID) {
+		folders[folder] = true
+	}
+	if len(folders) == 0 {
+		return nil, fmt.Errorf("extension %s is not installed in %s", extensionID, profile.Name)
+	}
+	for folder := range folders {
+		record.Folders = append(record.Folders, folder)
+	}
+	sort.Strings(record.Folders)
+
+	quarantineDir, err := QuarantineDir()
+	if err != nil {
+		return nil, err
+	}
+	record.ID = fmt.Sprintf("%s-%s-%s", profile.ID, strings.ToLower(extensionID), now.Format("20060102T150405.000"))
+	record.Path = filepath.Join(quarantineDir, record.ID)
+	if filepath.Dir(record.Path) != filepath.Clean(quarantineDir) {
+		return nil, fmt.Errorf("refusing to quarantine %s: %s is outside the quarantine folder", extensionID, record.Path)
+	}
+	if err := os.MkdirAll(record.Path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create quarantine folder: %w", err)
+	}
+
+	// Record first so a partially moved extension can still be restored
+	if err := SaveQuarantineRecord(record); err != nil {
+		return nil, err
+	}
+	for _, folder := range record.Folders {
+		if err := moveDir(filepath.Join(profile.ExtensionsDir, folder), filepath.Join(record.Path, folder)); err != nil {
+			return record, fmt.Errorf("failed to move %s to quarantine: %w", folder, err)
+		}
+	}
+	if len(record.IndexEntries) > 0 {
+		if err := WriteExtensionsIndex(profile.ExtensionsDir, newIndex); err != nil {
+			return record, err
+		}
+	}
+	return record, nil
+}
+
+// ListQuarantined returns the quarantined extensions, oldest first
+func ListQuarantined() ([]models.QuarantineRecord, error) {
+	quarantineDir, err := QuarantineDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(quarantineDir)
+	if os.IsNotExist(err) {
+		return []models.QuarantineRecord{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quarantine folder: %w", err)
+	}
+
+	records := []models.QuarantineRecord{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		record, err := readQuarantineRecord(filepath.Join(quarantineDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		records = append(records, *record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].QuarantinedAt.Before(records[j].QuarantinedAt) })
+	return records, nil
+}
+
+// GetQuarantined returns a quarantined extension by its quarantine ID
+func GetQuarantined(quarantineID string) (*models.QuarantineRecord, error) {
+	if quarantineID == "" || quarantineID != filepath.Base(quarantineID) {
+		return nil, fmt.Errorf("invalid quarantine ID: %q", quarantineID)
+	}
+	quarantineDir, err := QuarantineDir()
+	if err != nil {
+		return nil, err
+	}
+	record, err := readQuarantineRecord(filepath.Join(quarantineDir, quarantineID))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no quarantined extension with ID %s", quarantineID)
+	}
+	return record, err
+}
+
+// RestoreQuarantined moves a quarantined extension back into its extensions directory
+// and re-adds its extensions.json entries
+func RestoreQuarantined(quarantineID string) (*models.QuarantineRecord, error) {
+	record, err := GetQuarantined(quarantineID)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := ReadExtensionsIndex(record.ExtensionsDir)
+	if err != nil && indexFileExists(record.ExtensionsDir) {
+		return nil, err
+	}
+	if len(record.IndexEntries) > 0 && FindExtensionEntry(index, record.ExtensionID) != nil {
+		return nil, fmt.Errorf("%s is installed again in %s; uninstall it before restoring", record.ExtensionID, record.Editor)
+	}
+	// The record is a file on disk; its folder names must not point outside either directory
+	for _, folder := range record.Folders {
+		if _, err := extensionFolderPath(record.ExtensionsDir, folder); err != nil {
+			return nil, fmt.Errorf("refusing to restore %s: %w", record.ExtensionID, err)
+		}
+	}
+	for _, folder := range record.Folders {
+		if _, err := os.Stat(filepath.Join(record.ExtensionsDir, folder)); err == nil {
+			return nil, fmt.Errorf("%s already exists in %s", folder, record.ExtensionsDir)
+		}
+	}
+
+	if err := os.MkdirAll(record.ExtensionsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create extensions directory: %w", err)
+	}
+	for _, folder := range record.Folders {
+		src := filepath.Join(record.Path, folder)
+		if !isDir(src) {
+			continue
+		}
+		if err := moveDir(src, filepath.Join(record.ExtensionsDir, folder)); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", folder, err)
+		}
+	}
+	if len(record.IndexEntries) > 0 {
+		if err := WriteExtensionsIndex(record.ExtensionsDir, append(index, record.IndexEntries...)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.RemoveAll(record.Path); err != nil {
+		return record, fmt.Errorf("restored, but failed to remove quarantine folder: %w", err)
+	}
+	return record, nil
+}
+
+// DeleteQuarantined permanently deletes a quarantined extension
+func DeleteQuarantined(quarantineID string) (*models.QuarantineRecord, error) {
+	record, err := GetQuarantined(quarantineID)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(record.Path); err != nil {
+		return nil, fmt.Errorf("failed to delete quarantined extension: %w", err)
+	}
+	return record, nil
+}
+
+// SaveQuarantineRecord saves a record into its quarantine folder
+func SaveQuarantineRecord(record *models.QuarantineRecord) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal quarantine record: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(record.Path, quarantineRecordFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write quarantine record: %w", err)
+	}
+	return nil
+}
+
+// readQuarantineRecord loads the record of a quarantine folder
+func readQuarantineRecord(dir string) (*models.QuarantineRecord, error) {
+	data, err := os.ReadFile(filepath.Join(dir, quarantineRecordFile))
+	if err != nil {
+		return nil, err
+	}
+	var record models.QuarantineRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse quarantine record in %s: %w", dir, err)
+	}
+	record.ID = filepath.Base(dir)
+	record.Path = dir
+	return &record, nil
+}
+
+// moveDir moves a directory, copying it when a rename is not possible (e.g. across devices)
+func moveDir(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyDir(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
@@ -0,0 +1,126 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+func TestQuarantineAndRestore(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	extensionsDir := t.TempDir()
+	profile := models.EditorProfile{ID: models.EditorVSCode, Name: "VS Code", ExtensionsDir: extensionsDir}
+
+	writeTestExtensionFolder(t, extensionsDir, "bad.ext-1.0.0", "bad", "ext", "1.0.0")
+	writeTestExtensionFolder(t, extensionsDir, "pub.keep-2.0.0", "pub", "keep", "2.0.0")
+	WriteExtensionsIndex(extensionsDir, []models.ExtensionIndexEntry{
+		{Identifier: models.ExtensionIdentifier{ID: "bad.ext"}, Version: "1.0.0", RelativeLocation: "bad.ext-1.0.0",
+			Location: buildExtensionLocation(filepath.Join(extensionsDir, "bad.ext-1.0.0"))},
+		{Identifier: models.ExtensionIdentifier{ID: "pub.keep"}, Version: "2.0.0", RelativeLocation: "pub.keep-2.0.0",
+			Location: buildExtensionLocation(filepath.Join(extensionsDir, "pub.keep-2.0.0"))},
+	})
+
+	record, err := QuarantineExtension(profile, "bad.ext", "test", models.TrustLevelMalicious)
+	if err != nil {
+		t.Fatalf("QuarantineExtension failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(extensionsDir, "bad.ext-1.0.0")); !os.IsNotExist(err) {
+		t.Error("Quarantined folder should be gone from the extensions directory")
+	}
+	if _, err := os.Stat(filepath.Join(record.Path, "bad.ext-1.0.0", "package.json")); err != nil {
+		t.Errorf("Folder was not moved into quarantine: %v", err)
+	}
+	index, _ := ReadExtensionsIndex(extensionsDir)
+	if FindExtensionEntry(index, "bad.ext") != nil || FindExtensionEntry(index, "pub.keep") == nil {
+		t.Errorf("Unexpected index after quarantine: %+v", index)
+	}
+
+	records, err := ListQuarantined()
+	if err != nil || len(records) != 1 || records[0].ID != record.ID {
+		t.Fatalf("ListQuarantined = %+v, %v", records, err)
+	}
+	if records[0].TrustLevel != models.TrustLevelMalicious || len(records[0].IndexEntries) != 1 {
+		t.Errorf("Record metadata not saved: %+v", records[0])
+	}
+
+	if _, err := RestoreQuarantined(record.ID); err != nil {
+		t.Fatalf("RestoreQuarantined failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(extensionsDir, "bad.ext-1.0.0", "package.json")); err != nil {
+		t.Errorf("Folder was not restored: %v", err)
+	}
+	index, _ = ReadExtensionsIndex(extensionsDir)
+	if FindExtensionEntry(index, "bad.ext") == nil {
+		t.Error("Index entry was not restored")
+	}
+	if records, _ := ListQuarantined(); len(records) != 0 {
+		t.Errorf("Quarantine should be empty after restore, got %+v", records)
+	}
+}
+
+func TestQuarantineNotInstalled(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	profile := models.EditorProfile{ID: models.EditorVSCode, Name: "VS Code", ExtensionsDir: t.TempDir()}
+	if _, err := QuarantineExtension(profile, "pub.missing", "", ""); err == nil {
+		t.Error("Expected an error for an extension that is not installed")
+	}
+	if _, err := GetQuarantined("../escape"); err == nil {
+		t.Error("Expected an error for a quarantine ID with a path")
+	}
+}
+
+func TestQuarantineRejectsHostileLocation(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	extensionsDir := filepath.Join(t.TempDir(), "extensions")
+	os.MkdirAll(extensionsDir, 0755)
+	profile := models.EditorProfile{ID: models.EditorVSCode, Name: "VS Code", ExtensionsDir: extensionsDir}
+
+	WriteExtensionsIndex(extensionsDir, []models.ExtensionIndexEntry{
+		{Identifier: models.ExtensionIdentifier{ID: "bad.ext"}, Version: "1.0.0", RelativeLocation: ".."},
+	})
+	if _, err := QuarantineExtension(profile, "bad.ext", "test", models.TrustLevelMalicious); err == nil {
+		t.Fatal("Expected an error for relativeLocation \"..\"")
+	}
+	if _, err := os.Stat(extensionsDir); err != nil {
+		t.Fatalf("Extensions directory was moved: %v", err)
+	}
+
+	// A tampered record must not restore outside the extensions directory
+	writeTestExtensionFolder(t, extensionsDir, "bad.ext-1.0.0", "bad", "ext", "1.0.0")
+	WriteExtensionsIndex(extensionsDir, nil)
+	record, err := QuarantineExtension(profile, "bad.ext", "test", models.TrustLevelMalicious)
+	if err != nil {
+		t.Fatalf("QuarantineExtension failed: %v", err)
+	}
+	record.Folders = []string{"../escaped"}
+	if err := SaveQuarantineRecord(record); err != nil {
+		t.Fatalf("SaveQuarantineRecord failed: %v", err)
+	}
+	if _, err := RestoreQuarantined(record.ID); err == nil {
+		t.Error("Expected an error restoring a folder outside the extensions directory")
+	}
+}
+
+func TestQuarantineRejectsHostileID(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv(config.DirEnvVar, configDir)
+	extensionsDir := filepath.Join(t.TempDir(), "extensions")
+	os.MkdirAll(extensionsDir, 0755)
+	profile := models.EditorProfile{ID: models.EditorVSCode, Name: "VS Code", ExtensionsDir: extensionsDir}
+
+	writeTestExtensionFolder(t, extensionsDir, "evil-1.0.0", "..", "..", "1.0.0")
+	for _, id := range []string{"../../x", "pub.../x", "pub.a/b", `pub.a\b`} {
+		if _, err := QuarantineExtension(profile, id, "test", models.TrustLevelMalicious); err == nil {
+			t.Errorf("Expected an error for extension ID %q", id)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(configDir, quarantineFolder)); !os.IsNotExist(err) {
+		t.Errorf("Quarantine folder should not have been created, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(extensionsDir, "evil-1.0.0")); err != nil {
+		t.Errorf("Extension folder was moved: %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+package editor
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// orphanGracePeriod protects freshly written folders that the editor may not have indexed yet
+const orphanGracePeriod = 10 * time.Minute
+
+// extensionFolder is an extension folder on disk identified by its package.json
+type extensionFolder struct {
+	name    string
+	id      string
+	version string
+}
+
+// ReconcileExtensions compares the extension folders on disk with extensions.json.
+// The active version of each extension is the folder referenced by the index (or the
+// highest version when there is no index). Other versions of an indexed extension are
+// stale, folders for extensions missing from the index are orphaned, and index entries
+// whose folder does not exist are reported as missing.
+func ReconcileExtensions(extensionsDir string) (*models.ReconcileReport, error) {
+	return reconcileExtensions(extensionsDir, true)
+}
+
+// ActiveExtensionFolders returns the active folder of each extension by lowercase ID,
+// without walking the other folders to size them
+func ActiveExtensionFolders(extensionsDir string) (map[string]string, error) {
+	report, err := reconcileExtensions(extensionsDir, false)
+	if err != nil {
+		return nil, err
+	}
+	return report.ActiveFolders, nil
+}
+
+// reconcileExtensions builds the reconcile report, measuring the stale and orphaned
+// folders only when withSizes is set
+func reconcileExtensions(extensionsDir string, withSizes bool) (*models.ReconcileReport, error) {
+	index, err := ReadExtensionsIndex(extensionsDir)
+	if err != nil && indexFileExists(extensionsDir) {
+		return nil, err
+	}
+
+	report := &models.ReconcileReport{
+		ExtensionsDir:   extensionsDir,
+		IndexFound:      err == nil,
+		ActiveFolders:   map[string]string{},
+		StaleFolders:    []models.ExtensionFolder{},
+		OrphanedFolders: []models.ExtensionFolder{},
+		MissingFolders:  []models.ExtensionIndexEntry{},
+	}
+
+	folders, err := listExtensionFolders(extensionsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	obsolete, err := ReadObsoleteExtensions(extensionsDir)
+	if err != nil {
+		obsolete = map[string]bool{}
+	}
+
+	// Index entries decide the active folder of each extension
+	activeVersions := map[string]string{}
+	for _, entry := range index {
+		folderName := entryFolderName(entry)
+		if _, err := os.Stat(filepath.Join(extensionsDir, folderName)); err != nil {
+			report.MissingFolders = append(report.MissingFolders, entry)
+			continue
+		}
+		id := strings.ToLower(entry.Identifier.ID)
+		if current, ok := activeVersions[id]; ok && CompareVersions(current, entry.Version) >= 0 {
+			continue
+		}
+		report.ActiveFolders[id] = folderName
+		activeVersions[id] = entry.Version
+	}
+
+	// Without an index, the highest version on disk is the active one
+	if !report.IndexFound {
+		for _, folder := range folders {
+			if IsObsolete(obsolete, folder.name) {
+				continue
+			}
+			id := strings.ToLower(folder.id)
+			if current, ok := activeVersions[id]; ok && CompareVersions(current, folder.version) >= 0 {
+				continue
+			}
+			report.ActiveFolders[id] = folder.name
+			activeVersions[id] = folder.version
+		}
+	}
+
+	for _, folder := range folders {
+		id := strings.ToLower(folder.id)
+		if strings.EqualFold(report.ActiveFolders[id], folder.name) {
+			continue
+		}
+
+		entry := models.ExtensionFolder{
+			Folder:      folder.name,
+			ExtensionID: folder.id,
+			Version:     folder.version,
+		}
+		if withSizes {
+			entry.SizeBytes = dirSize(filepath.Join(extensionsDir, folder.name))
+		}
+
+		switch {
+		case IsObsolete(obsolete, folder.name):
+			entry.Reason = "marked obsolete by the editor"
+			report.StaleFolders = append(report.StaleFolders, entry)
+		case activeVersions[id] != "":
+			entry.Reason = fmt.Sprintf("superseded by active version %s", activeVersions[id])
+			report.StaleFolders = append(report.StaleFolders, entry)
+		default:
+			entry.Reason = "not listed in extensions.json"
+			report.OrphanedFolders = append(report.OrphanedFolders, entry)
+		}
+		report.ReclaimableBytes += entry.SizeBytes
+	}
+
+	return report, nil
+}
+
+// CleanExtensions removes stale and orphaned extension folders and prunes index entries
+// whose folder is missing. With dryRun set nothing is changed and the result lists what
+// would be removed. Recently modified orphans are skipped in case an install is in progress.
+func CleanExtensions(extensionsDir string, dryRun bool) (*models.CleanResult, error) {
+	report, err := ReconcileExtensions(extensionsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.CleanResult{
+		ExtensionsDir:  extensionsDir,
+		DryRun:         dryRun,
+		RemovedFolders: []models.ExtensionFolder{},
+	}
+
+	candidates := append([]models.ExtensionFolder{}, report.StaleFolders...)
+	for _, orphan := range report.OrphanedFolders {
+		info, err := os.Stat(filepath.Join(extensionsDir, orphan.Folder))
+		if err == nil && time.Since(info.ModTime()) < orphanGracePeriod {
+			orphan.Reason = "modified recently; may be an install in progress"
+			result.SkippedFolders = append(result.SkippedFolders, orphan)
+			continue
+		}
+		candidates = append(candidates, orphan)
+	}
+
+	for _, missing := range report.MissingFolders {
+		result.PrunedEntries = append(result.PrunedEntries, fmt.Sprintf("%s@%s", missing.Identifier.ID, missing.Version))
+	}
+
+	if dryRun {
+		result.RemovedFolders = candidates
+		for _, folder := range candidates {
+			result.ReclaimedBytes += folder.SizeBytes
+		}
+		return result, nil
+	}
+
+	// Drop index entries that point at folders which no longer exist
+	if len(report.MissingFolders) > 0 {
+		index, err := ReadExtensionsIndex(extensionsDir)
+		if err != nil {
+			return nil, err
+		}
+		newIndex := make([]models.ExtensionIndexEntry, 0, len(index))
+		for _, entry := range index {
+			if _, err := os.Stat(filepath.Join(extensionsDir, entryFolderName(entry))); err != nil {
+				continue
+			}
+			newIndex = append(newIndex, entry)
+		}
+		if err := WriteExtensionsIndex(extensionsDir, newIndex); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(candidates) == 0 {
+		return result, nil
+	}
+
+	folderNames := make([]string, 0, len(candidates))
+	for _, folder := range candidates {
+		folderNames = append(folderNames, folder.Folder)
+	}
+	if err := markObsolete(extensionsDir, folderNames...); err != nil {
+		return nil, err
+	}
+
+	removed := make([]string, 0, len(candidates))
+	for _, folder := range candidates {
+		if err := os.RemoveAll(filepath.Join(extensionsDir, folder.Folder)); err != nil {
+			result.PendingFolders = append(result.PendingFolders, folder.Folder)
+			continue
+		}
+		removed = append(removed, folder.Folder)
+		result.RemovedFolders = append(result.RemovedFolders, folder)
+		result.ReclaimedBytes += folder.SizeBytes
+	}
+
+	if err := unmarkObsolete(extensionsDir, removed...); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// listExtensionFolders returns every extension folder with a readable package.json, sorted by name
+func listExtensionFolders(extensionsDir string) ([]extensionFolder, error) {
+	entries, err := os.ReadDir(extensionsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read extensions directory: %w", err)
+	}
+
+	var folders []extensionFolder
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		pkg, err := readExtensionPackageJSON(filepath.Join(extensionsDir, entry.Name()))
+		if err != nil || pkg.Publisher == "" || pkg.Name == "" {
+			continue
+		}
+		folders = append(folders, extensionFolder{
+			name:    entry.Name(),
+			id:      pkg.Publisher + "." + pkg.Name,
+			version: pkg.Version,
+		})
+	}
+
+	sort.Slice(folders, func(i, j int) bool { return folders[i].name < folders[j].name })
+	return folders, nil
+}
+
+// entryFolderName returns the folder an index entry points at
+func entryFolderName(entry models.ExtensionIndexEntry) string {
+	if entry.RelativeLocation != "" {
+		return entry.RelativeLocation
+	}
+	return path.Base(entry.Location.Path)
+}
+
+// dirSize returns the total size of the files below a directory
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
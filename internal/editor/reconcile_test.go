@@ -0,0 +1,177 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// writeTestExtensionFolder creates an extension folder with a package.json
+func writeTestExtensionFolder(t *testing.T, extensionsDir, folder, publisher, name, version string) {
+	t.Helper()
+	dir := filepath.Join(extensionsDir, folder)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create extension folder: %v", err)
+	}
+	pkg := fmt.Sprintf(`{"publisher": %q, "name": %q, "version": %q}`, publisher, name, version)
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(pkg), 0644); err != nil {
+		t.Fatalf("Failed to write package.json: %v", err)
+	}
+	// Age the folder past the orphan grace period
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(dir, old, old)
+}
+
+func TestReconcileExtensions(t *testing.T) {
+	extensionsDir := t.TempDir()
+	writeTestExtensionFolder(t, extensionsDir, "pub.ext-1.2.0", "pub", "ext", "1.2.0")
+	writeTestExtensionFolder(t, extensionsDir, "pub.ext-1.3.0", "pub", "ext", "1.3.0")
+	writeTestExtensionFolder(t, extensionsDir, "pub.orphan-0.1.0", "pub", "orphan", "0.1.0")
+
+	WriteExtensionsIndex(extensionsDir, []models.ExtensionIndexEntry{
+		{Identifier: models.ExtensionIdentifier{ID: "pub.ext"}, Version: "1.3.0", RelativeLocation: "pub.ext-1.3.0"},
+		{Identifier: models.ExtensionIdentifier{ID: "pub.gone"}, Version: "2.0.0", RelativeLocation: "pub.gone-2.0.0"},
+	})
+
+	report, err := ReconcileExtensions(extensionsDir)
+	if err != nil {
+		t.Fatalf("ReconcileExtensions failed: %v", err)
+	}
+
+	if report.ActiveFolders["pub.ext"] != "pub.ext-1.3.0" {
+		t.Errorf("Active folder = %s, want pub.ext-1.3.0", report.ActiveFolders["pub.ext"])
+	}
+	if len(report.StaleFolders) != 1 || report.StaleFolders[0].Folder != "pub.ext-1.2.0" {
+		t.Errorf("Expected pub.ext-1.2.0 to be stale, got %+v", report.StaleFolders)
+	}
+	if len(report.OrphanedFolders) != 1 || report.OrphanedFolders[0].Folder != "pub.orphan-0.1.0" {
+		t.Errorf("Expected pub.orphan-0.1.0 to be orphaned, got %+v", report.OrphanedFolders)
+	}
+	if len(report.MissingFolders) != 1 || report.MissingFolders[0].Identifier.ID != "pub.gone" {
+		t.Errorf("Expected pub.gone to be missing, got %+v", report.MissingFolders)
+	}
+	if report.ReclaimableBytes == 0 {
+		t.Error("Expected the stale and orphaned folders to be sized")
+	}
+
+	active, err := ActiveExtensionFolders(extensionsDir)
+	if err != nil {
+		t.Fatalf("ActiveExtensionFolders failed: %v", err)
+	}
+	if len(active) != 1 || active["pub.ext"] != "pub.ext-1.3.0" {
+		t.Errorf("ActiveExtensionFolders = %v, want pub.ext -> pub.ext-1.3.0", active)
+	}
+}
+
+func TestReconcileExtensionsWithoutIndex(t *testing.T) {
+	extensionsDir := t.TempDir()
+	writeTestExtensionFolder(t, extensionsDir, "pub.ext-1.10.0", "pub", "ext", "1.10.0")
+	writeTestExtensionFolder(t, extensionsDir, "pub.ext-1.9.0", "pub", "ext", "1.9.0")
+
+	report, err := ReconcileExtensions(extensionsDir)
+	if err != nil {
+		t.Fatalf("ReconcileExtensions failed: %v", err)
+	}
+
+	if report.IndexFound {
+		t.Error("IndexFound should be false")
+	}
+	if report.ActiveFolders["pub.ext"] != "pub.ext-1.10.0" {
+		t.Errorf("Active folder = %s, want pub.ext-1.10.0", report.ActiveFolders["pub.ext"])
+	}
+	if len(report.OrphanedFolders) != 0 {
+		t.Errorf("Expected no orphans without an index, got %+v", report.OrphanedFolders)
+	}
+}
+
+func TestCleanExtensions(t *testing.T) {
+	extensionsDir := t.TempDir()
+	writeTestExtensionFolder(t, extensionsDir, "pub.ext-1.2.0", "pub", "ext", "1.2.0")
+	writeTestExtensionFolder(t, extensionsDir, "pub.ext-1.3.0", "pub", "ext", "1.3.0")
+	writeTestExtensionFolder(t, extensionsDir, "pub.orphan-0.1.0", "pub", "orphan", "0.1.0")
+
+	WriteExtensionsIndex(extensionsDir, []models.ExtensionIndexEntry{
+		{Identifier: models.ExtensionIdentifier{ID: "pub.ext"}, Version: "1.3.0", RelativeLocation: "pub.ext-1.3.0"},
+		{Identifier: models.ExtensionIdentifier{ID: "pub.gone"}, Version: "2.0.0", RelativeLocation: "pub.gone-2.0.0"},
+	})
+
+	// Dry run changes nothing
+	preview, err := CleanExtensions(extensionsDir, true)
+	if err != nil {
+		t.Fatalf("CleanExtensions dry run failed: %v", err)
+	}
+	if len(preview.RemovedFolders) != 2 {
+		t.Errorf("Expected 2 folders in preview, got %+v", preview.RemovedFolders)
+	}
+	if _, err := os.Stat(filepath.Join(extensionsDir, "pub.ext-1.2.0")); err != nil {
+		t.Error("Dry run should not remove folders")
+	}
+
+	result, err := CleanExtensions(extensionsDir, false)
+	if err != nil {
+		t.Fatalf("CleanExtensions failed: %v", err)
+	}
+	if len(result.RemovedFolders) != 2 {
+		t.Errorf("Expected 2 removed folders, got %+v", result.RemovedFolders)
+	}
+	if _, err := os.Stat(filepath.Join(extensionsDir, "pub.ext-1.3.0")); err != nil {
+		t.Error("Active version must not be removed")
+	}
+
+	entries, _ := ReadExtensionsIndex(extensionsDir)
+	if len(entries) != 1 || entries[0].Identifier.ID != "pub.ext" {
+		t.Errorf("Expected missing-folder entry to be pruned, got %+v", entries)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.2.0", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0", "1.0.0", 0},
+		{"1.0.0-beta.1", "1.0.0", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.10", -1},
+		{"v1.2.3", "1.2.3", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			if got := CompareVersions(tt.a, tt.b); got != tt.expected {
+				t.Errorf("CompareVersions(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEngineCompatible(t *testing.T) {
+	tests := []struct {
+		engine, editor string
+		expected       bool
+	}{
+		{"^1.80.0", "1.86.2", true},
+		{"^1.90.0", "1.86.2", false},
+		{">=1.80.0", "1.80.0", true},
+		{"^1.86.0-insider", "1.86.0", true},
+		{"^1.86.x", "1.86.1", true},
+		{"^2.0.0", "1.99.0", false},
+		{"*", "1.0.0", true},
+		{"", "1.0.0", true},
+		{"^1.99.0", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.engine+"_on_"+tt.editor, func(t *testing.T) {
+			if got := EngineCompatible(tt.engine, tt.editor); got != tt.expected {
+				t.Errorf("EngineCompatible(%q, %q) = %v, want %v", tt.engine, tt.editor, got, tt.expected)
+			}
+		})
+	}
+}
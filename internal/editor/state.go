@@ -0,0 +1,130 @@
+package editor
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// disabledExtensionsKey is the global storage key holding globally disabled extensions
+	disabledExtensionsKey = "extensionsIdentifiers/disabled"
+)
+
+// StateDBPath returns the path to the editor's global storage database
+func StateDBPath(profile models.EditorProfile) string {
+	if profile.UserDataDir == "" {
+		return ""
+	}
+	return filepath.Join(profile.UserDataDir, "User", "globalStorage", "state.vscdb")
+}
+
+// ReadDisabledExtensions returns the extensions disabled in the editor's global storage.
+// A missing state database means nothing is disabled.
+func ReadDisabledExtensions(profile models.EditorProfile) ([]models.ExtensionIdentifier, error) {
+	dbPath := StateDBPath(profile)
+	if dbPath == "" {
+		return []models.ExtensionIdentifier{}, nil
+	}
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return []models.ExtensionIdentifier{}, nil
+	}
+
+	db, err := sql.Open("sqlite", "file:"+filepath.ToSlash(dbPath)+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database: %w", err)
+	}
+	defer db.Close()
+
+	return readDisabledIdentifiers(db)
+}
+
+// SetExtensionEnabled enables or disables an extension in the editor's global storage.
+// The editor should be closed, since it keeps this state in memory and rewrites it on exit.
+func SetExtensionEnabled(profile models.EditorProfile, extensionID string, enabled bool) error {
+	dbPath := StateDBPath(profile)
+	if dbPath == "" {
+		return fmt.Errorf("editor %s has no user data directory configured", profile.ID)
+	}
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return fmt.Errorf("failed to create global storage directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+filepath.ToSlash(dbPath))
+	if err != nil {
+		return fmt.Errorf("failed to open state database: %w", err)
+	}
+	defer db.Close()
+
+	// Same schema VS Code uses for its storage databases
+	if _, err := db.Exec("CREATE TABLE IF NOT EXISTS ItemTable (key TEXT UNIQUE ON CONFLICT REPLACE, value BLOB)"); err != nil {
+		return fmt.Errorf("failed to prepare state database: %w", err)
+	}
+
+	disabled, err := readDisabledIdentifiers(db)
+	if err != nil {
+		return err
+	}
+
+	updated := make([]models.ExtensionIdentifier, 0, len(disabled)+1)
+	for _, id := range disabled {
+		if !strings.EqualFold(id.ID, extensionID) {
+			updated = append(updated, id)
+		}
+	}
+
+	if !enabled {
+		identifier := models.ExtensionIdentifier{ID: strings.ToLower(extensionID)}
+		// Keep the gallery UUID alongside the ID like the editor does
+		if index, err := ReadExtensionsIndex(profile.ExtensionsDir); err == nil {
+			if entry := FindExtensionEntry(index, extensionID); entry != nil {
+				identifier.UUID = entry.Identifier.UUID
+			}
+		}
+		updated = append(updated, identifier)
+	}
+
+	// The editor removes the key entirely once nothing is disabled
+	if len(updated) == 0 {
+		if _, err := db.Exec("DELETE FROM ItemTable WHERE key = ?", disabledExtensionsKey); err != nil {
+			return fmt.Errorf("failed to update state database: %w", err)
+		}
+		return nil
+	}
+
+	value, err := json.Marshal(updated)
+	if err != nil {
+		return fmt.Errorf("failed to marshal disabled extensions: %w", err)
+	}
+	if _, err := db.Exec("INSERT OR REPLACE INTO ItemTable (key, value) VALUES (?, ?)", disabledExtensionsKey, string(value)); err != nil {
+		return fmt.Errorf("failed to update state database: %w", err)
+	}
+	return nil
+}
+
+// readDisabledIdentifiers reads the disabled extensions list from an open state database
+func readDisabledIdentifiers(db *sql.DB) ([]models.ExtensionIdentifier, error) {
+	var value []byte
+	err := db.QueryRow("SELECT value FROM ItemTable WHERE key = ?", disabledExtensionsKey).Scan(&value)
+	if err == sql.ErrNoRows {
+		return []models.ExtensionIdentifier{}, nil
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "no such table") {
+			return []models.ExtensionIdentifier{}, nil
+		}
+		return nil, fmt.Errorf("failed to query state database: %w", err)
+	}
+
+	var disabled []models.ExtensionIdentifier
+	if err := json.Unmarshal(value, &disabled); err != nil {
+		return nil, fmt.Errorf("failed to parse disabled extensions: %w", err)
+	}
+	return disabled, nil
+}
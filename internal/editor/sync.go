@@ -121,17 +121,6 @@ func syncToTarget(sourceProfile models.EditorProfile, sourceIndex []models.Exten
 		}
 
 		// Create new index entry for target
-		// Normalize path for the platform
-		absPath, err := filepath.Abs(targetFolderPath)
-		if err != nil {
-			absPath = targetFolderPath
-		}
-		// Convert to forward slashes and ensure it starts with /
-		normalizedPath := filepath.ToSlash(absPath)
-		if !strings.HasPrefix(normalizedPath, "/") {
-			normalizedPath = "/" + normalizedPath
-		}
-
 		newEntry := models.ExtensionIndexEntry{
 			Identifier: models.ExtensionIdentifier{
 				ID:   sourceEntry.Identifier.ID,
@@ -139,12 +128,8 @@ func syncToTarget(sourceProfile models.EditorProfile, sourceIndex []models.Exten
 			},
 			Version:          sourceEntry.Version,
 			RelativeLocation: sourceEntry.RelativeLocation,
-			Location: models.ExtensionLocation{
-				Mid:    1,
-				Path:   normalizedPath,
-				Scheme: "file",
-			},
-			Metadata: sourceEntry.Metadata,
+			Location:         buildExtensionLocation(targetFolderPath),
+			Metadata:         sourceEntry.Metadata,
 		}
 		entriesToAdd = append(entriesToAdd, newEntry)
 		result.CopiedCount++
@@ -0,0 +1,145 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// UninstallExtension removes an extension from an editor. Every installed version is
+// removed from extensions.json and deleted from disk. Folders are marked in .obsolete
+// before deletion so that anything that cannot be deleted now (for example because the
+// editor has files open) is cleaned up by the editor on its next start.
+func UninstallExtension(profile models.EditorProfile, extensionID string) (*models.UninstallResult, error) {
+	result := &models.UninstallResult{
+		ExtensionID:     extensionID,
+		TargetEditor:    profile.ID,
+		RemovedVersions: []string{},
+		RemovedFolders:  []string{},
+	}
+
+	index, err := ReadExtensionsIndex(profile.ExtensionsDir)
+	if err != nil && indexFileExists(profile.ExtensionsDir) {
+		return nil, err
+	}
+
+	// Split the index into entries to keep and folders to remove
+	folders := map[string]bool{}
+	newIndex := make([]models.ExtensionIndexEntry, 0, len(index))
+	for _, entry := range index {
+		if strings.EqualFold(entry.Identifier.ID, extensionID) {
+			result.RemovedVersions = append(result.RemovedVersions, entry.Version)
+			if entry.RelativeLocation != "" {
+				if _, err := extensionFolderPath(profile.ExtensionsDir, entry.RelativeLocation); err != nil {
+					return nil, fmt.Errorf("refusing to uninstall %s: %w", extensionID, err)
+				}
+				folders[entry.RelativeLocation] = true
+			}
+			continue
+		}
+		newIndex = append(newIndex, entry)
+	}
+
+	// Also pick up folders that are not (or no longer) in the index
+	for _, folder := range findExtensionFolders(profile.ExtensionsDir, extensionID) {
+		folders[folder] = true
+	}
+
+	if len(folders) == 0 && len(result.RemovedVersions) == 0 {
+		return nil, fmt.Errorf("extension %s is not installed in %s", extensionID, profile.Name)
+	}
+
+	folderNames := make([]string, 0, len(folders))
+	for folder := range folders {
+		folderNames = append(folderNames, folder)
+	}
+
+	if err := markObsolete(profile.ExtensionsDir, folderNames...); err != nil {
+		return nil, err
+	}
+
+	if len(newIndex) != len(index) {
+		if err := WriteExtensionsIndex(profile.ExtensionsDir, newIndex); err != nil {
+			return nil, err
+		}
+		result.IndexUpdated = true
+	}
+
+	removed := make([]string, 0, len(folderNames))
+	for _, folder := range folderNames {
+		folderPath, err := extensionFolderPath(profile.ExtensionsDir, folder)
+		if err != nil {
+			result.PendingFolders = append(result.PendingFolders, folder)
+			continue
+		}
+		if err := os.RemoveAll(folderPath); err != nil {
+			result.PendingFolders = append(result.PendingFolders, folder)
+			continue
+		}
+		removed = append(removed, folder)
+	}
+	result.RemovedFolders = removed
+
+	// Folders that are gone no longer need an obsolete marker
+	if err := unmarkObsolete(profile.ExtensionsDir, removed...); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// extensionFolderPath returns the path of an extension folder named in the index,
+// refusing names that would resolve outside the extensions directory
+func extensionFolderPath(extensionsDir, folder string) (string, error) {
+	if !isValidRelativeLocation(folder) {
+		return "", fmt.Errorf("invalid extension folder %q", folder)
+	}
+	base := filepath.Clean(extensionsDir)
+	folderPath := filepath.Join(base, folder)
+	if filepath.Dir(folderPath) != base {
+		return "", fmt.Errorf("extension folder %q is outside %s", folder, base)
+	}
+	return folderPath, nil
+}
+
+// findExtensionFolders returns folders in the extensions directory whose package.json
+// identifies them as the given extension
+func findExtensionFolders(extensionsDir string, extensionID string) []string {
+	entries, err := os.ReadDir(extensionsDir)
+	if err != nil {
+		return nil
+	}
+
+	prefix := strings.ToLower(extensionID) + "-"
+	var folders []string
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(strings.ToLower(entry.Name()), prefix) {
+			continue
+		}
+		pkg, err := readExtensionPackageJSON(filepath.Join(extensionsDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(pkg.Publisher+"."+pkg.Name, extensionID) {
+			folders = append(folders, entry.Name())
+		}
+	}
+	return folders
+}
+
+// readExtensionPackageJSON reads the package.json of an installed extension folder
+func readExtensionPackageJSON(folderPath string) (*extensionPackageJSON, error) {
+	data, err := os.ReadFile(filepath.Join(folderPath, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	var pkg extensionPackageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
@@ -0,0 +1,170 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+func TestUninstallExtension(t *testing.T) {
+	extensionsDir := t.TempDir()
+	profile := models.EditorProfile{
+		ID:            models.EditorCursor,
+		Name:          "Test Editor",
+		ExtensionsDir: extensionsDir,
+	}
+
+	for _, data := range [][]byte{
+		buildTestVSIX(t, "pub", "ext", "1.0.0", nil),
+		buildTestVSIX(t, "pub", "other", "1.0.0", nil),
+	} {
+		if _, err := InstallVSIX(profile, data, "vsix", ""); err != nil {
+			t.Fatalf("InstallVSIX failed: %v", err)
+		}
+	}
+
+	// A stale folder for the same extension that the index does not know about
+	staleDir := filepath.Join(extensionsDir, "pub.ext-0.9.0")
+	if err := os.MkdirAll(staleDir, 0755); err != nil {
+		t.Fatalf("Failed to create stale dir: %v", err)
+	}
+	os.WriteFile(filepath.Join(staleDir, "package.json"), []byte(`{"publisher":"pub","name":"ext","version":"0.9.0"}`), 0644)
+
+	result, err := UninstallExtension(profile, "Pub.Ext")
+	if err != nil {
+		t.Fatalf("UninstallExtension failed: %v", err)
+	}
+
+	if !result.IndexUpdated {
+		t.Error("Expected index to be updated")
+	}
+	if len(result.RemovedFolders) != 2 {
+		t.Errorf("Expected 2 removed folders, got %v", result.RemovedFolders)
+	}
+
+	for _, folder := range []string{"pub.ext-1.0.0", "pub.ext-0.9.0"} {
+		if _, err := os.Stat(filepath.Join(extensionsDir, folder)); !os.IsNotExist(err) {
+			t.Errorf("Folder %s should have been removed", folder)
+		}
+	}
+
+	entries, _ := ReadExtensionsIndex(extensionsDir)
+	if len(entries) != 1 || entries[0].Identifier.ID != "pub.other" {
+		t.Errorf("Expected only pub.other to remain, got %+v", entries)
+	}
+
+	obsolete, err := ReadObsoleteExtensions(extensionsDir)
+	if err != nil {
+		t.Fatalf("ReadObsoleteExtensions failed: %v", err)
+	}
+	if len(obsolete) != 0 {
+		t.Errorf("Expected no obsolete markers after successful removal, got %v", obsolete)
+	}
+}
+
+func TestUninstallExtensionNotInstalled(t *testing.T) {
+	profile := models.EditorProfile{
+		ID:            models.EditorCursor,
+		ExtensionsDir: t.TempDir(),
+	}
+
+	if _, err := UninstallExtension(profile, "pub.missing"); err == nil {
+		t.Error("Expected error for extension that is not installed")
+	}
+}
+
+func TestUninstallExtensionRejectsHostileLocation(t *testing.T) {
+	root := t.TempDir()
+	extensionsDir := filepath.Join(root, "extensions")
+	victim := filepath.Join(root, "victim")
+	for _, dir := range []string{extensionsDir, victim} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+	profile := models.EditorProfile{ID: models.EditorCursor, ExtensionsDir: extensionsDir}
+
+	for _, location := range []string{"..", "../victim", "."} {
+		index := []models.ExtensionIndexEntry{{
+			Identifier:       models.ExtensionIdentifier{ID: "pub.evil"},
+			Version:          "1.0.0",
+			RelativeLocation: location,
+		}}
+		if err := WriteExtensionsIndex(extensionsDir, index); err != nil {
+			t.Fatalf("WriteExtensionsIndex failed: %v", err)
+		}
+
+		if _, err := UninstallExtension(profile, "pub.evil"); err == nil {
+			t.Errorf("Expected error for relativeLocation %q", location)
+		}
+		for _, dir := range []string{extensionsDir, victim} {
+			if _, err := os.Stat(dir); err != nil {
+				t.Fatalf("relativeLocation %q removed %s", location, dir)
+			}
+		}
+	}
+}
+
+func TestObsoleteMarkers(t *testing.T) {
+	extensionsDir := t.TempDir()
+
+	if err := markObsolete(extensionsDir, "pub.ext-1.0.0", "pub.ext-2.0.0"); err != nil {
+		t.Fatalf("markObsolete failed: %v", err)
+	}
+
+	obsolete, err := ReadObsoleteExtensions(extensionsDir)
+	if err != nil {
+		t.Fatalf("ReadObsoleteExtensions failed: %v", err)
+	}
+	if !IsObsolete(obsolete, "PUB.EXT-1.0.0") {
+		t.Error("Expected pub.ext-1.0.0 to be obsolete")
+	}
+
+	if err := unmarkObsolete(extensionsDir, "pub.ext-1.0.0", "pub.ext-2.0.0"); err != nil {
+		t.Fatalf("unmarkObsolete failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(extensionsDir, obsoleteFile)); !os.IsNotExist(err) {
+		t.Error("Expected .obsolete to be removed when empty")
+	}
+}
+
+func TestSetExtensionEnabled(t *testing.T) {
+	profile := models.EditorProfile{
+		ID:            models.EditorVSCode,
+		ExtensionsDir: t.TempDir(),
+		UserDataDir:   t.TempDir(),
+	}
+
+	disabled, err := ReadDisabledExtensions(profile)
+	if err != nil {
+		t.Fatalf("ReadDisabledExtensions failed: %v", err)
+	}
+	if len(disabled) != 0 {
+		t.Errorf("Expected no disabled extensions without a state database, got %v", disabled)
+	}
+
+	if err := SetExtensionEnabled(profile, "Pub.Ext", false); err != nil {
+		t.Fatalf("SetExtensionEnabled(false) failed: %v", err)
+	}
+	if err := SetExtensionEnabled(profile, "pub.other", false); err != nil {
+		t.Fatalf("SetExtensionEnabled(false) failed: %v", err)
+	}
+
+	disabled, err = ReadDisabledExtensions(profile)
+	if err != nil {
+		t.Fatalf("ReadDisabledExtensions failed: %v", err)
+	}
+	if len(disabled) != 2 || disabled[0].ID != "pub.ext" {
+		t.Errorf("Expected pub.ext and pub.other to be disabled, got %v", disabled)
+	}
+
+	if err := SetExtensionEnabled(profile, "pub.ext", true); err != nil {
+		t.Fatalf("SetExtensionEnabled(true) failed: %v", err)
+	}
+	disabled, _ = ReadDisabledExtensions(profile)
+	if len(disabled) != 1 || disabled[0].ID != "pub.other" {
+		t.Errorf("Expected only pub.other to be disabled, got %v", disabled)
+	}
+}
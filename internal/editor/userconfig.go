@@ -0,0 +1,341 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/jsonc"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/textdiff"
+)
+
+// editorSettingPrefixes are setting and command namespaces that only mean something in one editor
+var editorSettingPrefixes = map[models.EditorType][]string{
+	models.EditorCursor:   {"cursor."},
+	models.EditorWindsurf: {"windsurf."},
+	models.EditorKiro:     {"kiro."},
+}
+
+// nonPortableSettingPrefixes are install- or machine-specific settings that are never synced
+var nonPortableSettingPrefixes = []string{"update.", "settingsSync.", "telemetry."}
+
+// UserConfigDir returns the folder holding an editor's settings.json, keybindings.json and snippets
+func UserConfigDir(profile models.EditorProfile) string {
+	if profile.UserDataDir == "" {
+		return ""
+	}
+	return filepath.Join(profile.UserDataDir, "User")
+}
+
+// IsEditorSpecificSetting reports whether a setting key or command belongs to another editor
+// than target, or is machine-specific, and should not be synced to target
+func IsEditorSpecificSetting(key string, target models.EditorType) bool {
+	for _, prefix := range nonPortableSettingPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	for editorID, prefixes := range editorSettingPrefixes {
+		if editorID == target {
+			continue
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PlanUserConfigSync works out how settings, keybindings and snippets of the source editor
+// would be merged into the target editor. Nothing is written.
+func PlanUserConfigSync(source, target models.EditorProfile, options models.UserConfigSyncOptions) (*models.UserConfigSyncPlan, error) {
+	sourceDir, targetDir := UserConfigDir(source), UserConfigDir(target)
+	if sourceDir == "" {
+		return nil, fmt.Errorf("%s has no user data directory", source.Name)
+	}
+	if targetDir == "" {
+		return nil, fmt.Errorf("%s has no user data directory", target.Name)
+	}
+
+	plan := &models.UserConfigSyncPlan{
+		SourceEditor: source.ID,
+		TargetEditor: target.ID,
+		Changes:      []models.UserConfigChange{},
+	}
+
+	if options.Settings {
+		change, err := planSettings(filepath.Join(sourceDir, "settings.json"), filepath.Join(targetDir, "settings.json"), target.ID)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			plan.Changes = append(plan.Changes, *change)
+		}
+	}
+	if options.Keybindings {
+		change, err := planKeybindings(filepath.Join(sourceDir, "keybindings.json"), filepath.Join(targetDir, "keybindings.json"), target.ID)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			plan.Changes = append(plan.Changes, *change)
+		}
+	}
+	if options.Snippets {
+		changes, err := planSnippets(filepath.Join(sourceDir, "snippets"), filepath.Join(targetDir, "snippets"))
+		if err != nil {
+			return nil, err
+		}
+		plan.Changes = append(plan.Changes, changes...)
+	}
+
+	return plan, nil
+}
+
+// ApplyUserConfigSync writes the planned configuration files. Files that are replaced are
+// kept next to the original as a timestamped .vsynx-backup file, so earlier backups survive.
+func ApplyUserConfigSync(plan *models.UserConfigSyncPlan) error {
+	for _, change := range plan.Changes {
+		if change.Action == models.UserConfigUnchanged {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(change.TargetPath), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(change.TargetPath), err)
+		}
+		if change.Action == models.UserConfigUpdate {
+			existing, err := os.ReadFile(change.TargetPath)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", change.TargetPath, err)
+			}
+			backupPath := fmt.Sprintf("%s.%s.vsynx-backup", change.TargetPath, time.Now().Format("20060102-150405.000000000"))
+			if err := os.WriteFile(backupPath, existing, 0644); err != nil {
+				return fmt.Errorf("failed to back up %s: %w", change.TargetPath, err)
+			}
+			plan.BackupPaths = append(plan.BackupPaths, backupPath)
+		}
+		if err := os.WriteFile(change.TargetPath, []byte(change.Content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", change.TargetPath, err)
+		}
+	}
+	plan.Written = true
+	return nil
+}
+
+// planSettings merges source settings into the target's settings.json; source values win
+func planSettings(sourcePath, targetPath string, target models.EditorType) (*models.UserConfigChange, error) {
+	return planObjectMerge(models.UserConfigSettings, sourcePath, targetPath, true, func(key string) bool {
+		return IsEditorSpecificSetting(key, target)
+	})
+}
+
+// planSnippets merges every snippet file of the source into the target, keeping existing snippets
+func planSnippets(sourceDir, targetDir string) ([]models.UserConfigChange, error) {
+	entries, err := os.ReadDir(sourceDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snippets: %w", err)
+	}
+
+	var changes []models.UserConfigChange
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".code-snippets")) {
+			continue
+		}
+		change, err := planObjectMerge(models.UserConfigSnippets, filepath.Join(sourceDir, name), filepath.Join(targetDir, name), false, nil)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+	return changes, nil
+}
+
+// planObjectMerge merges the members of a JSONC object file into another, keeping the target's
+// comments and layout. With overwrite, differing values are replaced by the source's.
+// Members for which filter returns true are left out. A missing source yields no change.
+func planObjectMerge(kind models.UserConfigKind, sourcePath, targetPath string, overwrite bool, filter func(string) bool) (*models.UserConfigChange, error) {
+	sourceData, err := os.ReadFile(sourcePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", sourcePath, err)
+	}
+	sourceMembers, err := jsonc.Members(sourceData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", sourcePath, err)
+	}
+
+	change, targetData, err := newUserConfigChange(kind, sourcePath, targetPath, "{}\n")
+	if err != nil {
+		return nil, err
+	}
+	if change.Action == models.UserConfigCreate && filter == nil {
+		// Nothing to merge into: copy the source as-is, comments included
+		for _, member := range sourceMembers {
+			change.Added = append(change.Added, member.Key)
+		}
+		return finishUserConfigChange(change, nil, sourceData), nil
+	}
+
+	targetMembers, err := jsonc.Members(targetData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", targetPath, err)
+	}
+	existing := make(map[string]any, len(targetMembers))
+	for _, member := range targetMembers {
+		existing[member.Key] = decodeJSONC(member.Value)
+	}
+
+	merged := targetData
+	for _, member := range sourceMembers {
+		if filter != nil && filter(member.Key) {
+			change.Filtered = append(change.Filtered, member.Key)
+			continue
+		}
+		current, exists := existing[member.Key]
+		value := decodeJSONC(member.Value)
+		if exists && (!overwrite || reflect.DeepEqual(current, value)) {
+			continue
+		}
+		merged, err = jsonc.SetMember(merged, member.Key, json.RawMessage(jsonc.Standardize(member.Value)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge %s into %s: %w", member.Key, targetPath, err)
+		}
+		if exists {
+			change.Changed = append(change.Changed, member.Key)
+		} else {
+			change.Added = append(change.Added, member.Key)
+		}
+	}
+
+	return finishUserConfigChange(change, existingContent(change, targetData), merged), nil
+}
+
+// planKeybindings appends source keybindings that the target's keybindings.json lacks
+func planKeybindings(sourcePath, targetPath string, target models.EditorType) (*models.UserConfigChange, error) {
+	sourceData, err := os.ReadFile(sourcePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", sourcePath, err)
+	}
+	sourceElements, err := jsonc.Elements(sourceData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", sourcePath, err)
+	}
+
+	change, targetData, err := newUserConfigChange(models.UserConfigKeybindings, sourcePath, targetPath, "[]\n")
+	if err != nil {
+		return nil, err
+	}
+	targetElements, err := jsonc.Elements(targetData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", targetPath, err)
+	}
+	var existing []any
+	for _, element := range targetElements {
+		existing = append(existing, decodeJSONC(element.Value))
+	}
+
+	merged := targetData
+	for _, element := range sourceElements {
+		value := decodeJSONC(element.Value)
+		binding, _ := value.(map[string]any)
+		key, _ := binding["key"].(string)
+		command, _ := binding["command"].(string)
+		label := fmt.Sprintf("%s → %s", key, command)
+
+		if IsEditorSpecificSetting(strings.TrimPrefix(command, "-"), target) {
+			change.Filtered = append(change.Filtered, label)
+			continue
+		}
+		if containsValue(existing, value) {
+			continue
+		}
+		merged, err = jsonc.AppendElement(merged, json.RawMessage(jsonc.Standardize(element.Value)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge keybinding %s: %w", label, err)
+		}
+		existing = append(existing, value)
+		change.Added = append(change.Added, label)
+	}
+
+	return finishUserConfigChange(change, existingContent(change, targetData), merged), nil
+}
+
+// newUserConfigChange reads the target file, substituting empty when it does not exist yet
+func newUserConfigChange(kind models.UserConfigKind, sourcePath, targetPath, empty string) (*models.UserConfigChange, []byte, error) {
+	change := &models.UserConfigChange{Kind: kind, SourcePath: sourcePath, TargetPath: targetPath, Action: models.UserConfigUpdate}
+	data, err := os.ReadFile(targetPath)
+	if os.IsNotExist(err) {
+		change.Action = models.UserConfigCreate
+		return change, []byte(empty), nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", targetPath, err)
+	}
+	if strings.TrimSpace(string(jsonc.Standardize(data))) == "" {
+		data = []byte(empty)
+	}
+	return change, data, nil
+}
+
+// existingContent returns the target's current content, or nil if the file does not exist
+func existingContent(change *models.UserConfigChange, targetData []byte) []byte {
+	if change.Action == models.UserConfigCreate {
+		return nil
+	}
+	return targetData
+}
+
+// finishUserConfigChange records the new content and diff, marking the change unchanged if
+// nothing needs to be written
+func finishUserConfigChange(change *models.UserConfigChange, oldData, newData []byte) *models.UserConfigChange {
+	sort.Strings(change.Filtered)
+	if change.Action == models.UserConfigUpdate && len(change.Added) == 0 && len(change.Changed) == 0 {
+		change.Action = models.UserConfigUnchanged
+		return change
+	}
+
+	oldName := change.TargetPath
+	if oldData == nil {
+		oldName = "/dev/null"
+	}
+	change.Content = string(newData)
+	change.Diff = textdiff.Unified(oldName, change.TargetPath, string(oldData), change.Content)
+	return change
+}
+
+// decodeJSONC decodes a JSONC value for comparison, returning nil if it is malformed
+func decodeJSONC(data []byte) any {
+	var value any
+	if err := json.Unmarshal(jsonc.Standardize(data), &value); err != nil {
+		return nil
+	}
+	return value
+}
+
+// containsValue reports whether values holds a value deeply equal to value
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// writeUserConfigFile writes a file below an editor's User folder
+func writeUserConfigFile(t *testing.T, profile models.EditorProfile, name, content string) {
+	t.Helper()
+	path := filepath.Join(UserConfigDir(profile), name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
+
+func TestIsEditorSpecificSetting(t *testing.T) {
+	tests := []struct {
+		key    string
+		target models.EditorType
+		want   bool
+	}{
+		{"editor.fontSize", models.EditorCursor, false},
+		{"cursor.cpp.enablePartialAccepts", models.EditorVSCode, true},
+		{"cursor.cpp.enablePartialAccepts", models.EditorCursor, false},
+		{"windsurf.autocompleteSpeed", models.EditorCursor, true},
+		{"update.mode", models.EditorCursor, true},
+		{"telemetry.telemetryLevel", models.EditorVSCode, true},
+	}
+	for _, tt := range tests {
+		if got := IsEditorSpecificSetting(tt.key, tt.target); got != tt.want {
+			t.Errorf("IsEditorSpecificSetting(%s, %s) = %v, want %v", tt.key, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestPlanUserConfigSync(t *testing.T) {
+	source := models.EditorProfile{ID: models.EditorVSCode, Name: "VS Code", UserDataDir: t.TempDir()}
+	target := models.EditorProfile{ID: models.EditorCursor, Name: "Cursor", UserDataDir: t.TempDir()}
+
+	writeUserConfigFile(t, source, "settings.json", `{
+	"editor.fontSize": 14,
+	"editor.tabSize": 2, // spaces
+	"update.mode": "manual",
+	"windsurf.autocompleteSpeed": "fast",
+}`)
+	writeUserConfigFile(t, target, "settings.json", "{\n\t// my cursor settings\n\t\"editor.fontSize\": 12,\n\t\"cursor.general.enableShadowWorkspace\": true\n}\n")
+	writeUserConfigFile(t, source, "keybindings.json", `[
+	{"key": "ctrl+k", "command": "editor.action.format"},
+	{"key": "ctrl+l", "command": "windsurf.prioritized.chat.open"}
+]`)
+	writeUserConfigFile(t, source, "snippets/go.json", `{"main": {"prefix": "main", "body": ["func main() {}"]}}`)
+	writeUserConfigFile(t, source, "snippets/python.json", `{"ifmain": {"prefix": "ifm", "body": ["x"]}}`)
+	writeUserConfigFile(t, target, "snippets/python.json", `{"ifmain": {"prefix": "ifm", "body": ["mine"]}}`)
+
+	plan, err := PlanUserConfigSync(source, target, models.UserConfigSyncOptions{Settings: true, Keybindings: true, Snippets: true})
+	if err != nil {
+		t.Fatalf("PlanUserConfigSync failed: %v", err)
+	}
+
+	changes := map[string]models.UserConfigChange{}
+	for _, change := range plan.Changes {
+		changes[filepath.Base(change.TargetPath)] = change
+	}
+
+	settings := changes["settings.json"]
+	if settings.Action != models.UserConfigUpdate {
+		t.Fatalf("settings action = %s, want update", settings.Action)
+	}
+	if strings.Join(settings.Changed, ",") != "editor.fontSize" || strings.Join(settings.Added, ",") != "editor.tabSize" {
+		t.Errorf("settings changed=%v added=%v", settings.Changed, settings.Added)
+	}
+	if strings.Join(settings.Filtered, ",") != "update.mode,windsurf.autocompleteSpeed" {
+		t.Errorf("settings filtered = %v", settings.Filtered)
+	}
+	if !strings.Contains(settings.Content, "// my cursor settings") || !strings.Contains(settings.Content, `"editor.fontSize": 14`) {
+		t.Errorf("settings content lost comments or values:\n%s", settings.Content)
+	}
+	if !strings.Contains(settings.Diff, "-\t\"editor.fontSize\": 12,") {
+		t.Errorf("settings diff missing change:\n%s", settings.Diff)
+	}
+
+	keybindings := changes["keybindings.json"]
+	if keybindings.Action != models.UserConfigCreate || len(keybindings.Added) != 1 || len(keybindings.Filtered) != 1 {
+		t.Errorf("keybindings = %+v", keybindings)
+	}
+
+	if changes["go.json"].Action != models.UserConfigCreate {
+		t.Errorf("go.json action = %s, want create", changes["go.json"].Action)
+	}
+	if changes["python.json"].Action != models.UserConfigUnchanged {
+		t.Errorf("python.json must keep the existing snippet, got %s", changes["python.json"].Action)
+	}
+
+	if err := ApplyUserConfigSync(plan); err != nil {
+		t.Fatalf("ApplyUserConfigSync failed: %v", err)
+	}
+	if len(plan.BackupPaths) != 1 {
+		t.Errorf("Expected a backup of settings.json, got %v", plan.BackupPaths)
+	}
+	if _, err := os.Stat(filepath.Join(UserConfigDir(target), "snippets", "go.json")); err != nil {
+		t.Errorf("go.json snippets were not written: %v", err)
+	}
+
+	// Syncing again has nothing left to do
+	plan, err = PlanUserConfigSync(source, target, models.UserConfigSyncOptions{Settings: true, Keybindings: true, Snippets: true})
+	if err != nil {
+		t.Fatalf("PlanUserConfigSync failed: %v", err)
+	}
+	if plan.Pending() != 0 {
+		t.Errorf("Expected no pending changes after apply, got %+v", plan.Changes)
+	}
+
+	// A second sync keeps the earlier backup
+	settingsPath := filepath.Join(UserConfigDir(target), "settings.json")
+	if err := os.WriteFile(settingsPath, []byte("{\"editor.fontSize\": 10}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	plan, err = PlanUserConfigSync(source, target, models.UserConfigSyncOptions{Settings: true})
+	if err != nil {
+		t.Fatalf("PlanUserConfigSync failed: %v", err)
+	}
+	if err := ApplyUserConfigSync(plan); err != nil {
+		t.Fatalf("ApplyUserConfigSync failed: %v", err)
+	}
+	backups, _ := filepath.Glob(settingsPath + ".*.vsynx-backup")
+	if len(backups) != 2 {
+		t.Errorf("Expected two settings.json backups, got %v", backups)
+	}
+}
@@ -0,0 +1,98 @@
+package editor
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compares two extension versions (semver-like, e.g. 1.2.3 or 1.2.3-beta.1).
+// It returns -1 if a < b, 0 if they are equal and 1 if a > b.
+func CompareVersions(a, b string) int {
+	aCore, aPre, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	bCore, bPre, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+
+	if c := compareDotted(aCore, bCore); c != 0 {
+		return c
+	}
+
+	// A release sorts after any of its pre-releases
+	switch {
+	case aPre == "" && bPre == "":
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return compareDotted(aPre, bPre)
+}
+
+// compareDotted compares dot-separated identifiers, numerically where possible
+func compareDotted(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aPart, bPart string
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+
+		aNum, aErr := strconv.Atoi(aPart)
+		bNum, bErr := strconv.Atoi(bPart)
+		if aPart == "" {
+			aNum, aErr = 0, nil
+		}
+		if bPart == "" {
+			bNum, bErr = 0, nil
+		}
+
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum != bNum {
+				if aNum < bNum {
+					return -1
+				}
+				return 1
+			}
+		default:
+			if c := strings.Compare(aPart, bPart); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// EngineCompatible reports whether an editor implementing VS Code API version editorVersion
+// satisfies an extension's engines.vscode range ("^1.80.0", ">=1.80.0", "*"). Like VS Code,
+// a range is a minimum version; a caret also requires the same major version. An empty
+// range or editor version is treated as compatible.
+func EngineCompatible(engineRange, editorVersion string) bool {
+	engineRange = strings.TrimSpace(engineRange)
+	editorVersion, _, _ = strings.Cut(strings.TrimSpace(editorVersion), "-")
+	if engineRange == "" || engineRange == "*" || editorVersion == "" {
+		return true
+	}
+
+	caret := strings.HasPrefix(engineRange, "^")
+	minimum := strings.TrimSpace(strings.TrimLeft(engineRange, "^>="))
+	// Pre-release tags such as -insider only mark the build the extension was tested on
+	minimum, _, _ = strings.Cut(minimum, "-")
+	minimum = strings.NewReplacer("x", "0", "X", "0", "*", "0").Replace(minimum)
+
+	if compareDotted(editorVersion, minimum) < 0 {
+		return false
+	}
+	if caret {
+		requiredMajor, _, _ := strings.Cut(minimum, ".")
+		editorMajor, _, _ := strings.Cut(editorVersion, ".")
+		if requiredMajor != "0" && requiredMajor != editorMajor {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,499 @@
+package editor
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+const (
+	// vsixExtensionPrefix is the folder inside a VSIX archive that holds the extension files
+	vsixExtensionPrefix = "extension/"
+	vsixManifestFile    = "extension.vsixmanifest"
+	// maxExtractedSize bounds the unpacked size of a package, so a zip bomb cannot fill the disk
+	maxExtractedSize = 2 << 30
+)
+
+// VSIXPackage describes a verified VSIX archive
+type VSIXPackage struct {
+	Publisher      string `json:"publisher"`
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	DisplayName    string `json:"displayName,omitempty"`
+	Description    string `json:"description,omitempty"`
+	Repository     string `json:"repository,omitempty"`
+	EngineVSCode   string `json:"engineVscode,omitempty"` // engines.vscode range
+	TargetPlatform string `json:"targetPlatform,omitempty"`
+	PreRelease     bool   `json:"preRelease"`
+	SHA256         string `json:"sha256"`
+	Size           int64  `json:"size"`
+}
+
+// ID returns the extension ID in publisher.name form
+func (p *VSIXPackage) ID() string {
+	return fmt.Sprintf("%s.%s", p.Publisher, p.Name)
+}
+
+// FolderName returns the folder name used by VS Code family editors for this package
+func (p *VSIXPackage) FolderName() string {
+	folder := fmt.Sprintf("%s-%s", strings.ToLower(p.ID()), p.Version)
+	if isPlatformSpecific(p.TargetPlatform) {
+		folder += "-" + p.TargetPlatform
+	}
+	return folder
+}
+
+// extensionPackageJSON holds the package.json fields needed to identify an extension
+type extensionPackageJSON struct {
+	Publisher   string          `json:"publisher"`
+	Name        string          `json:"name"`
+	Version     string          `json:"version"`
+	DisplayName string          `json:"displayName"`
+	Description string          `json:"description"`
+	Repository  json.RawMessage `json:"repository"` // a URL string or {"type", "url"}
+	Engines     struct {
+		VSCode string `json:"vscode"`
+	} `json:"engines"`
+}
+
+// repositoryURL returns the repository URL from either package.json form
+func (p *extensionPackageJSON) repositoryURL() string {
+	var url string
+	if json.Unmarshal(p.Repository, &url) == nil {
+		return url
+	}
+	var repo struct {
+		URL string `json:"url"`
+	}
+	if json.Unmarshal(p.Repository, &repo) == nil {
+		return repo.URL
+	}
+	return ""
+}
+
+// vsixManifest represents the relevant parts of extension.vsixmanifest
+type vsixManifest struct {
+	Metadata struct {
+		Identity struct {
+			ID             string `xml:"Id,attr"`
+			Version        string `xml:"Version,attr"`
+			Publisher      string `xml:"Publisher,attr"`
+			TargetPlatform string `xml:"TargetPlatform,attr"`
+		} `xml:"Identity"`
+		Properties struct {
+			Property []struct {
+				ID    string `xml:"Id,attr"`
+				Value string `xml:"Value,attr"`
+			} `xml:"Property"`
+		} `xml:"Properties"`
+	} `xml:"Metadata"`
+}
+
+// ReadVSIX verifies a VSIX archive and returns its identity. Publisher, name, version
+// and target platform must be shaped so that they cannot name a path outside a folder.
+func ReadVSIX(data []byte) (*VSIXPackage, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid VSIX archive: %w", err)
+	}
+
+	var pkgJSON *extensionPackageJSON
+	var manifest *vsixManifest
+
+	for _, file := range reader.File {
+		if _, err := safeArchivePath(file.Name); err != nil {
+			return nil, err
+		}
+
+		switch file.Name {
+		case vsixExtensionPrefix + "package.json":
+			content, err := readZipFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read package.json: %w", err)
+			}
+			pkgJSON = &extensionPackageJSON{}
+			if err := json.Unmarshal(content, pkgJSON); err != nil {
+				return nil, fmt.Errorf("failed to parse package.json: %w", err)
+			}
+		case vsixManifestFile:
+			content, err := readZipFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", vsixManifestFile, err)
+			}
+			manifest = &vsixManifest{}
+			if err := xml.Unmarshal(content, manifest); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", vsixManifestFile, err)
+			}
+		}
+	}
+
+	if pkgJSON == nil {
+		return nil, fmt.Errorf("invalid VSIX archive: extension/package.json not found")
+	}
+	if pkgJSON.Publisher == "" || pkgJSON.Name == "" || pkgJSON.Version == "" {
+		return nil, fmt.Errorf("invalid VSIX archive: package.json is missing publisher, name or version")
+	}
+	// The identity names the install folder, so it must not be able to leave it
+	if !validName(pkgJSON.Publisher) || !validName(pkgJSON.Name) {
+		return nil, fmt.Errorf("invalid VSIX archive: invalid extension ID %q", pkgJSON.Publisher+"."+pkgJSON.Name)
+	}
+	if !ValidVersion(pkgJSON.Version) {
+		return nil, fmt.Errorf("invalid VSIX archive: invalid version %q", pkgJSON.Version)
+	}
+
+	hash := sha256.Sum256(data)
+	pkg := &VSIXPackage{
+		Publisher:    pkgJSON.Publisher,
+		Name:         pkgJSON.Name,
+		Version:      pkgJSON.Version,
+		DisplayName:  pkgJSON.DisplayName,
+		Description:  pkgJSON.Description,
+		Repository:   pkgJSON.repositoryURL(),
+		EngineVSCode: pkgJSON.Engines.VSCode,
+		SHA256:       hex.EncodeToString(hash[:]),
+		Size:         int64(len(data)),
+	}
+
+	// The manifest identity must agree with package.json when present
+	if manifest != nil {
+		identity := manifest.Metadata.Identity
+		if identity.Publisher != "" && !strings.EqualFold(identity.Publisher, pkg.Publisher) {
+			return nil, fmt.Errorf("VSIX identity mismatch: manifest publisher %s, package.json publisher %s", identity.Publisher, pkg.Publisher)
+		}
+		if identity.ID != "" && !strings.EqualFold(identity.ID, pkg.Name) {
+			return nil, fmt.Errorf("VSIX identity mismatch: manifest name %s, package.json name %s", identity.ID, pkg.Name)
+		}
+		if identity.Version != "" && identity.Version != pkg.Version {
+			return nil, fmt.Errorf("VSIX identity mismatch: manifest version %s, package.json version %s", identity.Version, pkg.Version)
+		}
+		pkg.TargetPlatform = identity.TargetPlatform
+		if !ValidTargetPlatform(pkg.TargetPlatform) {
+			return nil, fmt.Errorf("invalid VSIX archive: invalid target platform %q", pkg.TargetPlatform)
+		}
+		for _, prop := range manifest.Metadata.Properties.Property {
+			if prop.ID == "Microsoft.VisualStudio.Code.PreRelease" && prop.Value == "true" {
+				pkg.PreRelease = true
+			}
+		}
+	}
+
+	return pkg, nil
+}
+
+// TrustValidator classifies the trust level of an extension; validation.Validator implements it
+type TrustValidator interface {
+	ValidateExtension(extensionID string) (*models.ValidationResult, error)
+}
+
+// InstallVSIXFile installs a VSIX file from disk into an editor without using its CLI.
+// The extension it contains is validated first: Malicious extensions are refused, and
+// Suspicious or Unknown ones (including when validator is nil) unless allowUntrusted is set.
+func InstallVSIXFile(profile models.EditorProfile, vsixPath string, validator TrustValidator, allowUntrusted bool) (*models.VSIXInstallResult, error) {
+	data, err := os.ReadFile(vsixPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read VSIX file: %w", err)
+	}
+	pkg, err := ReadVSIX(data)
+	if err != nil {
+		return nil, err
+	}
+
+	trust, recommendation := models.TrustLevelUnknown, "no validator configured"
+	if validator != nil {
+		result, err := validator.ValidateExtension(pkg.ID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate %s: %w", pkg.ID(), err)
+		}
+		trust, recommendation = result.TrustLevel, result.Recommendation
+	}
+	switch {
+	case trust == models.TrustLevelMalicious:
+		return nil, fmt.Errorf("refusing to install %s: classified Malicious: %s", pkg.ID(), recommendation)
+	case trust != models.TrustLevelLegitimate && !allowUntrusted:
+		return nil, fmt.Errorf("refusing to install %s: classified %s: %s (allow untrusted packages to install it anyway)", pkg.ID(), trust, recommendation)
+	}
+	return InstallVSIX(profile, data, "vsix", "")
+}
+
+// InstallVSIX unpacks a VSIX package into the editor's extensions directory and
+// registers it in extensions.json. source is recorded in the index metadata: "vsix",
+// "gallery", or the registry the package came from ("marketplace" or "openvsx"), which is
+// recorded as "gallery" with the registry name kept under "registry". If expectedID is
+// set, the package must match it.
+func InstallVSIX(profile models.EditorProfile, data []byte, source string, expectedID string) (*models.VSIXInstallResult, error) {
+	pkg, err := ReadVSIX(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if expectedID != "" && !strings.EqualFold(pkg.ID(), expectedID) {
+		return nil, fmt.Errorf("VSIX contains %s, expected %s", pkg.ID(), expectedID)
+	}
+
+	folderName := pkg.FolderName()
+	targetPath, err := extensionFolderPath(profile.ExtensionsDir, folderName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(profile.ExtensionsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create extensions directory: %w", err)
+	}
+
+	// Read the existing index before touching the disk so a corrupt index aborts cleanly
+	index, err := ReadExtensionsIndex(profile.ExtensionsDir)
+	if err != nil && indexFileExists(profile.ExtensionsDir) {
+		return nil, err
+	}
+
+	// Unpack into a hidden staging folder first, then move it into place
+	stagingDir, err := os.MkdirTemp(profile.ExtensionsDir, ".vsynx-install-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	if err := extractVSIX(data, stagingDir); err != nil {
+		return nil, err
+	}
+
+	// Move an existing folder aside rather than deleting it, so it can be put back if the
+	// new one cannot be installed
+	backupPath := ""
+	if _, err := os.Lstat(targetPath); err == nil {
+		backupPath = stagingDir + "-previous"
+		if err := os.Rename(targetPath, backupPath); err != nil {
+			return nil, fmt.Errorf("failed to move existing folder %s aside: %w", folderName, err)
+		}
+	}
+	restore := func() {
+		if backupPath != "" {
+			os.RemoveAll(targetPath)
+			os.Rename(backupPath, targetPath)
+		}
+	}
+	if err := os.Rename(stagingDir, targetPath); err != nil {
+		restore()
+		return nil, fmt.Errorf("failed to move extension into place: %w", err)
+	}
+
+	// A previous uninstall may have left this folder marked for removal
+	if err := unmarkObsolete(profile.ExtensionsDir, folderName); err != nil {
+		restore()
+		return nil, err
+	}
+
+	result := &models.VSIXInstallResult{
+		ExtensionID:    strings.ToLower(pkg.ID()),
+		Version:        pkg.Version,
+		TargetEditor:   profile.ID,
+		InstallPath:    targetPath,
+		SHA256:         pkg.SHA256,
+		TargetPlatform: pkg.TargetPlatform,
+	}
+
+	// Replace any previous entry for this extension
+	newIndex := make([]models.ExtensionIndexEntry, 0, len(index)+1)
+	var previous *models.ExtensionIndexEntry
+	for i, entry := range index {
+		if strings.EqualFold(entry.Identifier.ID, pkg.ID()) {
+			previous = &index[i]
+			continue
+		}
+		newIndex = append(newIndex, entry)
+	}
+
+	var uuid string
+	preReleaseChannel := false
+	if previous != nil {
+		uuid = previous.Identifier.UUID
+		result.ReplacedVersion = previous.Version
+		// Keep the user's choice of the pre-release channel across updates
+		preReleaseChannel, _ = previous.Metadata["preRelease"].(bool)
+	}
+
+	targetPlatform := pkg.TargetPlatform
+	if targetPlatform == "" {
+		targetPlatform = "undefined"
+	}
+
+	metadata := map[string]any{
+		"installedTimestamp":  time.Now().UnixMilli(),
+		"source":              source,
+		"pinned":              source == "vsix",
+		"targetPlatform":      targetPlatform,
+		"isPreReleaseVersion": pkg.PreRelease,
+		"preRelease":          preReleaseChannel,
+		"isApplicationScoped": false,
+		"isMachineScoped":     false,
+		"isBuiltin":           false,
+		"updated":             previous != nil,
+	}
+	if source == "marketplace" || source == "openvsx" {
+		metadata["source"] = "gallery"
+		metadata["registry"] = source
+	}
+
+	newIndex = append(newIndex, models.ExtensionIndexEntry{
+		Identifier: models.ExtensionIdentifier{
+			ID:   result.ExtensionID,
+			UUID: uuid,
+		},
+		Version:          pkg.Version,
+		Location:         buildExtensionLocation(targetPath),
+		RelativeLocation: folderName,
+		Metadata:         metadata,
+	})
+
+	if err := WriteExtensionsIndex(profile.ExtensionsDir, newIndex); err != nil {
+		restore()
+		return nil, err
+	}
+	if backupPath != "" {
+		os.RemoveAll(backupPath)
+	}
+
+	// Remove the folder of the replaced version once the index no longer points to it
+	// (or leave it to the editor via .obsolete if it is still in use). A location that
+	// does not name a folder directly inside the extensions directory is left alone.
+	if previous != nil && previous.RelativeLocation != "" && previous.RelativeLocation != folderName {
+		if previousPath, err := extensionFolderPath(profile.ExtensionsDir, previous.RelativeLocation); err == nil {
+			if err := os.RemoveAll(previousPath); err != nil {
+				markObsolete(profile.ExtensionsDir, previous.RelativeLocation)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// extractVSIX writes the extension/ folder of a VSIX archive into destDir, refusing
+// archives that unpack to more than maxExtractedSize
+func extractVSIX(data []byte, destDir string) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("invalid VSIX archive: %w", err)
+	}
+
+	var remaining int64 = maxExtractedSize
+
+	for _, file := range reader.File {
+		if !strings.HasPrefix(file.Name, vsixExtensionPrefix) {
+			continue
+		}
+
+		relPath, err := safeArchivePath(strings.TrimPrefix(file.Name, vsixExtensionPrefix))
+		if err != nil {
+			return err
+		}
+		if relPath == "" {
+			continue
+		}
+		targetPath := filepath.Join(destDir, relPath)
+
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(targetPath, 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			continue
+		}
+
+		// Only regular files are extracted; symlinks could escape the extension folder
+		if !file.Mode().IsRegular() {
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		// Check the declared size first; writeZipFile stops at the limit if it is false
+		if file.UncompressedSize64 > uint64(remaining) {
+			return fmt.Errorf("invalid VSIX archive: unpacks to more than %d bytes", maxExtractedSize)
+		}
+		written, err := writeZipFile(file, targetPath, remaining)
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+		}
+		remaining -= written
+	}
+
+	return nil
+}
+
+// safeArchivePath cleans an archive entry name and rejects paths that escape the archive root
+func safeArchivePath(name string) (string, error) {
+	if strings.Contains(name, "\\") || path.IsAbs(name) {
+		return "", fmt.Errorf("invalid VSIX archive: unsafe path %q", name)
+	}
+	cleaned := path.Clean(name)
+	if cleaned == "." {
+		return "", nil
+	}
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("invalid VSIX archive: unsafe path %q", name)
+	}
+	return filepath.FromSlash(cleaned), nil
+}
+
+// readZipFile reads the full contents of an archive entry
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// writeZipFile writes an archive entry of at most limit bytes to disk and returns its size
+func writeZipFile(file *zip.File, targetPath string, limit int64) (int64, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	mode := file.Mode().Perm()
+	if mode == 0 {
+		mode = 0644
+	}
+
+	dst, err := os.OpenFile(targetPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+
+	written, err := io.Copy(dst, io.LimitReader(rc, limit+1))
+	if err == nil && written > limit {
+		err = fmt.Errorf("archive unpacks to more than %d bytes", maxExtractedSize)
+	}
+	return written, err
+}
+
+// isPlatformSpecific reports whether a target platform adds a suffix to the folder name
+func isPlatformSpecific(targetPlatform string) bool {
+	return targetPlatform != "" && targetPlatform != "universal" && targetPlatform != "undefined"
+}
+
+// indexFileExists reports whether an extensions index file is present
+func indexFileExists(extensionsDir string) bool {
+	for _, name := range indexFiles(extensionsDir) {
+		if _, err := os.Stat(filepath.Join(extensionsDir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,317 @@
+package editor
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// buildTestVSIX creates an in-memory VSIX archive with the given identity and extra files
+func buildTestVSIX(t *testing.T, publisher, name, version string, extraFiles map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	files := map[string]string{
+		"extension.vsixmanifest": fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<PackageManifest Version="2.0.0" xmlns="http://schemas.microsoft.com/developer/vsx-schema/2011">
+  <Metadata>
+    <Identity Language="en-US" Id="%s" Version="%s" Publisher="%s" />
+  </Metadata>
+</PackageManifest>`, name, version, publisher),
+		"extension/package.json": fmt.Sprintf(`{"publisher": %q, "name": %q, "version": %q}`, publisher, name, version),
+		"extension/out/main.js":  "module.exports = {}",
+	}
+	for k, v := range extraFiles {
+		files[k] = v
+	}
+
+	for fileName, content := range files {
+		f, err := w.Create(fileName)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write zip entry: %v", err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadVSIX(t *testing.T) {
+	data := buildTestVSIX(t, "TestPub", "my-ext", "1.2.3", nil)
+
+	pkg, err := ReadVSIX(data)
+	if err != nil {
+		t.Fatalf("ReadVSIX failed: %v", err)
+	}
+
+	if pkg.ID() != "TestPub.my-ext" {
+		t.Errorf("ID = %s, want TestPub.my-ext", pkg.ID())
+	}
+	if pkg.FolderName() != "testpub.my-ext-1.2.3" {
+		t.Errorf("FolderName = %s, want testpub.my-ext-1.2.3", pkg.FolderName())
+	}
+	if pkg.SHA256 == "" {
+		t.Error("SHA256 should be set")
+	}
+}
+
+func TestReadVSIXPackageDetails(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository string
+		want       string
+	}{
+		{"repository object", `{"type": "git", "url": "https://github.com/pub/ext.git"}`, "https://github.com/pub/ext.git"},
+		{"repository string", `"https://github.com/pub/ext"`, "https://github.com/pub/ext"},
+		{"no repository", `null`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packageJSON := fmt.Sprintf(`{"publisher": "pub", "name": "ext", "version": "1.0.0",
+				"description": "Does things", "repository": %s, "engines": {"vscode": "^1.80.0"}}`, tt.repository)
+			data := buildTestVSIX(t, "pub", "ext", "1.0.0", map[string]string{"extension/package.json": packageJSON})
+
+			pkg, err := ReadVSIX(data)
+			if err != nil {
+				t.Fatalf("ReadVSIX failed: %v", err)
+			}
+			if pkg.Repository != tt.want {
+				t.Errorf("Repository = %q, want %q", pkg.Repository, tt.want)
+			}
+			if pkg.Description != "Does things" || pkg.EngineVSCode != "^1.80.0" {
+				t.Errorf("Description/EngineVSCode = %q/%q", pkg.Description, pkg.EngineVSCode)
+			}
+		})
+	}
+}
+
+func TestReadVSIXInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"Not a zip", []byte("not a zip file")},
+		{"Missing package.json", buildTestVSIX(t, "pub", "ext", "1.0.0", map[string]string{"extension/package.json": "{}"})},
+		{"Path traversal", buildTestVSIX(t, "pub", "ext", "1.0.0", map[string]string{"extension/../../evil.js": "x"})},
+		{"Manifest mismatch", buildTestVSIX(t, "pub", "ext", "1.0.0", map[string]string{
+			"extension/package.json": `{"publisher": "other", "name": "ext", "version": "1.0.0"}`,
+		})},
+		{"Version traversal", buildTestVSIX(t, "pub", "ext", "1.0.0/../../../escaped", nil)},
+		{"Name traversal", buildTestVSIX(t, "pub", "..", "1.0.0", nil)},
+		{"Publisher separator", buildTestVSIX(t, `pub\evil`, "ext", "1.0.0", nil)},
+		{"Version not a version", buildTestVSIX(t, "pub", "ext", "latest", nil)},
+		{"Platform traversal", buildTestVSIX(t, "pub", "ext", "1.0.0", map[string]string{
+			"extension.vsixmanifest": `<PackageManifest><Metadata><Identity Id="ext" Version="1.0.0" Publisher="pub" TargetPlatform="../x" /></Metadata></PackageManifest>`,
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadVSIX(tt.data); err == nil {
+				t.Error("Expected error for invalid VSIX")
+			}
+		})
+	}
+}
+
+func TestInstallVSIX(t *testing.T) {
+	extensionsDir := t.TempDir()
+	profile := models.EditorProfile{
+		ID:            models.EditorWindsurf,
+		Name:          "Test Editor",
+		ExtensionsDir: extensionsDir,
+		IndexFile:     "extensions.json",
+	}
+
+	result, err := InstallVSIX(profile, buildTestVSIX(t, "pub", "ext", "1.0.0", nil), "vsix", "pub.ext")
+	if err != nil {
+		t.Fatalf("InstallVSIX failed: %v", err)
+	}
+
+	if result.ExtensionID != "pub.ext" {
+		t.Errorf("ExtensionID = %s, want pub.ext", result.ExtensionID)
+	}
+	if _, err := os.Stat(filepath.Join(extensionsDir, "pub.ext-1.0.0", "out", "main.js")); err != nil {
+		t.Errorf("Extension files were not extracted: %v", err)
+	}
+
+	entries, err := ReadExtensionsIndex(extensionsDir)
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 index entry, got %d", len(entries))
+	}
+	if entries[0].RelativeLocation != "pub.ext-1.0.0" {
+		t.Errorf("RelativeLocation = %s, want pub.ext-1.0.0", entries[0].RelativeLocation)
+	}
+	if entries[0].Location.Scheme != "file" {
+		t.Errorf("Location.Scheme = %s, want file", entries[0].Location.Scheme)
+	}
+
+	// Upgrading replaces the entry and removes the old folder
+	result, err = InstallVSIX(profile, buildTestVSIX(t, "pub", "ext", "2.0.0", nil), "gallery", "")
+	if err != nil {
+		t.Fatalf("InstallVSIX upgrade failed: %v", err)
+	}
+	if result.ReplacedVersion != "1.0.0" {
+		t.Errorf("ReplacedVersion = %s, want 1.0.0", result.ReplacedVersion)
+	}
+	if _, err := os.Stat(filepath.Join(extensionsDir, "pub.ext-1.0.0")); !os.IsNotExist(err) {
+		t.Error("Old version folder should have been removed")
+	}
+
+	entries, _ = ReadExtensionsIndex(extensionsDir)
+	if len(entries) != 1 || entries[0].Version != "2.0.0" {
+		t.Errorf("Expected single entry at 2.0.0, got %+v", entries)
+	}
+}
+
+func TestInstallVSIXExpectedIDMismatch(t *testing.T) {
+	profile := models.EditorProfile{
+		ID:            models.EditorCursor,
+		ExtensionsDir: t.TempDir(),
+	}
+
+	_, err := InstallVSIX(profile, buildTestVSIX(t, "pub", "ext", "1.0.0", nil), "gallery", "other.ext")
+	if err == nil {
+		t.Error("Expected error for mismatched extension ID")
+	}
+}
+
+func TestInstallVSIXReplacesFolderSafely(t *testing.T) {
+	root := t.TempDir()
+	extensionsDir := filepath.Join(root, "extensions")
+	profile := models.EditorProfile{ID: models.EditorCursor, ExtensionsDir: extensionsDir}
+
+	if _, err := InstallVSIX(profile, buildTestVSIX(t, "pub", "ext", "1.0.0", map[string]string{"extension/old.js": "old"}), "gallery", ""); err != nil {
+		t.Fatalf("InstallVSIX failed: %v", err)
+	}
+	// Reinstalling the same version replaces the folder and leaves no backup behind
+	if _, err := InstallVSIX(profile, buildTestVSIX(t, "pub", "ext", "1.0.0", nil), "gallery", ""); err != nil {
+		t.Fatalf("InstallVSIX reinstall failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(extensionsDir, "pub.ext-1.0.0", "old.js")); !os.IsNotExist(err) {
+		t.Error("Reinstall should replace the folder contents")
+	}
+	dirs, _ := os.ReadDir(extensionsDir)
+	for _, dir := range dirs {
+		if dir.IsDir() && dir.Name() != "pub.ext-1.0.0" {
+			t.Errorf("Unexpected folder left behind: %s", dir.Name())
+		}
+	}
+
+	// A tampered index entry must not make an upgrade delete folders outside the directory
+	victim := filepath.Join(root, "victim")
+	if err := os.Mkdir(victim, 0755); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := ReadExtensionsIndex(extensionsDir)
+	entries[0].RelativeLocation = "../victim"
+	if err := WriteExtensionsIndex(extensionsDir, entries); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := InstallVSIX(profile, buildTestVSIX(t, "pub", "ext", "2.0.0", nil), "gallery", ""); err != nil {
+		t.Fatalf("InstallVSIX upgrade failed: %v", err)
+	}
+	if _, err := os.Stat(victim); err != nil {
+		t.Errorf("Folder outside the extensions directory was removed: %v", err)
+	}
+}
+
+func TestInstallVSIXRejectsOversizedArchive(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"extension.vsixmanifest": `<?xml version="1.0" encoding="utf-8"?>
+<PackageManifest Version="2.0.0" xmlns="http://schemas.microsoft.com/developer/vsx-schema/2011">
+  <Metadata><Identity Language="en-US" Id="ext" Version="1.0.0" Publisher="pub" /></Metadata>
+</PackageManifest>`,
+		"extension/package.json": `{"publisher": "pub", "name": "ext", "version": "1.0.0"}`,
+	} {
+		f, _ := w.Create(name)
+		f.Write([]byte(content))
+	}
+	// A stored entry that claims to unpack to far more than the limit
+	raw, err := w.CreateRaw(&zip.FileHeader{Name: "extension/bomb.bin", Method: zip.Store, CompressedSize64: 4, UncompressedSize64: 1 << 40})
+	if err != nil {
+		t.Fatalf("Failed to create raw entry: %v", err)
+	}
+	raw.Write([]byte("boom"))
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+
+	profile := models.EditorProfile{ID: models.EditorCursor, ExtensionsDir: t.TempDir()}
+	if _, err := InstallVSIX(profile, buf.Bytes(), "vsix", ""); err == nil {
+		t.Error("Expected an oversized archive to be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(profile.ExtensionsDir, "pub.ext-1.0.0")); !os.IsNotExist(err) {
+		t.Error("Nothing should be installed from an oversized archive")
+	}
+}
+
+func TestInstallVSIXRejectsEscapingVersion(t *testing.T) {
+	root := t.TempDir()
+	profile := models.EditorProfile{ID: models.EditorCursor, ExtensionsDir: filepath.Join(root, "a", "b", "extensions")}
+	victim := filepath.Join(root, "escaped")
+	if err := os.MkdirAll(victim, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buildTestVSIX(t, "pub", "ext", "1.0.0/../../../../escaped", nil)
+	if _, err := InstallVSIX(profile, data, "vsix", ""); err == nil {
+		t.Fatal("Expected a version that leaves the extensions directory to be rejected")
+	}
+	if _, err := os.Stat(victim); err != nil {
+		t.Errorf("Folder outside the extensions directory was touched: %v", err)
+	}
+}
+
+// fixedTrust is a TrustValidator that returns one trust level
+type fixedTrust models.TrustLevel
+
+func (f fixedTrust) ValidateExtension(extensionID string) (*models.ValidationResult, error) {
+	return &models.ValidationResult{ExtensionID: extensionID, TrustLevel: models.TrustLevel(f)}, nil
+}
+
+func TestInstallVSIXFileValidatesTrust(t *testing.T) {
+	vsixPath := filepath.Join(t.TempDir(), "pub.ext-1.0.0.vsix")
+	if err := os.WriteFile(vsixPath, buildTestVSIX(t, "pub", "ext", "1.0.0", nil), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		validator      TrustValidator
+		allowUntrusted bool
+		wantErr        bool
+	}{
+		{"legitimate", fixedTrust(models.TrustLevelLegitimate), false, false},
+		{"malicious", fixedTrust(models.TrustLevelMalicious), true, true},
+		{"suspicious", fixedTrust(models.TrustLevelSuspicious), false, true},
+		{"suspicious allowed", fixedTrust(models.TrustLevelSuspicious), true, false},
+		{"no validator", nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := models.EditorProfile{ID: models.EditorCursor, ExtensionsDir: t.TempDir()}
+			_, err := InstallVSIXFile(profile, vsixPath, tt.validator, tt.allowUntrusted)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("InstallVSIXFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
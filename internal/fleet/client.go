@@ -0,0 +1,68 @@
+package fleet
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+const (
+	// SignatureHeader carries the base64 ed25519 signature of the request body
+	SignatureHeader = "X-Vsynx-Signature"
+	// ReportsPath is the collector endpoint that receives audit submissions
+	ReportsPath = "/api/v1/reports"
+)
+
+// Submit signs an audit report with the machine key and posts it to a collector.
+// url may be the collector's base URL or the full reports endpoint. A non-empty
+// token is sent as a bearer token.
+func Submit(url string, report *models.AuditReport, key ed25519.PrivateKey, token string) (*models.AuditSubmission, error) {
+	submission := &models.AuditSubmission{
+		Machine:     Identity(key),
+		Report:      report,
+		SubmittedAt: time.Now(),
+	}
+	body, err := json.Marshal(submission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit report: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, reportsURL(url), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid collector URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, base64.StdEncoding.EncodeToString(ed25519.Sign(key, body)))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit audit report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("collector returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return submission, nil
+}
+
+// reportsURL appends the reports path to a collector base URL
+func reportsURL(url string) string {
+	url = strings.TrimRight(url, "/")
+	if strings.HasSuffix(url, ReportsPath) {
+		return url
+	}
+	return url + ReportsPath
+}
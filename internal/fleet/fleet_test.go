@@ -0,0 +1,241 @@
+package fleet
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+func testReport(results ...models.ValidationResult) *models.AuditReport {
+	report := &models.AuditReport{TotalExtensions: len(results), Results: results}
+	for _, result := range results {
+		switch result.TrustLevel {
+		case models.TrustLevelMalicious:
+			report.MaliciousCount++
+		case models.TrustLevelSuspicious:
+			report.SuspiciousCount++
+		}
+	}
+	return report
+}
+
+func newTestKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return key
+}
+
+func TestLoadMachineKey(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+
+	key, err := LoadMachineKey()
+	if err != nil {
+		t.Fatalf("LoadMachineKey failed: %v", err)
+	}
+	again, err := LoadMachineKey()
+	if err != nil {
+		t.Fatalf("LoadMachineKey failed on reload: %v", err)
+	}
+	if !key.Equal(again) {
+		t.Error("Machine key should be stable across loads")
+	}
+	if id := Identity(key).MachineID; id != MachineID(key.Public().(ed25519.PublicKey)) || len(id) != 16 {
+		t.Errorf("Unexpected machine ID %q", id)
+	}
+}
+
+func TestSubmitAndSummarize(t *testing.T) {
+	server, err := NewServer(t.TempDir(), "secret", "reader")
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	report := testReport(
+		models.ValidationResult{ExtensionID: "evil.ext", TrustLevel: models.TrustLevelMalicious},
+		models.ValidationResult{ExtensionID: "odd.ext", TrustLevel: models.TrustLevelSuspicious},
+		models.ValidationResult{ExtensionID: "good.ext", TrustLevel: models.TrustLevelLegitimate},
+	)
+	if _, err := Submit(ts.URL, report, newTestKey(t), "secret"); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	// A second report from the same machine replaces the first
+	key := newTestKey(t)
+	for i := 0; i < 2; i++ {
+		if _, err := Submit(ts.URL+ReportsPath, testReport(
+			models.ValidationResult{ExtensionID: "Evil.Ext", TrustLevel: models.TrustLevelMalicious},
+		), key, "secret"); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+
+	if _, err := Submit(ts.URL, report, newTestKey(t), "wrong"); err == nil {
+		t.Error("Expected submission with a wrong token to fail")
+	}
+
+	// The submit token cannot read reports and the read token cannot submit them
+	if _, err := Submit(ts.URL, report, newTestKey(t), "reader"); err == nil {
+		t.Error("Expected submission with the read token to fail")
+	}
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/machines", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Machines request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Reading with the submit token returned %s, want 401", resp.Status)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, ts.URL+"/api/v1/summary", nil)
+	req.Header.Set("Authorization", "Bearer reader")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Summary request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var summary models.FleetSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		t.Fatalf("Failed to decode summary: %v", err)
+	}
+	if summary.TotalMachines != 2 || summary.AffectedMachines != 2 {
+		t.Errorf("Unexpected machine counts: %+v", summary)
+	}
+	if len(summary.MaliciousExtensions) != 1 || len(summary.MaliciousExtensions[0].Machines) != 2 {
+		t.Errorf("evil.ext should be reported on 2 machines: %+v", summary.MaliciousExtensions)
+	}
+	if len(summary.SuspiciousExtensions) != 1 || summary.SuspiciousExtensions[0].ExtensionID != "odd.ext" {
+		t.Errorf("Unexpected suspicious extensions: %+v", summary.SuspiciousExtensions)
+	}
+}
+
+func TestVerifySubmissionRejectsTampering(t *testing.T) {
+	key := newTestKey(t)
+	submission := models.AuditSubmission{Machine: Identity(key), Report: testReport()}
+	body, _ := json.Marshal(submission)
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, body))
+
+	if _, err := VerifySubmission(body, signature); err != nil {
+		t.Fatalf("Valid submission rejected: %v", err)
+	}
+
+	tampered := bytes.Replace(body, []byte(`"totalExtensions":0`), []byte(`"totalExtensions":9`), 1)
+	if _, err := VerifySubmission(tampered, signature); err == nil {
+		t.Error("Expected tampered body to be rejected")
+	}
+
+	// Claiming another machine's ID with a different key
+	other := submission
+	other.Machine.PublicKey = Identity(newTestKey(t)).PublicKey
+	body, _ = json.Marshal(other)
+	if _, err := VerifySubmission(body, base64.StdEncoding.EncodeToString(ed25519.Sign(key, body))); err == nil {
+		t.Error("Expected mismatched machine ID to be rejected")
+	}
+}
+
+func TestNewServerRejectsSharedToken(t *testing.T) {
+	if _, err := NewServer(t.TempDir(), "same", "same"); err == nil {
+		t.Error("Expected identical read and write tokens to be rejected")
+	}
+}
+
+func TestServerAcceptsOnlyEnrolledMachines(t *testing.T) {
+	enrolledKey, otherKey := newTestKey(t), newTestKey(t)
+	path := filepath.Join(t.TempDir(), "enrolled")
+	content := "# fleet machines\n" + Identity(enrolledKey).PublicKey + "  build-01\n\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	enrolled, err := LoadEnrolledMachines(path)
+	if err != nil {
+		t.Fatalf("LoadEnrolledMachines failed: %v", err)
+	}
+
+	server, err := NewServer(t.TempDir(), "", "")
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	server.Enrolled = enrolled
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	if _, err := Submit(ts.URL, testReport(), enrolledKey, ""); err != nil {
+		t.Errorf("Enrolled machine rejected: %v", err)
+	}
+	if _, err := Submit(ts.URL, testReport(), otherKey, ""); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected unenrolled machine to be rejected with 403, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("not-a-key\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadEnrolledMachines(path); err == nil {
+		t.Error("Expected an invalid key to be rejected")
+	}
+}
+
+// postSubmission signs and posts a submission with the given time, returning the status
+func postSubmission(t *testing.T, url string, key ed25519.PrivateKey, submittedAt time.Time) int {
+	t.Helper()
+	body, _ := json.Marshal(models.AuditSubmission{Machine: Identity(key), Report: testReport(), SubmittedAt: submittedAt})
+	req, _ := http.NewRequest(http.MethodPost, url+ReportsPath, bytes.NewReader(body))
+	req.Header.Set(SignatureHeader, base64.StdEncoding.EncodeToString(ed25519.Sign(key, body)))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Submission failed: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestServerRejectsReplayedSubmissions(t *testing.T) {
+	server, err := NewServer(t.TempDir(), "", "")
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	key := newTestKey(t)
+	now := time.Now()
+	if status := postSubmission(t, ts.URL, key, now.Add(-time.Hour)); status != http.StatusAccepted {
+		t.Fatalf("First submission status = %d", status)
+	}
+	if status := postSubmission(t, ts.URL, key, now); status != http.StatusAccepted {
+		t.Errorf("Newer submission status = %d", status)
+	}
+
+	tests := []struct {
+		name        string
+		submittedAt time.Time
+		want        int
+	}{
+		{"replayed", now, http.StatusConflict},
+		{"older than stored", now.Add(-time.Hour), http.StatusConflict},
+		{"too old", now.Add(-maxSubmissionAge - time.Hour), http.StatusBadRequest},
+		{"in the future", now.Add(time.Hour), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if status := postSubmission(t, ts.URL, key, tt.submittedAt); status != tt.want {
+			t.Errorf("%s submission status = %d, want %d", tt.name, status, tt.want)
+		}
+	}
+}
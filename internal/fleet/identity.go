@@ -0,0 +1,78 @@
+package fleet
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// machineKeyFile is the config file holding the machine's ed25519 signing key
+const machineKeyFile = "machine.key"
+
+// LoadMachineKey reads the machine's signing key from the config directory,
+// generating and saving a new one on first use
+func LoadMachineKey() (ed25519.PrivateKey, error) {
+	path, err := config.Path(machineKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid machine key in %s", path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read machine key: %w", err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate machine key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key.Seed())+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to save machine key: %w", err)
+	}
+	return key, nil
+}
+
+// MachineID derives a stable machine ID from a public key
+func MachineID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Identity describes this machine for the given signing key
+func Identity(key ed25519.PrivateKey) models.MachineIdentity {
+	publicKey := key.Public().(ed25519.PublicKey)
+	identity := models.MachineIdentity{
+		MachineID: MachineID(publicKey),
+		PublicKey: base64.StdEncoding.EncodeToString(publicKey),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		identity.Hostname = hostname
+	}
+	if current, err := user.Current(); err == nil {
+		identity.User = current.Username
+	}
+	return identity
+}
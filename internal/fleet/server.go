@@ -0,0 +1,348 @@
+package fleet
+
+import (
+	"crypto/ed25519"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+const (
+	// maxSubmissionSize bounds the size of an audit submission
+	maxSubmissionSize = 16 << 20
+	// maxSubmissionAge is how old a submission may be when it arrives, so a captured
+	// report cannot be replayed later
+	maxSubmissionAge = 24 * time.Hour
+	// maxClockSkew is how far in the future a machine's clock may be
+	maxClockSkew = 5 * time.Minute
+)
+
+// errStaleSubmission is returned when a machine's stored report is newer than a submission
+var errStaleSubmission = errors.New("submission is older than the stored report")
+
+// Server is a fleet collector that stores the latest audit report of each machine
+type Server struct {
+	Dir        string          // one <machine-id>.json file per machine
+	WriteToken string          // bearer token required to submit reports; empty disables the check
+	ReadToken  string          // bearer token required to read reports; empty disables the check
+	Enrolled   map[string]bool // machine IDs allowed to submit; nil accepts any machine key
+
+	mu sync.Mutex
+}
+
+// NewServer creates a collector that stores submissions in dir. The read and write
+// tokens must differ so that machines submitting reports cannot read the fleet's.
+func NewServer(dir, writeToken, readToken string) (*Server, error) {
+	if writeToken != "" && writeToken == readToken {
+		return nil, fmt.Errorf("the read and write tokens must differ")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	return &Server{Dir: dir, WriteToken: writeToken, ReadToken: readToken}, nil
+}
+
+// LoadEnrolledMachines reads the machine public keys allowed to submit reports: one
+// base64 key per line as printed by 'vsynx audit --machine-key', optionally followed
+// by a comment. Blank lines and lines starting with # are skipped.
+func LoadEnrolledMachines(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enrolled machines: %w", err)
+	}
+	enrolled := map[string]bool{}
+	for i, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		publicKey, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%s:%d: invalid machine public key", path, i+1)
+		}
+		enrolled[MachineID(publicKey)] = true
+	}
+	return enrolled, nil
+}
+
+// Handler returns the collector's HTTP API:
+//
+//	POST /api/v1/reports          submit a signed audit report
+//	GET  /api/v1/summary          fleet summary of flagged extensions
+//	GET  /api/v1/machines         latest report summary per machine
+//	GET  /api/v1/machines/{id}    latest submission of one machine
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+ReportsPath, authenticate(s.WriteToken, s.handleSubmit))
+	mux.HandleFunc("GET /api/v1/summary", authenticate(s.ReadToken, s.handleSummary))
+	mux.HandleFunc("GET /api/v1/machines", authenticate(s.ReadToken, s.handleMachines))
+	mux.HandleFunc("GET /api/v1/machines/{id}", authenticate(s.ReadToken, s.handleMachine))
+	return mux
+}
+
+// authenticate rejects requests without the given bearer token, unless it is empty
+func authenticate(required string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if required != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(required)) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSubmissionSize+1))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxSubmissionSize {
+		http.Error(w, "submission too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	submission, err := VerifySubmission(body, r.Header.Get(SignatureHeader))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if s.Enrolled != nil && !s.Enrolled[submission.Machine.MachineID] {
+		log.Printf("[Fleet] Rejected report from unenrolled machine %s (%s)", submission.Machine.MachineID, submission.Machine.Hostname)
+		http.Error(w, "machine is not enrolled", http.StatusForbidden)
+		return
+	}
+	submission.ReceivedAt = time.Now()
+	if age := submission.ReceivedAt.Sub(submission.SubmittedAt); age > maxSubmissionAge || age < -maxClockSkew {
+		http.Error(w, "submission time is out of range", http.StatusBadRequest)
+		return
+	}
+	if err := s.store(submission); errors.Is(err, errStaleSubmission) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	} else if err != nil {
+		log.Printf("[Fleet] Failed to store report from %s: %v", submission.Machine.MachineID, err)
+		http.Error(w, "failed to store report", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[Fleet] Report from %s (%s): %d extensions, %d malicious",
+		submission.Machine.Hostname, submission.Machine.MachineID,
+		submission.Report.TotalExtensions, submission.Report.MaliciousCount)
+	writeJSON(w, http.StatusAccepted, map[string]string{"machineId": submission.Machine.MachineID})
+}
+
+func (s *Server) handleSummary(w http.ResponseWriter, r *http.Request) {
+	submissions, err := s.Submissions()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, Summarize(submissions))
+}
+
+func (s *Server) handleMachines(w http.ResponseWriter, r *http.Request) {
+	submissions, err := s.Submissions()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, Summarize(submissions).Machines)
+}
+
+func (s *Server) handleMachine(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		http.Error(w, "invalid machine ID", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	data, err := os.ReadFile(filepath.Join(s.Dir, id+".json"))
+	s.mu.Unlock()
+	if os.IsNotExist(err) {
+		http.Error(w, "machine not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// VerifySubmission parses a submission body and checks its signature against the
+// submitted public key, which must also match the claimed machine ID. The key itself
+// is self-asserted; Server.Enrolled restricts which keys are accepted.
+func VerifySubmission(body []byte, signature string) (*models.AuditSubmission, error) {
+	var submission models.AuditSubmission
+	if err := json.Unmarshal(body, &submission); err != nil {
+		return nil, fmt.Errorf("invalid submission: %w", err)
+	}
+	if submission.Report == nil {
+		return nil, fmt.Errorf("submission has no audit report")
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(submission.Machine.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid machine public key")
+	}
+	if MachineID(publicKey) != submission.Machine.MachineID {
+		return nil, fmt.Errorf("machine ID does not match its public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || !ed25519.Verify(publicKey, body, sig) {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	return &submission, nil
+}
+
+// store saves a submission as the machine's latest report, refusing one that is not
+// newer than the stored report
+func (s *Server) store(submission *models.AuditSubmission) error {
+	data, err := json.MarshalIndent(submission, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := filepath.Join(s.Dir, submission.Machine.MachineID+".json")
+	if existing, err := os.ReadFile(path); err == nil {
+		var stored models.AuditSubmission
+		if json.Unmarshal(existing, &stored) == nil && !submission.SubmittedAt.After(stored.SubmittedAt) {
+			return errStaleSubmission
+		}
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// Submissions loads the latest submission of every machine
+func (s *Server) Submissions() ([]models.AuditSubmission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data directory: %w", err)
+	}
+	submissions := []models.AuditSubmission{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.Dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var submission models.AuditSubmission
+		if err := json.Unmarshal(data, &submission); err != nil || submission.Report == nil {
+			log.Printf("[Fleet] Skipping unreadable report %s", entry.Name())
+			continue
+		}
+		submissions = append(submissions, submission)
+	}
+	return submissions, nil
+}
+
+// Summarize aggregates submissions into a fleet summary
+func Summarize(submissions []models.AuditSubmission) *models.FleetSummary {
+	summary := &models.FleetSummary{
+		TotalMachines:        len(submissions),
+		MaliciousExtensions:  []models.FleetExtension{},
+		SuspiciousExtensions: []models.FleetExtension{},
+		Machines:             []models.FleetMachine{},
+		GeneratedAt:          time.Now(),
+	}
+
+	malicious := map[string]*models.FleetExtension{}
+	suspicious := map[string]*models.FleetExtension{}
+	for _, submission := range submissions {
+		machine := submission.Machine
+		report := submission.Report
+		summary.Machines = append(summary.Machines, models.FleetMachine{
+			MachineID:       machine.MachineID,
+			Hostname:        machine.Hostname,
+			User:            machine.User,
+			OS:              machine.OS,
+			LastReport:      submission.SubmittedAt,
+			TotalExtensions: report.TotalExtensions,
+			SuspiciousCount: report.SuspiciousCount,
+			MaliciousCount:  report.MaliciousCount,
+		})
+		if report.MaliciousCount > 0 {
+			summary.AffectedMachines++
+		}
+
+		ref := models.FleetMachineRef{MachineID: machine.MachineID, Hostname: machine.Hostname, User: machine.User}
+		for _, result := range report.Results {
+			var group map[string]*models.FleetExtension
+			switch result.TrustLevel {
+			case models.TrustLevelMalicious:
+				group = malicious
+			case models.TrustLevelSuspicious:
+				group = suspicious
+			default:
+				continue
+			}
+			id := strings.ToLower(result.ExtensionID)
+			if group[id] == nil {
+				group[id] = &models.FleetExtension{ExtensionID: result.ExtensionID, TrustLevel: result.TrustLevel}
+			}
+			group[id].Machines = append(group[id].Machines, ref)
+		}
+	}
+
+	summary.MaliciousExtensions = sortedFleetExtensions(malicious)
+	summary.SuspiciousExtensions = sortedFleetExtensions(suspicious)
+	sort.Slice(summary.Machines, func(i, j int) bool {
+		return summary.Machines[i].Hostname < summary.Machines[j].Hostname
+	})
+	return summary
+}
+
+// sortedFleetExtensions orders flagged extensions by the number of affected machines
+func sortedFleetExtensions(group map[string]*models.FleetExtension) []models.FleetExtension {
+	extensions := make([]models.FleetExtension, 0, len(group))
+	for _, ext := range group {
+		extensions = append(extensions, *ext)
+	}
+	sort.Slice(extensions, func(i, j int) bool {
+		if len(extensions[i].Machines) != len(extensions[j].Machines) {
+			return len(extensions[i].Machines) > len(extensions[j].Machines)
+		}
+		return extensions[i].ExtensionID < extensions[j].ExtensionID
+	})
+	return extensions
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
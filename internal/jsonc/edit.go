@@ -0,0 +1,304 @@
+package jsonc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Member is a property of the top-level object of a JSONC document
+type Member struct {
+	Key        string
+	Value      []byte // raw value text, which may contain comments
+	ValueStart int
+	ValueEnd   int
+}
+
+// Element is an item of the top-level array of a JSONC document
+type Element struct {
+	Value []byte
+	Start int
+	End   int
+}
+
+// Members returns the properties of the top-level object in order
+func Members(data []byte) ([]Member, error) {
+	var members []Member
+	_, err := scanContainer(data, '{', func(key string, start, end int) {
+		members = append(members, Member{Key: key, Value: data[start:end], ValueStart: start, ValueEnd: end})
+	})
+	return members, err
+}
+
+// Elements returns the items of the top-level array in order
+func Elements(data []byte) ([]Element, error) {
+	var elements []Element
+	_, err := scanContainer(data, '[', func(_ string, start, end int) {
+		elements = append(elements, Element{Value: data[start:end], Start: start, End: end})
+	})
+	return elements, err
+}
+
+// SetMember sets a property of the top-level object, replacing the value in place when the key
+// exists and appending it otherwise. Comments and formatting elsewhere are kept.
+func SetMember(data []byte, key string, value any) ([]byte, error) {
+	members, err := Members(data)
+	if err != nil {
+		return nil, err
+	}
+	indent := detectIndent(data, members)
+
+	for _, member := range members {
+		if member.Key == key {
+			encoded, err := json.MarshalIndent(value, lineIndent(data, member.ValueStart), indent)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %w", key, err)
+			}
+			return splice(data, member.ValueStart, member.ValueEnd, encoded), nil
+		}
+	}
+
+	encodedKey, _ := json.Marshal(key)
+	encoded, err := json.MarshalIndent(value, indent, indent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	entry := append(append(encodedKey, ": "...), encoded...)
+
+	var last *Member
+	if len(members) > 0 {
+		last = &members[len(members)-1]
+	}
+	return appendItem(data, '{', entry, indent, lastEnd(last))
+}
+
+// AppendElement appends an item to the top-level array, keeping comments and formatting
+func AppendElement(data []byte, value any) ([]byte, error) {
+	elements, err := Elements(data)
+	if err != nil {
+		return nil, err
+	}
+	indent := detectIndent(data, nil)
+	if len(elements) > 0 {
+		indent = lineIndent(data, elements[0].Start)
+	}
+
+	encoded, err := json.MarshalIndent(value, indent, indentUnit(indent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode element: %w", err)
+	}
+
+	end := -1
+	if len(elements) > 0 {
+		end = elements[len(elements)-1].End
+	}
+	return appendItem(data, '[', encoded, indent, end)
+}
+
+// lastEnd returns the end offset of a member's value, or -1 when there is none
+func lastEnd(member *Member) int {
+	if member == nil {
+		return -1
+	}
+	return member.ValueEnd
+}
+
+// appendItem inserts item as the last entry of the top-level container. lastValueEnd is the
+// end of the current last entry (-1 if empty); a separating comma is added after it if needed.
+func appendItem(data []byte, open byte, item []byte, indent string, lastValueEnd int) ([]byte, error) {
+	closeAt, err := scanContainer(data, open, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Insert after the last significant text (including trailing comments) before the close
+	insertAt := closeAt
+	for insertAt > 0 && isSpace(data[insertAt-1]) {
+		insertAt--
+	}
+
+	newline := []byte("\n")
+	if bytes.Contains(data, []byte("\r\n")) {
+		newline = []byte("\r\n")
+	}
+	var insert []byte
+	insert = append(insert, newline...)
+	insert = append(insert, indent...)
+	insert = append(insert, item...)
+	if lastValueEnd < 0 {
+		insert = append(insert, newline...)
+	}
+	out := splice(data, insertAt, insertAt, insert)
+
+	if lastValueEnd >= 0 && nextSignificant(data, lastValueEnd) != ',' {
+		out = splice(out, lastValueEnd, lastValueEnd, []byte(","))
+	}
+	return out, nil
+}
+
+// scanContainer walks the top-level object or array, calling visit for each entry, and returns
+// the offset of the closing bracket
+func scanContainer(data []byte, open byte, visit func(key string, start, end int)) (int, error) {
+	closeByte := byte('}')
+	if open == '[' {
+		closeByte = ']'
+	}
+
+	i := skipInsignificant(data, 0)
+	if i >= len(data) || data[i] != open {
+		return 0, fmt.Errorf("expected %q at top level", open)
+	}
+	i = skipInsignificant(data, i+1)
+
+	for i < len(data) && data[i] != closeByte {
+		var key string
+		if open == '{' {
+			if data[i] != '"' {
+				return 0, fmt.Errorf("expected property name at offset %d", i)
+			}
+			end := skipString(data, i)
+			if err := json.Unmarshal(data[i:end], &key); err != nil {
+				return 0, fmt.Errorf("invalid property name at offset %d: %w", i, err)
+			}
+			i = skipInsignificant(data, end)
+			if i >= len(data) || data[i] != ':' {
+				return 0, fmt.Errorf("expected ':' after %q", key)
+			}
+			i = skipInsignificant(data, i+1)
+		}
+
+		start := i
+		end, err := skipValue(data, i)
+		if err != nil {
+			return 0, err
+		}
+		if visit != nil {
+			visit(key, start, end)
+		}
+
+		i = skipInsignificant(data, end)
+		if i < len(data) && data[i] == ',' {
+			i = skipInsignificant(data, i+1)
+		} else if i < len(data) && data[i] != closeByte {
+			return 0, fmt.Errorf("expected ',' or %q at offset %d", closeByte, i)
+		}
+	}
+
+	if i >= len(data) {
+		return 0, fmt.Errorf("unterminated %q", open)
+	}
+	return i, nil
+}
+
+// skipValue returns the offset just past the value starting at data[i]
+func skipValue(data []byte, i int) (int, error) {
+	if i >= len(data) {
+		return 0, fmt.Errorf("unexpected end of input")
+	}
+	switch data[i] {
+	case '"':
+		return skipString(data, i), nil
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(data); j++ {
+			switch {
+			case data[j] == '"':
+				j = skipString(data, j) - 1
+			case data[j] == '/' && j+1 < len(data) && (data[j+1] == '/' || data[j+1] == '*'):
+				j = skipComment(data, j) - 1
+			case data[j] == '{' || data[j] == '[':
+				depth++
+			case data[j] == '}' || data[j] == ']':
+				depth--
+				if depth == 0 {
+					return j + 1, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("unterminated value at offset %d", i)
+	default:
+		j := i
+		for j < len(data) && !isSpace(data[j]) && data[j] != ',' && data[j] != '}' && data[j] != ']' && data[j] != '/' {
+			j++
+		}
+		if j == i {
+			return 0, fmt.Errorf("expected value at offset %d", i)
+		}
+		return j, nil
+	}
+}
+
+// skipInsignificant returns the offset of the next byte that is not whitespace or a comment
+func skipInsignificant(data []byte, i int) int {
+	for i < len(data) {
+		switch {
+		case isSpace(data[i]):
+			i++
+		case data[i] == '/' && i+1 < len(data) && (data[i+1] == '/' || data[i+1] == '*'):
+			i = skipComment(data, i)
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// skipComment returns the offset just past the comment starting at data[i]
+func skipComment(data []byte, i int) int {
+	if data[i+1] == '/' {
+		for i < len(data) && data[i] != '\n' {
+			i++
+		}
+		return i
+	}
+	i += 2
+	for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+		i++
+	}
+	return min(i+2, len(data))
+}
+
+// detectIndent returns the indentation of the first entry, defaulting to a tab
+func detectIndent(data []byte, members []Member) string {
+	if len(members) > 0 {
+		// The key starts on the same line as the value
+		if indent := lineIndent(data, members[0].ValueStart); indent != "" {
+			return indent
+		}
+	}
+	return "\t"
+}
+
+// lineIndent returns the leading whitespace of the line containing offset
+func lineIndent(data []byte, offset int) string {
+	start := bytes.LastIndexByte(data[:offset], '\n') + 1
+	end := start
+	for end < offset && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[start:end])
+}
+
+// indentUnit guesses one level of indentation from a line's indent
+func indentUnit(indent string) string {
+	if indent == "" || indent[0] == '\t' {
+		return "\t"
+	}
+	if len(indent)%4 == 0 {
+		return "    "
+	}
+	return "  "
+}
+
+// splice replaces data[start:end] with insert, returning a new slice
+func splice(data []byte, start, end int, insert []byte) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(insert))
+	out = append(out, data[:start]...)
+	out = append(out, insert...)
+	return append(out, data[end:]...)
+}
+
+// isSpace reports whether c is JSON whitespace
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,79 @@
+package jsonc
+
+// Standardize converts JSON with comments (as used by VS Code's settings.json and argv.json)
+// into plain JSON by removing // and /* */ comments and trailing commas.
+// String contents are left untouched.
+func Standardize(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"':
+			end := skipString(data, i)
+			out = append(out, data[i:end]...)
+			i = end - 1
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				if data[i] == '\n' {
+					out = append(out, '\n')
+				}
+				i++
+			}
+			i++
+		case c == ',':
+			if next := nextSignificant(data, i+1); next == '}' || next == ']' {
+				continue
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// skipString returns the index just past the string literal starting at data[start]
+func skipString(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// nextSignificant returns the next byte after i that is not whitespace or part of a comment
+func nextSignificant(data []byte, i int) byte {
+	for i < len(data) {
+		switch {
+		case data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r':
+			i++
+		case data[i] == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case data[i] == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i += 2
+		default:
+			return data[i]
+		}
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+package jsonc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStandardize(t *testing.T) {
+	input := `// Settings
+{
+	/* block
+	   comment */
+	"url": "https://example.com/*not-a-comment*/", // trailing comment
+	"list": [1, 2, 3,],
+	"escaped": "quote \" and // slash",
+	"nested": {"a": true, /* last */},
+}`
+
+	var result map[string]any
+	if err := json.Unmarshal(Standardize([]byte(input)), &result); err != nil {
+		t.Fatalf("Standardized output is not valid JSON: %v\n%s", err, Standardize([]byte(input)))
+	}
+
+	if result["url"] != "https://example.com/*not-a-comment*/" {
+		t.Errorf("url = %v", result["url"])
+	}
+	if result["escaped"] != `quote " and // slash` {
+		t.Errorf("escaped = %v", result["escaped"])
+	}
+	if list, ok := result["list"].([]any); !ok || len(list) != 3 {
+		t.Errorf("list = %v", result["list"])
+	}
+}
+
+func TestStandardizePlainJSON(t *testing.T) {
+	input := `{"a": [1, 2], "b": "c"}`
+	if got := string(Standardize([]byte(input))); got != input {
+		t.Errorf("Standardize changed plain JSON: %s", got)
+	}
+}
+
+func TestMembers(t *testing.T) {
+	input := `{
+	// editor
+	"editor.fontSize": 14, /* inline */
+	"files.exclude": {"**/.git": true, "url": "a,b}"},
+	"list": [1, [2, 3]],
+}`
+	members, err := Members([]byte(input))
+	if err != nil {
+		t.Fatalf("Members failed: %v", err)
+	}
+	if len(members) != 3 {
+		t.Fatalf("Expected 3 members, got %d", len(members))
+	}
+	if members[0].Key != "editor.fontSize" || string(members[0].Value) != "14" {
+		t.Errorf("members[0] = %s: %s", members[0].Key, members[0].Value)
+	}
+	if string(members[1].Value) != `{"**/.git": true, "url": "a,b}"}` {
+		t.Errorf("members[1] value = %s", members[1].Value)
+	}
+	if string(members[2].Value) != "[1, [2, 3]]" {
+		t.Errorf("members[2] value = %s", members[2].Value)
+	}
+
+	if _, err := Members([]byte(`[1, 2]`)); err == nil {
+		t.Error("Expected error for a top-level array")
+	}
+}
+
+func TestSetMember(t *testing.T) {
+	input := "{\n\t// keep me\n\t\"a\": 1, // trailing\n\t\"b\": \"x\" // last\n}\n"
+
+	out, err := SetMember([]byte(input), "a", 2)
+	if err != nil {
+		t.Fatalf("SetMember(replace) failed: %v", err)
+	}
+	want := "{\n\t// keep me\n\t\"a\": 2, // trailing\n\t\"b\": \"x\" // last\n}\n"
+	if string(out) != want {
+		t.Errorf("replace:\n%s\nwant:\n%s", out, want)
+	}
+
+	out, err = SetMember(out, "c", map[string]bool{"on": true})
+	if err != nil {
+		t.Fatalf("SetMember(insert) failed: %v", err)
+	}
+	want = "{\n\t// keep me\n\t\"a\": 2, // trailing\n\t\"b\": \"x\", // last\n\t\"c\": {\n\t\t\"on\": true\n\t}\n}\n"
+	if string(out) != want {
+		t.Errorf("insert:\n%s\nwant:\n%s", out, want)
+	}
+
+	var parsed map[string]any
+	if err := json.Unmarshal(Standardize(out), &parsed); err != nil {
+		t.Errorf("Result is not valid JSONC: %v", err)
+	}
+
+	out, err = SetMember([]byte("{}"), "a", true)
+	if err != nil {
+		t.Fatalf("SetMember(empty) failed: %v", err)
+	}
+	if string(out) != "{\n\t\"a\": true\n}" {
+		t.Errorf("empty object insert = %q", out)
+	}
+}
+
+func TestAppendElement(t *testing.T) {
+	input := "// Keybindings\n[\n    {\"key\": \"ctrl+a\", \"command\": \"a\"},\n]\n"
+
+	out, err := AppendElement([]byte(input), map[string]string{"key": "ctrl+b"})
+	if err != nil {
+		t.Fatalf("AppendElement failed: %v", err)
+	}
+	want := "// Keybindings\n[\n    {\"key\": \"ctrl+a\", \"command\": \"a\"},\n    {\n        \"key\": \"ctrl+b\"\n    }\n]\n"
+	if string(out) != want {
+		t.Errorf("append:\n%s\nwant:\n%s", out, want)
+	}
+
+	elements, err := Elements(out)
+	if err != nil || len(elements) != 2 {
+		t.Errorf("Expected 2 elements after append, got %d (%v)", len(elements), err)
+	}
+}
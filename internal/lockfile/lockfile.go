@@ -0,0 +1,347 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// DefaultFileName is the lockfile name looked up in the current directory
+const DefaultFileName = "vsynx.lock"
+
+var (
+	extensionIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*\.[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	sha256Pattern      = regexp.MustCompile(`^[a-fA-F0-9]{64}$`)
+)
+
+// Fetcher validates and downloads extension packages for Apply and Generate.
+// validation.Validator implements it.
+type Fetcher interface {
+	ValidateExtension(extensionID string) (*models.ValidationResult, error)
+	DownloadExtensionVersion(extensionID, version, source string) ([]byte, string, error)
+}
+
+// Load reads and validates a lockfile
+func Load(path string) (*models.Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	var lock models.Lockfile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile %s: %w", path, err)
+	}
+	if err := Validate(&lock); err != nil {
+		return nil, fmt.Errorf("invalid lockfile %s: %w", path, err)
+	}
+	return &lock, nil
+}
+
+// Save writes a lockfile with stable formatting so it diffs cleanly in version control
+func Save(path string, lock *models.Lockfile) error {
+	if err := Validate(lock); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lockfile: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// Validate checks a lockfile for malformed or conflicting entries
+func Validate(lock *models.Lockfile) error {
+	if lock.Version != models.LockfileVersion {
+		return fmt.Errorf("unsupported lockfile version %d (expected %d)", lock.Version, models.LockfileVersion)
+	}
+
+	seen := map[string]bool{}
+	for i, entry := range lock.Extensions {
+		if !extensionIDPattern.MatchString(entry.ID) {
+			return fmt.Errorf("entry %d: invalid extension ID %q", i, entry.ID)
+		}
+		if entry.Version == "" {
+			return fmt.Errorf("%s: version is required", entry.ID)
+		}
+		if !editor.ValidVersion(entry.Version) {
+			return fmt.Errorf("%s: invalid version %q", entry.ID, entry.Version)
+		}
+		if entry.SHA256 != "" && !sha256Pattern.MatchString(entry.SHA256) {
+			return fmt.Errorf("%s: sha256 must be 64 hex characters", entry.ID)
+		}
+		switch entry.Source {
+		case "", "marketplace", "openvsx":
+		default:
+			return fmt.Errorf("%s: unknown source %q (expected marketplace or openvsx)", entry.ID, entry.Source)
+		}
+
+		editors := EntryEditors(lock, entry)
+		if len(editors) == 0 {
+			return fmt.Errorf("%s: no target editors (set editors on the entry or at the top level)", entry.ID)
+		}
+		for _, editorID := range editors {
+			key := string(editorID) + "/" + strings.ToLower(entry.ID)
+			if seen[key] {
+				return fmt.Errorf("%s is listed more than once for %s", entry.ID, editorID)
+			}
+			seen[key] = true
+		}
+	}
+	return nil
+}
+
+// EntryEditors returns the editors an entry applies to
+func EntryEditors(lock *models.Lockfile, entry models.LockEntry) []models.EditorType {
+	if len(entry.Editors) > 0 {
+		return entry.Editors
+	}
+	return lock.Editors
+}
+
+// TargetEditors returns every editor referenced by the lockfile, in order of first use
+func TargetEditors(lock *models.Lockfile) []models.EditorType {
+	var editors []models.EditorType
+	seen := map[models.EditorType]bool{}
+	add := func(editorID models.EditorType) {
+		if !seen[editorID] {
+			seen[editorID] = true
+			editors = append(editors, editorID)
+		}
+	}
+	for _, editorID := range lock.Editors {
+		add(editorID)
+	}
+	for _, entry := range lock.Extensions {
+		for _, editorID := range entry.Editors {
+			add(editorID)
+		}
+	}
+	return editors
+}
+
+// InstalledVersions reads the installed extension versions of every editor the lockfile targets
+func InstalledVersions(lock *models.Lockfile) (map[models.EditorType]map[string]string, error) {
+	installed := map[models.EditorType]map[string]string{}
+	for _, editorID := range TargetEditors(lock) {
+		profile, err := editor.GetEditorProfile(editorID)
+		if err != nil {
+			return nil, err
+		}
+		versions, err := editor.InstalledVersions(profile.ExtensionsDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s extensions: %w", editorID, err)
+		}
+		installed[editorID] = versions
+	}
+	return installed, nil
+}
+
+// Plan compares a lockfile with the installed versions (editor -> lowercase ID -> version).
+// Extensions installed in a targeted editor but absent from the lockfile are listed as
+// unmanaged, or planned for removal when prune is set.
+func Plan(lock *models.Lockfile, installed map[models.EditorType]map[string]string, prune bool) *models.LockPlan {
+	plan := &models.LockPlan{
+		Steps:     []models.LockPlanStep{},
+		Unmanaged: map[models.EditorType][]string{},
+	}
+
+	managed := map[models.EditorType]map[string]bool{}
+	for _, entry := range lock.Extensions {
+		id := strings.ToLower(entry.ID)
+		for _, editorID := range EntryEditors(lock, entry) {
+			if managed[editorID] == nil {
+				managed[editorID] = map[string]bool{}
+			}
+			managed[editorID][id] = true
+
+			current := installed[editorID][id]
+			step := models.LockPlanStep{
+				Editor:         editorID,
+				ExtensionID:    entry.ID,
+				CurrentVersion: current,
+				TargetVersion:  entry.Version,
+				SHA256:         strings.ToLower(entry.SHA256),
+				Source:         entry.Source,
+			}
+			switch c := editor.CompareVersions(current, entry.Version); {
+			case current == "":
+				step.Action = models.LockActionInstall
+			case c < 0:
+				step.Action = models.LockActionUpdate
+			case c > 0:
+				step.Action = models.LockActionDowngrade
+			default:
+				plan.UpToDate++
+				continue
+			}
+			plan.Steps = append(plan.Steps, step)
+		}
+	}
+
+	for _, editorID := range TargetEditors(lock) {
+		var extra []string
+		for id := range installed[editorID] {
+			if !managed[editorID][id] {
+				extra = append(extra, id)
+			}
+		}
+		sort.Strings(extra)
+
+		if !prune {
+			if len(extra) > 0 {
+				plan.Unmanaged[editorID] = extra
+			}
+			continue
+		}
+		for _, id := range extra {
+			plan.Steps = append(plan.Steps, models.LockPlanStep{
+				Editor:         editorID,
+				ExtensionID:    id,
+				Action:         models.LockActionRemove,
+				CurrentVersion: installed[editorID][id],
+			})
+		}
+	}
+
+	return plan
+}
+
+// Apply carries out a plan. Each extension is validated and its package downloaded once,
+// checked against the lockfile's SHA256 and version, and only then installed into the
+// editors that need it. Malicious extensions are refused.
+func Apply(plan *models.LockPlan, fetcher Fetcher) *models.LockApplyReport {
+	report := &models.LockApplyReport{Results: make([]models.LockApplyResult, 0, len(plan.Steps))}
+
+	type download struct {
+		data  []byte
+		hash  string
+		trust models.TrustLevel
+		err   error
+	}
+	downloads := map[string]*download{}
+
+	for _, step := range plan.Steps {
+		result := models.LockApplyResult{Step: step}
+
+		profile, err := editor.GetEditorProfile(step.Editor)
+		if err != nil {
+			result.Error = err.Error()
+			report.Results = append(report.Results, result)
+			report.Failed++
+			continue
+		}
+
+		if step.Action == models.LockActionRemove {
+			_, err = editor.UninstallExtension(profile, step.ExtensionID)
+		} else {
+			key := strings.ToLower(step.ExtensionID) + "@" + step.TargetVersion + "@" + step.Source
+			d, ok := downloads[key]
+			if !ok {
+				d = &download{}
+				d.trust, d.err = validateStep(fetcher, step)
+				if d.err == nil {
+					d.data, d.hash, d.err = fetchVerified(fetcher, step)
+				}
+				downloads[key] = d
+			}
+			err = d.err
+			result.TrustLevel = d.trust
+			result.SHA256 = d.hash
+			if err == nil {
+				_, err = editor.InstallVSIX(profile, d.data, stepSource(step), step.ExtensionID)
+			}
+		}
+
+		if err != nil {
+			result.Error = err.Error()
+			report.Failed++
+		} else {
+			result.Success = true
+			report.Succeeded++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// validateStep validates the extension of an install step, refusing Malicious ones
+func validateStep(fetcher Fetcher, step models.LockPlanStep) (models.TrustLevel, error) {
+	validation, err := fetcher.ValidateExtension(step.ExtensionID)
+	if err != nil {
+		return "", fmt.Errorf("validation failed: %w", err)
+	}
+	if validation.TrustLevel == models.TrustLevelMalicious {
+		return validation.TrustLevel, fmt.Errorf("refusing to install a Malicious extension: %s", validation.Recommendation)
+	}
+	return validation.TrustLevel, nil
+}
+
+// stepSource returns the registry a step installs from; entries without a source use the Marketplace
+func stepSource(step models.LockPlanStep) string {
+	if step.Source == "" {
+		return "marketplace"
+	}
+	return step.Source
+}
+
+// fetchVerified downloads the package for a step and checks its hash and version
+func fetchVerified(fetcher Fetcher, step models.LockPlanStep) ([]byte, string, error) {
+	data, hash, err := fetcher.DownloadExtensionVersion(step.ExtensionID, step.TargetVersion, step.Source)
+	if err != nil {
+		return nil, "", err
+	}
+	if step.SHA256 != "" && !strings.EqualFold(hash, step.SHA256) {
+		return nil, hash, fmt.Errorf("SHA256 mismatch: lockfile has %s, downloaded package is %s", step.SHA256, hash)
+	}
+
+	pkg, err := editor.ReadVSIX(data)
+	if err != nil {
+		return nil, hash, err
+	}
+	if pkg.Version != step.TargetVersion {
+		return nil, hash, fmt.Errorf("downloaded package is version %s, lockfile pins %s", pkg.Version, step.TargetVersion)
+	}
+	return data, hash, nil
+}
+
+// Generate creates a lockfile pinning every extension installed in an editor to its
+// current version, targeting the given editors. With fetcher set, each package is
+// downloaded to record its SHA256.
+func Generate(versions map[string]string, editors []models.EditorType, fetcher Fetcher) (*models.Lockfile, error) {
+	lock := &models.Lockfile{
+		Version:    models.LockfileVersion,
+		Editors:    editors,
+		Extensions: []models.LockEntry{},
+	}
+
+	ids := make([]string, 0, len(versions))
+	for id := range versions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		entry := models.LockEntry{ID: id, Version: versions[id], Source: "marketplace"}
+		if fetcher != nil {
+			_, hash, err := fetcher.DownloadExtensionVersion(id, entry.Version, entry.Source)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash %s@%s: %w", id, entry.Version, err)
+			}
+			entry.SHA256 = hash
+		}
+		lock.Extensions = append(lock.Extensions, entry)
+	}
+
+	return lock, nil
+}
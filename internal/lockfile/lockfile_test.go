@@ -0,0 +1,286 @@
+package lockfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/validation"
+)
+
+// buildTestVSIX creates an in-memory VSIX archive for an extension version
+func buildTestVSIX(t *testing.T, id, version string) []byte {
+	t.Helper()
+	publisher, name, _ := strings.Cut(id, ".")
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string]string{
+		"extension.vsixmanifest": fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<PackageManifest Version="2.0.0" xmlns="http://schemas.microsoft.com/developer/vsx-schema/2011">
+  <Metadata>
+    <Identity Language="en-US" Id="%s" Version="%s" Publisher="%s" />
+  </Metadata>
+</PackageManifest>`, name, version, publisher),
+		"extension/package.json": fmt.Sprintf(`{"publisher": %q, "name": %q, "version": %q}`, publisher, name, version),
+	}
+	for fileName, content := range files {
+		f, _ := w.Create(fileName)
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// fakeFetcher serves test packages and trust levels and counts downloads
+type fakeFetcher struct {
+	packages  map[string][]byte
+	trust     map[string]models.TrustLevel
+	sources   []string
+	downloads int
+}
+
+func (f *fakeFetcher) ValidateExtension(extensionID string) (*models.ValidationResult, error) {
+	level, ok := f.trust[extensionID]
+	if !ok {
+		level = models.TrustLevelLegitimate
+	}
+	return &models.ValidationResult{ExtensionID: extensionID, TrustLevel: level}, nil
+}
+
+func (f *fakeFetcher) DownloadExtensionVersion(extensionID, version, source string) ([]byte, string, error) {
+	f.downloads++
+	f.sources = append(f.sources, source)
+	data, ok := f.packages[extensionID+"@"+version]
+	if !ok {
+		return nil, "", fmt.Errorf("not found: %s@%s", extensionID, version)
+	}
+	return data, validation.ComputeSHA256(data), nil
+}
+
+func TestValidate(t *testing.T) {
+	valid := models.Lockfile{
+		Version:    models.LockfileVersion,
+		Editors:    []models.EditorType{models.EditorVSCode},
+		Extensions: []models.LockEntry{{ID: "pub.ext", Version: "1.0.0"}},
+	}
+	if err := Validate(&valid); err != nil {
+		t.Errorf("Valid lockfile rejected: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		entry models.LockEntry
+	}{
+		{"bad id", models.LockEntry{ID: "noperiod", Version: "1.0.0"}},
+		{"missing version", models.LockEntry{ID: "pub.ext"}},
+		{"traversal version", models.LockEntry{ID: "pub.ext", Version: "../../x"}},
+		{"non-version", models.LockEntry{ID: "pub.ext", Version: "latest"}},
+		{"bad sha", models.LockEntry{ID: "pub.ext", Version: "1.0.0", SHA256: "abc"}},
+		{"bad source", models.LockEntry{ID: "pub.ext", Version: "1.0.0", Source: "github"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lock := valid
+			lock.Extensions = []models.LockEntry{tt.entry}
+			if err := Validate(&lock); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+
+	duplicate := valid
+	duplicate.Extensions = []models.LockEntry{{ID: "pub.ext", Version: "1.0.0"}, {ID: "Pub.Ext", Version: "2.0.0"}}
+	if err := Validate(&duplicate); err == nil {
+		t.Error("Expected an error for duplicate entries")
+	}
+
+	noEditors := valid
+	noEditors.Editors = nil
+	if err := Validate(&noEditors); err == nil {
+		t.Error("Expected an error for entries without editors")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	lock := &models.Lockfile{
+		Version: models.LockfileVersion,
+		Editors: []models.EditorType{models.EditorVSCode, models.EditorCursor},
+		Extensions: []models.LockEntry{
+			{ID: "pub.same", Version: "1.0.0"},
+			{ID: "pub.new", Version: "2.0.0"},
+			{ID: "pub.pinned", Version: "1.5.0", Editors: []models.EditorType{models.EditorVSCode}},
+		},
+	}
+	installed := map[models.EditorType]map[string]string{
+		models.EditorVSCode: {"pub.same": "1.0.0", "pub.pinned": "1.6.0", "pub.extra": "0.1.0"},
+		models.EditorCursor: {"pub.same": "0.9.0"},
+	}
+
+	plan := Plan(lock, installed, false)
+
+	actions := map[string]models.LockAction{}
+	for _, step := range plan.Steps {
+		actions[string(step.Editor)+"/"+step.ExtensionID] = step.Action
+	}
+	expected := map[string]models.LockAction{
+		"cursor/pub.same":   models.LockActionUpdate,
+		"vscode/pub.new":    models.LockActionInstall,
+		"cursor/pub.new":    models.LockActionInstall,
+		"vscode/pub.pinned": models.LockActionDowngrade,
+	}
+	for key, action := range expected {
+		if actions[key] != action {
+			t.Errorf("%s: action = %q, want %q", key, actions[key], action)
+		}
+	}
+	if len(plan.Steps) != len(expected) {
+		t.Errorf("Expected %d steps, got %+v", len(expected), plan.Steps)
+	}
+	if plan.UpToDate != 1 {
+		t.Errorf("UpToDate = %d, want 1", plan.UpToDate)
+	}
+	if got := plan.Unmanaged[models.EditorVSCode]; len(got) != 1 || got[0] != "pub.extra" {
+		t.Errorf("Unmanaged = %v, want [pub.extra]", got)
+	}
+
+	pruned := Plan(lock, installed, true)
+	last := pruned.Steps[len(pruned.Steps)-1]
+	if last.Action != models.LockActionRemove || last.ExtensionID != "pub.extra" {
+		t.Errorf("Expected pub.extra to be removed with prune, got %+v", last)
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	dirA := filepath.Join(t.TempDir(), "a")
+	dirB := filepath.Join(t.TempDir(), "b")
+	for id, dir := range map[models.EditorType]string{"team-a": dirA, "team-b": dirB} {
+		if _, err := editor.AddCustomEditorProfile(models.EditorProfile{ID: id, ExtensionsDir: dir}); err != nil {
+			t.Fatalf("Failed to add editor: %v", err)
+		}
+	}
+
+	good := buildTestVSIX(t, "pub.ext", "1.2.0")
+	tampered := buildTestVSIX(t, "pub.bad", "1.0.0")
+	fetcher := &fakeFetcher{packages: map[string][]byte{
+		"pub.ext@1.2.0": good,
+		"pub.bad@1.0.0": tampered,
+	}}
+
+	lock := &models.Lockfile{
+		Version: models.LockfileVersion,
+		Editors: []models.EditorType{"team-a", "team-b"},
+		Extensions: []models.LockEntry{
+			{ID: "pub.ext", Version: "1.2.0", SHA256: validation.ComputeSHA256(good)},
+			{ID: "pub.bad", Version: "1.0.0", SHA256: strings.Repeat("0", 64)},
+		},
+	}
+
+	installed, err := InstalledVersions(lock)
+	if err != nil {
+		t.Fatalf("InstalledVersions failed: %v", err)
+	}
+	report := Apply(Plan(lock, installed, false), fetcher)
+
+	if report.Succeeded != 2 || report.Failed != 2 {
+		t.Errorf("Succeeded/Failed = %d/%d, want 2/2: %+v", report.Succeeded, report.Failed, report.Results)
+	}
+	if fetcher.downloads != 2 {
+		t.Errorf("Expected one download per package, got %d", fetcher.downloads)
+	}
+	for _, result := range report.Results {
+		if result.Step.ExtensionID == "pub.bad" && !strings.Contains(result.Error, "SHA256 mismatch") {
+			t.Errorf("Expected SHA256 mismatch for pub.bad, got %q", result.Error)
+		}
+	}
+
+	// Both editors now match the lockfile for pub.ext
+	installed, _ = InstalledVersions(lock)
+	for _, editorID := range lock.Editors {
+		if installed[editorID]["pub.ext"] != "1.2.0" {
+			t.Errorf("%s has pub.ext %q, want 1.2.0", editorID, installed[editorID]["pub.ext"])
+		}
+		if _, ok := installed[editorID]["pub.bad"]; ok {
+			t.Errorf("%s should not have the tampered package installed", editorID)
+		}
+	}
+}
+
+func TestApplyValidatesAndUsesEntrySource(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	dir := filepath.Join(t.TempDir(), "exts")
+	if _, err := editor.AddCustomEditorProfile(models.EditorProfile{ID: "team", ExtensionsDir: dir}); err != nil {
+		t.Fatalf("Failed to add editor: %v", err)
+	}
+
+	fetcher := &fakeFetcher{
+		packages: map[string][]byte{
+			"pub.ovsx@1.0.0": buildTestVSIX(t, "pub.ovsx", "1.0.0"),
+			"pub.evil@1.0.0": buildTestVSIX(t, "pub.evil", "1.0.0"),
+		},
+		trust: map[string]models.TrustLevel{"pub.evil": models.TrustLevelMalicious},
+	}
+	lock := &models.Lockfile{
+		Version: models.LockfileVersion,
+		Editors: []models.EditorType{"team"},
+		Extensions: []models.LockEntry{
+			{ID: "pub.ovsx", Version: "1.0.0", Source: "openvsx"},
+			{ID: "pub.evil", Version: "1.0.0"},
+		},
+	}
+
+	report := Apply(Plan(lock, map[models.EditorType]map[string]string{}, false), fetcher)
+
+	if report.Succeeded != 1 || report.Failed != 1 {
+		t.Fatalf("Succeeded/Failed = %d/%d, want 1/1: %+v", report.Succeeded, report.Failed, report.Results)
+	}
+	if strings.Join(fetcher.sources, ",") != "openvsx" {
+		t.Errorf("Downloads from %v, want only the openvsx entry", fetcher.sources)
+	}
+	for _, result := range report.Results {
+		if result.Step.ExtensionID == "pub.evil" && !strings.Contains(result.Error, "Malicious") {
+			t.Errorf("Expected pub.evil to be refused, got %q", result.Error)
+		}
+	}
+
+	index, err := editor.ReadExtensionsIndex(dir)
+	if err != nil || len(index) != 1 {
+		t.Fatalf("Expected one index entry, got %+v (%v)", index, err)
+	}
+	if index[0].Metadata["source"] != "gallery" || index[0].Metadata["registry"] != "openvsx" {
+		t.Errorf("Index metadata = %v, want source gallery from registry openvsx", index[0].Metadata)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	fetcher := &fakeFetcher{packages: map[string][]byte{"pub.ext@1.0.0": buildTestVSIX(t, "pub.ext", "1.0.0")}}
+
+	lock, err := Generate(map[string]string{"pub.ext": "1.0.0"}, []models.EditorType{models.EditorVSCode}, fetcher)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(lock.Extensions) != 1 || lock.Extensions[0].SHA256 == "" {
+		t.Errorf("Expected one hashed entry, got %+v", lock.Extensions)
+	}
+
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	if err := Save(path, lock); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Extensions[0].Version != "1.0.0" {
+		t.Errorf("Loaded version = %s, want 1.0.0", loaded.Extensions[0].Version)
+	}
+}
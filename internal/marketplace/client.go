@@ -7,20 +7,33 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/yourusername/secureopenvsx/internal/asset"
 	"github.com/yourusername/secureopenvsx/internal/models"
 )
 
 const (
 	MarketplaceAPIURL = "https://marketplace.visualstudio.com/_apis/public/gallery/extensionquery"
+	GalleryURL        = "https://marketplace.visualstudio.com/_apis/public/gallery"
 	APIVersion        = "7.0-preview.1"
 	UserAgent         = "Vsynx/1.0"
+
+	// SignatureAssetType is the asset holding a version's signature archive (.signature.p7s)
+	SignatureAssetType = "Microsoft.VisualStudio.Services.VsixSignature"
+
+	readmeAssetType    = "Microsoft.VisualStudio.Services.Content.Details"
+	changelogAssetType = "Microsoft.VisualStudio.Services.Content.Changelog"
+	licenseAssetType   = "Microsoft.VisualStudio.Services.Content.License"
+	manifestAssetType  = "Microsoft.VisualStudio.Code.Manifest"
 )
 
 // Client handles communication with the Microsoft Marketplace API
 type Client struct {
 	httpClient *http.Client
+	apiURL     string
 }
 
 // NewClient creates a new marketplace API client
@@ -29,6 +42,7 @@ func NewClient() *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		apiURL: MarketplaceAPIURL,
 	}
 }
 
@@ -39,7 +53,11 @@ type marketplaceQuery struct {
 }
 
 type filter struct {
-	Criteria []criterion `json:"criteria"`
+	Criteria   []criterion `json:"criteria"`
+	PageNumber int         `json:"pageNumber,omitempty"`
+	PageSize   int         `json:"pageSize,omitempty"`
+	SortBy     int         `json:"sortBy,omitempty"`
+	SortOrder  int         `json:"sortOrder,omitempty"`
 }
 
 type criterion struct {
@@ -47,139 +65,240 @@ type criterion struct {
 	Value      string `json:"value"`
 }
 
+// Gallery query filter types
+const (
+	filterTag              = 1
+	filterCategory         = 5
+	filterExtensionName    = 7
+	filterTarget           = 8
+	filterSearchText       = 10
+	filterExcludeWithFlags = 12
+)
+
+// Gallery sort orders
+const (
+	sortByRelevance   = 0
+	sortByLastUpdated = 1
+	sortByInstalls    = 4
+	sortByRating      = 12 // weighted rating
+	sortDescending    = 2
+)
+
+// unpublishedFlag excludes unpublished extensions from search results
+const unpublishedFlag = "4096"
+
+// galleryExtension is an extension in a marketplace API response
+type galleryExtension struct {
+	Publisher struct {
+		PublisherName    string `json:"publisherName"`
+		PublisherID      string `json:"publisherId"`
+		Domain           string `json:"domain"`
+		IsDomainVerified bool   `json:"isDomainVerified"`
+		Flags            string `json:"flags"`
+	} `json:"publisher"`
+	ExtensionName    string           `json:"extensionName"`
+	DisplayName      string           `json:"displayName"`
+	ShortDescription string           `json:"shortDescription"`
+	Flags            string           `json:"flags"`
+	Categories       []string         `json:"categories"`
+	Tags             []string         `json:"tags"`
+	Versions         []galleryVersion `json:"versions"`
+	Statistics       []struct {
+		StatisticName string  `json:"statisticName"`
+		Value         float64 `json:"value"`
+	} `json:"statistics"`
+}
+
+// galleryVersion is one version (and target platform) of a gallery extension
+type galleryVersion struct {
+	Version        string `json:"version"`
+	TargetPlatform string `json:"targetPlatform"`
+	LastUpdated    string `json:"lastUpdated"`
+	Flags          string `json:"flags"`
+	Files          []struct {
+		AssetType string `json:"assetType"`
+		Source    string `json:"source"`
+	} `json:"files"`
+	Properties []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"properties"`
+}
+
 // marketplaceResponse represents the response from the marketplace API
 type marketplaceResponse struct {
 	Results []struct {
-		Extensions []struct {
-			Publisher struct {
-				PublisherName    string `json:"publisherName"`
-				PublisherID      string `json:"publisherId"`
-				Domain           string `json:"domain"`
-				IsDomainVerified bool   `json:"isDomainVerified"`
-				Flags            string `json:"flags"`
-			} `json:"publisher"`
-			ExtensionName    string `json:"extensionName"`
-			DisplayName      string `json:"displayName"`
-			ShortDescription string `json:"shortDescription"`
-			Flags            string `json:"flags"`
-			Versions         []struct {
-				Version     string `json:"version"`
-				LastUpdated string `json:"lastUpdated"`
-				Flags       string `json:"flags"`
-				Files       []struct {
-					AssetType string `json:"assetType"`
-					Source    string `json:"source"`
-				} `json:"files"`
-				Properties []struct {
-					Key   string `json:"key"`
-					Value string `json:"value"`
-				} `json:"properties"`
-			} `json:"versions"`
-		} `json:"extensions"`
+		Extensions     []galleryExtension `json:"extensions"`
+		ResultMetadata []struct {
+			MetadataType  string `json:"metadataType"`
+			MetadataItems []struct {
+				Name  string `json:"name"`
+				Count int    `json:"count"`
+			} `json:"metadataItems"`
+		} `json:"resultMetadata"`
 	} `json:"results"`
 }
 
 // SearchExtensions searches for extensions using keywords or partial names
 func (c *Client) SearchExtensions(searchTerm string) ([]*models.ExtensionMetadata, error) {
-	log.Printf("[Marketplace] Searching for extensions matching: %s", searchTerm)
-
-	// Use FilterType 10 for text search (supports keywords/wildcards)
-	query := marketplaceQuery{
-		Filters: []filter{
-			{
-				Criteria: []criterion{
-					{
-						FilterType: 10, // SearchText (keyword search)
-						Value:      searchTerm,
-					},
-				},
-			},
-		},
-		Flags: 0x192, // Include versions, files, and other details
+	results, err := c.Search(models.SearchOptions{Query: searchTerm})
+	if err != nil {
+		return nil, err
 	}
+	return results.Extensions, nil
+}
 
-	jsonData, err := json.Marshal(query)
+// Search queries the marketplace with sorting, paging and category/tag filters. The
+// gallery cannot filter on verified publishers, so VerifiedOnly is applied to each page
+// and Total stays the unfiltered gallery count (marked by TotalUnfiltered).
+func (c *Client) Search(opts models.SearchOptions) (*models.SearchResults, error) {
+	log.Printf("[Marketplace] Searching for extensions matching: %q (sort %s, page %d)", opts.Query, opts.SortBy, opts.Page)
+
+	sortBy, err := sortCode(opts.SortBy)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal query: %w", err)
+		return nil, err
+	}
+	page := opts.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := opts.PageSize
+	if pageSize < 1 {
+		pageSize = models.DefaultSearchPageSize
 	}
 
-	req, err := http.NewRequest("POST", MarketplaceAPIURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	criteria := []criterion{
+		{FilterType: filterTarget, Value: "Microsoft.VisualStudio.Code"},
+		{FilterType: filterExcludeWithFlags, Value: unpublishedFlag},
+	}
+	if opts.Query != "" {
+		criteria = append(criteria, criterion{FilterType: filterSearchText, Value: opts.Query})
+	}
+	if opts.Category != "" {
+		criteria = append(criteria, criterion{FilterType: filterCategory, Value: opts.Category})
+	}
+	for _, tag := range opts.Tags {
+		criteria = append(criteria, criterion{FilterType: filterTag, Value: tag})
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", fmt.Sprintf("application/json; api-version=%s", APIVersion))
-	req.Header.Set("User-Agent", UserAgent)
+	query := marketplaceQuery{
+		Filters: []filter{{
+			Criteria:   criteria,
+			PageNumber: page,
+			PageSize:   pageSize,
+			SortBy:     sortBy,
+			SortOrder:  sortDescending,
+		}},
+		Flags: 0x196, // Include versions, files, categories and tags, statistics and asset URIs
+	}
+	if sortBy == sortByRelevance {
+		query.Filters[0].SortOrder = 0
+	}
 
-	resp, err := c.httpClient.Do(req)
+	apiResp, err := c.query(query)
 	if err != nil {
-		log.Printf("[Marketplace] Search request failed for %s: %v", searchTerm, err)
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		log.Printf("[Marketplace] Search request failed for %q: %v", opts.Query, err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-	log.Printf("[Marketplace] Search response status for %s: %d", searchTerm, resp.StatusCode)
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("marketplace API returned status %d: %s", resp.StatusCode, string(body))
-	}
+	results := &models.SearchResults{
+		Extensions: []*models.ExtensionMetadata{},
+		Page:       page,
+		PageSize:   pageSize,
 
-	var apiResp marketplaceResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		TotalUnfiltered: opts.VerifiedOnly,
+	}
+	if len(apiResp.Results) == 0 {
+		return results, nil
 	}
 
-	if len(apiResp.Results) == 0 || len(apiResp.Results[0].Extensions) == 0 {
-		log.Printf("[Marketplace] No extensions found matching: %s", searchTerm)
-		return []*models.ExtensionMetadata{}, nil
+	for _, meta := range apiResp.Results[0].ResultMetadata {
+		if meta.MetadataType != "ResultCount" {
+			continue
+		}
+		for _, item := range meta.MetadataItems {
+			if item.Name == "TotalCount" {
+				results.Total = item.Count
+			}
+		}
 	}
 
-	// Convert all results to ExtensionMetadata
-	var results []*models.ExtensionMetadata
 	for _, ext := range apiResp.Results[0].Extensions {
-		if len(ext.Versions) == 0 {
+		metadata := extensionMetadata(ext)
+		if metadata == nil || (opts.VerifiedOnly && !metadata.IsVerifiedPublisher) {
 			continue
 		}
+		results.Extensions = append(results.Extensions, metadata)
+	}
 
-		latestVersion := ext.Versions[0]
-		var downloadURL, repoURL string
+	log.Printf("[Marketplace] Found %d extensions matching %q (%d total)", len(results.Extensions), opts.Query, results.Total)
+	return results, nil
+}
 
-		for _, file := range latestVersion.Files {
-			if file.AssetType == "Microsoft.VisualStudio.Services.VSIXPackage" {
-				downloadURL = file.Source
-			}
-		}
+// sortCode maps a sort option to the gallery's sort code
+func sortCode(sortBy string) (int, error) {
+	switch sortBy {
+	case "", models.SortRelevance:
+		return sortByRelevance, nil
+	case models.SortInstalls:
+		return sortByInstalls, nil
+	case models.SortRating:
+		return sortByRating, nil
+	case models.SortUpdated:
+		return sortByLastUpdated, nil
+	default:
+		return 0, fmt.Errorf("unknown sort order %q (expected installs, rating, updated or relevance)", sortBy)
+	}
+}
 
-		for _, prop := range latestVersion.Properties {
-			if prop.Key == "Microsoft.VisualStudio.Services.Links.Source" ||
-				prop.Key == "Microsoft.VisualStudio.Services.Links.Repository" {
-				repoURL = prop.Value
-			}
-		}
+// extensionMetadata converts a gallery extension to metadata about its latest version
+func extensionMetadata(ext galleryExtension) *models.ExtensionMetadata {
+	if len(ext.Versions) == 0 {
+		return nil
+	}
 
-		lastUpdated, _ := time.Parse(time.RFC3339, latestVersion.LastUpdated)
-		isVerified := ext.Publisher.IsDomainVerified || ext.Publisher.Flags == "verified"
-
-		metadata := &models.ExtensionMetadata{
-			ID:                  fmt.Sprintf("%s.%s", ext.Publisher.PublisherName, ext.ExtensionName),
-			Publisher:           ext.Publisher.PublisherName,
-			PublisherDomain:     ext.Publisher.Domain,
-			IsVerifiedPublisher: isVerified,
-			Name:                ext.ExtensionName,
-			Version:             latestVersion.Version,
-			DisplayName:         ext.DisplayName,
-			Description:         ext.ShortDescription,
-			RepositoryURL:       repoURL,
-			DownloadURL:         downloadURL,
-			LastUpdated:         lastUpdated,
-			Source:              "marketplace",
+	latestVersion := ext.Versions[0]
+	var downloadURL, repoURL string
+	for _, file := range latestVersion.Files {
+		if file.AssetType == "Microsoft.VisualStudio.Services.VSIXPackage" {
+			downloadURL = file.Source
+		}
+	}
+	for _, prop := range latestVersion.Properties {
+		if prop.Key == "Microsoft.VisualStudio.Services.Links.Source" ||
+			prop.Key == "Microsoft.VisualStudio.Services.Links.Repository" {
+			repoURL = prop.Value
 		}
-		results = append(results, metadata)
 	}
 
-	log.Printf("[Marketplace] Found %d extensions matching: %s", len(results), searchTerm)
-	return results, nil
+	lastUpdated, _ := time.Parse(time.RFC3339, latestVersion.LastUpdated)
+	metadata := &models.ExtensionMetadata{
+		ID:                  fmt.Sprintf("%s.%s", ext.Publisher.PublisherName, ext.ExtensionName),
+		Publisher:           ext.Publisher.PublisherName,
+		PublisherDomain:     ext.Publisher.Domain,
+		IsVerifiedPublisher: ext.Publisher.IsDomainVerified || ext.Publisher.Flags == "verified",
+		Name:                ext.ExtensionName,
+		Version:             latestVersion.Version,
+		DisplayName:         ext.DisplayName,
+		Description:         ext.ShortDescription,
+		RepositoryURL:       repoURL,
+		DownloadURL:         downloadURL,
+		LastUpdated:         lastUpdated,
+		Categories:          ext.Categories,
+		Tags:                ext.Tags,
+		Source:              "marketplace",
+	}
+	for _, stat := range ext.Statistics {
+		switch stat.StatisticName {
+		case "install":
+			metadata.InstallCount = int64(stat.Value)
+		case "averagerating":
+			metadata.AverageRating = stat.Value
+		case "ratingcount":
+			metadata.RatingCount = int64(stat.Value)
+		}
+	}
+	return metadata
 }
 
 // FetchMetadata fetches extension metadata from the Microsoft Marketplace
@@ -190,7 +309,7 @@ func (c *Client) FetchMetadata(extensionID string) (*models.ExtensionMetadata, e
 			{
 				Criteria: []criterion{
 					{
-						FilterType: 7, // Extension name (exact match)
+						FilterType: filterExtensionName, // Exact match
 						Value:      extensionID,
 					},
 				},
@@ -204,7 +323,7 @@ func (c *Client) FetchMetadata(extensionID string) (*models.ExtensionMetadata, e
 		return nil, fmt.Errorf("failed to marshal query: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", MarketplaceAPIURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequest("POST", c.apiURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -244,10 +363,13 @@ func (c *Client) FetchMetadata(extensionID string) (*models.ExtensionMetadata, e
 	latestVersion := ext.Versions[0]
 
 	// Extract download URL and repository URL
-	var downloadURL, repoURL string
+	var downloadURL, signatureURL, repoURL string
 	for _, file := range latestVersion.Files {
-		if file.AssetType == "Microsoft.VisualStudio.Services.VSIXPackage" {
+		switch file.AssetType {
+		case "Microsoft.VisualStudio.Services.VSIXPackage":
 			downloadURL = file.Source
+		case SignatureAssetType:
+			signatureURL = file.Source
 		}
 	}
 
@@ -274,9 +396,17 @@ func (c *Client) FetchMetadata(extensionID string) (*models.ExtensionMetadata, e
 		Description:         ext.ShortDescription,
 		RepositoryURL:       repoURL,
 		DownloadURL:         downloadURL,
+		SignatureURL:        signatureURL,
 		LastUpdated:         lastUpdated,
 		Source:              "marketplace",
 	}
+	if stats := extensionMetadata(ext); stats != nil {
+		metadata.InstallCount = stats.InstallCount
+		metadata.AverageRating = stats.AverageRating
+		metadata.RatingCount = stats.RatingCount
+		metadata.Categories = stats.Categories
+		metadata.Tags = stats.Tags
+	}
 
 	if isVerified {
 		log.Printf("[Marketplace] Successfully fetched metadata for %s (version %s) - VERIFIED PUBLISHER", extensionID, metadata.Version)
@@ -286,6 +416,174 @@ func (c *Client) FetchMetadata(extensionID string) (*models.ExtensionMetadata, e
 	return metadata, nil
 }
 
+// ListVersions returns every published version of an extension, newest first. Each version
+// lists the target platforms it was built for; none means a universal package.
+func (c *Client) ListVersions(extensionID string) ([]models.ExtensionVersion, error) {
+	log.Printf("[Marketplace] Listing versions for extension: %s", extensionID)
+	query := marketplaceQuery{
+		Filters: []filter{
+			{
+				Criteria: []criterion{
+					{
+						FilterType: filterExtensionName, // Exact match
+						Value:      extensionID,
+					},
+				},
+			},
+		},
+		Flags: 0x93, // Include all versions, files, version properties and asset URIs
+	}
+
+	apiResp, err := c.query(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(apiResp.Results) == 0 || len(apiResp.Results[0].Extensions) == 0 {
+		return nil, fmt.Errorf("extension not found in marketplace: %s", extensionID)
+	}
+
+	versions := groupVersions(apiResp.Results[0].Extensions[0].Versions)
+	log.Printf("[Marketplace] Found %d versions of %s", len(versions), extensionID)
+	return versions, nil
+}
+
+// groupVersions merges the gallery's entries, one per version and platform, into versions
+func groupVersions(entries []galleryVersion) []models.ExtensionVersion {
+	var versions []models.ExtensionVersion
+	index := map[string]int{}
+	for _, v := range entries {
+		i, seen := index[v.Version]
+		if !seen {
+			lastUpdated, _ := time.Parse(time.RFC3339, v.LastUpdated)
+			version := models.ExtensionVersion{Version: v.Version, LastUpdated: lastUpdated}
+			for _, prop := range v.Properties {
+				switch prop.Key {
+				case "Microsoft.VisualStudio.Code.PreRelease":
+					version.PreRelease = prop.Value == "true"
+				case "Microsoft.VisualStudio.Code.Engine":
+					version.EngineVSCode = prop.Value
+				}
+			}
+			i = len(versions)
+			index[v.Version] = i
+			versions = append(versions, version)
+		}
+		if v.TargetPlatform != "" {
+			versions[i].TargetPlatforms = append(versions[i].TargetPlatforms, v.TargetPlatform)
+		}
+	}
+	return versions
+}
+
+// Details fetches the full listing of an extension: its latest metadata, every published
+// version (at most maxVersions, newest first; 0 for all) and the README, changelog and
+// license of the latest version.
+func (c *Client) Details(extensionID string, maxVersions int) (*models.ExtensionDetails, error) {
+	log.Printf("[Marketplace] Fetching details for extension: %s", extensionID)
+	query := marketplaceQuery{
+		Filters: []filter{
+			{
+				Criteria: []criterion{
+					{
+						FilterType: filterExtensionName, // Exact match
+						Value:      extensionID,
+					},
+				},
+			},
+		},
+		Flags: 0x197, // Include all versions, files, categories and tags, version properties, asset URIs and statistics
+	}
+
+	apiResp, err := c.query(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(apiResp.Results) == 0 || len(apiResp.Results[0].Extensions) == 0 {
+		return nil, fmt.Errorf("extension not found in marketplace: %s", extensionID)
+	}
+
+	ext := apiResp.Results[0].Extensions[0]
+	metadata := extensionMetadata(ext)
+	if metadata == nil {
+		return nil, fmt.Errorf("no versions found for extension: %s", extensionID)
+	}
+
+	versions := groupVersions(ext.Versions)
+	details := &models.ExtensionDetails{
+		Metadata:      metadata,
+		Versions:      versions,
+		TotalVersions: len(versions),
+	}
+	if maxVersions > 0 && len(versions) > maxVersions {
+		details.Versions = versions[:maxVersions]
+	}
+
+	// Assets extracted from the latest VSIX
+	for _, file := range ext.Versions[0].Files {
+		switch file.AssetType {
+		case readmeAssetType:
+			details.Readme = c.fetchAsset(file.Source)
+		case changelogAssetType:
+			details.Changelog = c.fetchAsset(file.Source)
+		case licenseAssetType:
+			details.LicenseURL = file.Source
+		case manifestAssetType:
+			var manifest struct {
+				License string `json:"license"`
+			}
+			if err := json.Unmarshal([]byte(c.fetchAsset(file.Source)), &manifest); err == nil {
+				details.License = manifest.License
+			}
+		}
+	}
+
+	log.Printf("[Marketplace] Fetched details for %s (%d versions)", extensionID, details.TotalVersions)
+	return details, nil
+}
+
+// fetchAsset downloads a text asset of a version, returning "" if it is unavailable
+func (c *Client) fetchAsset(assetURL string) string {
+	text, err := asset.Fetch(c.httpClient, assetURL, UserAgent)
+	if err != nil {
+		log.Printf("[Marketplace] Failed to fetch asset %s: %v", assetURL, err)
+	}
+	return text
+}
+
+// query posts an extension query to the marketplace API
+func (c *Client) query(query marketplaceQuery) (*marketplaceResponse, error) {
+	jsonData, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.apiURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", fmt.Sprintf("application/json; api-version=%s", APIVersion))
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("marketplace API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var apiResp marketplaceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &apiResp, nil
+}
+
 // DownloadExtension downloads the VSIX package from the marketplace
 func (c *Client) DownloadExtension(downloadURL string) ([]byte, error) {
 	if downloadURL == "" {
@@ -316,3 +614,27 @@ func (c *Client) DownloadExtension(downloadURL string) ([]byte, error) {
 
 	return data, nil
 }
+
+// VSIXPackageURL returns the download URL of a specific version of an extension
+func VSIXPackageURL(extensionID, version string) (string, error) {
+	return PlatformPackageURL(extensionID, version, "")
+}
+
+// PlatformPackageURL returns the download URL of a specific version of an extension built
+// for a target platform (e.g. "linux-x64"). An empty or "universal" platform selects the
+// platform-neutral package.
+func PlatformPackageURL(extensionID, version, targetPlatform string) (string, error) {
+	publisher, name, ok := strings.Cut(extensionID, ".")
+	if !ok || publisher == "" || name == "" {
+		return "", fmt.Errorf("invalid extension ID format: %s (expected publisher.name)", extensionID)
+	}
+	if version == "" {
+		return "", fmt.Errorf("version is required")
+	}
+	packageURL := fmt.Sprintf("%s/publishers/%s/vsextensions/%s/%s/vspackage",
+		GalleryURL, url.PathEscape(publisher), url.PathEscape(name), url.PathEscape(version))
+	if targetPlatform != "" && targetPlatform != "universal" {
+		packageURL += "?targetPlatform=" + url.QueryEscape(targetPlatform)
+	}
+	return packageURL, nil
+}
@@ -1,7 +1,13 @@
 package marketplace
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
 )
 
 func TestNewClient(t *testing.T) {
@@ -61,3 +67,143 @@ func TestDownloadExtensionInvalidURL(t *testing.T) {
 		t.Error("Expected error for invalid URL")
 	}
 }
+
+func TestPlatformPackageURL(t *testing.T) {
+	base := GalleryURL + "/publishers/ms-python/vsextensions/python/2024.1.0/vspackage"
+	tests := []struct {
+		platform string
+		want     string
+	}{
+		{"", base},
+		{"universal", base},
+		{"linux-x64", base + "?targetPlatform=linux-x64"},
+	}
+	for _, tt := range tests {
+		got, err := PlatformPackageURL("ms-python.python", "2024.1.0", tt.platform)
+		if err != nil {
+			t.Fatalf("PlatformPackageURL(%q) error: %v", tt.platform, err)
+		}
+		if got != tt.want {
+			t.Errorf("PlatformPackageURL(%q) = %s, want %s", tt.platform, got, tt.want)
+		}
+	}
+
+	if _, err := PlatformPackageURL("invalid", "1.0.0", ""); err == nil {
+		t.Error("Expected error for invalid extension ID")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	var received marketplaceQuery
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		fmt.Fprint(w, `{"results": [{
+			"extensions": [
+				{"publisher": {"publisherName": "ms-python", "isDomainVerified": true}, "extensionName": "python",
+				 "categories": ["Programming Languages"], "tags": ["python"],
+				 "versions": [{"version": "2024.1.0", "lastUpdated": "2024-01-02T03:04:05Z"}],
+				 "statistics": [{"statisticName": "install", "value": 120000000}, {"statisticName": "averagerating", "value": 4.5}, {"statisticName": "ratingcount", "value": 600}]},
+				{"publisher": {"publisherName": "someone"}, "extensionName": "python-snippets",
+				 "versions": [{"version": "1.0.0"}]}
+			],
+			"resultMetadata": [{"metadataType": "ResultCount", "metadataItems": [{"name": "TotalCount", "count": 345}]}]
+		}]}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	client.apiURL = srv.URL
+
+	results, err := client.Search(models.SearchOptions{
+		Query:        "python",
+		SortBy:       models.SortInstalls,
+		Page:         2,
+		PageSize:     10,
+		Category:     "Programming Languages",
+		Tags:         []string{"python"},
+		VerifiedOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+
+	f := received.Filters[0]
+	if f.PageNumber != 2 || f.PageSize != 10 || f.SortBy != sortByInstalls || f.SortOrder != sortDescending {
+		t.Errorf("filter paging/sort = %+v", f)
+	}
+	want := map[int]string{filterSearchText: "python", filterCategory: "Programming Languages", filterTag: "python"}
+	for _, c := range f.Criteria {
+		if v, ok := want[c.FilterType]; ok && v == c.Value {
+			delete(want, c.FilterType)
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("missing criteria %v in %+v", want, f.Criteria)
+	}
+
+	if results.Total != 345 || !results.TotalUnfiltered || results.Page != 2 || len(results.Extensions) != 1 {
+		t.Fatalf("results = %+v", results)
+	}
+	ext := results.Extensions[0]
+	if ext.ID != "ms-python.python" || ext.InstallCount != 120000000 || ext.AverageRating != 4.5 || ext.RatingCount != 600 {
+		t.Errorf("extension = %+v", ext)
+	}
+	if len(ext.Categories) != 1 || ext.Tags[0] != "python" {
+		t.Errorf("categories/tags = %v/%v", ext.Categories, ext.Tags)
+	}
+
+	if _, err := client.Search(models.SearchOptions{SortBy: "popularity"}); err == nil {
+		t.Error("expected error for unknown sort order")
+	}
+}
+
+func TestDetails(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/readme":
+			fmt.Fprint(w, "# Python")
+		case "/changelog":
+			fmt.Fprint(w, "## 2024.2.0")
+		case "/manifest":
+			fmt.Fprint(w, `{"name": "python", "license": "MIT"}`)
+		default:
+			fmt.Fprintf(w, `{"results": [{"extensions": [{
+				"publisher": {"publisherName": "ms-python"}, "extensionName": "python",
+				"versions": [
+					{"version": "2024.3.0", "targetPlatform": "linux-x64", "lastUpdated": "2024-03-01T00:00:00Z",
+					 "properties": [{"key": "Microsoft.VisualStudio.Code.PreRelease", "value": "true"}, {"key": "Microsoft.VisualStudio.Code.Engine", "value": "^1.86.0"}],
+					 "files": [
+						{"assetType": "Microsoft.VisualStudio.Services.Content.Details", "source": "%[1]s/readme"},
+						{"assetType": "Microsoft.VisualStudio.Services.Content.Changelog", "source": "%[1]s/changelog"},
+						{"assetType": "Microsoft.VisualStudio.Services.Content.License", "source": "%[1]s/license"},
+						{"assetType": "Microsoft.VisualStudio.Code.Manifest", "source": "%[1]s/manifest"}]},
+					{"version": "2024.3.0", "targetPlatform": "win32-x64", "lastUpdated": "2024-03-01T00:00:00Z"},
+					{"version": "2024.2.0", "lastUpdated": "2024-02-01T00:00:00Z"},
+					{"version": "2024.1.0", "lastUpdated": "2024-01-01T00:00:00Z"}
+				]}]}]}`, srv.URL)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	client.apiURL = srv.URL
+
+	details, err := client.Details("ms-python.python", 2)
+	if err != nil {
+		t.Fatalf("Details() error: %v", err)
+	}
+	if details.Metadata.Version != "2024.3.0" || details.TotalVersions != 3 || len(details.Versions) != 2 {
+		t.Fatalf("details = %+v", details)
+	}
+	latest := details.Versions[0]
+	if !latest.PreRelease || latest.EngineVSCode != "^1.86.0" || len(latest.TargetPlatforms) != 2 {
+		t.Errorf("latest version = %+v", latest)
+	}
+	if details.Readme != "# Python" || details.Changelog != "## 2024.2.0" {
+		t.Errorf("readme/changelog = %q/%q", details.Readme, details.Changelog)
+	}
+	if details.License != "MIT" || details.LicenseURL != srv.URL+"/license" {
+		t.Errorf("license = %q (%s)", details.License, details.LicenseURL)
+	}
+}
@@ -0,0 +1,445 @@
+package mirror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/download"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+const (
+	// IndexFile is the index of a mirror repository, stored at its root
+	IndexFile = "index.json"
+	// SchemaVersion is the current index format
+	SchemaVersion = 1
+)
+
+// Fetcher lists, resolves and downloads extension packages; download.Downloader implements it
+type Fetcher interface {
+	ListVersions(extensionID, source string) ([]models.ExtensionVersion, error)
+	Resolve(extensionID, version, source, targetPlatform string) (*models.DownloadInfo, error)
+	Download(info *models.DownloadInfo, dir string) error
+}
+
+// Validator classifies the trust level of an extension
+type Validator interface {
+	ValidateExtension(extensionID string) (*models.ValidationResult, error)
+}
+
+// Options selects what is mirrored
+type Options struct {
+	Sources    []string // registries to try, in order of preference
+	Platforms  []string // target platforms; universal packages are always taken as-is
+	Versions   int      // number of latest versions per extension; 0 mirrors every version
+	PreRelease bool     // include pre-release versions
+
+	AllowSuspicious bool // mirror extensions classified Suspicious
+	AllowUnknown    bool // mirror extensions whose trust could not be established, or without a validator
+}
+
+// errPreRelease rejects a package that turns out to be a pre-release when none were requested
+var errPreRelease = errors.New("package is a pre-release")
+
+// LoadIndex reads the index of a mirror repository. A missing index is an empty repository.
+func LoadIndex(dir string) (*models.MirrorIndex, error) {
+	data, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return &models.MirrorIndex{SchemaVersion: SchemaVersion}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror index: %w", err)
+	}
+	var index models.MirrorIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse mirror index: %w", err)
+	}
+	if index.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("mirror index schema %d is newer than supported (%d)", index.SchemaVersion, SchemaVersion)
+	}
+	return &index, nil
+}
+
+// SaveIndex atomically writes the index of a mirror repository
+func SaveIndex(dir string, index *models.MirrorIndex) error {
+	index.SchemaVersion = SchemaVersion
+	index.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal mirror index: %w", err)
+	}
+	target := filepath.Join(dir, IndexFile)
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write mirror index: %w", err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write mirror index: %w", err)
+	}
+	return nil
+}
+
+// Mirror downloads the selected versions and platforms of each extension into dir, verifies
+// them and records them in the index. Malicious extensions are refused, as are Suspicious
+// and Unknown ones unless opts allows them, and packages already in the repository are
+// kept. The index is saved after every extension so an interrupted run keeps its progress.
+func Mirror(dir string, extensionIDs []string, opts Options, fetcher Fetcher, validator Validator) (*models.MirrorReport, error) {
+	if len(opts.Sources) == 0 {
+		opts.Sources = []string{"marketplace"}
+	}
+	if len(opts.Platforms) == 0 {
+		opts.Platforms = []string{download.UniversalPlatform}
+	}
+	for _, platform := range opts.Platforms {
+		if err := download.ValidatePlatform(platform); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create mirror directory: %w", err)
+	}
+	index, err := LoadIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.MirrorReport{Dir: dir, Extensions: len(extensionIDs), Added: []models.MirrorEntry{}}
+	m := &run{dir: dir, index: index, report: report, fetcher: fetcher, opts: opts}
+	for _, extensionID := range extensionIDs {
+		m.mirrorExtension(extensionID, validator)
+		if err := SaveIndex(dir, index); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// run holds the state of a mirror run
+type run struct {
+	dir     string
+	index   *models.MirrorIndex
+	report  *models.MirrorReport
+	fetcher Fetcher
+	opts    Options
+}
+
+// fail records a package that could not be mirrored
+func (m *run) fail(extensionID, version, platform string, err error) {
+	log.Printf("[Mirror] Failed %s %s %s: %v", extensionID, version, platform, err)
+	m.report.Failed = append(m.report.Failed, models.MirrorFailure{
+		ExtensionID:    extensionID,
+		Version:        version,
+		TargetPlatform: platform,
+		Error:          err.Error(),
+	})
+}
+
+// mirrorExtension mirrors the selected versions of one extension
+func (m *run) mirrorExtension(extensionID string, validator Validator) {
+	publisher, name, ok := strings.Cut(extensionID, ".")
+	if !ok || publisher == "" || name == "" {
+		m.fail(extensionID, "", "", fmt.Errorf("invalid extension ID format (expected publisher.name)"))
+		return
+	}
+
+	trust, recommendation := models.TrustLevelUnknown, "no validator configured"
+	if validator != nil {
+		result, err := validator.ValidateExtension(extensionID)
+		if err != nil {
+			m.fail(extensionID, "", "", fmt.Errorf("validation failed: %w", err))
+			return
+		}
+		trust, recommendation = result.TrustLevel, result.Recommendation
+	}
+	if !m.trusted(trust) {
+		m.fail(extensionID, "", "", fmt.Errorf("extension is classified %s: %s", trust, recommendation))
+		return
+	}
+
+	source, versions, err := m.listVersions(extensionID)
+	if err != nil {
+		m.fail(extensionID, "", "", err)
+		return
+	}
+
+	mirrored := 0
+	for _, version := range selectVersions(versions, m.opts) {
+		if m.opts.Versions > 0 && mirrored == m.opts.Versions {
+			break
+		}
+		platforms := platformsFor(version, source, m.opts.Platforms)
+		if len(platforms) == 0 {
+			m.fail(extensionID, version.Version, "", fmt.Errorf("not built for %s (available: %s)",
+				strings.Join(m.opts.Platforms, ", "), strings.Join(version.TargetPlatforms, ", ")))
+			mirrored++
+			continue
+		}
+		done := map[string]bool{}
+		preRelease := false
+		for _, platform := range platforms {
+			if done[platform] {
+				continue
+			}
+			entry, err := m.mirrorPackage(extensionID, version.Version, source, platform, trust)
+			if errors.Is(err, errPreRelease) {
+				// Registries that do not flag pre-releases in their version list (OpenVSX)
+				// are only caught here; the version does not count towards opts.Versions
+				log.Printf("[Mirror] Skipped %s %s: %v", extensionID, version.Version, err)
+				preRelease = true
+				break
+			}
+			if err != nil {
+				m.fail(extensionID, version.Version, platform, err)
+				continue
+			}
+			done[platform] = true
+			done[entry.TargetPlatform] = true
+		}
+		if !preRelease {
+			mirrored++
+		}
+	}
+}
+
+// trusted reports whether extensions of a trust level may be mirrored
+func (m *run) trusted(trust models.TrustLevel) bool {
+	switch trust {
+	case models.TrustLevelLegitimate:
+		return true
+	case models.TrustLevelSuspicious:
+		return m.opts.AllowSuspicious
+	case models.TrustLevelMalicious:
+		return false
+	default:
+		return m.opts.AllowUnknown
+	}
+}
+
+// listVersions returns the versions of an extension from the first source that has it
+func (m *run) listVersions(extensionID string) (string, []models.ExtensionVersion, error) {
+	var errs []string
+	for _, source := range m.opts.Sources {
+		versions, err := m.fetcher.ListVersions(extensionID, source)
+		if err == nil && len(versions) > 0 {
+			return source, versions, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("no versions published")
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", source, err))
+	}
+	return "", nil, fmt.Errorf("not available: %s", strings.Join(errs, "; "))
+}
+
+// mirrorPackage downloads, verifies and indexes one package, or returns the existing entry
+func (m *run) mirrorPackage(extensionID, version, source, platform string, trust models.TrustLevel) (*models.MirrorEntry, error) {
+	if existing := m.lookup(extensionID, version, platform); existing != nil {
+		if existing.PreRelease && !m.opts.PreRelease {
+			return nil, errPreRelease
+		}
+		if _, err := os.Stat(filepath.Join(m.dir, filepath.FromSlash(existing.File))); err == nil {
+			m.report.Existing++
+			return existing, nil
+		}
+	}
+
+	info, err := m.fetcher.Resolve(extensionID, version, source, platform)
+	if err != nil && source == "openvsx" && platform != download.UniversalPlatform {
+		// OpenVSX does not say which platforms a version was built for; fall back to universal
+		info, err = m.fetcher.Resolve(extensionID, version, source, download.UniversalPlatform)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !editor.ValidVersion(info.Version) {
+		return nil, fmt.Errorf("refusing unsafe version %q", info.Version)
+	}
+	if info.TargetPlatform != platform {
+		if existing := m.lookup(extensionID, version, info.TargetPlatform); existing != nil {
+			if existing.PreRelease && !m.opts.PreRelease {
+				return nil, errPreRelease
+			}
+			m.report.Existing++
+			return existing, nil
+		}
+	}
+
+	publisher, name, _ := strings.Cut(strings.ToLower(extensionID), ".")
+	relDir := path.Join(publisher, name, info.Version)
+	if err := m.fetcher.Download(info, filepath.Join(m.dir, filepath.FromSlash(relDir))); err != nil {
+		return nil, err
+	}
+
+	pkg, err := verifyPackage(info)
+	if err != nil {
+		removeDownload(info.File)
+		return nil, err
+	}
+	if pkg.PreRelease && !m.opts.PreRelease {
+		removeDownload(info.File)
+		return nil, errPreRelease
+	}
+
+	entry := models.MirrorEntry{
+		ExtensionID:    strings.ToLower(pkg.ID()),
+		Version:        pkg.Version,
+		TargetPlatform: info.TargetPlatform,
+		DisplayName:    pkg.DisplayName,
+		PreRelease:     pkg.PreRelease,
+		Source:         info.Source,
+		File:           path.Join(relDir, filepath.Base(info.File)),
+		Size:           info.Size,
+		SHA256:         info.SHA256,
+		TrustLevel:     trust,
+		MirroredAt:     time.Now(),
+	}
+	m.upsert(entry)
+	m.report.Added = append(m.report.Added, entry)
+	log.Printf("[Mirror] Added %s %s (%s) from %s", entry.ExtensionID, entry.Version, entry.TargetPlatform, entry.Source)
+	return &entry, nil
+}
+
+// lookup returns the index entry of a package, if any
+func (m *run) lookup(extensionID, version, platform string) *models.MirrorEntry {
+	for i, entry := range m.index.Entries {
+		if strings.EqualFold(entry.ExtensionID, extensionID) && entry.Version == version && entry.TargetPlatform == platform {
+			return &m.index.Entries[i]
+		}
+	}
+	return nil
+}
+
+// upsert adds an entry to the index, replacing the entry of the same package
+func (m *run) upsert(entry models.MirrorEntry) {
+	if existing := m.lookup(entry.ExtensionID, entry.Version, entry.TargetPlatform); existing != nil {
+		*existing = entry
+		return
+	}
+	m.index.Entries = append(m.index.Entries, entry)
+}
+
+// verifyPackage checks that a downloaded VSIX is the requested extension, version and platform
+func verifyPackage(info *models.DownloadInfo) (*editor.VSIXPackage, error) {
+	data, err := os.ReadFile(info.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read download: %w", err)
+	}
+	pkg, err := editor.ReadVSIX(data)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(pkg.ID(), info.ExtensionID) {
+		return nil, fmt.Errorf("package contains %s, expected %s", pkg.ID(), info.ExtensionID)
+	}
+	if pkg.Version != info.Version {
+		return nil, fmt.Errorf("package is version %s, expected %s", pkg.Version, info.Version)
+	}
+	if info.TargetPlatform != download.UniversalPlatform && pkg.TargetPlatform != "" && pkg.TargetPlatform != info.TargetPlatform {
+		return nil, fmt.Errorf("package targets %s, expected %s", pkg.TargetPlatform, info.TargetPlatform)
+	}
+	return pkg, nil
+}
+
+// removeDownload deletes a rejected download and its sidecars
+func removeDownload(file string) {
+	for _, p := range []string{file, file + download.ChecksumSuffix, file + download.MetadataSuffix} {
+		os.Remove(p)
+	}
+}
+
+// selectVersions returns the candidate versions to mirror, newest first, skipping those the
+// registry flags as pre-release unless requested. The caller stops after opts.Versions.
+func selectVersions(versions []models.ExtensionVersion, opts Options) []models.ExtensionVersion {
+	var selected []models.ExtensionVersion
+	for _, v := range versions {
+		if v.PreRelease && !opts.PreRelease {
+			continue
+		}
+		selected = append(selected, v)
+	}
+	return selected
+}
+
+// platformsFor returns the platforms to mirror a version for. The Marketplace lists the
+// platforms of each version, so universal packages are taken once and platform-specific
+// ones only for requested platforms. OpenVSX does not, so each requested platform is tried.
+func platformsFor(version models.ExtensionVersion, source string, requested []string) []string {
+	if source == "openvsx" {
+		return requested
+	}
+	if len(version.TargetPlatforms) == 0 {
+		return []string{download.UniversalPlatform}
+	}
+	var platforms []string
+	for _, platform := range requested {
+		for _, available := range version.TargetPlatforms {
+			if platform == available {
+				platforms = append(platforms, platform)
+			}
+		}
+	}
+	return platforms
+}
+
+// Find returns the package to install for an extension: the given version (or the latest
+// release) built for platform, or its universal package
+func Find(index *models.MirrorIndex, extensionID, version, platform string) (*models.MirrorEntry, error) {
+	var best *models.MirrorEntry
+	for i := range index.Entries {
+		entry := &index.Entries[i]
+		if !strings.EqualFold(entry.ExtensionID, extensionID) {
+			continue
+		}
+		if entry.TargetPlatform != platform && entry.TargetPlatform != download.UniversalPlatform {
+			continue
+		}
+		if version != "" && entry.Version != version {
+			continue
+		}
+		if version == "" && entry.PreRelease {
+			continue
+		}
+		if best == nil {
+			best = entry
+			continue
+		}
+		switch c := editor.CompareVersions(entry.Version, best.Version); {
+		case c > 0:
+			best = entry
+		case c == 0 && entry.TargetPlatform == platform:
+			best = entry
+		}
+	}
+	if best == nil {
+		if version != "" {
+			return nil, fmt.Errorf("%s@%s for %s is not in the mirror", extensionID, version, platform)
+		}
+		return nil, fmt.Errorf("%s for %s is not in the mirror", extensionID, platform)
+	}
+	return best, nil
+}
+
+// ReadPackage reads a mirrored package and checks it against its index entry
+func ReadPackage(dir string, entry *models.MirrorEntry) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(entry.File)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirrored package: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != entry.SHA256 {
+		return nil, fmt.Errorf("mirrored package %s does not match its index checksum", entry.File)
+	}
+	return data, nil
+}
@@ -0,0 +1,298 @@
+package mirror
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/download"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// buildTestVSIX creates an in-memory VSIX archive with the given identity
+func buildTestVSIX(t *testing.T, extensionID, version, platform string, preRelease bool) []byte {
+	t.Helper()
+	publisher, name, _ := strings.Cut(extensionID, ".")
+	if platform == download.UniversalPlatform {
+		platform = ""
+	}
+	properties := ""
+	if preRelease {
+		properties = `<Properties><Property Id="Microsoft.VisualStudio.Code.PreRelease" Value="true" /></Properties>`
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string]string{
+		"extension.vsixmanifest": fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<PackageManifest Version="2.0.0" xmlns="http://schemas.microsoft.com/developer/vsx-schema/2011">
+  <Metadata>
+    <Identity Language="en-US" Id="%s" Version="%s" Publisher="%s" TargetPlatform="%s" />
+    %s
+  </Metadata>
+</PackageManifest>`, name, version, publisher, platform, properties),
+		"extension/package.json": fmt.Sprintf(`{"publisher": %q, "name": %q, "version": %q}`, publisher, name, version),
+	}
+	for fileName, content := range files {
+		f, err := w.Create(fileName)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// fakeFetcher serves packages from memory
+type fakeFetcher struct {
+	t         *testing.T
+	versions  map[string][]models.ExtensionVersion // by source
+	packages  map[string]bool                      // "version@platform" that can be resolved
+	wrongID   bool                                 // serve a package of another extension
+	pre       map[string]bool                      // versions whose package is a pre-release
+	version   string                               // version Resolve reports instead of the requested one
+	downloads int
+}
+
+func (f *fakeFetcher) ListVersions(extensionID, source string) ([]models.ExtensionVersion, error) {
+	versions, ok := f.versions[source]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return versions, nil
+}
+
+func (f *fakeFetcher) Resolve(extensionID, version, source, platform string) (*models.DownloadInfo, error) {
+	if !f.packages[version+"@"+platform] {
+		return nil, fmt.Errorf("%s@%s not published for %s", extensionID, version, platform)
+	}
+	if f.version != "" {
+		version = f.version
+	}
+	return &models.DownloadInfo{ExtensionID: extensionID, Version: version, Source: source, TargetPlatform: platform, URL: "fake"}, nil
+}
+
+func (f *fakeFetcher) Download(info *models.DownloadInfo, dir string) error {
+	f.downloads++
+	id := info.ExtensionID
+	if f.wrongID {
+		id = "evil.impostor"
+	}
+	data := buildTestVSIX(f.t, id, info.Version, info.TargetPlatform, f.pre[info.Version])
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	info.File = filepath.Join(dir, download.FileName(info))
+	sum := sha256.Sum256(data)
+	info.SHA256 = hex.EncodeToString(sum[:])
+	info.Size = int64(len(data))
+	return os.WriteFile(info.File, data, 0644)
+}
+
+// fakeValidator returns a fixed trust level
+type fakeValidator struct{ trust models.TrustLevel }
+
+func (v fakeValidator) ValidateExtension(extensionID string) (*models.ValidationResult, error) {
+	return &models.ValidationResult{ExtensionID: extensionID, TrustLevel: v.trust}, nil
+}
+
+func TestMirrorMarketplacePlatforms(t *testing.T) {
+	dir := t.TempDir()
+	fetcher := &fakeFetcher{
+		t: t,
+		versions: map[string][]models.ExtensionVersion{
+			"marketplace": {
+				{Version: "3.0.0-pre", PreRelease: true},
+				{Version: "2.0.0", TargetPlatforms: []string{"linux-x64", "win32-x64", "darwin-arm64"}},
+				{Version: "1.0.0"},
+				{Version: "0.9.0"},
+			},
+		},
+		packages: map[string]bool{"2.0.0@linux-x64": true, "2.0.0@win32-x64": true, "1.0.0@universal": true},
+	}
+	opts := Options{Platforms: []string{"linux-x64", "win32-x64"}, Versions: 2}
+
+	report, err := Mirror(dir, []string{"Pub.Ext"}, opts, fetcher, fakeValidator{models.TrustLevelLegitimate})
+	if err != nil {
+		t.Fatalf("Mirror() error: %v", err)
+	}
+	if len(report.Added) != 3 || len(report.Failed) != 0 {
+		t.Fatalf("added %d, failed %v; want 3 added", len(report.Added), report.Failed)
+	}
+
+	index, err := LoadIndex(dir)
+	if err != nil {
+		t.Fatalf("LoadIndex() error: %v", err)
+	}
+	if len(index.Entries) != 3 {
+		t.Fatalf("index has %d entries, want 3", len(index.Entries))
+	}
+	for _, entry := range index.Entries {
+		if entry.TrustLevel != models.TrustLevelLegitimate || entry.ExtensionID != "pub.ext" {
+			t.Errorf("entry = %+v", entry)
+		}
+		if !strings.HasPrefix(entry.File, "pub/ext/"+entry.Version+"/") {
+			t.Errorf("File = %s, want it under pub/ext/%s/", entry.File, entry.Version)
+		}
+		if _, err := ReadPackage(dir, &entry); err != nil {
+			t.Errorf("ReadPackage(%s) error: %v", entry.File, err)
+		}
+	}
+
+	// A second run keeps what is already there
+	report, err = Mirror(dir, []string{"pub.ext"}, opts, fetcher, fakeValidator{models.TrustLevelLegitimate})
+	if err != nil {
+		t.Fatalf("second Mirror() error: %v", err)
+	}
+	if len(report.Added) != 0 || report.Existing != 3 || fetcher.downloads != 3 {
+		t.Errorf("second run added %d, existing %d, downloads %d; want 0, 3, 3", len(report.Added), report.Existing, fetcher.downloads)
+	}
+}
+
+func TestMirrorOpenVSXFallsBackToUniversal(t *testing.T) {
+	dir := t.TempDir()
+	fetcher := &fakeFetcher{
+		t:        t,
+		versions: map[string][]models.ExtensionVersion{"openvsx": {{Version: "1.0.0"}}},
+		packages: map[string]bool{"1.0.0@universal": true},
+	}
+	opts := Options{Sources: []string{"marketplace", "openvsx"}, Platforms: []string{"linux-x64", "darwin-arm64"}, AllowUnknown: true}
+
+	report, err := Mirror(dir, []string{"pub.ext"}, opts, fetcher, nil)
+	if err != nil {
+		t.Fatalf("Mirror() error: %v", err)
+	}
+	if len(report.Added) != 1 || report.Added[0].TargetPlatform != download.UniversalPlatform || report.Added[0].Source != "openvsx" {
+		t.Fatalf("added = %+v, want one universal package from openvsx", report.Added)
+	}
+	if report.Added[0].TrustLevel != models.TrustLevelUnknown {
+		t.Errorf("TrustLevel = %s, want Unknown without a validator", report.Added[0].TrustLevel)
+	}
+}
+
+func TestMirrorRefusesUntrusted(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		validator Validator
+		refused   bool
+	}{
+		{"malicious", Options{AllowSuspicious: true, AllowUnknown: true}, fakeValidator{models.TrustLevelMalicious}, true},
+		{"suspicious", Options{}, fakeValidator{models.TrustLevelSuspicious}, true},
+		{"suspicious allowed", Options{AllowSuspicious: true}, fakeValidator{models.TrustLevelSuspicious}, false},
+		{"unknown", Options{}, fakeValidator{models.TrustLevelUnknown}, true},
+		{"no validator", Options{}, nil, true},
+		{"no validator allowed", Options{AllowUnknown: true}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := &fakeFetcher{
+				t:        t,
+				versions: map[string][]models.ExtensionVersion{"marketplace": {{Version: "1.0.0"}}},
+				packages: map[string]bool{"1.0.0@universal": true},
+			}
+			report, _ := Mirror(t.TempDir(), []string{"pub.ext"}, tt.opts, fetcher, tt.validator)
+			if refused := len(report.Added) == 0; refused != tt.refused || (tt.refused && fetcher.downloads != 0) {
+				t.Errorf("added %d, failed %v, downloads %d; refused want %v", len(report.Added), report.Failed, fetcher.downloads, tt.refused)
+			}
+		})
+	}
+}
+
+func TestMirrorRefusesUnsafeVersion(t *testing.T) {
+	dir := t.TempDir()
+	fetcher := &fakeFetcher{
+		t:        t,
+		versions: map[string][]models.ExtensionVersion{"marketplace": {{Version: "1.0.0"}}},
+		packages: map[string]bool{"1.0.0@universal": true},
+		version:  "../../../escape",
+	}
+
+	report, _ := Mirror(dir, []string{"pub.ext"}, Options{}, fetcher, fakeValidator{models.TrustLevelLegitimate})
+	if len(report.Failed) != 1 || !strings.Contains(report.Failed[0].Error, "unsafe version") || fetcher.downloads != 0 {
+		t.Errorf("failed = %+v, downloads %d; want the version refused before downloading", report.Failed, fetcher.downloads)
+	}
+}
+
+func TestMirrorSkipsUnflaggedPreRelease(t *testing.T) {
+	dir := t.TempDir()
+	fetcher := &fakeFetcher{
+		t:        t,
+		versions: map[string][]models.ExtensionVersion{"openvsx": {{Version: "1.1.0"}, {Version: "1.0.0"}}},
+		packages: map[string]bool{"1.1.0@universal": true, "1.0.0@universal": true},
+		pre:      map[string]bool{"1.1.0": true},
+	}
+	opts := Options{Sources: []string{"openvsx"}, Versions: 1}
+
+	report, _ := Mirror(dir, []string{"pub.ext"}, opts, fetcher, fakeValidator{models.TrustLevelLegitimate})
+	if len(report.Failed) != 0 || len(report.Added) != 1 || report.Added[0].Version != "1.0.0" {
+		t.Fatalf("added = %+v, failed = %+v; want only the 1.0.0 release", report.Added, report.Failed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pub", "ext", "1.1.0", "pub.ext-1.1.0.vsix")); !os.IsNotExist(err) {
+		t.Error("pre-release package should be removed")
+	}
+
+	opts.PreRelease = true
+	report, _ = Mirror(dir, []string{"pub.ext"}, opts, fetcher, fakeValidator{models.TrustLevelLegitimate})
+	if len(report.Added) != 1 || !report.Added[0].PreRelease {
+		t.Errorf("added = %+v, want the 1.1.0 pre-release", report.Added)
+	}
+}
+
+func TestMirrorRefusesMismatched(t *testing.T) {
+	dir := t.TempDir()
+	fetcher := &fakeFetcher{
+		t:        t,
+		versions: map[string][]models.ExtensionVersion{"marketplace": {{Version: "1.0.0"}}},
+		packages: map[string]bool{"1.0.0@universal": true},
+	}
+
+	fetcher.wrongID = true
+	report, _ := Mirror(dir, []string{"pub.ext"}, Options{}, fetcher, fakeValidator{models.TrustLevelLegitimate})
+	if len(report.Failed) != 1 || !strings.Contains(report.Failed[0].Error, "expected pub.ext") {
+		t.Fatalf("mismatch: failed = %+v", report.Failed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pub", "ext", "1.0.0", "pub.ext-1.0.0.vsix")); !os.IsNotExist(err) {
+		t.Error("rejected package should be removed")
+	}
+}
+
+func TestFind(t *testing.T) {
+	index := &models.MirrorIndex{Entries: []models.MirrorEntry{
+		{ExtensionID: "pub.ext", Version: "1.0.0", TargetPlatform: "universal"},
+		{ExtensionID: "pub.ext", Version: "2.0.0", TargetPlatform: "linux-x64"},
+		{ExtensionID: "pub.ext", Version: "2.0.0", TargetPlatform: "win32-x64"},
+		{ExtensionID: "pub.ext", Version: "3.0.0", TargetPlatform: "universal", PreRelease: true},
+	}}
+
+	tests := []struct {
+		version, platform string
+		want              string
+	}{
+		{"", "linux-x64", "2.0.0@linux-x64"},
+		{"", "darwin-arm64", "1.0.0@universal"},
+		{"3.0.0", "linux-x64", "3.0.0@universal"},
+	}
+	for _, tt := range tests {
+		entry, err := Find(index, "Pub.Ext", tt.version, tt.platform)
+		if err != nil {
+			t.Fatalf("Find(%q, %s) error: %v", tt.version, tt.platform, err)
+		}
+		if got := entry.Version + "@" + entry.TargetPlatform; got != tt.want {
+			t.Errorf("Find(%q, %s) = %s, want %s", tt.version, tt.platform, got, tt.want)
+		}
+	}
+
+	if _, err := Find(index, "pub.ext", "9.9.9", "linux-x64"); err == nil {
+		t.Error("expected error for a version not in the mirror")
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+// RiskLevel rates how much a change between two versions of an extension adds risk
+type RiskLevel string
+
+const (
+	RiskNone   RiskLevel = "none"
+	RiskLow    RiskLevel = "low"
+	RiskMedium RiskLevel = "medium"
+	RiskHigh   RiskLevel = "high"
+	// RiskUnknown marks versions that could not be compared
+	RiskUnknown RiskLevel = "unknown"
+)
+
+// PackageChangeKind is the area of a package a change was found in
+type PackageChangeKind string
+
+const (
+	ChangePublisher           PackageChangeKind = "publisher"
+	ChangeActivationEvent     PackageChangeKind = "activationEvent"
+	ChangeDependency          PackageChangeKind = "dependency"
+	ChangeExtensionDependency PackageChangeKind = "extensionDependency"
+	ChangeContributes         PackageChangeKind = "contributes"
+	ChangeEntryPoint          PackageChangeKind = "entryPoint"
+	ChangeNativeBinary        PackageChangeKind = "nativeBinary"
+	ChangeMinifiedFile        PackageChangeKind = "minifiedFile"
+)
+
+// PackageChange is one security-relevant difference between two versions of an extension
+type PackageChange struct {
+	Kind   PackageChangeKind `json:"kind"`
+	Action string            `json:"action"` // "added", "removed" or "changed"
+	Detail string            `json:"detail"`
+	Risk   RiskLevel         `json:"risk"`
+}
+
+// PackageDiff compares two versions of an extension package
+type PackageDiff struct {
+	ExtensionID      string          `json:"extensionId"`
+	OldVersion       string          `json:"oldVersion"`
+	NewVersion       string          `json:"newVersion"`
+	OldPublisher     string          `json:"oldPublisher,omitempty"`
+	NewPublisher     string          `json:"newPublisher,omitempty"`
+	Changes          []PackageChange `json:"changes"`
+	FilesAdded       int             `json:"filesAdded"`
+	FilesRemoved     int             `json:"filesRemoved"`
+	FilesChanged     int             `json:"filesChanged"`
+	ManifestDiff     string          `json:"manifestDiff,omitempty"` // unified diff of package.json
+	Risk             RiskLevel       `json:"risk"`                   // highest risk of any change
+	RequiresApproval bool            `json:"requiresApproval"`
+	Error            string          `json:"error,omitempty"` // why the versions could not be compared
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// DownloadVerification is how a downloaded package was checked
+type DownloadVerification string
+
+const (
+	// VerifiedSHA256 means the package matched the checksum published by the registry
+	VerifiedSHA256 DownloadVerification = "sha256"
+	// VerifiedSizeOnly means only the transfer size was checked; the registry publishes
+	// no checksum (the Marketplace), so the package content is not authenticated
+	VerifiedSizeOnly DownloadVerification = "size-only"
+	// Unverified means neither was checked: the registry publishes no checksum and the
+	// server did not send the package size
+	Unverified DownloadVerification = "unverified"
+)
+
+// DownloadInfo describes an extension package download. It is written next to the
+// downloaded VSIX as its metadata JSON.
+type DownloadInfo struct {
+	ExtensionID    string    `json:"extensionId"`
+	Version        string    `json:"version"`
+	Source         string    `json:"source"` // "marketplace" or "openvsx"
+	TargetPlatform string    `json:"targetPlatform"`
+	URL            string    `json:"url"`
+	ExpectedSHA256 string    `json:"expectedSha256,omitempty"` // checksum published by the registry
+	File           string    `json:"file,omitempty"`
+	Size           int64     `json:"size,omitempty"`
+	SHA256         string    `json:"sha256,omitempty"`
+	ResumedFrom    int64     `json:"resumedFrom,omitempty"` // bytes reused from a partial download
+	DownloadedAt   time.Time `json:"downloadedAt,omitempty"`
+
+	Verification DownloadVerification `json:"verification,omitempty"`
+}
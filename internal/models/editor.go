@@ -19,6 +19,7 @@ type EditorProfile struct {
 	Name           string     `json:"name"`
 	ExtensionsDir  string     `json:"extensionsDir"`
 	IndexFile      string     `json:"indexFile"`
+	UserDataDir    string     `json:"userDataDir,omitempty"`
 	CLICommand     string     `json:"cliCommand,omitempty"`
 	IsVSCodeFamily bool       `json:"isVSCodeFamily"`
 	IsCustom       bool       `json:"isCustom"`
@@ -36,6 +37,36 @@ type EditorStatus struct {
 	IsAvailable     bool          `json:"isAvailable"`
 }
 
+// ExtensionRootSource describes how an extensions directory was discovered
+type ExtensionRootSource string
+
+const (
+	RootSourceDefault  ExtensionRootSource = "default"
+	RootSourceEnv      ExtensionRootSource = "env"
+	RootSourceArgv     ExtensionRootSource = "argv"
+	RootSourcePortable ExtensionRootSource = "portable"
+	RootSourceFlatpak  ExtensionRootSource = "flatpak"
+	RootSourceSnap     ExtensionRootSource = "snap"
+	RootSourceRemote   ExtensionRootSource = "remote"
+	RootSourceWSLHost  ExtensionRootSource = "wslHost"
+	RootSourceCustom   ExtensionRootSource = "custom"
+)
+
+// ExtensionRoot represents one extensions directory used by an editor
+type ExtensionRoot struct {
+	Path           string              `json:"path"`
+	Source         ExtensionRootSource `json:"source"`
+	Exists         bool                `json:"exists"`
+	Active         bool                `json:"active"`
+	ExtensionCount int                 `json:"extensionCount"`
+}
+
+// EditorDiscovery lists every extensions directory found for an editor
+type EditorDiscovery struct {
+	Editor EditorProfile   `json:"editor"`
+	Roots  []ExtensionRoot `json:"roots"`
+}
+
 // SyncRequest represents a request to sync extensions between editors
 type SyncRequest struct {
 	SourceEditor       EditorType   `json:"sourceEditor"`
@@ -58,11 +89,12 @@ type SyncResult struct {
 
 // SyncReport represents the full sync operation report
 type SyncReport struct {
-	SourceEditor EditorType   `json:"sourceEditor"`
-	Results      []SyncResult `json:"results"`
-	TotalCopied  int          `json:"totalCopied"`
-	TotalSkipped int          `json:"totalSkipped"`
-	TotalErrors  int          `json:"totalErrors"`
+	SourceEditor EditorType           `json:"sourceEditor"`
+	Results      []SyncResult         `json:"results"`
+	TotalCopied  int                  `json:"totalCopied"`
+	TotalSkipped int                  `json:"totalSkipped"`
+	TotalErrors  int                  `json:"totalErrors"`
+	UserConfig   []UserConfigSyncPlan `json:"userConfig,omitempty"`
 }
 
 // CLIStatus represents the status of VS Code family CLI tools
@@ -115,3 +147,89 @@ type ExtensionLocation struct {
 	Path   string `json:"path"`
 	Scheme string `json:"scheme,omitempty"`
 }
+
+// VSIXInstallResult represents the result of natively installing a VSIX package into an editor
+type VSIXInstallResult struct {
+	ExtensionID     string     `json:"extensionId"`
+	Version         string     `json:"version"`
+	TargetEditor    EditorType `json:"targetEditor"`
+	InstallPath     string     `json:"installPath"`
+	SHA256          string     `json:"sha256"`
+	TargetPlatform  string     `json:"targetPlatform,omitempty"`
+	ReplacedVersion string     `json:"replacedVersion,omitempty"`
+}
+
+// UninstallResult represents the result of removing an extension from an editor
+type UninstallResult struct {
+	ExtensionID     string     `json:"extensionId"`
+	TargetEditor    EditorType `json:"targetEditor"`
+	RemovedVersions []string   `json:"removedVersions"`
+	RemovedFolders  []string   `json:"removedFolders"`
+	PendingFolders  []string   `json:"pendingFolders,omitempty"`
+	IndexUpdated    bool       `json:"indexUpdated"`
+}
+
+// ExtensionFolder describes an extension folder on disk that is not the active install
+type ExtensionFolder struct {
+	Folder      string `json:"folder"`
+	ExtensionID string `json:"extensionId"`
+	Version     string `json:"version"`
+	SizeBytes   int64  `json:"sizeBytes"`
+	Reason      string `json:"reason"`
+}
+
+// ReconcileReport describes how extension folders line up with extensions.json
+type ReconcileReport struct {
+	ExtensionsDir    string                `json:"extensionsDir"`
+	IndexFound       bool                  `json:"indexFound"`
+	ActiveFolders    map[string]string     `json:"activeFolders"`
+	StaleFolders     []ExtensionFolder     `json:"staleFolders"`
+	OrphanedFolders  []ExtensionFolder     `json:"orphanedFolders"`
+	MissingFolders   []ExtensionIndexEntry `json:"missingFolders"`
+	ReclaimableBytes int64                 `json:"reclaimableBytes"`
+}
+
+// CleanResult represents the result of removing stale and orphaned extension folders
+type CleanResult struct {
+	ExtensionsDir  string            `json:"extensionsDir"`
+	DryRun         bool              `json:"dryRun"`
+	RemovedFolders []ExtensionFolder `json:"removedFolders"`
+	SkippedFolders []ExtensionFolder `json:"skippedFolders,omitempty"`
+	PendingFolders []string          `json:"pendingFolders,omitempty"`
+	PrunedEntries  []string          `json:"prunedEntries,omitempty"`
+	ReclaimedBytes int64             `json:"reclaimedBytes"`
+}
+
+// IndexIssueKind identifies a problem found by the extensions directory doctor
+type IndexIssueKind string
+
+const (
+	IndexIssueMissingIndex        IndexIssueKind = "missingIndex"
+	IndexIssueMalformedIndex      IndexIssueKind = "malformedIndex"
+	IndexIssueMalformedEntry      IndexIssueKind = "malformedEntry"
+	IndexIssueMissingFolder       IndexIssueKind = "missingFolder"
+	IndexIssueUnindexedFolder     IndexIssueKind = "unindexedFolder"
+	IndexIssueWrongLocation       IndexIssueKind = "wrongLocation"
+	IndexIssueDuplicateID         IndexIssueKind = "duplicateId"
+	IndexIssueBadRelativeLocation IndexIssueKind = "badRelativeLocation"
+)
+
+// IndexIssue represents a single problem in an editor's extensions directory and its planned repair
+type IndexIssue struct {
+	Kind        IndexIssueKind `json:"kind"`
+	ExtensionID string         `json:"extensionId,omitempty"`
+	Folder      string         `json:"folder,omitempty"`
+	Detail      string         `json:"detail"`
+	Repair      string         `json:"repair"`
+}
+
+// DoctorReport represents the result of checking (and optionally repairing) an extensions directory
+type DoctorReport struct {
+	ExtensionsDir string       `json:"extensionsDir"`
+	IndexPath     string       `json:"indexPath"`
+	EntryCount    int          `json:"entryCount"`
+	FolderCount   int          `json:"folderCount"`
+	Issues        []IndexIssue `json:"issues"`
+	Fixed         bool         `json:"fixed"`
+	BackupPath    string       `json:"backupPath,omitempty"`
+}
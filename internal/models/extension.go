@@ -12,6 +12,34 @@ const (
 	TrustLevelUnknown    TrustLevel = "Unknown"
 )
 
+// InstallState represents whether an installed extension is active in its editor
+type InstallState string
+
+const (
+	InstallStateEnabled        InstallState = "enabled"
+	InstallStateDisabled       InstallState = "disabled"
+	InstallStatePendingRemoval InstallState = "pendingRemoval"
+)
+
+// SignatureStatus is the outcome of verifying a VSIX package signature
+type SignatureStatus string
+
+const (
+	SignatureMarketplace SignatureStatus = "signed-marketplace"
+	SignatureOpenVSX     SignatureStatus = "signed-openvsx"
+	SignatureUnsigned    SignatureStatus = "unsigned"
+	SignatureInvalid     SignatureStatus = "invalid"
+)
+
+// SignatureCheck is the signature verification of one registry's package
+type SignatureCheck struct {
+	Source  string          `json:"source"` // "marketplace" or "openvsx"
+	Version string          `json:"version,omitempty"`
+	Status  SignatureStatus `json:"status,omitempty"` // empty when the check could not run
+	Signer  string          `json:"signer,omitempty"`
+	Detail  string          `json:"detail,omitempty"`
+}
+
 // ExtensionMetadata represents metadata for a VS Code extension
 type ExtensionMetadata struct {
 	ID                  string            `json:"id"`
@@ -28,10 +56,82 @@ type ExtensionMetadata struct {
 	FileSize            int64             `json:"fileSize,omitempty"`
 	LastUpdated         time.Time         `json:"lastUpdated"`
 	DownloadURL         string            `json:"downloadUrl"`
+	SignatureURL        string            `json:"signatureUrl,omitempty"` // .signature.p7s archive or .sigzip
+	PublicKeyURL        string            `json:"publicKeyUrl,omitempty"` // OpenVSX signing key
+	SHA256URL           string            `json:"sha256Url,omitempty"`    // OpenVSX published checksum
+	TargetPlatform      string            `json:"targetPlatform,omitempty"`
+	InstallCount        int64             `json:"installCount,omitempty"`
+	AverageRating       float64           `json:"averageRating,omitempty"`
+	RatingCount         int64             `json:"ratingCount,omitempty"`
+	Categories          []string          `json:"categories,omitempty"`
+	Tags                []string          `json:"tags,omitempty"`
 	Source              string            `json:"source"` // "marketplace" or "openvsx"
 	AdditionalData      map[string]string `json:"additionalData,omitempty"`
 }
 
+// Search sort orders
+const (
+	SortRelevance = "relevance"
+	SortInstalls  = "installs"
+	SortRating    = "rating"
+	SortUpdated   = "updated"
+)
+
+// DefaultSearchPageSize is the number of results per page when none is given
+const DefaultSearchPageSize = 20
+
+// SearchOptions selects, filters and orders registry search results
+type SearchOptions struct {
+	Query        string   `json:"query"`
+	SortBy       string   `json:"sortBy,omitempty"` // installs, rating, updated or relevance
+	Page         int      `json:"page,omitempty"`   // 1-based
+	PageSize     int      `json:"pageSize,omitempty"`
+	Category     string   `json:"category,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	VerifiedOnly bool     `json:"verifiedOnly,omitempty"`
+}
+
+// SearchResults is one page of registry search results
+type SearchResults struct {
+	Extensions []*ExtensionMetadata `json:"extensions"`
+	Total      int                  `json:"total"` // matches across all pages, when the registry reports it
+	Page       int                  `json:"page"`
+	PageSize   int                  `json:"pageSize"`
+	// TotalUnfiltered is set when filters applied to each page (VerifiedOnly on the
+	// Marketplace) are not reflected in Total, so a page may hold fewer than PageSize results
+	TotalUnfiltered bool `json:"totalUnfiltered,omitempty"`
+}
+
+// ExtensionVersion is one published version of an extension on a registry
+type ExtensionVersion struct {
+	Version         string    `json:"version"`
+	TargetPlatforms []string  `json:"targetPlatforms,omitempty"` // empty for a universal package
+	PreRelease      bool      `json:"preRelease,omitempty"`
+	EngineVSCode    string    `json:"engineVscode,omitempty"` // engines.vscode range, e.g. "^1.80.0"
+	LastUpdated     time.Time `json:"lastUpdated,omitempty"`
+}
+
+// ExtensionDetails is the full listing of an extension on one registry: its latest
+// metadata, the published versions and the README, changelog and license of the latest version
+type ExtensionDetails struct {
+	Metadata      *ExtensionMetadata `json:"metadata"`
+	Versions      []ExtensionVersion `json:"versions"`
+	TotalVersions int                `json:"totalVersions"`     // published versions, before any limit
+	License       string             `json:"license,omitempty"` // SPDX identifier from the manifest
+	LicenseURL    string             `json:"licenseUrl,omitempty"`
+	Readme        string             `json:"readme,omitempty"`
+	Changelog     string             `json:"changelog,omitempty"`
+}
+
+// RegistryDetails are the details of an extension on the Marketplace and OpenVSX
+type RegistryDetails struct {
+	ExtensionID      string            `json:"extensionId"`
+	Marketplace      *ExtensionDetails `json:"marketplace,omitempty"`
+	OpenVSX          *ExtensionDetails `json:"openvsx,omitempty"`
+	MarketplaceError string            `json:"marketplaceError,omitempty"`
+	OpenVSXError     string            `json:"openvsxError,omitempty"`
+}
+
 // ValidationResult represents the result of validating an extension
 type ValidationResult struct {
 	ExtensionID        string             `json:"extensionId"`
@@ -43,28 +143,102 @@ type ValidationResult struct {
 	SHAMatch           bool               `json:"shaMatch"`
 	SHAMismatchDetails string             `json:"shaMismatchDetails,omitempty"`
 	Recommendation     string             `json:"recommendation"`
+	SignatureStatus    SignatureStatus    `json:"signatureStatus,omitempty"`
+	SignatureChecks    []SignatureCheck   `json:"signatureChecks,omitempty"`
+	InstallState       InstallState       `json:"installState,omitempty"`
 	ValidationTime     time.Time          `json:"validationTime"`
 	Error              string             `json:"error,omitempty"`
 }
 
 // InstalledExtension represents an extension installed in the editor
 type InstalledExtension struct {
-	ID           string    `json:"id"`
-	Path         string    `json:"path"`
-	Publisher    string    `json:"publisher"`
-	Name         string    `json:"name"`
-	Version      string    `json:"version"`
-	IsEnabled    bool      `json:"isEnabled"`
-	LastModified time.Time `json:"lastModified"`
+	ID               string    `json:"id"`
+	Path             string    `json:"path"`
+	Publisher        string    `json:"publisher"`
+	Name             string    `json:"name"`
+	Version          string    `json:"version"`
+	IsEnabled        bool      `json:"isEnabled"`
+	IsPendingRemoval bool      `json:"isPendingRemoval"`
+	OtherVersions    []string  `json:"otherVersions,omitempty"`
+	LastModified     time.Time `json:"lastModified"`
+}
+
+// State returns the install state of the extension
+func (e InstalledExtension) State() InstallState {
+	if e.IsPendingRemoval {
+		return InstallStatePendingRemoval
+	}
+	if !e.IsEnabled {
+		return InstallStateDisabled
+	}
+	return InstallStateEnabled
 }
 
 // AuditReport represents a full audit of all installed extensions
 type AuditReport struct {
-	TotalExtensions int                `json:"totalExtensions"`
-	LegitimateCount int                `json:"legitimateCount"`
-	SuspiciousCount int                `json:"suspiciousCount"`
-	MaliciousCount  int                `json:"maliciousCount"`
-	UnknownCount    int                `json:"unknownCount"`
-	Results         []ValidationResult `json:"results"`
-	AuditTime       time.Time          `json:"auditTime"`
+	TotalExtensions     int                `json:"totalExtensions"`
+	LegitimateCount     int                `json:"legitimateCount"`
+	SuspiciousCount     int                `json:"suspiciousCount"`
+	MaliciousCount      int                `json:"maliciousCount"`
+	UnknownCount        int                `json:"unknownCount"`
+	DisabledCount       int                `json:"disabledCount"`
+	PendingRemovalCount int                `json:"pendingRemovalCount"`
+	Results             []ValidationResult `json:"results"`
+	AuditTime           time.Time          `json:"auditTime"`
+}
+
+// InventoryCell represents an extension as installed in one editor
+type InventoryCell struct {
+	Version string       `json:"version"`
+	State   InstallState `json:"state"`
+}
+
+// InventoryRow represents one extension across all scanned editors
+type InventoryRow struct {
+	ExtensionID  string                       `json:"extensionId"`
+	Editors      map[EditorType]InventoryCell `json:"editors"`
+	TrustLevel   TrustLevel                   `json:"trustLevel,omitempty"`
+	VersionSkew  bool                         `json:"versionSkew"`
+	SingleEditor bool                         `json:"singleEditor"`
+}
+
+// Inventory represents a matrix of installed extensions by editor
+type Inventory struct {
+	Editors           []EditorType   `json:"editors"`
+	Rows              []InventoryRow `json:"rows"`
+	TotalExtensions   int            `json:"totalExtensions"`
+	VersionSkewCount  int            `json:"versionSkewCount"`
+	SingleEditorCount int            `json:"singleEditorCount"`
+	TrustChecked      bool           `json:"trustChecked"`
+	GeneratedAt       time.Time      `json:"generatedAt"`
+}
+
+// RegistryPresence tells which registries publish an extension
+type RegistryPresence string
+
+const (
+	PresenceBoth            RegistryPresence = "both"
+	PresenceMarketplaceOnly RegistryPresence = "marketplace-only"
+	PresenceOpenVSXOnly     RegistryPresence = "openvsx-only"
+)
+
+// DualSearchRow is one extension found in either or both registries
+type DualSearchRow struct {
+	ExtensionID   string             `json:"extensionId"`
+	DisplayName   string             `json:"displayName,omitempty"`
+	Marketplace   *ExtensionMetadata `json:"marketplace,omitempty"`
+	OpenVSX       *ExtensionMetadata `json:"openvsx,omitempty"`
+	Presence      RegistryPresence   `json:"presence"`
+	VersionsMatch bool               `json:"versionsMatch"` // both registries publish the same latest version
+}
+
+// DualSearchResults are the merged search results of the Marketplace and OpenVSX
+type DualSearchResults struct {
+	Query            string          `json:"query"`
+	Rows             []DualSearchRow `json:"rows"`
+	MarketplaceTotal int             `json:"marketplaceTotal"`
+	OpenVSXTotal     int             `json:"openvsxTotal"`
+	MarketplaceError string          `json:"marketplaceError,omitempty"`
+	OpenVSXError     string          `json:"openvsxError,omitempty"`
+	TotalsUnfiltered bool            `json:"totalsUnfiltered,omitempty"` // totals count matches before the verified filter
 }
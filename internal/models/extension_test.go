@@ -172,3 +172,23 @@ func TestAuditReport(t *testing.T) {
 		t.Errorf("TotalExtensions = %d, want %d", decoded.TotalExtensions, report.TotalExtensions)
 	}
 }
+
+func TestInstalledExtensionState(t *testing.T) {
+	tests := []struct {
+		name     string
+		ext      InstalledExtension
+		expected InstallState
+	}{
+		{"Enabled", InstalledExtension{IsEnabled: true}, InstallStateEnabled},
+		{"Disabled", InstalledExtension{IsEnabled: false}, InstallStateDisabled},
+		{"Pending removal wins", InstalledExtension{IsEnabled: false, IsPendingRemoval: true}, InstallStatePendingRemoval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ext.State(); got != tt.expected {
+				t.Errorf("State() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
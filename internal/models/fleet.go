@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// MachineIdentity identifies the machine that submitted an audit report. The machine ID
+// is derived from the machine's signing key, so it cannot be claimed by another machine.
+type MachineIdentity struct {
+	MachineID string `json:"machineId"`
+	PublicKey string `json:"publicKey"` // base64 ed25519 public key
+	Hostname  string `json:"hostname"`
+	User      string `json:"user"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+// AuditSubmission is an audit report sent to a fleet collector
+type AuditSubmission struct {
+	Machine     MachineIdentity `json:"machine"`
+	Report      *AuditReport    `json:"report"`
+	SubmittedAt time.Time       `json:"submittedAt"`
+	ReceivedAt  time.Time       `json:"receivedAt,omitempty"` // set by the collector
+}
+
+// FleetMachine summarizes the latest report from one machine
+type FleetMachine struct {
+	MachineID       string    `json:"machineId"`
+	Hostname        string    `json:"hostname"`
+	User            string    `json:"user"`
+	OS              string    `json:"os"`
+	LastReport      time.Time `json:"lastReport"`
+	TotalExtensions int       `json:"totalExtensions"`
+	SuspiciousCount int       `json:"suspiciousCount"`
+	MaliciousCount  int       `json:"maliciousCount"`
+}
+
+// FleetExtension lists the machines where a flagged extension is installed
+type FleetExtension struct {
+	ExtensionID string            `json:"extensionId"`
+	TrustLevel  TrustLevel        `json:"trustLevel"`
+	Machines    []FleetMachineRef `json:"machines"`
+}
+
+// FleetMachineRef is a short reference to a machine in a fleet summary
+type FleetMachineRef struct {
+	MachineID string `json:"machineId"`
+	Hostname  string `json:"hostname"`
+	User      string `json:"user"`
+}
+
+// FleetSummary aggregates the latest audit report of every machine
+type FleetSummary struct {
+	TotalMachines        int              `json:"totalMachines"`
+	AffectedMachines     int              `json:"affectedMachines"` // machines with a malicious extension
+	MaliciousExtensions  []FleetExtension `json:"maliciousExtensions"`
+	SuspiciousExtensions []FleetExtension `json:"suspiciousExtensions"`
+	Machines             []FleetMachine   `json:"machines"`
+	GeneratedAt          time.Time        `json:"generatedAt"`
+}
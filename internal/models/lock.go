@@ -0,0 +1,65 @@
+package models
+
+// LockfileVersion is the current vsynx.lock format version
+const LockfileVersion = 1
+
+// Lockfile represents a vsynx.lock manifest: the extensions every listed editor should have
+type Lockfile struct {
+	Version    int          `json:"version"`
+	Editors    []EditorType `json:"editors,omitempty"`
+	Extensions []LockEntry  `json:"extensions"`
+}
+
+// LockEntry pins one extension to a version, hash, source registry and set of editors.
+// Editors defaults to the lockfile's top-level Editors list.
+type LockEntry struct {
+	ID      string       `json:"id"`
+	Version string       `json:"version"`
+	SHA256  string       `json:"sha256,omitempty"`
+	Source  string       `json:"source,omitempty"` // "marketplace" (default) or "openvsx"
+	Editors []EditorType `json:"editors,omitempty"`
+}
+
+// LockAction is the change needed to bring an editor in line with the lockfile
+type LockAction string
+
+const (
+	LockActionInstall   LockAction = "install"
+	LockActionUpdate    LockAction = "update"
+	LockActionDowngrade LockAction = "downgrade"
+	LockActionRemove    LockAction = "remove"
+)
+
+// LockPlanStep represents one change to a single editor
+type LockPlanStep struct {
+	Editor         EditorType `json:"editor"`
+	ExtensionID    string     `json:"extensionId"`
+	Action         LockAction `json:"action"`
+	CurrentVersion string     `json:"currentVersion,omitempty"`
+	TargetVersion  string     `json:"targetVersion,omitempty"`
+	SHA256         string     `json:"sha256,omitempty"`
+	Source         string     `json:"source,omitempty"`
+}
+
+// LockPlan represents the difference between a lockfile and the installed extensions
+type LockPlan struct {
+	Steps     []LockPlanStep          `json:"steps"`
+	UpToDate  int                     `json:"upToDate"`
+	Unmanaged map[EditorType][]string `json:"unmanaged,omitempty"`
+}
+
+// LockApplyResult represents the outcome of one plan step
+type LockApplyResult struct {
+	Step       LockPlanStep `json:"step"`
+	Success    bool         `json:"success"`
+	TrustLevel TrustLevel   `json:"trustLevel,omitempty"`
+	SHA256     string       `json:"sha256,omitempty"`
+	Error      string       `json:"error,omitempty"`
+}
+
+// LockApplyReport represents the result of applying a lockfile
+type LockApplyReport struct {
+	Results   []LockApplyResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// MirrorEntry is one extension package stored in a local mirror repository
+type MirrorEntry struct {
+	ExtensionID    string     `json:"extensionId"`
+	Version        string     `json:"version"`
+	TargetPlatform string     `json:"targetPlatform"`
+	DisplayName    string     `json:"displayName,omitempty"`
+	PreRelease     bool       `json:"preRelease,omitempty"`
+	Source         string     `json:"source"` // registry it was mirrored from
+	File           string     `json:"file"`   // slash-separated path relative to the repository root
+	Size           int64      `json:"size"`
+	SHA256         string     `json:"sha256"`
+	TrustLevel     TrustLevel `json:"trustLevel"`
+	MirroredAt     time.Time  `json:"mirroredAt"`
+}
+
+// MirrorIndex lists the packages of a local mirror repository (index.json at its root)
+type MirrorIndex struct {
+	SchemaVersion int           `json:"schemaVersion"`
+	UpdatedAt     time.Time     `json:"updatedAt"`
+	Entries       []MirrorEntry `json:"entries"`
+}
+
+// MirrorFailure is a package that could not be mirrored
+type MirrorFailure struct {
+	ExtensionID    string `json:"extensionId"`
+	Version        string `json:"version,omitempty"`
+	TargetPlatform string `json:"targetPlatform,omitempty"`
+	Error          string `json:"error"`
+}
+
+// MirrorReport summarizes a mirror run
+type MirrorReport struct {
+	Dir        string          `json:"dir"`
+	Extensions int             `json:"extensions"`
+	Added      []MirrorEntry   `json:"added"`
+	Existing   int             `json:"existing"` // packages already in the repository
+	Failed     []MirrorFailure `json:"failed,omitempty"`
+}
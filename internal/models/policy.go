@@ -0,0 +1,109 @@
+package models
+
+import "time"
+
+// PolicyVersion is the current organization policy format version
+const PolicyVersion = 1
+
+// Policy is an organization-wide extension policy
+type Policy struct {
+	Version   int               `json:"version"`
+	Serial    int64             `json:"serial,omitempty"` // raised with every published revision
+	Name      string            `json:"name,omitempty"`
+	Editors   []EditorType      `json:"editors,omitempty"` // default: every detected editor
+	Blocked   []PolicyRule      `json:"blocked,omitempty"`
+	Mandatory []PolicyExtension `json:"mandatory,omitempty"`
+	Pinned    []PolicyExtension `json:"pinned,omitempty"`
+}
+
+// PolicyRule blocks an extension ID, or every extension of a publisher with "publisher.*"
+type PolicyRule struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// PolicyExtension is a mandatory or pinned extension. Mandatory extensions without a
+// version are installed at the latest marketplace version.
+type PolicyExtension struct {
+	ID      string `json:"id"`
+	Version string `json:"version,omitempty"`
+	SHA256  string `json:"sha256,omitempty"`
+	Source  string `json:"source,omitempty"` // "marketplace" (default) or "openvsx"
+}
+
+// PolicySource configures where the organization policy is fetched from
+type PolicySource struct {
+	URL       string `json:"url,omitempty"`     // HTTP(S) URL of the policy JSON
+	Git       string `json:"git,omitempty"`     // git repository URL or path
+	GitFile   string `json:"gitFile,omitempty"` // policy file inside the repository
+	GitRef    string `json:"gitRef,omitempty"`  // branch or tag (default: the remote HEAD)
+	PublicKey string `json:"publicKey,omitempty"`
+	Interval  string `json:"interval,omitempty"` // how often to refresh the cache, e.g. "1h"
+
+	// AllowUnsigned accepts policies without a signature; only meant for testing
+	AllowUnsigned bool `json:"allowUnsigned,omitempty"`
+	// AllowHTTP accepts unencrypted http:// and git:// locations; only meant for testing
+	AllowHTTP bool `json:"allowHttp,omitempty"`
+}
+
+// PolicyStatus describes the cached policy
+type PolicyStatus struct {
+	Source    PolicySource `json:"source"`
+	Policy    *Policy      `json:"policy,omitempty"`
+	SHA256    string       `json:"sha256,omitempty"`
+	FetchedAt time.Time    `json:"fetchedAt,omitempty"`
+	Signed    bool         `json:"signed"`
+	Stale     bool         `json:"stale"`           // the last refresh failed; the cache was used
+	Error     string       `json:"error,omitempty"` // why the refresh failed
+}
+
+// EnforcementAction is a change made to bring an editor in line with the policy
+type EnforcementAction string
+
+const (
+	EnforcementUninstall EnforcementAction = "uninstall"
+	EnforcementInstall   EnforcementAction = "install"
+	EnforcementPin       EnforcementAction = "pin"
+)
+
+// EnforcementStep is one planned enforcement action
+type EnforcementStep struct {
+	Editor         EditorType        `json:"editor"`
+	ExtensionID    string            `json:"extensionId"`
+	Action         EnforcementAction `json:"action"`
+	CurrentVersion string            `json:"currentVersion,omitempty"`
+	TargetVersion  string            `json:"targetVersion,omitempty"` // empty installs the latest version
+	SHA256         string            `json:"sha256,omitempty"`
+	Source         string            `json:"source,omitempty"`
+	Reason         string            `json:"reason,omitempty"`
+}
+
+// EnforcementPlan lists the actions needed to comply with the policy
+type EnforcementPlan struct {
+	Policy    string            `json:"policy,omitempty"`
+	PolicySHA string            `json:"policySha256,omitempty"`
+	Editors   []EditorType      `json:"editors"`
+	Steps     []EnforcementStep `json:"steps"`
+	Compliant int               `json:"compliant"` // policy entries already satisfied
+}
+
+// EnforcementResult is the outcome of one enforcement step, as written to the audit log
+type EnforcementResult struct {
+	Time       time.Time       `json:"time"`
+	Policy     string          `json:"policy,omitempty"`
+	PolicySHA  string          `json:"policySha256,omitempty"`
+	Step       EnforcementStep `json:"step"`
+	Version    string          `json:"version,omitempty"` // version installed
+	TrustLevel TrustLevel      `json:"trustLevel,omitempty"`
+	SHA256     string          `json:"sha256,omitempty"`
+	Success    bool            `json:"success"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// EnforcementReport summarizes an enforcement run
+type EnforcementReport struct {
+	Plan      *EnforcementPlan    `json:"plan"`
+	Results   []EnforcementResult `json:"results"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// QuarantineRecord describes an extension moved out of an editor's extensions directory
+type QuarantineRecord struct {
+	ID            string                `json:"id"` // name of the quarantine folder
+	ExtensionID   string                `json:"extensionId"`
+	Editor        EditorType            `json:"editor"`
+	ExtensionsDir string                `json:"extensionsDir"`
+	Folders       []string              `json:"folders"`
+	IndexEntries  []ExtensionIndexEntry `json:"indexEntries,omitempty"`
+	Reason        string                `json:"reason,omitempty"`
+	TrustLevel    TrustLevel            `json:"trustLevel,omitempty"`
+	QuarantinedAt time.Time             `json:"quarantinedAt"`
+	Path          string                `json:"path"`
+
+	// Set when the extension was replaced by its official build (vsynx remediate)
+	ReplacementVersion string `json:"replacementVersion,omitempty"`
+	ReplacementSHA256  string `json:"replacementSha256,omitempty"`
+}
+
+// RemediationResult describes an extension replaced by its official marketplace build
+type RemediationResult struct {
+	ExtensionID      string     `json:"extensionId"`
+	Editor           EditorType `json:"editor"`
+	TrustLevel       TrustLevel `json:"trustLevel,omitempty"` // before remediation
+	PreviousVersions []string   `json:"previousVersions"`
+	QuarantineID     string     `json:"quarantineId"`
+	OfficialVersion  string     `json:"officialVersion"`
+	SHA256           string     `json:"sha256"`
+	InstallPath      string     `json:"installPath"`
+}
+
+// FixAction is the remediation applied to a flagged extension by 'vsynx audit --fix'
+type FixAction string
+
+const (
+	FixActionQuarantine FixAction = "quarantine"
+	FixActionReplace    FixAction = "replace"
+	FixActionUninstall  FixAction = "uninstall"
+)
+
+// FixStatus is the outcome of fixing one extension
+type FixStatus string
+
+const (
+	FixStatusFixed   FixStatus = "fixed"
+	FixStatusSkipped FixStatus = "skipped"
+	FixStatusFailed  FixStatus = "failed"
+)
+
+// AuditFixResult describes the action taken for one flagged extension
+type AuditFixResult struct {
+	ExtensionID     string     `json:"extensionId"`
+	TrustLevel      TrustLevel `json:"trustLevel"`
+	Action          FixAction  `json:"action"`
+	Status          FixStatus  `json:"status"`
+	QuarantineID    string     `json:"quarantineId,omitempty"`
+	OfficialVersion string     `json:"officialVersion,omitempty"`
+	Error           string     `json:"error,omitempty"`
+}
+
+// AuditFixReport summarizes the fixes applied to an audit report
+type AuditFixReport struct {
+	Editor       EditorType       `json:"editor"`
+	Action       FixAction        `json:"action"`
+	Results      []AuditFixResult `json:"results"`
+	FixedCount   int              `json:"fixedCount"`
+	SkippedCount int              `json:"skippedCount"`
+	FailedCount  int              `json:"failedCount"`
+	FixedAt      time.Time        `json:"fixedAt"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// OutdatedExtension compares an installed extension with the newest versions on both
+// registries that suit the editor's VS Code version, platform and release channel
+type OutdatedExtension struct {
+	ExtensionID       string `json:"extensionId"`
+	InstalledVersion  string `json:"installedVersion"`
+	PreReleaseChannel bool   `json:"preReleaseChannel,omitempty"`
+	MarketplaceLatest string `json:"marketplaceLatest,omitempty"`
+	OpenVSXLatest     string `json:"openvsxLatest,omitempty"`
+	MarketplaceError  string `json:"marketplaceError,omitempty"`
+	OpenVSXError      string `json:"openvsxError,omitempty"`
+	Outdated          bool   `json:"outdated"`
+	UpdateVersion     string `json:"updateVersion,omitempty"`  // newest compatible version on either registry
+	UpdateSource      string `json:"updateSource,omitempty"`   // "marketplace" or "openvsx"
+	UpdatePlatform    string `json:"updatePlatform,omitempty"` // package to install, "universal" or e.g. "linux-x64"
+}
+
+// OutdatedReport lists the installed extensions of one editor with their available updates
+type OutdatedReport struct {
+	Editor        EditorType          `json:"editor"`
+	EditorName    string              `json:"editorName"`
+	EngineVersion string              `json:"engineVersion,omitempty"` // VS Code version of the editor, if known
+	Platform      string              `json:"platform"`
+	Extensions    []OutdatedExtension `json:"extensions"`
+	OutdatedCount int                 `json:"outdatedCount"`
+	CheckedAt     time.Time           `json:"checkedAt"`
+}
+
+// UpdateStatus is the outcome of updating one extension
+type UpdateStatus string
+
+const (
+	UpdateStatusUpdated UpdateStatus = "updated"
+	UpdateStatusSkipped UpdateStatus = "skipped"
+	UpdateStatusFailed  UpdateStatus = "failed"
+)
+
+// UpdateResult describes the update of one extension
+type UpdateResult struct {
+	ExtensionID string       `json:"extensionId"`
+	FromVersion string       `json:"fromVersion"`
+	ToVersion   string       `json:"toVersion"`
+	Source      string       `json:"source"`
+	Method      string       `json:"method,omitempty"` // "native" or the editor CLI used
+	TrustLevel  TrustLevel   `json:"trustLevel,omitempty"`
+	SHA256      string       `json:"sha256,omitempty"`
+	Review      *PackageDiff `json:"review,omitempty"` // changes from the installed version, when reviewed
+	Status      UpdateStatus `json:"status"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// UpdateReport summarizes the updates applied to one editor
+type UpdateReport struct {
+	Editor       EditorType     `json:"editor"`
+	Results      []UpdateResult `json:"results"`
+	UpdatedCount int            `json:"updatedCount"`
+	SkippedCount int            `json:"skippedCount"`
+	FailedCount  int            `json:"failedCount"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+}
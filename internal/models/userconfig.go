@@ -0,0 +1,64 @@
+package models
+
+// UserConfigKind is a kind of user configuration synced between editors
+type UserConfigKind string
+
+const (
+	UserConfigSettings    UserConfigKind = "settings"
+	UserConfigKeybindings UserConfigKind = "keybindings"
+	UserConfigSnippets    UserConfigKind = "snippets"
+)
+
+// UserConfigAction is what syncing does to a target configuration file
+type UserConfigAction string
+
+const (
+	UserConfigCreate    UserConfigAction = "create"
+	UserConfigUpdate    UserConfigAction = "update"
+	UserConfigUnchanged UserConfigAction = "unchanged"
+)
+
+// UserConfigSyncOptions selects which user configuration is synced
+type UserConfigSyncOptions struct {
+	Settings    bool `json:"settings"`
+	Keybindings bool `json:"keybindings"`
+	Snippets    bool `json:"snippets"`
+}
+
+// Any reports whether any kind of configuration is selected
+func (o UserConfigSyncOptions) Any() bool {
+	return o.Settings || o.Keybindings || o.Snippets
+}
+
+// UserConfigChange is the planned change to one configuration file of the target editor
+type UserConfigChange struct {
+	Kind       UserConfigKind   `json:"kind"`
+	SourcePath string           `json:"sourcePath"`
+	TargetPath string           `json:"targetPath"`
+	Action     UserConfigAction `json:"action"`
+	Added      []string         `json:"added,omitempty"`    // settings keys, keybindings or snippet names
+	Changed    []string         `json:"changed,omitempty"`  // settings keys whose value is replaced
+	Filtered   []string         `json:"filtered,omitempty"` // editor-specific entries left out
+	Diff       string           `json:"diff,omitempty"`
+	Content    string           `json:"-"` // new file content
+}
+
+// UserConfigSyncPlan lists the configuration changes for one target editor
+type UserConfigSyncPlan struct {
+	SourceEditor EditorType         `json:"sourceEditor"`
+	TargetEditor EditorType         `json:"targetEditor"`
+	Changes      []UserConfigChange `json:"changes"`
+	Written      bool               `json:"written"`
+	BackupPaths  []string           `json:"backupPaths,omitempty"`
+}
+
+// Pending returns the number of files the plan would create or update
+func (p *UserConfigSyncPlan) Pending() int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action != UserConfigUnchanged {
+			count++
+		}
+	}
+	return count
+}
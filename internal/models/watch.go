@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// WatchAction is what the watcher did about a new or changed extension
+type WatchAction string
+
+const (
+	WatchActionValidated   WatchAction = "validated"
+	WatchActionQuarantined WatchAction = "quarantined"
+)
+
+// WatchEvent is the result of validating an extension folder that appeared or changed
+type WatchEvent struct {
+	Time         time.Time   `json:"time"`
+	Editor       EditorType  `json:"editor"`
+	ExtensionID  string      `json:"extensionId"`
+	Version      string      `json:"version"`
+	Folder       string      `json:"folder"`
+	TrustLevel   TrustLevel  `json:"trustLevel"`
+	Issues       []string    `json:"issues,omitempty"`
+	Action       WatchAction `json:"action"`
+	QuarantineID string      `json:"quarantineId,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+// IsAlert reports whether the event is about a suspicious or malicious extension
+func (e WatchEvent) IsAlert() bool {
+	return e.TrustLevel == TrustLevelSuspicious || e.TrustLevel == TrustLevelMalicious
+}
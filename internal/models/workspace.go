@@ -0,0 +1,45 @@
+package models
+
+// WorkspaceRecommendations mirrors a workspace's .vscode/extensions.json
+type WorkspaceRecommendations struct {
+	Recommendations         []string `json:"recommendations"`
+	UnwantedRecommendations []string `json:"unwantedRecommendations,omitempty"`
+}
+
+// ProfileExtension is one extension of a VS Code .code-profile export
+type ProfileExtension struct {
+	Identifier  ExtensionIdentifier `json:"identifier"`
+	DisplayName string              `json:"displayName,omitempty"`
+	Version     string              `json:"version,omitempty"`
+	Disabled    bool                `json:"disabled,omitempty"`
+	PreRelease  bool                `json:"preRelease,omitempty"`
+}
+
+// CodeProfile is the part of a .code-profile export vsynx reads and writes
+type CodeProfile struct {
+	Name       string             `json:"name"`
+	Extensions []ProfileExtension `json:"extensions"`
+}
+
+// WorkspaceExtensionCheck is the state of one recommended or unwanted extension in an editor
+type WorkspaceExtensionCheck struct {
+	ExtensionID      string     `json:"extensionId"`
+	Unwanted         bool       `json:"unwanted"`
+	Version          string     `json:"version,omitempty"` // version requested by a profile
+	Installed        bool       `json:"installed"`
+	InstalledVersion string     `json:"installedVersion,omitempty"`
+	TrustLevel       TrustLevel `json:"trustLevel,omitempty"`
+	Error            string     `json:"error,omitempty"`
+}
+
+// WorkspaceCheckReport compares a recommendations file or profile with an editor's extensions
+type WorkspaceCheckReport struct {
+	Source            string                    `json:"source"`
+	Editor            EditorType                `json:"editor"`
+	Extensions        []WorkspaceExtensionCheck `json:"extensions"`
+	MissingCount      int                       `json:"missingCount"`
+	UnwantedInstalled int                       `json:"unwantedInstalled"`
+	UntrustedCount    int                       `json:"untrustedCount"`
+	TrustChecked      bool                      `json:"trustChecked"`
+	Installs          []InstallResult           `json:"installs,omitempty"`
+}
@@ -1,13 +1,19 @@
 package openvsx
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/yourusername/secureopenvsx/internal/asset"
 	"github.com/yourusername/secureopenvsx/internal/models"
 )
 
@@ -32,33 +38,95 @@ func NewClient() *Client {
 	}
 }
 
+// NewClientWithBaseURL creates a client for an OpenVSX-compatible registry at baseURL
+// (e.g. "http://localhost:8081/api" for vsynx serve-registry)
+func NewClientWithBaseURL(baseURL string) *Client {
+	client := NewClient()
+	client.baseURL = strings.TrimSuffix(baseURL, "/")
+	return client
+}
+
 // openVSXExtension represents the response from OpenVSX API
 type openVSXExtension struct {
-	Namespace   string `json:"namespace"`
-	Name        string `json:"name"`
-	Version     string `json:"version"`
-	DisplayName string `json:"displayName"`
-	Description string `json:"description"`
-	Repository  string `json:"repository"`
-	Homepage    string `json:"homepage"`
-	Files       struct {
-		Download string `json:"download"`
+	Namespace      string            `json:"namespace"`
+	Name           string            `json:"name"`
+	Version        string            `json:"version"`
+	TargetPlatform string            `json:"targetPlatform"`
+	DisplayName    string            `json:"displayName"`
+	Description    string            `json:"description"`
+	Repository     string            `json:"repository"`
+	Homepage       string            `json:"homepage"`
+	License        string            `json:"license"`
+	PreRelease     bool              `json:"preRelease"`
+	Engines        map[string]string `json:"engines"`
+	Files          struct {
+		Download  string `json:"download"`
+		Signature string `json:"signature"`
+		PublicKey string `json:"publicKey"`
+		SHA256    string `json:"sha256"`
+		Readme    string `json:"readme"`
+		Changelog string `json:"changelog"`
+		License   string `json:"license"`
 	} `json:"files"`
-	Timestamp string `json:"timestamp"`
+	Timestamp                 string `json:"timestamp"`
+	AllTargetPlatformVersions []struct {
+		Version         string   `json:"version"`
+		TargetPlatforms []string `json:"targetPlatforms"`
+	} `json:"allTargetPlatformVersions"`
 }
 
 // FetchMetadata fetches extension metadata from the OpenVSX registry
 func (c *Client) FetchMetadata(extensionID string) (*models.ExtensionMetadata, error) {
 	log.Printf("[OpenVSX] Fetching metadata for extension: %s", extensionID)
+	return c.fetchMetadata(extensionID, "", "")
+}
+
+// FetchVersionMetadata fetches the metadata of a specific extension version from the OpenVSX registry
+func (c *Client) FetchVersionMetadata(extensionID, version string) (*models.ExtensionMetadata, error) {
+	log.Printf("[OpenVSX] Fetching metadata for extension: %s@%s", extensionID, version)
+	if version == "" {
+		return nil, fmt.Errorf("version is required")
+	}
+	return c.fetchMetadata(extensionID, version, "")
+}
+
+// FetchPlatformMetadata fetches the metadata of an extension built for a target platform
+// (e.g. "linux-x64"). An empty version fetches the latest.
+func (c *Client) FetchPlatformMetadata(extensionID, version, targetPlatform string) (*models.ExtensionMetadata, error) {
+	log.Printf("[OpenVSX] Fetching metadata for extension: %s@%s (%s)", extensionID, version, targetPlatform)
+	return c.fetchMetadata(extensionID, version, targetPlatform)
+}
+
+// fetchMetadata fetches the latest (or the given) version of an extension, optionally
+// for a specific target platform
+func (c *Client) fetchMetadata(extensionID, version, targetPlatform string) (*models.ExtensionMetadata, error) {
+	ext, err := c.fetchExtension(extensionID, version, targetPlatform)
+	if err != nil {
+		return nil, err
+	}
+	metadata := ext.metadata()
+	log.Printf("[OpenVSX] Successfully fetched metadata for %s (version %s)", extensionID, metadata.Version)
+	return metadata, nil
+}
+
+// fetchExtension fetches the registry's JSON for the latest (or the given) version of an
+// extension, optionally for a specific target platform
+func (c *Client) fetchExtension(extensionID, version, targetPlatform string) (*openVSXExtension, error) {
 	// Parse extensionID (format: publisher.name)
 	publisher, name, err := parseExtensionID(extensionID)
 	if err != nil {
 		return nil, err
 	}
 
-	url := fmt.Sprintf("%s/%s/%s", c.baseURL, publisher, name)
+	requestURL := fmt.Sprintf("%s/%s/%s", c.baseURL, url.PathEscape(publisher), url.PathEscape(name))
+	if targetPlatform != "" && targetPlatform != "universal" {
+		requestURL += "/" + url.PathEscape(targetPlatform)
+	}
+	if version != "" {
+		requestURL += "/" + url.PathEscape(version)
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequest("GET", requestURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -88,25 +156,353 @@ func (c *Client) FetchMetadata(extensionID string) (*models.ExtensionMetadata, e
 	if err := json.NewDecoder(resp.Body).Decode(&ext); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	return &ext, nil
+}
 
+// metadata converts a registry response to extension metadata
+func (ext *openVSXExtension) metadata() *models.ExtensionMetadata {
 	lastUpdated, _ := time.Parse(time.RFC3339, ext.Timestamp)
 
-	metadata := &models.ExtensionMetadata{
-		ID:            fmt.Sprintf("%s.%s", ext.Namespace, ext.Name),
-		Publisher:     ext.Namespace,
-		Name:          ext.Name,
-		Version:       ext.Version,
-		DisplayName:   ext.DisplayName,
-		Description:   ext.Description,
-		RepositoryURL: ext.Repository,
-		HomepageURL:   ext.Homepage,
-		DownloadURL:   ext.Files.Download,
-		LastUpdated:   lastUpdated,
-		Source:        "openvsx",
+	return &models.ExtensionMetadata{
+		ID:             fmt.Sprintf("%s.%s", ext.Namespace, ext.Name),
+		Publisher:      ext.Namespace,
+		Name:           ext.Name,
+		Version:        ext.Version,
+		DisplayName:    ext.DisplayName,
+		Description:    ext.Description,
+		RepositoryURL:  ext.Repository,
+		HomepageURL:    ext.Homepage,
+		DownloadURL:    ext.Files.Download,
+		SignatureURL:   ext.Files.Signature,
+		PublicKeyURL:   ext.Files.PublicKey,
+		SHA256URL:      ext.Files.SHA256,
+		TargetPlatform: ext.TargetPlatform,
+		LastUpdated:    lastUpdated,
+		Source:         "openvsx",
 	}
+}
 
-	log.Printf("[OpenVSX] Successfully fetched metadata for %s (version %s)", extensionID, metadata.Version)
-	return metadata, nil
+// detailWorkers is the number of versions fetched concurrently for extension details
+const detailWorkers = 4
+
+// maxDetailedVersions caps the per-version requests of Details; older versions are
+// listed without their date, engine range and pre-release flag
+const maxDetailedVersions = 20
+
+// Details fetches the full listing of an extension: its latest metadata, every published
+// version (at most maxVersions, newest first; 0 for all) and the README, changelog and
+// license of the latest version. OpenVSX only reports dates, engines and the pre-release
+// flag per version, so the newest maxDetailedVersions versions are fetched separately.
+func (c *Client) Details(extensionID string, maxVersions int) (*models.ExtensionDetails, error) {
+	log.Printf("[OpenVSX] Fetching details for extension: %s", extensionID)
+	latest, err := c.fetchExtension(extensionID, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []models.ExtensionVersion
+	for _, v := range latest.AllTargetPlatformVersions {
+		version := models.ExtensionVersion{Version: v.Version}
+		for _, platform := range v.TargetPlatforms {
+			if platform != "universal" {
+				version.TargetPlatforms = append(version.TargetPlatforms, platform)
+			}
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		// Older registries do not list the platforms of each version
+		if versions, err = c.ListVersions(extensionID); err != nil {
+			return nil, err
+		}
+	}
+
+	details := &models.ExtensionDetails{
+		Metadata:      latest.metadata(),
+		TotalVersions: len(versions),
+		License:       latest.License,
+		LicenseURL:    latest.Files.License,
+	}
+	if maxVersions > 0 && len(versions) > maxVersions {
+		versions = versions[:maxVersions]
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < detailWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				c.fillVersion(extensionID, &versions[i])
+			}
+		}()
+	}
+	for i := range versions[:min(len(versions), maxDetailedVersions)] {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	details.Versions = versions
+
+	if latest.Files.Readme != "" {
+		details.Readme = c.fetchAsset(latest.Files.Readme)
+	}
+	if latest.Files.Changelog != "" {
+		details.Changelog = c.fetchAsset(latest.Files.Changelog)
+	}
+
+	log.Printf("[OpenVSX] Fetched details for %s (%d versions)", extensionID, details.TotalVersions)
+	return details, nil
+}
+
+// fillVersion completes a listed version with its date, engine range and pre-release flag
+func (c *Client) fillVersion(extensionID string, version *models.ExtensionVersion) {
+	platform := ""
+	if len(version.TargetPlatforms) > 0 {
+		// Platform-specific versions have no universal package to look up
+		platform = version.TargetPlatforms[0]
+	}
+	details, err := c.VersionDetails(extensionID, version.Version, platform)
+	if err != nil {
+		log.Printf("[OpenVSX] Failed to fetch %s@%s: %v", extensionID, version.Version, err)
+		return
+	}
+	version.LastUpdated = details.LastUpdated
+	version.PreRelease = details.PreRelease
+	version.EngineVSCode = details.EngineVSCode
+}
+
+// VersionDetails fetches the date, engine range and pre-release flag of one version of
+// an extension, built for targetPlatform (empty for the universal package). The version
+// lists the single platform that was found, since OpenVSX reports one package at a time.
+func (c *Client) VersionDetails(extensionID, version, targetPlatform string) (*models.ExtensionVersion, error) {
+	ext, err := c.fetchExtension(extensionID, version, targetPlatform)
+	if err != nil {
+		return nil, err
+	}
+	details := &models.ExtensionVersion{
+		Version:      ext.Version,
+		PreRelease:   ext.PreRelease,
+		EngineVSCode: ext.Engines["vscode"],
+	}
+	details.LastUpdated, _ = time.Parse(time.RFC3339, ext.Timestamp)
+	if ext.TargetPlatform != "" && ext.TargetPlatform != "universal" {
+		details.TargetPlatforms = []string{ext.TargetPlatform}
+	}
+	return details, nil
+}
+
+// fetchAsset downloads a text file of a version, returning "" if it is unavailable
+func (c *Client) fetchAsset(assetURL string) string {
+	text, err := asset.Fetch(c.httpClient, assetURL, UserAgent)
+	if err != nil {
+		log.Printf("[OpenVSX] Failed to fetch asset %s: %v", assetURL, err)
+	}
+	return text
+}
+
+// searchEntry is an extension in an OpenVSX search response
+type searchEntry struct {
+	Namespace     string  `json:"namespace"`
+	Name          string  `json:"name"`
+	Version       string  `json:"version"`
+	DisplayName   string  `json:"displayName"`
+	Description   string  `json:"description"`
+	Timestamp     string  `json:"timestamp"`
+	Verified      bool    `json:"verified"`
+	DownloadCount int64   `json:"downloadCount"`
+	AverageRating float64 `json:"averageRating"`
+	ReviewCount   int64   `json:"reviewCount"`
+	Files         struct {
+		Download string `json:"download"`
+	} `json:"files"`
+}
+
+// Search queries the OpenVSX registry with sorting, paging and a category filter. OpenVSX
+// search does not support tags, and VerifiedOnly is applied to each page, so Total stays
+// the unfiltered count (marked by TotalUnfiltered).
+func (c *Client) Search(opts models.SearchOptions) (*models.SearchResults, error) {
+	log.Printf("[OpenVSX] Searching for extensions matching: %q (sort %s, page %d)", opts.Query, opts.SortBy, opts.Page)
+
+	var sortBy string
+	switch opts.SortBy {
+	case "", models.SortRelevance:
+		sortBy = "relevance"
+	case models.SortInstalls:
+		sortBy = "downloadCount"
+	case models.SortRating:
+		sortBy = "rating"
+	case models.SortUpdated:
+		sortBy = "timestamp"
+	default:
+		return nil, fmt.Errorf("unknown sort order %q (expected installs, rating, updated or relevance)", opts.SortBy)
+	}
+	page := opts.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := opts.PageSize
+	if pageSize < 1 {
+		pageSize = models.DefaultSearchPageSize
+	}
+
+	params := url.Values{}
+	params.Set("query", opts.Query)
+	params.Set("sortBy", sortBy)
+	params.Set("sortOrder", "desc")
+	params.Set("offset", strconv.Itoa((page-1)*pageSize))
+	params.Set("size", strconv.Itoa(pageSize))
+	if opts.Category != "" {
+		params.Set("category", opts.Category)
+	}
+
+	req, err := http.NewRequest("GET", c.baseURL+"/-/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		log.Printf("[OpenVSX] Search request failed for %q: %v", opts.Query, err)
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("OpenVSX API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var apiResp struct {
+		TotalSize  int           `json:"totalSize"`
+		Extensions []searchEntry `json:"extensions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	results := &models.SearchResults{
+		Extensions: []*models.ExtensionMetadata{},
+		Total:      apiResp.TotalSize,
+		Page:       page,
+		PageSize:   pageSize,
+
+		TotalUnfiltered: opts.VerifiedOnly,
+	}
+	for _, ext := range apiResp.Extensions {
+		if opts.VerifiedOnly && !ext.Verified {
+			continue
+		}
+		lastUpdated, _ := time.Parse(time.RFC3339, ext.Timestamp)
+		results.Extensions = append(results.Extensions, &models.ExtensionMetadata{
+			ID:                  fmt.Sprintf("%s.%s", ext.Namespace, ext.Name),
+			Publisher:           ext.Namespace,
+			IsVerifiedPublisher: ext.Verified,
+			Name:                ext.Name,
+			Version:             ext.Version,
+			DisplayName:         ext.DisplayName,
+			Description:         ext.Description,
+			DownloadURL:         ext.Files.Download,
+			LastUpdated:         lastUpdated,
+			InstallCount:        ext.DownloadCount,
+			AverageRating:       ext.AverageRating,
+			RatingCount:         ext.ReviewCount,
+			Source:              "openvsx",
+		})
+	}
+
+	log.Printf("[OpenVSX] Found %d extensions matching %q (%d total)", len(results.Extensions), opts.Query, results.Total)
+	return results, nil
+}
+
+// versionsPageSize is the number of versions requested per page when listing versions
+const versionsPageSize = 100
+
+// ListVersions returns the published versions of an extension, newest first. OpenVSX does
+// not report target platforms here, so TargetPlatforms is left empty.
+func (c *Client) ListVersions(extensionID string) ([]models.ExtensionVersion, error) {
+	log.Printf("[OpenVSX] Listing versions for extension: %s", extensionID)
+	publisher, name, err := parseExtensionID(extensionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []models.ExtensionVersion
+	for offset := 0; ; {
+		url := fmt.Sprintf("%s/%s/%s/versions?offset=%d&size=%d", c.baseURL, publisher, name, offset, versionsPageSize)
+		page, total, err := c.fetchVersionsPage(url)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range page {
+			versions = append(versions, models.ExtensionVersion{Version: v})
+		}
+		offset += len(page)
+		if len(page) == 0 || offset >= total {
+			break
+		}
+	}
+
+	log.Printf("[OpenVSX] Found %d versions of %s", len(versions), extensionID)
+	return versions, nil
+}
+
+// fetchVersionsPage fetches one page of the versions endpoint. The versions object maps
+// version to URL; its keys are decoded in order because the registry sorts them newest first.
+func (c *Client) fetchVersionsPage(url string) ([]string, int, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, 0, fmt.Errorf("extension not found in OpenVSX")
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, 0, fmt.Errorf("OpenVSX API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var page struct {
+		TotalSize int             `json:"totalSize"`
+		Versions  json.RawMessage `json:"versions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	var versions []string
+	if len(page.Versions) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(page.Versions))
+		if _, err := dec.Token(); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode versions: %w", err)
+		}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to decode versions: %w", err)
+			}
+			var link string
+			if err := dec.Decode(&link); err != nil {
+				return nil, 0, fmt.Errorf("failed to decode versions: %w", err)
+			}
+			if version, ok := key.(string); ok {
+				versions = append(versions, version)
+			}
+		}
+	}
+	return versions, page.TotalSize, nil
 }
 
 // DownloadExtension downloads the VSIX package from OpenVSX
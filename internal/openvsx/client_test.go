@@ -1,7 +1,15 @@
 package openvsx
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
 )
 
 func TestNewClient(t *testing.T) {
@@ -124,3 +132,177 @@ func TestDownloadExtensionEmptyURL(t *testing.T) {
 		t.Errorf("Error message = %s, want %s", err.Error(), expectedMsg)
 	}
 }
+
+func TestListVersionsKeepsRegistryOrder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pub/ext/versions" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("offset") == "0" {
+			fmt.Fprint(w, `{"offset":0,"totalSize":3,"versions":{"2.0.0":"u","1.10.0":"u"}}`)
+		} else {
+			fmt.Fprint(w, `{"offset":2,"totalSize":3,"versions":{"1.9.0":"u"}}`)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	client.baseURL = srv.URL
+
+	versions, err := client.ListVersions("pub.ext")
+	if err != nil {
+		t.Fatalf("ListVersions() error: %v", err)
+	}
+	var got []string
+	for _, v := range versions {
+		got = append(got, v.Version)
+	}
+	if strings.Join(got, ",") != "2.0.0,1.10.0,1.9.0" {
+		t.Errorf("versions = %v, want [2.0.0 1.10.0 1.9.0]", got)
+	}
+
+	if _, err := client.ListVersions("pub.missing"); err == nil {
+		t.Error("expected error for unknown extension")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/-/search" {
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.Query()
+		fmt.Fprint(w, `{"offset": 10, "totalSize": 42, "extensions": [
+			{"namespace": "redhat", "name": "java", "version": "1.30.0", "verified": true, "downloadCount": 5000, "averageRating": 4.2, "reviewCount": 12,
+			 "files": {"download": "https://open-vsx.org/redhat.java-1.30.0.vsix"}},
+			{"namespace": "someone", "name": "java-snippets", "version": "0.1.0"}
+		]}`)
+	}))
+	defer srv.Close()
+
+	client := NewClientWithBaseURL(srv.URL)
+	results, err := client.Search(models.SearchOptions{Query: "java", SortBy: models.SortInstalls, Page: 2, PageSize: 10, VerifiedOnly: true})
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+
+	if query.Get("query") != "java" || query.Get("sortBy") != "downloadCount" || query.Get("offset") != "10" || query.Get("size") != "10" {
+		t.Errorf("query parameters = %v", query)
+	}
+	if results.Total != 42 || len(results.Extensions) != 1 {
+		t.Fatalf("results = %+v", results)
+	}
+	ext := results.Extensions[0]
+	if ext.ID != "redhat.java" || ext.InstallCount != 5000 || ext.RatingCount != 12 || ext.Source != "openvsx" {
+		t.Errorf("extension = %+v", ext)
+	}
+}
+
+func TestDetails(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pub/ext":
+			fmt.Fprintf(w, `{"namespace": "pub", "name": "ext", "version": "2.0.0", "license": "Apache-2.0",
+				"repository": "https://github.com/pub/ext", "timestamp": "2024-02-01T00:00:00Z",
+				"files": {"readme": "%[1]s/readme", "changelog": "%[1]s/changelog", "license": "%[1]s/license"},
+				"allTargetPlatformVersions": [
+					{"version": "2.0.0", "targetPlatforms": ["universal"]},
+					{"version": "1.0.0", "targetPlatforms": ["linux-x64", "win32-x64"]}]}`, srv.URL)
+		case "/pub/ext/2.0.0":
+			fmt.Fprint(w, `{"version": "2.0.0", "timestamp": "2024-02-01T00:00:00Z", "preRelease": true, "engines": {"vscode": "^1.85.0"}}`)
+		case "/pub/ext/linux-x64/1.0.0":
+			fmt.Fprint(w, `{"version": "1.0.0", "timestamp": "2024-01-01T00:00:00Z", "engines": {"vscode": "^1.80.0"}}`)
+		case "/readme":
+			fmt.Fprint(w, "# Ext")
+		case "/changelog":
+			fmt.Fprint(w, "## 2.0.0")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	client.baseURL = srv.URL
+
+	details, err := client.Details("pub.ext", 0)
+	if err != nil {
+		t.Fatalf("Details() error: %v", err)
+	}
+	if details.Metadata.RepositoryURL != "https://github.com/pub/ext" || details.License != "Apache-2.0" || details.TotalVersions != 2 {
+		t.Fatalf("details = %+v", details)
+	}
+	v2, v1 := details.Versions[0], details.Versions[1]
+	if !v2.PreRelease || v2.EngineVSCode != "^1.85.0" || len(v2.TargetPlatforms) != 0 || v2.LastUpdated.IsZero() {
+		t.Errorf("2.0.0 = %+v", v2)
+	}
+	if v1.PreRelease || v1.EngineVSCode != "^1.80.0" || strings.Join(v1.TargetPlatforms, ",") != "linux-x64,win32-x64" {
+		t.Errorf("1.0.0 = %+v", v1)
+	}
+	if details.Readme != "# Ext" || details.Changelog != "## 2.0.0" {
+		t.Errorf("readme/changelog = %q/%q", details.Readme, details.Changelog)
+	}
+}
+
+func TestDetailsCapsVersionRequests(t *testing.T) {
+	const published = maxDetailedVersions + 15
+	var mu sync.Mutex
+	versionRequests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pub/ext" {
+			var list []string
+			for i := published; i > 0; i-- {
+				list = append(list, fmt.Sprintf(`{"version": "1.%d.0", "targetPlatforms": ["universal"]}`, i))
+			}
+			fmt.Fprintf(w, `{"namespace": "pub", "name": "ext", "version": "1.%d.0", "allTargetPlatformVersions": [%s]}`,
+				published, strings.Join(list, ","))
+			return
+		}
+		mu.Lock()
+		versionRequests++
+		mu.Unlock()
+		fmt.Fprint(w, `{"version": "1.0.0", "timestamp": "2024-01-01T00:00:00Z"}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	client.baseURL = srv.URL
+
+	details, err := client.Details("pub.ext", 0)
+	if err != nil {
+		t.Fatalf("Details() error: %v", err)
+	}
+	if len(details.Versions) != published || details.TotalVersions != published {
+		t.Errorf("listed %d of %d versions, want %d", len(details.Versions), details.TotalVersions, published)
+	}
+	if versionRequests != maxDetailedVersions {
+		t.Errorf("made %d version requests, want %d", versionRequests, maxDetailedVersions)
+	}
+	if details.Versions[maxDetailedVersions-1].LastUpdated.IsZero() || !details.Versions[maxDetailedVersions].LastUpdated.IsZero() {
+		t.Error("only the newest versions should be filled in")
+	}
+}
+
+func TestFetchPlatformMetadataEscapesSegments(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		fmt.Fprint(w, `{"namespace": "pub", "name": "ext", "version": "1.0.0"}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	client.baseURL = srv.URL
+
+	if _, err := client.FetchPlatformMetadata("pub.ext", "1.0.0/../../x", "linux-x64?a=b"); err != nil {
+		t.Fatalf("FetchPlatformMetadata() error: %v", err)
+	}
+	want := "/pub/ext/linux-x64%3Fa=b/1.0.0%2F..%2F..%2Fx"
+	if len(paths) != 1 || paths[0] != want {
+		t.Errorf("requested %v, want [%s]", paths, want)
+	}
+}
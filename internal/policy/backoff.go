@@ -0,0 +1,43 @@
+package policy
+
+import "time"
+
+// MinBackoff is the wait after the first failed enforcement attempt
+const MinBackoff = 30 * time.Second
+
+// Backoff spaces out enforcement attempts after failures, so that a policy that cannot
+// be fetched or applied is not retried on every trigger. Each consecutive failure doubles
+// the wait, from MinBackoff up to a maximum.
+type Backoff struct {
+	max      time.Duration
+	failures int
+	next     time.Time
+}
+
+// NewBackoff creates a backoff that waits at most max between failed attempts
+func NewBackoff(max time.Duration) *Backoff {
+	if max < MinBackoff {
+		max = MinBackoff
+	}
+	return &Backoff{max: max}
+}
+
+// Ready reports whether an attempt may run at now
+func (b *Backoff) Ready(now time.Time) bool {
+	return !now.Before(b.next)
+}
+
+// Record notes the outcome of an attempt made at now
+func (b *Backoff) Record(failed bool, now time.Time) {
+	if !failed {
+		b.failures = 0
+		b.next = time.Time{}
+		return
+	}
+	b.failures++
+	wait := b.max
+	if shift := b.failures - 1; shift < 32 && MinBackoff<<shift < b.max {
+		wait = MinBackoff << shift
+	}
+	b.next = now.Add(wait)
+}
@@ -0,0 +1,30 @@
+package policy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	now := time.Now()
+	b := NewBackoff(2 * time.Minute)
+	if !b.Ready(now) {
+		t.Fatal("A new backoff should be ready")
+	}
+
+	waits := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 2 * time.Minute}
+	for i, wait := range waits {
+		b.Record(true, now)
+		if b.Ready(now.Add(wait - time.Second)) {
+			t.Errorf("failure %d: ready before %s", i+1, wait)
+		}
+		if !b.Ready(now.Add(wait)) {
+			t.Errorf("failure %d: not ready after %s", i+1, wait)
+		}
+	}
+
+	b.Record(false, now)
+	if !b.Ready(now) {
+		t.Error("A success should reset the backoff")
+	}
+}
@@ -0,0 +1,396 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// AuditLogFile is the config file that records every enforcement action as a JSON line
+const AuditLogFile = "enforce.log"
+
+var (
+	extensionIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*\.[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	publisherPattern   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*\.\*$`)
+	sha256Pattern      = regexp.MustCompile(`^[a-fA-F0-9]{64}$`)
+)
+
+// Fetcher validates and downloads extension packages for Enforce; an empty version means
+// the latest. validation.Validator implements it.
+type Fetcher interface {
+	ValidateExtension(extensionID string) (*models.ValidationResult, error)
+	DownloadExtensionVersion(extensionID, version, source string) ([]byte, string, error)
+}
+
+// Parse decodes and validates a policy document
+func Parse(data []byte) (*models.Policy, error) {
+	var policy models.Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+	if err := Validate(&policy); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// Validate checks a policy for malformed or contradictory entries
+func Validate(policy *models.Policy) error {
+	if policy.Version != models.PolicyVersion {
+		return fmt.Errorf("unsupported policy version %d (expected %d)", policy.Version, models.PolicyVersion)
+	}
+
+	for _, rule := range policy.Blocked {
+		if !extensionIDPattern.MatchString(rule.ID) && !publisherPattern.MatchString(rule.ID) {
+			return fmt.Errorf("blocked: invalid extension ID %q", rule.ID)
+		}
+	}
+
+	seen := map[string]string{}
+	check := func(list string, ext models.PolicyExtension) error {
+		if !extensionIDPattern.MatchString(ext.ID) {
+			return fmt.Errorf("%s: invalid extension ID %q", list, ext.ID)
+		}
+		id := strings.ToLower(ext.ID)
+		if other, ok := seen[id]; ok {
+			return fmt.Errorf("%s is listed in both %s and %s", ext.ID, other, list)
+		}
+		seen[id] = list
+		if rule := blockingRule(policy, ext.ID); rule != nil {
+			return fmt.Errorf("%s is %s but blocked by %q", ext.ID, list, rule.ID)
+		}
+		if ext.SHA256 != "" && !sha256Pattern.MatchString(ext.SHA256) {
+			return fmt.Errorf("%s: sha256 must be 64 hex characters", ext.ID)
+		}
+		if ext.SHA256 != "" && ext.Version == "" {
+			return fmt.Errorf("%s: sha256 requires a version", ext.ID)
+		}
+		switch ext.Source {
+		case "", "marketplace", "openvsx":
+		default:
+			return fmt.Errorf("%s: unknown source %q (expected marketplace or openvsx)", ext.ID, ext.Source)
+		}
+		return nil
+	}
+
+	for _, ext := range policy.Mandatory {
+		if err := check("mandatory", ext); err != nil {
+			return err
+		}
+	}
+	for _, ext := range policy.Pinned {
+		if err := check("pinned", ext); err != nil {
+			return err
+		}
+		if ext.Version == "" {
+			return fmt.Errorf("%s: pinned extensions need a version", ext.ID)
+		}
+	}
+	return nil
+}
+
+// blockingRule returns the rule that blocks an extension, or nil
+func blockingRule(policy *models.Policy, extensionID string) *models.PolicyRule {
+	id := strings.ToLower(extensionID)
+	for i, rule := range policy.Blocked {
+		pattern := strings.ToLower(rule.ID)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(id, prefix) {
+				return &policy.Blocked[i]
+			}
+		} else if id == pattern {
+			return &policy.Blocked[i]
+		}
+	}
+	return nil
+}
+
+// TargetProfiles returns the editors a policy applies to on this machine: the listed
+// editors, or every known editor, limited to those with an extensions directory
+func TargetProfiles(policy *models.Policy) []models.EditorProfile {
+	var candidates []models.EditorProfile
+	if len(policy.Editors) == 0 {
+		candidates = editor.GetEditorProfiles()
+	} else {
+		for _, editorID := range policy.Editors {
+			if profile, err := editor.GetEditorProfile(editorID); err == nil {
+				candidates = append(candidates, profile)
+			}
+		}
+	}
+
+	var profiles []models.EditorProfile
+	for _, profile := range candidates {
+		if info, err := os.Stat(profile.ExtensionsDir); err == nil && info.IsDir() {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// InstalledVersions reads the installed extension versions of each profile
+func InstalledVersions(profiles []models.EditorProfile) (map[models.EditorType]map[string]string, error) {
+	installed := map[models.EditorType]map[string]string{}
+	for _, profile := range profiles {
+		versions, err := editor.InstalledVersions(profile.ExtensionsDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s extensions: %w", profile.ID, err)
+		}
+		installed[profile.ID] = versions
+	}
+	return installed, nil
+}
+
+// Plan compares the policy with the installed versions (editor -> lowercase ID -> version)
+// of the given editors. Blocked extensions are uninstalled, missing mandatory extensions
+// installed, and mandatory or pinned extensions at the wrong version reinstalled at the
+// pinned version.
+func Plan(policy *models.Policy, editors []models.EditorType, installed map[models.EditorType]map[string]string) *models.EnforcementPlan {
+	plan := &models.EnforcementPlan{
+		Policy:  policy.Name,
+		Editors: editors,
+		Steps:   []models.EnforcementStep{},
+	}
+
+	for _, editorID := range editors {
+		versions := installed[editorID]
+
+		for _, id := range sortedIDs(versions) {
+			if rule := blockingRule(policy, id); rule != nil {
+				reason := fmt.Sprintf("blocked by %s", rule.ID)
+				if rule.Reason != "" {
+					reason += ": " + rule.Reason
+				}
+				plan.Steps = append(plan.Steps, models.EnforcementStep{
+					Editor:         editorID,
+					ExtensionID:    id,
+					Action:         models.EnforcementUninstall,
+					CurrentVersion: versions[id],
+					Reason:         reason,
+				})
+			}
+		}
+
+		for _, ext := range policy.Mandatory {
+			current, ok := versions[strings.ToLower(ext.ID)]
+			switch {
+			case !ok:
+				plan.Steps = append(plan.Steps, enforcementStep(editorID, ext, models.EnforcementInstall, "", "mandatory"))
+			case ext.Version != "" && current != ext.Version:
+				plan.Steps = append(plan.Steps, enforcementStep(editorID, ext, models.EnforcementPin, current, "mandatory at "+ext.Version))
+			default:
+				plan.Compliant++
+			}
+		}
+
+		for _, ext := range policy.Pinned {
+			current, ok := versions[strings.ToLower(ext.ID)]
+			if !ok {
+				continue
+			}
+			if current != ext.Version {
+				plan.Steps = append(plan.Steps, enforcementStep(editorID, ext, models.EnforcementPin, current, "pinned at "+ext.Version))
+			} else {
+				plan.Compliant++
+			}
+		}
+	}
+
+	return plan
+}
+
+// PlanStatus plans enforcement of a fetched policy across the editors detected on this machine
+func PlanStatus(status *models.PolicyStatus) (*models.EnforcementPlan, error) {
+	profiles := TargetProfiles(status.Policy)
+	installed, err := InstalledVersions(profiles)
+	if err != nil {
+		return nil, err
+	}
+	editors := make([]models.EditorType, 0, len(profiles))
+	for _, profile := range profiles {
+		editors = append(editors, profile.ID)
+	}
+	plan := Plan(status.Policy, editors, installed)
+	plan.PolicySHA = status.SHA256
+	return plan, nil
+}
+
+// enforcementStep builds an install or pin step for a policy extension
+func enforcementStep(editorID models.EditorType, ext models.PolicyExtension, action models.EnforcementAction, current, reason string) models.EnforcementStep {
+	return models.EnforcementStep{
+		Editor:         editorID,
+		ExtensionID:    ext.ID,
+		Action:         action,
+		CurrentVersion: current,
+		TargetVersion:  ext.Version,
+		SHA256:         strings.ToLower(ext.SHA256),
+		Source:         ext.Source,
+		Reason:         reason,
+	}
+}
+
+// Enforce carries out a plan. Extensions are validated and Malicious ones refused, and
+// packages are downloaded once per version and verified before they are installed.
+// When logPath is set, every result is appended to it as a JSON line as soon as the
+// action completes.
+func Enforce(plan *models.EnforcementPlan, fetcher Fetcher, logPath string) *models.EnforcementReport {
+	report := &models.EnforcementReport{Plan: plan, Results: []models.EnforcementResult{}}
+
+	type download struct {
+		data    []byte
+		hash    string
+		version string
+		trust   models.TrustLevel
+		err     error
+	}
+	downloads := map[string]*download{}
+
+	for _, step := range plan.Steps {
+		result := models.EnforcementResult{Policy: plan.Policy, PolicySHA: plan.PolicySHA, Step: step}
+
+		profile, err := editor.GetEditorProfile(step.Editor)
+		if err == nil {
+			if step.Action == models.EnforcementUninstall {
+				_, err = editor.UninstallExtension(profile, step.ExtensionID)
+			} else {
+				key := strings.ToLower(step.ExtensionID) + "@" + step.TargetVersion + "@" + step.Source
+				d, ok := downloads[key]
+				if !ok {
+					d = &download{}
+					d.trust, d.err = validateStep(fetcher, step)
+					if d.err == nil {
+						d.data, d.hash, d.version, d.err = fetchVerified(fetcher, step)
+					}
+					downloads[key] = d
+				}
+				err = d.err
+				result.TrustLevel = d.trust
+				result.SHA256 = d.hash
+				result.Version = d.version
+				if err == nil {
+					_, err = editor.InstallVSIX(profile, d.data, "gallery", step.ExtensionID)
+				}
+			}
+		}
+
+		result.Time = time.Now()
+		if err != nil {
+			result.Error = err.Error()
+			report.Failed++
+		} else {
+			result.Success = true
+			report.Succeeded++
+		}
+		report.Results = append(report.Results, result)
+
+		if logPath != "" {
+			if err := appendAuditLog(logPath, result); err != nil {
+				report.Results[len(report.Results)-1].Error += fmt.Sprintf(" (audit log: %v)", err)
+			}
+		}
+	}
+
+	return report
+}
+
+// validateStep validates the extension of an install or pin step, refusing Malicious ones
+func validateStep(fetcher Fetcher, step models.EnforcementStep) (models.TrustLevel, error) {
+	validation, err := fetcher.ValidateExtension(step.ExtensionID)
+	if err != nil {
+		return "", fmt.Errorf("validation failed: %w", err)
+	}
+	if validation.TrustLevel == models.TrustLevelMalicious {
+		return validation.TrustLevel, fmt.Errorf("refusing to install a Malicious extension: %s", validation.Recommendation)
+	}
+	return validation.TrustLevel, nil
+}
+
+// fetchVerified downloads the package for a step and checks its identity, version and hash
+func fetchVerified(fetcher Fetcher, step models.EnforcementStep) ([]byte, string, string, error) {
+	data, hash, err := fetcher.DownloadExtensionVersion(step.ExtensionID, step.TargetVersion, step.Source)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if step.SHA256 != "" && !strings.EqualFold(hash, step.SHA256) {
+		return nil, hash, "", fmt.Errorf("SHA256 mismatch: policy has %s, downloaded package is %s", step.SHA256, hash)
+	}
+
+	pkg, err := editor.ReadVSIX(data)
+	if err != nil {
+		return nil, hash, "", err
+	}
+	if !strings.EqualFold(pkg.ID(), step.ExtensionID) {
+		return nil, hash, "", fmt.Errorf("downloaded package is %s, expected %s", pkg.ID(), step.ExtensionID)
+	}
+	if step.TargetVersion != "" && pkg.Version != step.TargetVersion {
+		return nil, hash, "", fmt.Errorf("downloaded package is version %s, policy requires %s", pkg.Version, step.TargetVersion)
+	}
+	return data, hash, pkg.Version, nil
+}
+
+// AuditLogPath returns the default enforcement audit log path
+func AuditLogPath() (string, error) {
+	return config.Path(AuditLogFile)
+}
+
+// appendAuditLog appends one enforcement result to the audit log
+func appendAuditLog(path string, result models.EnforcementResult) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// ReadAuditLog returns the enforcement results recorded in the audit log, oldest first
+func ReadAuditLog(path string) ([]models.EnforcementResult, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return []models.EnforcementResult{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	results := []models.EnforcementResult{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var result models.EnforcementResult
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// sortedIDs returns the keys of a version map in order
+func sortedIDs(versions map[string]string) []string {
+	ids := make([]string, 0, len(versions))
+	for id := range versions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
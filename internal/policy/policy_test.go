@@ -0,0 +1,235 @@
+package policy
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// buildTestVSIX creates an in-memory VSIX archive for an extension version
+func buildTestVSIX(t *testing.T, id, version string) []byte {
+	t.Helper()
+	publisher, name, _ := strings.Cut(id, ".")
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string]string{
+		"extension.vsixmanifest": fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<PackageManifest Version="2.0.0" xmlns="http://schemas.microsoft.com/developer/vsx-schema/2011">
+  <Metadata>
+    <Identity Language="en-US" Id="%s" Version="%s" Publisher="%s" />
+  </Metadata>
+</PackageManifest>`, name, version, publisher),
+		"extension/package.json": fmt.Sprintf(`{"publisher": %q, "name": %q, "version": %q}`, publisher, name, version),
+	}
+	for fileName, content := range files {
+		f, _ := w.Create(fileName)
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// fakeFetcher builds packages on demand; latest is the version served for an empty version
+type fakeFetcher struct {
+	t         *testing.T
+	latest    string
+	calls     int
+	malicious map[string]bool // extensions classified as Malicious
+}
+
+func (f *fakeFetcher) ValidateExtension(extensionID string) (*models.ValidationResult, error) {
+	trust := models.TrustLevelLegitimate
+	if f.malicious[extensionID] {
+		trust = models.TrustLevelMalicious
+	}
+	return &models.ValidationResult{ExtensionID: extensionID, TrustLevel: trust}, nil
+}
+
+func (f *fakeFetcher) DownloadExtensionVersion(extensionID, version, source string) ([]byte, string, error) {
+	f.calls++
+	if version == "" {
+		version = f.latest
+	}
+	data := buildTestVSIX(f.t, extensionID, version)
+	sum := sha256.Sum256(data)
+	return data, hex.EncodeToString(sum[:]), nil
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy models.Policy
+		valid  bool
+	}{
+		{"Valid", models.Policy{Version: 1,
+			Blocked:   []models.PolicyRule{{ID: "evil.*"}, {ID: "bad.ext"}},
+			Mandatory: []models.PolicyExtension{{ID: "corp.linter"}},
+			Pinned:    []models.PolicyExtension{{ID: "corp.theme", Version: "1.0.0"}},
+		}, true},
+		{"Wrong version", models.Policy{Version: 2}, false},
+		{"Invalid blocked ID", models.Policy{Version: 1, Blocked: []models.PolicyRule{{ID: "*"}}}, false},
+		{"Mandatory but blocked", models.Policy{Version: 1,
+			Blocked:   []models.PolicyRule{{ID: "corp.*"}},
+			Mandatory: []models.PolicyExtension{{ID: "corp.linter"}},
+		}, false},
+		{"Pinned without version", models.Policy{Version: 1, Pinned: []models.PolicyExtension{{ID: "corp.theme"}}}, false},
+		{"Listed twice", models.Policy{Version: 1,
+			Mandatory: []models.PolicyExtension{{ID: "corp.linter"}},
+			Pinned:    []models.PolicyExtension{{ID: "Corp.Linter", Version: "1.0.0"}},
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.policy)
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid policy, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	policy := &models.Policy{
+		Version:   1,
+		Blocked:   []models.PolicyRule{{ID: "evil.*", Reason: "known malware publisher"}},
+		Mandatory: []models.PolicyExtension{{ID: "corp.linter"}, {ID: "corp.fmt", Version: "2.0.0"}},
+		Pinned:    []models.PolicyExtension{{ID: "corp.theme", Version: "1.0.0"}, {ID: "corp.absent", Version: "1.0.0"}},
+	}
+	installed := map[models.EditorType]map[string]string{
+		models.EditorVSCode: {"evil.miner": "0.1.0", "corp.fmt": "1.0.0", "corp.theme": "1.0.0", "other.ext": "1.0.0"},
+		models.EditorCursor: {"corp.linter": "3.0.0", "corp.fmt": "2.0.0", "corp.theme": "1.1.0"},
+	}
+
+	plan := Plan(policy, []models.EditorType{models.EditorVSCode, models.EditorCursor}, installed)
+
+	got := map[string]models.EnforcementAction{}
+	for _, step := range plan.Steps {
+		got[string(step.Editor)+"/"+step.ExtensionID] = step.Action
+	}
+	want := map[string]models.EnforcementAction{
+		"vscode/evil.miner":  models.EnforcementUninstall,
+		"vscode/corp.linter": models.EnforcementInstall,
+		"vscode/corp.fmt":    models.EnforcementPin,
+		"cursor/corp.theme":  models.EnforcementPin,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Steps = %v, want %v", got, want)
+	}
+	for key, action := range want {
+		if got[key] != action {
+			t.Errorf("%s = %q, want %q", key, got[key], action)
+		}
+	}
+	if plan.Compliant != 3 {
+		t.Errorf("Compliant = %d, want 3", plan.Compliant)
+	}
+}
+
+func TestEnforce(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	extensionsDir := t.TempDir()
+	profile := models.EditorProfile{ID: "policy-test", Name: "Policy Test", ExtensionsDir: extensionsDir}
+	if _, err := editor.AddCustomEditorProfile(profile); err != nil {
+		t.Fatalf("Failed to register editor: %v", err)
+	}
+	for id, version := range map[string]string{"evil.miner": "0.1.0", "corp.theme": "1.1.0"} {
+		if _, err := editor.InstallVSIX(profile, buildTestVSIX(t, id, version), "vsix", ""); err != nil {
+			t.Fatalf("Failed to install %s: %v", id, err)
+		}
+	}
+
+	policy := &models.Policy{
+		Version:   1,
+		Name:      "corp",
+		Editors:   []models.EditorType{"policy-test"},
+		Blocked:   []models.PolicyRule{{ID: "evil.*"}},
+		Mandatory: []models.PolicyExtension{{ID: "corp.linter"}},
+		Pinned:    []models.PolicyExtension{{ID: "corp.theme", Version: "1.0.0"}},
+	}
+	profiles := TargetProfiles(policy)
+	if len(profiles) != 1 {
+		t.Fatalf("Expected 1 target editor, got %d", len(profiles))
+	}
+	installed, err := InstalledVersions(profiles)
+	if err != nil {
+		t.Fatalf("InstalledVersions failed: %v", err)
+	}
+	plan := Plan(policy, []models.EditorType{"policy-test"}, installed)
+
+	logPath := filepath.Join(t.TempDir(), "enforce.log")
+	report := Enforce(plan, &fakeFetcher{t: t, latest: "4.0.0"}, logPath)
+	if report.Failed != 0 || report.Succeeded != 3 {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+
+	versions, _ := editor.InstalledVersions(extensionsDir)
+	want := map[string]string{"corp.linter": "4.0.0", "corp.theme": "1.0.0"}
+	if len(versions) != len(want) {
+		t.Errorf("Installed = %v, want %v", versions, want)
+	}
+	for id, version := range want {
+		if versions[id] != version {
+			t.Errorf("%s = %q, want %q", id, versions[id], version)
+		}
+	}
+
+	entries, err := ReadAuditLog(logPath)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("Audit log = %+v, %v", entries, err)
+	}
+	if entries[0].Policy != "corp" || !entries[0].Success {
+		t.Errorf("Unexpected audit log entry: %+v", entries[0])
+	}
+
+	// A second run has nothing left to do
+	installed, _ = InstalledVersions(profiles)
+	if plan := Plan(policy, []models.EditorType{"policy-test"}, installed); len(plan.Steps) != 0 {
+		t.Errorf("Expected a compliant editor, got %+v", plan.Steps)
+	}
+}
+
+func TestEnforceRejectsWrongHash(t *testing.T) {
+	plan := &models.EnforcementPlan{Steps: []models.EnforcementStep{{
+		Editor:        models.EditorVSCode,
+		ExtensionID:   "corp.linter",
+		Action:        models.EnforcementInstall,
+		TargetVersion: "1.0.0",
+		SHA256:        strings.Repeat("0", 64),
+	}}}
+	report := Enforce(plan, &fakeFetcher{t: t}, "")
+	if report.Failed != 1 || !strings.Contains(report.Results[0].Error, "SHA256 mismatch") {
+		t.Errorf("Expected a SHA256 mismatch, got %+v", report.Results)
+	}
+}
+
+func TestEnforceRefusesMalicious(t *testing.T) {
+	plan := &models.EnforcementPlan{Steps: []models.EnforcementStep{{
+		Editor:      models.EditorVSCode,
+		ExtensionID: "corp.linter",
+		Action:      models.EnforcementInstall,
+	}}}
+	fetcher := &fakeFetcher{t: t, latest: "1.0.0", malicious: map[string]bool{"corp.linter": true}}
+	report := Enforce(plan, fetcher, "")
+	if report.Failed != 1 || report.Results[0].TrustLevel != models.TrustLevelMalicious {
+		t.Errorf("Expected a refused Malicious extension, got %+v", report.Results)
+	}
+	if fetcher.calls != 0 {
+		t.Errorf("Malicious extension was downloaded %d times", fetcher.calls)
+	}
+}
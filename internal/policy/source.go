@@ -0,0 +1,406 @@
+package policy
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+const (
+	// SourceFile is the config file holding the policy source settings
+	SourceFile = "policy-source.json"
+	// DefaultInterval is how long a fetched policy is used before it is refreshed
+	DefaultInterval = time.Hour
+
+	cacheDirName   = "policy"
+	cachePolicy    = "policy.json"
+	cacheSignature = "policy.json.sig"
+	cacheState     = "state.json"
+	maxPolicySize  = 4 << 20
+)
+
+// cachedState records where and when the cached policy was fetched
+type cachedState struct {
+	Source    string    `json:"source"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	SHA256    string    `json:"sha256"`
+}
+
+// LoadSource reads the policy source settings from the config directory
+func LoadSource() (*models.PolicySource, error) {
+	path, err := config.Path(SourceFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no policy source configured; run 'vsynx policy set' first")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy source: %w", err)
+	}
+
+	var source models.PolicySource
+	if err := json.Unmarshal(data, &source); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := ValidateSource(&source); err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+// SaveSource validates and saves the policy source settings
+func SaveSource(source *models.PolicySource) error {
+	if err := ValidateSource(source); err != nil {
+		return err
+	}
+	path, err := config.Path(SourceFile)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(source, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal policy source: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write policy source: %w", err)
+	}
+	return nil
+}
+
+// ValidateSource checks that a source names exactly one location and a usable key
+func ValidateSource(source *models.PolicySource) error {
+	if (source.URL == "") == (source.Git == "") {
+		return fmt.Errorf("policy source needs either a URL or a git repository")
+	}
+	if source.URL != "" && !strings.HasPrefix(source.URL, "https://") && !strings.HasPrefix(source.URL, "http://") {
+		return fmt.Errorf("policy URL must be http(s): %s", source.URL)
+	}
+	if !source.AllowHTTP {
+		for _, location := range []string{source.URL, source.Git} {
+			if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "git://") {
+				return fmt.Errorf("refusing unencrypted policy location %s (allow it explicitly for testing)", location)
+			}
+		}
+	}
+	if source.Git != "" && source.GitFile == "" {
+		return fmt.Errorf("git policy source needs the policy file path inside the repository")
+	}
+	if source.PublicKey == "" && !source.AllowUnsigned {
+		return fmt.Errorf("a public key is required to verify the policy signature")
+	}
+	if source.PublicKey != "" {
+		if _, err := decodePublicKey(source.PublicKey); err != nil {
+			return err
+		}
+	}
+	if _, err := Interval(source); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Interval returns how often the cached policy is refreshed
+func Interval(source *models.PolicySource) (time.Duration, error) {
+	if source.Interval == "" {
+		return DefaultInterval, nil
+	}
+	interval, err := time.ParseDuration(source.Interval)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("invalid policy refresh interval %q", source.Interval)
+	}
+	return interval, nil
+}
+
+// Current returns the policy, refreshing the cache when it is older than the source's
+// interval or when force is set. If the refresh fails, the cached policy is returned
+// with Stale set; without a cache the error is returned.
+func Current(source *models.PolicySource, force bool) (*models.PolicyStatus, error) {
+	cached, cacheErr := Cached(source)
+	if cacheErr == nil && !force {
+		interval, _ := Interval(source)
+		if time.Since(cached.FetchedAt) < interval {
+			return cached, nil
+		}
+	}
+
+	status, err := Fetch(source)
+	if err == nil {
+		return status, nil
+	}
+	if cacheErr != nil {
+		return nil, err
+	}
+	cached.Stale = true
+	cached.Error = err.Error()
+	return cached, nil
+}
+
+// Fetch downloads the policy and its signature, verifies them and updates the cache. A
+// policy with a lower serial than the cached one is refused, so an older signed policy
+// cannot be replayed.
+func Fetch(source *models.PolicySource) (*models.PolicyStatus, error) {
+	if err := ValidateSource(source); err != nil {
+		return nil, err
+	}
+
+	var data, signature []byte
+	var err error
+	if source.URL != "" {
+		data, signature, err = fetchURL(source.URL)
+	} else {
+		data, signature, err = fetchGit(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := verify(source, data, signature)
+	if err != nil {
+		return nil, err
+	}
+	if cached, err := Cached(source); err == nil && status.Policy.Serial < cached.Policy.Serial {
+		return nil, fmt.Errorf("policy serial %d is older than the cached serial %d", status.Policy.Serial, cached.Policy.Serial)
+	}
+	status.FetchedAt = time.Now()
+	if err := writeCache(source, data, signature, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Cached returns the cached policy if it was fetched from source, verifying its signature
+// again with the source's key
+func Cached(source *models.PolicySource) (*models.PolicyStatus, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+	var state cachedState
+	if stateData, err := os.ReadFile(filepath.Join(dir, cacheState)); err == nil {
+		json.Unmarshal(stateData, &state)
+	}
+	if state.Source != sourceKey(source) {
+		return nil, fmt.Errorf("no cached policy for this source")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, cachePolicy))
+	if err != nil {
+		return nil, fmt.Errorf("no cached policy: %w", err)
+	}
+	signature, err := os.ReadFile(filepath.Join(dir, cacheSignature))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read cached signature: %w", err)
+	}
+
+	status, err := verify(source, data, signature)
+	if err != nil {
+		return nil, fmt.Errorf("cached policy: %w", err)
+	}
+	status.FetchedAt = state.FetchedAt
+	return status, nil
+}
+
+// sourceKey identifies where a policy comes from, so a cache from another source is not used
+func sourceKey(source *models.PolicySource) string {
+	if source.URL != "" {
+		return source.URL
+	}
+	return strings.Join([]string{"git", source.Git, source.GitRef, source.GitFile}, "|")
+}
+
+// verify checks a policy signature and parses the policy
+func verify(source *models.PolicySource, data, signature []byte) (*models.PolicyStatus, error) {
+	status := &models.PolicyStatus{Source: *source, SHA256: sha256Hex(data)}
+
+	if source.PublicKey != "" {
+		publicKey, err := decodePublicKey(source.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(signature)) == 0 {
+			return nil, fmt.Errorf("policy is not signed")
+		}
+		sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil || !ed25519.Verify(publicKey, data, sig) {
+			return nil, fmt.Errorf("policy signature verification failed")
+		}
+		status.Signed = true
+	} else if !source.AllowUnsigned {
+		return nil, fmt.Errorf("a public key is required to verify the policy signature")
+	}
+
+	policy, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	status.Policy = policy
+	return status, nil
+}
+
+// fetchURL downloads a policy and its detached signature (<url>.sig)
+func fetchURL(url string) ([]byte, []byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	data, err := httpGet(client, url)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch policy: %w", err)
+	}
+	signature, err := httpGet(client, url+".sig")
+	if err != nil {
+		// A missing signature is reported by verify if one is required
+		signature = nil
+	}
+	return data, signature, nil
+}
+
+// httpGet fetches a URL, failing on non-2xx responses
+func httpGet(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPolicySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxPolicySize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", url, maxPolicySize)
+	}
+	return data, nil
+}
+
+// fetchGit updates a shallow checkout of the policy repository in the cache and reads
+// the policy file and its signature (<file>.sig) from it
+func fetchGit(source *models.PolicySource) ([]byte, []byte, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	checkout := filepath.Join(dir, "git")
+
+	ref := source.GitRef
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if _, err := os.Stat(filepath.Join(checkout, ".git")); os.IsNotExist(err) {
+		os.RemoveAll(checkout)
+		if err := runGit("", "init", "--quiet", checkout); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := runGit(checkout, "fetch", "--quiet", "--depth", "1", "--", source.Git, ref); err != nil {
+		return nil, nil, err
+	}
+	if err := runGit(checkout, "reset", "--quiet", "--hard", "FETCH_HEAD"); err != nil {
+		return nil, nil, err
+	}
+
+	file := filepath.Join(checkout, filepath.FromSlash(source.GitFile))
+	if rel, err := filepath.Rel(checkout, file); err != nil || strings.HasPrefix(rel, "..") {
+		return nil, nil, fmt.Errorf("policy file must be inside the repository: %s", source.GitFile)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read policy from repository: %w", err)
+	}
+	signature, _ := os.ReadFile(file + ".sig")
+	return data, signature, nil
+}
+
+// runGit runs a git command, including its output in the error
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s failed: %v: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// writeCache stores a verified policy, its signature, its source and the fetch time
+func writeCache(source *models.PolicySource, data, signature []byte, status *models.PolicyStatus) error {
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create policy cache: %w", err)
+	}
+
+	state, _ := json.MarshalIndent(cachedState{Source: sourceKey(source), FetchedAt: status.FetchedAt, SHA256: status.SHA256}, "", "  ")
+	files := map[string][]byte{cachePolicy: data, cacheSignature: signature, cacheState: state}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path+".tmp", content, 0644); err != nil {
+			return fmt.Errorf("failed to write policy cache: %w", err)
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return fmt.Errorf("failed to write policy cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// cacheDir returns the directory holding the cached policy
+func cacheDir() (string, error) {
+	return config.Path(cacheDirName)
+}
+
+// GenerateKey creates an ed25519 key pair for signing policies, base64 encoded
+func GenerateKey() (publicKey, privateKey string, err error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(public), base64.StdEncoding.EncodeToString(private.Seed()), nil
+}
+
+// Sign returns the base64 signature of a policy document for a base64 private key
+func Sign(data []byte, privateKey string) (string, error) {
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(privateKey))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return "", fmt.Errorf("invalid private key")
+	}
+	if _, err := Parse(data); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(ed25519.NewKeyFromSeed(seed), data)), nil
+}
+
+// decodePublicKey decodes a base64 ed25519 public key
+func decodePublicKey(value string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid policy public key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// sha256Hex returns the hex SHA256 of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,236 @@
+package policy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+const testPolicy = `{"version": 1, "name": "corp", "blocked": [{"id": "evil.*"}]}`
+
+// signedPolicy returns a public key and the signature of testPolicy
+func signedPolicy(t *testing.T) (string, string) {
+	t.Helper()
+	publicKey, privateKey, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	signature, err := Sign([]byte(testPolicy), privateKey)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	return publicKey, signature
+}
+
+func TestFetchURL(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	publicKey, signature := signedPolicy(t)
+
+	body := testPolicy
+	available := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case "/policy.json":
+			w.Write([]byte(body))
+		case "/policy.json.sig":
+			w.Write([]byte(signature))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	source := &models.PolicySource{URL: ts.URL + "/policy.json", PublicKey: publicKey, AllowHTTP: true}
+	if err := SaveSource(source); err != nil {
+		t.Fatalf("SaveSource failed: %v", err)
+	}
+	if _, err := LoadSource(); err != nil {
+		t.Fatalf("LoadSource failed: %v", err)
+	}
+
+	status, err := Current(source, false)
+	if err != nil {
+		t.Fatalf("Current failed: %v", err)
+	}
+	if !status.Signed || status.Policy.Name != "corp" || status.Stale {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	// A tampered policy is rejected and the verified cache is kept
+	body = `{"version": 1, "name": "corp", "blocked": []}`
+	if _, err := Fetch(source); err == nil {
+		t.Error("Expected tampered policy to fail verification")
+	}
+	cached, err := Cached(source)
+	if err != nil || len(cached.Policy.Blocked) != 1 {
+		t.Errorf("Cache should hold the verified policy: %+v, %v", cached, err)
+	}
+
+	// An unreachable server falls back to the cache
+	available = false
+	status, err = Current(source, true)
+	if err != nil {
+		t.Fatalf("Current should fall back to the cache: %v", err)
+	}
+	if !status.Stale || status.Error == "" {
+		t.Errorf("Expected a stale status, got %+v", status)
+	}
+
+	// A cache signed with another key is not trusted
+	otherKey, _, _ := GenerateKey()
+	if _, err := Cached(&models.PolicySource{URL: source.URL, PublicKey: otherKey}); err == nil {
+		t.Error("Expected cached policy to fail verification with another key")
+	}
+
+	// A cache from another source is not used for this one
+	if _, err := Cached(&models.PolicySource{URL: ts.URL + "/other.json", PublicKey: publicKey}); err == nil {
+		t.Error("Expected no cached policy for a different source")
+	}
+}
+
+func TestFetchUnsigned(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	publicKey, _ := signedPolicy(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/policy.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testPolicy))
+	}))
+	defer ts.Close()
+
+	if _, err := Fetch(&models.PolicySource{URL: ts.URL + "/policy.json", PublicKey: publicKey, AllowHTTP: true}); err == nil {
+		t.Error("Expected unsigned policy to be rejected")
+	}
+	status, err := Fetch(&models.PolicySource{URL: ts.URL + "/policy.json", AllowUnsigned: true, AllowHTTP: true})
+	if err != nil || status.Signed {
+		t.Errorf("Expected unsigned policy to be accepted with AllowUnsigned: %+v, %v", status, err)
+	}
+}
+
+func TestFetchRefusesRollback(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	publicKey, privateKey, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	sign := func(body string) string {
+		signature, err := Sign([]byte(body), privateKey)
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		return signature
+	}
+	older := `{"version": 1, "serial": 1, "name": "corp"}`
+	newer := `{"version": 1, "serial": 2, "name": "corp", "blocked": [{"id": "evil.*"}]}`
+
+	body, signature := newer, sign(newer)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/policy.json":
+			w.Write([]byte(body))
+		case "/policy.json.sig":
+			w.Write([]byte(signature))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	source := &models.PolicySource{URL: ts.URL + "/policy.json", PublicKey: publicKey, AllowHTTP: true}
+	if _, err := Fetch(source); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	// The older policy is validly signed, but replaying it must not replace the cache
+	body, signature = older, sign(older)
+	if _, err := Fetch(source); err == nil {
+		t.Error("Expected a policy with a lower serial to be refused")
+	}
+	status, err := Current(source, true)
+	if err != nil {
+		t.Fatalf("Current failed: %v", err)
+	}
+	if !status.Stale || status.Policy.Serial != 2 {
+		t.Errorf("Expected the cached serial 2 policy, got %+v", status)
+	}
+}
+
+func TestFetchGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	publicKey, signature := signedPolicy(t)
+
+	repo := t.TempDir()
+	files := map[string]string{"vsynx/policy.json": testPolicy, "vsynx/policy.json.sig": signature}
+	for name, content := range files {
+		path := filepath.Join(repo, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "policy"},
+	} {
+		if err := runGit(repo, args...); err != nil {
+			t.Fatalf("Failed to set up repository: %v", err)
+		}
+	}
+
+	source := &models.PolicySource{Git: repo, GitFile: "vsynx/policy.json", PublicKey: publicKey}
+	for i := 0; i < 2; i++ {
+		status, err := Fetch(source)
+		if err != nil {
+			t.Fatalf("Fetch from git failed: %v", err)
+		}
+		if !status.Signed || status.Policy.Name != "corp" {
+			t.Errorf("Unexpected status: %+v", status)
+		}
+	}
+}
+
+func TestValidateSource(t *testing.T) {
+	publicKey, _ := signedPolicy(t)
+	tests := []struct {
+		name   string
+		source models.PolicySource
+		valid  bool
+	}{
+		{"URL", models.PolicySource{URL: "https://example.com/policy.json", PublicKey: publicKey, Interval: "30m"}, true},
+		{"Neither", models.PolicySource{PublicKey: publicKey}, false},
+		{"Both", models.PolicySource{URL: "https://example.com/p.json", Git: "repo", GitFile: "p.json", PublicKey: publicKey}, false},
+		{"Git without file", models.PolicySource{Git: "repo", PublicKey: publicKey}, false},
+		{"No key", models.PolicySource{URL: "https://example.com/policy.json"}, false},
+		{"Bad interval", models.PolicySource{URL: "https://example.com/policy.json", PublicKey: publicKey, Interval: "soon"}, false},
+		{"Plain HTTP", models.PolicySource{URL: "http://example.com/policy.json", PublicKey: publicKey}, false},
+		{"Plain HTTP allowed", models.PolicySource{URL: "http://example.com/policy.json", PublicKey: publicKey, AllowHTTP: true}, true},
+		{"Git protocol", models.PolicySource{Git: "git://example.com/policy", GitFile: "p.json", PublicKey: publicKey}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSource(&tt.source)
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid source, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
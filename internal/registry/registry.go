@@ -0,0 +1,439 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/download"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/mirror"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// defaultSearchSize is the page size of search results when the client does not ask for one
+const defaultSearchSize = 18
+
+// Server serves the packages of a mirror repository through the subset of the OpenVSX
+// REST API that openvsx.Client uses. Only packages classified Legitimate whose files
+// still match the index checksum are served.
+type Server struct {
+	Dir             string
+	AllowSuspicious bool   // also serve packages classified Suspicious
+	BaseURL         string // external URL for links; derived from each request when empty
+
+	mu       sync.Mutex
+	loadedAt time.Time // modification time of the index the catalog was built from
+	packages []servedPackage
+	skipped  []models.MirrorFailure
+}
+
+// servedPackage is a verified package of the repository
+type servedPackage struct {
+	entry models.MirrorEntry
+	pkg   *editor.VSIXPackage
+}
+
+// NewServer creates a registry for the mirror repository in dir and verifies its packages
+func NewServer(dir string, allowSuspicious bool) (*Server, error) {
+	s := &Server{Dir: dir, AllowSuspicious: allowSuspicious}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Served returns the number of packages being served
+func (s *Server) Served() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.packages)
+}
+
+// Skipped returns the packages of the repository that are not served and why
+func (s *Server) Skipped() []models.MirrorFailure {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.MirrorFailure{}, s.skipped...)
+}
+
+// reload rebuilds the catalog when the repository index has changed since it was loaded
+func (s *Server) reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(filepath.Join(s.Dir, mirror.IndexFile))
+	if err != nil {
+		return fmt.Errorf("failed to read mirror index: %w", err)
+	}
+	if !s.loadedAt.IsZero() && info.ModTime().Equal(s.loadedAt) {
+		return nil
+	}
+
+	index, err := mirror.LoadIndex(s.Dir)
+	if err != nil {
+		return err
+	}
+
+	var packages []servedPackage
+	var skipped []models.MirrorFailure
+	skip := func(entry models.MirrorEntry, reason string) {
+		skipped = append(skipped, models.MirrorFailure{
+			ExtensionID:    entry.ExtensionID,
+			Version:        entry.Version,
+			TargetPlatform: entry.TargetPlatform,
+			Error:          reason,
+		})
+	}
+	for _, entry := range index.Entries {
+		if entry.TrustLevel != models.TrustLevelLegitimate &&
+			!(s.AllowSuspicious && entry.TrustLevel == models.TrustLevelSuspicious) {
+			skip(entry, fmt.Sprintf("not validated (trust level %s)", entry.TrustLevel))
+			continue
+		}
+		data, err := mirror.ReadPackage(s.Dir, &entry)
+		if err != nil {
+			skip(entry, err.Error())
+			continue
+		}
+		pkg, err := editor.ReadVSIX(data)
+		if err != nil {
+			skip(entry, err.Error())
+			continue
+		}
+		if !strings.EqualFold(pkg.ID(), entry.ExtensionID) || pkg.Version != entry.Version {
+			skip(entry, fmt.Sprintf("package contains %s %s", pkg.ID(), pkg.Version))
+			continue
+		}
+		packages = append(packages, servedPackage{entry: entry, pkg: pkg})
+	}
+
+	// Newest first, so listings and "latest" lookups can take the first match
+	sort.SliceStable(packages, func(i, j int) bool {
+		return editor.CompareVersions(packages[i].entry.Version, packages[j].entry.Version) > 0
+	})
+
+	s.packages = packages
+	s.skipped = skipped
+	s.loadedAt = info.ModTime()
+	log.Printf("[Registry] Serving %d packages from %s (%d skipped)", len(packages), s.Dir, len(skipped))
+	return nil
+}
+
+// catalog returns the current packages, reloading the index if it changed
+func (s *Server) catalog() []servedPackage {
+	if err := s.reload(); err != nil {
+		log.Printf("[Registry] Keeping previous catalog: %v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.packages
+}
+
+// Handler returns the registry's HTTP API:
+//
+//	GET /api/-/search?query=&offset=&size=              search extensions
+//	GET /api/{namespace}/{name}                         latest version
+//	GET /api/{namespace}/{name}/versions                all versions, newest first
+//	GET /api/{namespace}/{name}/{version}               a version (or latest for a platform)
+//	GET /api/{namespace}/{name}/{platform}/{version}    a version for a target platform
+//	GET .../file/{file}                                 the VSIX package or its .sha256
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/-/search", s.handleSearch)
+	mux.HandleFunc("GET /api/{namespace}/{name}", s.handleExtension)
+	mux.HandleFunc("GET /api/{namespace}/{name}/versions", s.handleVersions)
+	mux.HandleFunc("GET /api/{namespace}/{name}/{segment}", s.handleExtension)
+	mux.HandleFunc("GET /api/{namespace}/{name}/{platform}/{version}", s.handleExtension)
+	mux.HandleFunc("GET /api/{namespace}/{name}/{version}/file/{file}", s.handleFile)
+	mux.HandleFunc("GET /api/{namespace}/{name}/{platform}/{version}/file/{file}", s.handleFile)
+	return mux
+}
+
+// extensionJSON is the OpenVSX representation of an extension version
+type extensionJSON struct {
+	URL            string            `json:"url"`
+	Namespace      string            `json:"namespace"`
+	Name           string            `json:"name"`
+	Version        string            `json:"version"`
+	TargetPlatform string            `json:"targetPlatform"`
+	PreRelease     bool              `json:"preRelease"`
+	DisplayName    string            `json:"displayName,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	Repository     string            `json:"repository,omitempty"`
+	Timestamp      string            `json:"timestamp"`
+	Engines        map[string]string `json:"engines,omitempty"`
+	Files          map[string]string `json:"files"`
+}
+
+// searchResultJSON is the OpenVSX search response
+type searchResultJSON struct {
+	Offset     int             `json:"offset"`
+	TotalSize  int             `json:"totalSize"`
+	Extensions []extensionJSON `json:"extensions"`
+}
+
+func (s *Server) handleExtension(w http.ResponseWriter, r *http.Request) {
+	version, platform := r.PathValue("version"), r.PathValue("platform")
+	if segment := r.PathValue("segment"); segment != "" {
+		// /api/{namespace}/{name}/{segment} is a version or, as on OpenVSX, a target platform
+		if download.ValidatePlatform(segment) == nil {
+			platform = segment
+		} else {
+			version = segment
+		}
+	}
+
+	served := s.find(extensionID(r), version, platform)
+	if served == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Extension not found: %s", extensionID(r)))
+		return
+	}
+	writeJSON(w, s.extensionJSON(r, served))
+}
+
+func (s *Server) handleVersions(w http.ResponseWriter, r *http.Request) {
+	id := extensionID(r)
+	var versions []servedPackage
+	seen := map[string]bool{}
+	for _, served := range s.catalog() {
+		if strings.EqualFold(served.entry.ExtensionID, id) && !seen[served.entry.Version] {
+			seen[served.entry.Version] = true
+			versions = append(versions, served)
+		}
+	}
+	if len(versions) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Extension not found: %s", id))
+		return
+	}
+
+	offset, size := pageParams(r, len(versions))
+	page := versions[min(offset, len(versions)):min(offset+size, len(versions))]
+
+	// Write the versions object by hand so its keys stay newest first
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `{"offset":%d,"totalSize":%d,"versions":{`, offset, len(versions))
+	for i, served := range page {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(served.entry.Version)
+		link, _ := json.Marshal(s.apiURL(r, served.entry, false))
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(link)
+	}
+	buf.WriteString("}}")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buf.Bytes())
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("query")))
+
+	// One result per extension: its latest release (or latest version if all are pre-releases)
+	var results []extensionJSON
+	seen := map[string]bool{}
+	for _, served := range s.catalog() {
+		id := served.entry.ExtensionID
+		if seen[id] || !matches(served, query) {
+			continue
+		}
+		if latest := s.find(id, "", ""); latest != nil {
+			seen[id] = true
+			results = append(results, s.extensionJSON(r, latest))
+		}
+	}
+
+	offset, size := pageParams(r, len(results))
+	if r.URL.Query().Get("size") == "" {
+		size = defaultSearchSize
+	}
+	writeJSON(w, searchResultJSON{
+		Offset:     offset,
+		TotalSize:  len(results),
+		Extensions: append([]extensionJSON{}, results[min(offset, len(results)):min(offset+size, len(results))]...),
+	})
+}
+
+func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
+	platform := r.PathValue("platform")
+	if platform == "" {
+		platform = download.UniversalPlatform
+	}
+	file := r.PathValue("file")
+
+	for _, served := range s.catalog() {
+		entry := served.entry
+		if !strings.EqualFold(entry.ExtensionID, extensionID(r)) || entry.Version != r.PathValue("version") || entry.TargetPlatform != platform {
+			continue
+		}
+		name := filepath.Base(filepath.FromSlash(entry.File))
+		switch file {
+		case name:
+			// Re-check the file against the index on every request; it may have changed on disk
+			data, err := mirror.ReadPackage(s.Dir, &entry)
+			if err != nil {
+				log.Printf("[Registry] Refusing to serve %s: %v", entry.File, err)
+				writeError(w, http.StatusInternalServerError, fmt.Sprintf("Package %s failed verification", name))
+				return
+			}
+			w.Header().Set("Content-Type", "application/octet-stream")
+			http.ServeContent(w, r, name, entry.MirroredAt, bytes.NewReader(data))
+			return
+		case name + download.ChecksumSuffix:
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprintf(w, "%s  %s\n", entry.SHA256, name)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "File not found")
+}
+
+// find returns the package of an extension with the given version (or its latest release,
+// or latest pre-release if it has no release) for platform, falling back to the universal
+// package. An empty platform matches any, preferring universal.
+func (s *Server) find(id, version, platform string) *servedPackage {
+	var candidates []*servedPackage
+	packages := s.catalog()
+	for i := range packages {
+		entry := packages[i].entry
+		if strings.EqualFold(entry.ExtensionID, id) && platformMatches(entry, platform) &&
+			(version == "" || entry.Version == version) {
+			candidates = append(candidates, &packages[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// The catalog is sorted newest first
+	if version == "" {
+		version = candidates[0].entry.Version
+		for _, served := range candidates {
+			if !served.entry.PreRelease {
+				version = served.entry.Version
+				break
+			}
+		}
+	}
+
+	var best *servedPackage
+	for _, served := range candidates {
+		if served.entry.Version != version {
+			continue
+		}
+		if served.entry.TargetPlatform == platform || (platform == "" && served.entry.TargetPlatform == download.UniversalPlatform) {
+			return served
+		}
+		if best == nil {
+			best = served
+		}
+	}
+	return best
+}
+
+// platformMatches reports whether a package can be served for a platform request
+func platformMatches(entry models.MirrorEntry, platform string) bool {
+	return platform == "" || entry.TargetPlatform == platform || entry.TargetPlatform == download.UniversalPlatform
+}
+
+// matches reports whether a package matches a lower-case search query
+func matches(served servedPackage, query string) bool {
+	if query == "" {
+		return true
+	}
+	for _, field := range []string{served.entry.ExtensionID, served.pkg.DisplayName, served.pkg.Description} {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// extensionJSON builds the OpenVSX representation of a package
+func (s *Server) extensionJSON(r *http.Request, served *servedPackage) extensionJSON {
+	entry, pkg := served.entry, served.pkg
+	fileURL := s.apiURL(r, entry, true) + "/file/" + filepath.Base(filepath.FromSlash(entry.File))
+	ext := extensionJSON{
+		URL:            s.apiURL(r, entry, true),
+		Namespace:      pkg.Publisher,
+		Name:           pkg.Name,
+		Version:        entry.Version,
+		TargetPlatform: entry.TargetPlatform,
+		PreRelease:     entry.PreRelease,
+		DisplayName:    pkg.DisplayName,
+		Description:    pkg.Description,
+		Repository:     pkg.Repository,
+		Timestamp:      entry.MirroredAt.UTC().Format(time.RFC3339),
+		Files: map[string]string{
+			"download": fileURL,
+			"sha256":   fileURL + download.ChecksumSuffix,
+		},
+	}
+	if pkg.EngineVSCode != "" {
+		ext.Engines = map[string]string{"vscode": pkg.EngineVSCode}
+	}
+	return ext
+}
+
+// apiURL returns the API URL of a package version; withPlatform adds a non-universal platform
+func (s *Server) apiURL(r *http.Request, entry models.MirrorEntry, withPlatform bool) string {
+	publisher, name, _ := strings.Cut(entry.ExtensionID, ".")
+	url := fmt.Sprintf("%s/api/%s/%s", s.baseURL(r), publisher, name)
+	if withPlatform && entry.TargetPlatform != download.UniversalPlatform {
+		url += "/" + entry.TargetPlatform
+	}
+	return url + "/" + entry.Version
+}
+
+// baseURL returns the external URL of the registry
+func (s *Server) baseURL(r *http.Request) string {
+	if s.BaseURL != "" {
+		return strings.TrimSuffix(s.BaseURL, "/")
+	}
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// extensionID returns the publisher.name of a request path
+func extensionID(r *http.Request) string {
+	return r.PathValue("namespace") + "." + r.PathValue("name")
+}
+
+// pageParams returns the offset and size query parameters, both capped at total so
+// offset+size cannot overflow
+func pageParams(r *http.Request, total int) (int, int) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	size, err := strconv.Atoi(r.URL.Query().Get("size"))
+	if err != nil || size <= 0 {
+		size = total
+	}
+	return min(max(offset, 0), total), min(size, total)
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an OpenVSX-style error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
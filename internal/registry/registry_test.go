@@ -0,0 +1,261 @@
+package registry
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/download"
+	"github.com/yourusername/secureopenvsx/internal/mirror"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/openvsx"
+)
+
+// buildTestVSIX creates an in-memory VSIX archive with the given identity
+func buildTestVSIX(t *testing.T, extensionID, version, platform string) []byte {
+	t.Helper()
+	publisher, name, _ := strings.Cut(extensionID, ".")
+	if platform == download.UniversalPlatform {
+		platform = ""
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string]string{
+		"extension.vsixmanifest": fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<PackageManifest Version="2.0.0" xmlns="http://schemas.microsoft.com/developer/vsx-schema/2011">
+  <Metadata>
+    <Identity Language="en-US" Id="%s" Version="%s" Publisher="%s" TargetPlatform="%s" />
+  </Metadata>
+</PackageManifest>`, name, version, publisher, platform),
+		"extension/package.json": fmt.Sprintf(`{"publisher": %q, "name": %q, "version": %q, "displayName": "Test %s",
+			"description": "A test extension", "repository": {"type": "git", "url": "https://github.com/%s/%s"},
+			"engines": {"vscode": "^1.80.0"}}`, publisher, name, version, name, publisher, name),
+	}
+	for fileName, content := range files {
+		f, err := w.Create(fileName)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// addPackage writes a package into a mirror repository and returns its index entry
+func addPackage(t *testing.T, dir, extensionID, version, platform string, trust models.TrustLevel) models.MirrorEntry {
+	t.Helper()
+	data := buildTestVSIX(t, extensionID, version, platform)
+	info := &models.DownloadInfo{ExtensionID: extensionID, Version: version, TargetPlatform: platform}
+	publisher, name, _ := strings.Cut(extensionID, ".")
+	rel := path.Join(publisher, name, version, download.FileName(info))
+	target := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	return models.MirrorEntry{
+		ExtensionID:    extensionID,
+		Version:        version,
+		TargetPlatform: platform,
+		Source:         "marketplace",
+		File:           rel,
+		Size:           int64(len(data)),
+		SHA256:         hex.EncodeToString(sum[:]),
+		TrustLevel:     trust,
+		MirroredAt:     time.Now(),
+	}
+}
+
+// testRepository creates a mirror repository with validated, suspicious and tampered packages
+func testRepository(t *testing.T) string {
+	dir := t.TempDir()
+	tampered := addPackage(t, dir, "pub.tampered", "1.0.0", "universal", models.TrustLevelLegitimate)
+	tampered.SHA256 = strings.Repeat("0", 64)
+	index := &models.MirrorIndex{Entries: []models.MirrorEntry{
+		addPackage(t, dir, "pub.ext", "1.0.0", "universal", models.TrustLevelLegitimate),
+		addPackage(t, dir, "pub.ext", "2.0.0", "universal", models.TrustLevelLegitimate),
+		addPackage(t, dir, "pub.native", "1.5.0", "linux-x64", models.TrustLevelLegitimate),
+		addPackage(t, dir, "pub.native", "1.5.0", "win32-x64", models.TrustLevelLegitimate),
+		addPackage(t, dir, "pub.shady", "1.0.0", "universal", models.TrustLevelSuspicious),
+		tampered,
+	}}
+	if err := mirror.SaveIndex(dir, index); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestServerWithOpenVSXClient(t *testing.T) {
+	dir := testRepository(t)
+	server, err := NewServer(dir, false)
+	if err != nil {
+		t.Fatalf("NewServer() error: %v", err)
+	}
+	if server.Served() != 4 || len(server.Skipped()) != 2 {
+		t.Errorf("served %d, skipped %v; want 4 served, 2 skipped", server.Served(), server.Skipped())
+	}
+
+	srv := httptest.NewServer(server.Handler())
+	defer srv.Close()
+	client := openvsx.NewClientWithBaseURL(srv.URL + "/api")
+
+	latest, err := client.FetchMetadata("pub.ext")
+	if err != nil {
+		t.Fatalf("FetchMetadata() error: %v", err)
+	}
+	if latest.Version != "2.0.0" || latest.DisplayName != "Test ext" || latest.RepositoryURL != "https://github.com/pub/ext" {
+		t.Errorf("latest = %+v", latest)
+	}
+
+	older, err := client.FetchVersionMetadata("pub.ext", "1.0.0")
+	if err != nil || older.Version != "1.0.0" {
+		t.Fatalf("FetchVersionMetadata() = %+v, %v", older, err)
+	}
+	data, err := client.DownloadExtension(older.DownloadURL)
+	if err != nil {
+		t.Fatalf("DownloadExtension() error: %v", err)
+	}
+	checksum, err := client.DownloadExtension(older.SHA256URL)
+	if err != nil {
+		t.Fatalf("checksum download error: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	if !strings.HasPrefix(string(checksum), hex.EncodeToString(sum[:])) {
+		t.Errorf("checksum %q does not match the package", checksum)
+	}
+
+	native, err := client.FetchPlatformMetadata("pub.native", "", "win32-x64")
+	if err != nil || native.TargetPlatform != "win32-x64" {
+		t.Fatalf("FetchPlatformMetadata() = %+v, %v", native, err)
+	}
+	if _, err := client.DownloadExtension(native.DownloadURL); err != nil {
+		t.Errorf("platform package download error: %v", err)
+	}
+
+	versions, err := client.ListVersions("pub.ext")
+	if err != nil || len(versions) != 2 || versions[0].Version != "2.0.0" {
+		t.Errorf("ListVersions() = %+v, %v", versions, err)
+	}
+
+	for _, id := range []string{"pub.shady", "pub.tampered", "pub.missing"} {
+		if _, err := client.FetchMetadata(id); err == nil {
+			t.Errorf("%s should not be served", id)
+		}
+	}
+}
+
+func TestServerSearch(t *testing.T) {
+	server, err := NewServer(testRepository(t), true)
+	if err != nil {
+		t.Fatalf("NewServer() error: %v", err)
+	}
+	srv := httptest.NewServer(server.Handler())
+	defer srv.Close()
+
+	search := func(query string) searchResultJSON {
+		resp, err := http.Get(srv.URL + "/api/-/search?query=" + query)
+		if err != nil {
+			t.Fatalf("search error: %v", err)
+		}
+		defer resp.Body.Close()
+		var result searchResultJSON
+		json.NewDecoder(resp.Body).Decode(&result)
+		return result
+	}
+
+	all := search("")
+	if all.TotalSize != 3 {
+		t.Errorf("search(\"\") found %d extensions, want 3 (suspicious allowed, tampered never)", all.TotalSize)
+	}
+	native := search("native")
+	if native.TotalSize != 1 || native.Extensions[0].Name != "native" {
+		t.Errorf("search(native) = %+v", native)
+	}
+}
+
+func TestServerReloadsIndex(t *testing.T) {
+	dir := testRepository(t)
+	server, err := NewServer(dir, false)
+	if err != nil {
+		t.Fatalf("NewServer() error: %v", err)
+	}
+
+	index, _ := mirror.LoadIndex(dir)
+	index.Entries = append(index.Entries, addPackage(t, dir, "pub.new", "0.1.0", "universal", models.TrustLevelLegitimate))
+	if err := mirror.SaveIndex(dir, index); err != nil {
+		t.Fatal(err)
+	}
+	// Make sure the modification time differs on coarse-grained filesystems
+	later := time.Now().Add(2 * time.Second)
+	os.Chtimes(filepath.Join(dir, mirror.IndexFile), later, later)
+
+	if server.find("pub.new", "", "") == nil {
+		t.Error("package added to the index should be served after a reload")
+	}
+}
+
+func TestServerRefusesFileChangedAfterLoad(t *testing.T) {
+	dir := testRepository(t)
+	server, err := NewServer(dir, false)
+	if err != nil {
+		t.Fatalf("NewServer() error: %v", err)
+	}
+	srv := httptest.NewServer(server.Handler())
+	defer srv.Close()
+
+	served := server.find("pub.ext", "2.0.0", "")
+	fileURL := srv.URL + "/api/pub/ext/2.0.0/file/" + path.Base(served.entry.File)
+	if resp, err := http.Get(fileURL); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s = %v, %v", fileURL, resp, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(served.entry.File)), []byte("swapped"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Get(fileURL)
+	if err != nil {
+		t.Fatalf("GET error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		t.Error("a package changed on disk after loading should not be served")
+	}
+}
+
+func TestServerPagingWithHugeSize(t *testing.T) {
+	server, err := NewServer(testRepository(t), false)
+	if err != nil {
+		t.Fatalf("NewServer() error: %v", err)
+	}
+	srv := httptest.NewServer(server.Handler())
+	defer srv.Close()
+
+	for _, query := range []string{"/api/-/search?offset=1&size=9223372036854775807", "/api/pub/ext/versions?offset=1&size=9223372036854775807"} {
+		resp, err := http.Get(srv.URL + query)
+		if err != nil {
+			t.Fatalf("GET %s error: %v", query, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s = %d, want 200", query, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package remediation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// ParseFixAction validates an --fix value
+func ParseFixAction(value string) (models.FixAction, error) {
+	action := models.FixAction(strings.ToLower(strings.TrimSpace(value)))
+	switch action {
+	case models.FixActionQuarantine, models.FixActionReplace, models.FixActionUninstall:
+		return action, nil
+	}
+	return "", fmt.Errorf("unknown fix action %q (use quarantine, replace or uninstall)", value)
+}
+
+// FixTargets returns the audit results that need fixing: suspicious and malicious
+// extensions that are not already pending removal
+func FixTargets(report *models.AuditReport) []models.ValidationResult {
+	targets := []models.ValidationResult{}
+	for _, result := range report.Results {
+		if result.TrustLevel != models.TrustLevelSuspicious && result.TrustLevel != models.TrustLevelMalicious {
+			continue
+		}
+		if result.InstallState == models.InstallStatePendingRemoval {
+			continue
+		}
+		targets = append(targets, result)
+	}
+	return targets
+}
+
+// FixAudit applies a fix action to every flagged extension in an audit report, reusing the
+// report's trust levels instead of validating again. confirm is asked before each fix;
+// a nil confirm fixes everything. The downloader is only used by the replace action.
+func FixAudit(profile models.EditorProfile, report *models.AuditReport, action models.FixAction, downloader Downloader, confirm func(models.ValidationResult) bool) *models.AuditFixReport {
+	fixReport := &models.AuditFixReport{
+		Editor:  profile.ID,
+		Action:  action,
+		Results: []models.AuditFixResult{},
+		FixedAt: time.Now(),
+	}
+
+	for _, target := range FixTargets(report) {
+		result := models.AuditFixResult{
+			ExtensionID: target.ExtensionID,
+			TrustLevel:  target.TrustLevel,
+			Action:      action,
+		}
+
+		if confirm != nil && !confirm(target) {
+			result.Status = models.FixStatusSkipped
+			fixReport.SkippedCount++
+			fixReport.Results = append(fixReport.Results, result)
+			continue
+		}
+
+		if err := applyFix(profile, target, action, downloader, &result); err != nil {
+			result.Status = models.FixStatusFailed
+			result.Error = err.Error()
+			fixReport.FailedCount++
+		} else {
+			result.Status = models.FixStatusFixed
+			fixReport.FixedCount++
+		}
+		fixReport.Results = append(fixReport.Results, result)
+	}
+	return fixReport
+}
+
+// applyFix performs one fix action and records its details in result
+func applyFix(profile models.EditorProfile, target models.ValidationResult, action models.FixAction, downloader Downloader, result *models.AuditFixResult) error {
+	reason := fmt.Sprintf("%s in audit", target.TrustLevel)
+
+	switch action {
+	case models.FixActionQuarantine:
+		record, err := editor.QuarantineExtension(profile, target.ExtensionID, reason, target.TrustLevel)
+		if record != nil {
+			result.QuarantineID = record.ID
+		}
+		return err
+	case models.FixActionReplace:
+		remediated, err := Remediate(profile, target.ExtensionID, target.TrustLevel, downloader)
+		if err != nil {
+			return err
+		}
+		result.QuarantineID = remediated.QuarantineID
+		result.OfficialVersion = remediated.OfficialVersion
+		return nil
+	case models.FixActionUninstall:
+		_, err := editor.UninstallExtension(profile, target.ExtensionID)
+		return err
+	}
+	return fmt.Errorf("unknown fix action: %s", action)
+}
@@ -0,0 +1,81 @@
+package remediation
+
+import (
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+func TestParseFixAction(t *testing.T) {
+	if action, err := ParseFixAction("Replace"); err != nil || action != models.FixActionReplace {
+		t.Errorf("ParseFixAction(Replace) = %q, %v", action, err)
+	}
+	if _, err := ParseFixAction("delete"); err == nil {
+		t.Error("Expected error for unknown action")
+	}
+}
+
+func TestFixAudit(t *testing.T) {
+	profile := installSuspect(t)
+	for _, id := range []string{"bad.one", "bad.two", "good.ext"} {
+		if _, err := editor.InstallVSIX(profile, buildTestVSIX(t, id, "1.0.0"), "vsix", ""); err != nil {
+			t.Fatalf("Failed to install %s: %v", id, err)
+		}
+	}
+
+	report := &models.AuditReport{Results: []models.ValidationResult{
+		{ExtensionID: "pub.ext", TrustLevel: models.TrustLevelMalicious},
+		{ExtensionID: "bad.one", TrustLevel: models.TrustLevelSuspicious},
+		{ExtensionID: "bad.two", TrustLevel: models.TrustLevelSuspicious},
+		{ExtensionID: "good.ext", TrustLevel: models.TrustLevelLegitimate},
+		{ExtensionID: "gone.ext", TrustLevel: models.TrustLevelMalicious, InstallState: models.InstallStatePendingRemoval},
+	}}
+	if targets := FixTargets(report); len(targets) != 3 {
+		t.Fatalf("Expected 3 fix targets, got %+v", targets)
+	}
+
+	// Decline bad.two
+	confirm := func(result models.ValidationResult) bool { return result.ExtensionID != "bad.two" }
+	fixReport := FixAudit(profile, report, models.FixActionQuarantine, nil, confirm)
+
+	if fixReport.FixedCount != 2 || fixReport.SkippedCount != 1 || fixReport.FailedCount != 0 {
+		t.Fatalf("Unexpected counts: %+v", fixReport)
+	}
+	for _, result := range fixReport.Results {
+		if result.Status == models.FixStatusFixed && result.QuarantineID == "" {
+			t.Errorf("Fixed %s should have a quarantine ID", result.ExtensionID)
+		}
+	}
+
+	versions, _ := editor.InstalledVersions(profile.ExtensionsDir)
+	for id, want := range map[string]bool{"pub.ext": false, "bad.one": false, "bad.two": true, "good.ext": true} {
+		if _, ok := versions[id]; ok != want {
+			t.Errorf("%s installed = %v, want %v", id, ok, want)
+		}
+	}
+	if records, _ := editor.ListQuarantined(); len(records) != 2 {
+		t.Errorf("Expected 2 quarantine records, got %d", len(records))
+	}
+}
+
+func TestFixAuditUninstallAndFailure(t *testing.T) {
+	profile := installSuspect(t)
+	report := &models.AuditReport{Results: []models.ValidationResult{
+		{ExtensionID: "pub.ext", TrustLevel: models.TrustLevelMalicious},
+		{ExtensionID: "missing.ext", TrustLevel: models.TrustLevelSuspicious},
+	}}
+
+	fixReport := FixAudit(profile, report, models.FixActionUninstall, nil, nil)
+	if fixReport.FixedCount != 1 || fixReport.FailedCount != 1 {
+		t.Fatalf("Unexpected counts: %+v", fixReport)
+	}
+	if fixReport.Results[1].Error == "" {
+		t.Error("Failed fix should carry an error")
+	}
+
+	versions, _ := editor.InstalledVersions(profile.ExtensionsDir)
+	if _, ok := versions["pub.ext"]; ok {
+		t.Error("pub.ext should be uninstalled")
+	}
+}
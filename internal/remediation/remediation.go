@@ -0,0 +1,141 @@
+package remediation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// Downloader validates an extension and downloads its official marketplace build with a
+// verified Marketplace signature; validation.Validator implements it
+type Downloader interface {
+	ValidateExtension(extensionID string) (*models.ValidationResult, error)
+	DownloadSignedExtension(metadata *models.ExtensionMetadata) ([]byte, string, error)
+}
+
+// Remediate replaces an installed extension with its official marketplace build. The
+// extension is validated and the official package downloaded; it must carry a verified
+// Marketplace signature and be the version the Marketplace lists, and nothing is changed
+// if either cannot be confirmed. Then the installed folders are moved into the quarantine
+// and the package is installed natively. If the install fails, the quarantined extension
+// is put back. Undo reverses a successful remediation. An empty trustLevel records the
+// result of the validation.
+func Remediate(profile models.EditorProfile, extensionID string, trustLevel models.TrustLevel, downloader Downloader) (*models.RemediationResult, error) {
+	validation, err := downloader.ValidateExtension(extensionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate %s: %w", extensionID, err)
+	}
+	if validation.MarketplaceData == nil {
+		return nil, fmt.Errorf("%s is not on the Microsoft Marketplace; there is no official build to install", extensionID)
+	}
+	if trustLevel == "" {
+		trustLevel = validation.TrustLevel
+	}
+
+	data, hash, err := downloader.DownloadSignedExtension(validation.MarketplaceData)
+	if err != nil {
+		return nil, fmt.Errorf("official %s failed verification: %w", extensionID, err)
+	}
+	pkg, err := verifyPackage(data, extensionID, validation.MarketplaceData.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := "replaced with the official marketplace build"
+	if trustLevel != "" {
+		reason = fmt.Sprintf("%s: %s", trustLevel, reason)
+	}
+	record, err := editor.QuarantineExtension(profile, extensionID, reason, trustLevel)
+	if err != nil {
+		if record != nil {
+			editor.RestoreQuarantined(record.ID)
+		}
+		return nil, fmt.Errorf("failed to quarantine %s: %w", extensionID, err)
+	}
+
+	installed, err := editor.InstallVSIX(profile, data, "gallery", extensionID)
+	if err != nil {
+		if _, restoreErr := editor.RestoreQuarantined(record.ID); restoreErr != nil {
+			return nil, fmt.Errorf("failed to install official %s: %w (restoring the original also failed: %v; it is kept in quarantine as %s)",
+				extensionID, err, restoreErr, record.ID)
+		}
+		return nil, fmt.Errorf("failed to install official %s, original restored: %w", extensionID, err)
+	}
+
+	record.ReplacementVersion = installed.Version
+	record.ReplacementSHA256 = hash
+	if err := editor.SaveQuarantineRecord(record); err != nil {
+		return nil, err
+	}
+
+	result := &models.RemediationResult{
+		ExtensionID:      pkg.ID(),
+		Editor:           profile.ID,
+		TrustLevel:       trustLevel,
+		PreviousVersions: previousVersions(record),
+		QuarantineID:     record.ID,
+		OfficialVersion:  installed.Version,
+		SHA256:           hash,
+		InstallPath:      installed.InstallPath,
+	}
+	return result, nil
+}
+
+// Undo reverses a remediation: the official build is uninstalled and the quarantined
+// extension is moved back into the editor
+func Undo(quarantineID string) (*models.QuarantineRecord, error) {
+	record, err := editor.GetQuarantined(quarantineID)
+	if err != nil {
+		return nil, err
+	}
+	if record.ReplacementVersion == "" {
+		return nil, fmt.Errorf("%s was quarantined but not remediated; use 'vsynx quarantine restore' instead", quarantineID)
+	}
+
+	profile, err := editor.GetEditorProfile(record.Editor)
+	if err != nil {
+		profile = models.EditorProfile{ID: record.Editor, Name: string(record.Editor)}
+	}
+	profile.ExtensionsDir = record.ExtensionsDir
+
+	installed, err := editor.InstalledVersions(profile.ExtensionsDir)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := installed[strings.ToLower(record.ExtensionID)]; ok {
+		if _, err := editor.UninstallExtension(profile, record.ExtensionID); err != nil {
+			return nil, fmt.Errorf("failed to remove the official build: %w", err)
+		}
+	}
+
+	return editor.RestoreQuarantined(quarantineID)
+}
+
+// verifyPackage checks that a downloaded package is the expected extension and version
+func verifyPackage(data []byte, extensionID, version string) (*editor.VSIXPackage, error) {
+	pkg, err := editor.ReadVSIX(data)
+	if err != nil {
+		return nil, fmt.Errorf("official %s failed verification: %w", extensionID, err)
+	}
+	if !strings.EqualFold(pkg.ID(), extensionID) {
+		return nil, fmt.Errorf("official package contains %s, expected %s", pkg.ID(), extensionID)
+	}
+	if pkg.Version == "" || pkg.Version != version {
+		return nil, fmt.Errorf("official %s failed verification: package is version %q, the Marketplace lists %s", extensionID, pkg.Version, version)
+	}
+	return pkg, nil
+}
+
+// previousVersions lists the versions that were quarantined
+func previousVersions(record *models.QuarantineRecord) []string {
+	versions := []string{}
+	for _, entry := range record.IndexEntries {
+		versions = append(versions, entry.Version)
+	}
+	if len(versions) == 0 {
+		versions = append(versions, record.Folders...)
+	}
+	return versions
+}
@@ -0,0 +1,158 @@
+package remediation
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// buildTestVSIX creates an in-memory VSIX archive for an extension version
+func buildTestVSIX(t *testing.T, id, version string) []byte {
+	t.Helper()
+	publisher, name, _ := strings.Cut(id, ".")
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string]string{
+		"extension.vsixmanifest": fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<PackageManifest Version="2.0.0" xmlns="http://schemas.microsoft.com/developer/vsx-schema/2011">
+  <Metadata>
+    <Identity Language="en-US" Id="%s" Version="%s" Publisher="%s" />
+  </Metadata>
+</PackageManifest>`, name, version, publisher),
+		"extension/package.json": fmt.Sprintf(`{"publisher": %q, "name": %q, "version": %q}`, publisher, name, version),
+	}
+	for fileName, content := range files {
+		f, _ := w.Create(fileName)
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// fakeDownloader serves one package as the official build listed by the Marketplace
+type fakeDownloader struct {
+	data     []byte
+	version  string // version the Marketplace lists; empty means the package's own
+	unsigned bool   // the signature cannot be verified
+}
+
+func (f *fakeDownloader) ValidateExtension(extensionID string) (*models.ValidationResult, error) {
+	result := &models.ValidationResult{ExtensionID: extensionID, TrustLevel: models.TrustLevelSuspicious}
+	if f.data != nil {
+		version := f.version
+		if version == "" {
+			pkg, _ := editor.ReadVSIX(f.data)
+			version = pkg.Version
+		}
+		result.MarketplaceData = &models.ExtensionMetadata{ID: extensionID, Version: version}
+	}
+	return result, nil
+}
+
+func (f *fakeDownloader) DownloadSignedExtension(metadata *models.ExtensionMetadata) ([]byte, string, error) {
+	if f.unsigned {
+		return nil, "", fmt.Errorf("signature not verified")
+	}
+	sum := sha256.Sum256(f.data)
+	return f.data, hex.EncodeToString(sum[:]), nil
+}
+
+// installSuspect installs a look-alike build of pub.ext into a fresh extensions directory
+func installSuspect(t *testing.T) models.EditorProfile {
+	t.Helper()
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	profile := models.EditorProfile{ID: models.EditorVSCode, Name: "VS Code", ExtensionsDir: t.TempDir()}
+	if _, err := editor.InstallVSIX(profile, buildTestVSIX(t, "pub.ext", "9.9.9"), "vsix", ""); err != nil {
+		t.Fatalf("Failed to install suspect build: %v", err)
+	}
+	return profile
+}
+
+func TestRemediateAndUndo(t *testing.T) {
+	profile := installSuspect(t)
+	downloader := &fakeDownloader{data: buildTestVSIX(t, "pub.ext", "1.2.0")}
+
+	result, err := Remediate(profile, "pub.ext", models.TrustLevelMalicious, downloader)
+	if err != nil {
+		t.Fatalf("Remediate failed: %v", err)
+	}
+	if result.OfficialVersion != "1.2.0" || strings.Join(result.PreviousVersions, ",") != "9.9.9" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	versions, _ := editor.InstalledVersions(profile.ExtensionsDir)
+	if versions["pub.ext"] != "1.2.0" {
+		t.Errorf("Installed version = %q, want 1.2.0", versions["pub.ext"])
+	}
+	if _, err := os.Stat(filepath.Join(profile.ExtensionsDir, "pub.ext-9.9.9")); !os.IsNotExist(err) {
+		t.Error("Suspect folder should be quarantined")
+	}
+	record, err := editor.GetQuarantined(result.QuarantineID)
+	if err != nil || record.ReplacementVersion != "1.2.0" || record.TrustLevel != models.TrustLevelMalicious {
+		t.Fatalf("Quarantine record = %+v, %v", record, err)
+	}
+
+	if _, err := Undo(result.QuarantineID); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	versions, _ = editor.InstalledVersions(profile.ExtensionsDir)
+	if versions["pub.ext"] != "9.9.9" {
+		t.Errorf("Installed version after undo = %q, want 9.9.9", versions["pub.ext"])
+	}
+	if _, err := os.Stat(filepath.Join(profile.ExtensionsDir, "pub.ext-1.2.0")); !os.IsNotExist(err) {
+		t.Error("Official build should be removed by undo")
+	}
+}
+
+func TestRemediateVerificationFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		downloader *fakeDownloader
+	}{
+		{"wrong ID", &fakeDownloader{data: buildTestVSIX(t, "other.ext", "1.0.0")}},
+		{"unverified signature", &fakeDownloader{data: buildTestVSIX(t, "pub.ext", "1.2.0"), unsigned: true}},
+		{"version not listed", &fakeDownloader{data: buildTestVSIX(t, "pub.ext", "1.2.0"), version: "1.3.0"}},
+		{"not on the Marketplace", &fakeDownloader{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := installSuspect(t)
+			if _, err := Remediate(profile, "pub.ext", "", tt.downloader); err == nil {
+				t.Fatal("Expected a verification error")
+			}
+
+			// Nothing was touched
+			versions, _ := editor.InstalledVersions(profile.ExtensionsDir)
+			if versions["pub.ext"] != "9.9.9" {
+				t.Errorf("Suspect build should still be installed, got %q", versions["pub.ext"])
+			}
+			if records, _ := editor.ListQuarantined(); len(records) != 0 {
+				t.Errorf("Nothing should be quarantined, got %+v", records)
+			}
+		})
+	}
+}
+
+func TestUndoRequiresRemediation(t *testing.T) {
+	profile := installSuspect(t)
+	record, err := editor.QuarantineExtension(profile, "pub.ext", "", "")
+	if err != nil {
+		t.Fatalf("QuarantineExtension failed: %v", err)
+	}
+	if _, err := Undo(record.ID); err == nil {
+		t.Error("Expected Undo to refuse a plain quarantine")
+	}
+}
@@ -0,0 +1,49 @@
+package search
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// DetailFetcher fetches the full listing of an extension from one registry;
+// marketplace.Client and openvsx.Client implement it
+type DetailFetcher interface {
+	Details(extensionID string, maxVersions int) (*models.ExtensionDetails, error)
+}
+
+// Details fetches an extension's details from the Marketplace and OpenVSX concurrently.
+// A registry that fails is reported in the result; if both fail, Details fails.
+func Details(extensionID string, maxVersions int, marketplace, openvsx DetailFetcher) (*models.RegistryDetails, error) {
+	var wg sync.WaitGroup
+	result := &models.RegistryDetails{ExtensionID: extensionID}
+	var mpErr, ovsxErr error
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		result.Marketplace, mpErr = marketplace.Details(extensionID, maxVersions)
+	}()
+	go func() {
+		defer wg.Done()
+		result.OpenVSX, ovsxErr = openvsx.Details(extensionID, maxVersions)
+	}()
+	wg.Wait()
+
+	if mpErr != nil && ovsxErr != nil {
+		return nil, fmt.Errorf("both registries failed: marketplace: %v; OpenVSX: %v", mpErr, ovsxErr)
+	}
+	if mpErr != nil {
+		log.Printf("[Search] Marketplace details failed for %s: %v", extensionID, mpErr)
+		result.MarketplaceError = mpErr.Error()
+		result.Marketplace = nil
+	}
+	if ovsxErr != nil {
+		log.Printf("[Search] OpenVSX details failed for %s: %v", extensionID, ovsxErr)
+		result.OpenVSXError = ovsxErr.Error()
+		result.OpenVSX = nil
+	}
+	return result, nil
+}
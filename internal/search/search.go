@@ -0,0 +1,149 @@
+package search
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// lookupWorkers is the number of extensions looked up concurrently in the other registry
+const lookupWorkers = 4
+
+// Searcher searches one registry and looks up single extensions; marketplace.Client
+// and openvsx.Client implement it
+type Searcher interface {
+	Search(opts models.SearchOptions) (*models.SearchResults, error)
+	FetchMetadata(extensionID string) (*models.ExtensionMetadata, error)
+}
+
+// Dual queries the Marketplace and OpenVSX concurrently and merges the results by
+// extension ID, in Marketplace order followed by extensions only OpenVSX has. Extensions
+// found on only one registry's page are looked up by ID on the other before they are
+// labelled. If one registry fails its error is reported in the results; if both fail,
+// Dual fails. Tags are rejected because OpenVSX search cannot filter by them.
+func Dual(opts models.SearchOptions, marketplace, openvsx Searcher) (*models.DualSearchResults, error) {
+	if len(opts.Tags) > 0 {
+		return nil, fmt.Errorf("OpenVSX search does not support tags; search the Marketplace alone to filter by tag")
+	}
+
+	var wg sync.WaitGroup
+	var mpResults, ovsxResults *models.SearchResults
+	var mpErr, ovsxErr error
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		mpResults, mpErr = marketplace.Search(opts)
+	}()
+	go func() {
+		defer wg.Done()
+		ovsxResults, ovsxErr = openvsx.Search(opts)
+	}()
+	wg.Wait()
+
+	if mpErr != nil && ovsxErr != nil {
+		return nil, fmt.Errorf("both registries failed: marketplace: %v; OpenVSX: %v", mpErr, ovsxErr)
+	}
+
+	results := &models.DualSearchResults{Query: opts.Query, Rows: []models.DualSearchRow{}}
+	if mpErr != nil {
+		log.Printf("[Search] Marketplace search failed: %v", mpErr)
+		results.MarketplaceError = mpErr.Error()
+		mpResults = &models.SearchResults{}
+	}
+	if ovsxErr != nil {
+		log.Printf("[Search] OpenVSX search failed: %v", ovsxErr)
+		results.OpenVSXError = ovsxErr.Error()
+		ovsxResults = &models.SearchResults{}
+	}
+	results.MarketplaceTotal = mpResults.Total
+	results.OpenVSXTotal = ovsxResults.Total
+	results.TotalsUnfiltered = opts.VerifiedOnly
+
+	index := map[string]int{}
+	for _, ext := range mpResults.Extensions {
+		key := strings.ToLower(ext.ID)
+		if _, seen := index[key]; seen {
+			continue
+		}
+		index[key] = len(results.Rows)
+		results.Rows = append(results.Rows, models.DualSearchRow{
+			ExtensionID: ext.ID,
+			DisplayName: ext.DisplayName,
+			Marketplace: ext,
+			Presence:    models.PresenceMarketplaceOnly,
+		})
+	}
+	for _, ext := range ovsxResults.Extensions {
+		key := strings.ToLower(ext.ID)
+		if i, seen := index[key]; seen {
+			row := &results.Rows[i]
+			if row.OpenVSX != nil {
+				continue
+			}
+			row.OpenVSX = ext
+			row.Presence = models.PresenceBoth
+			row.VersionsMatch = row.Marketplace.Version == ext.Version
+			if row.DisplayName == "" {
+				row.DisplayName = ext.DisplayName
+			}
+			continue
+		}
+		index[key] = len(results.Rows)
+		results.Rows = append(results.Rows, models.DualSearchRow{
+			ExtensionID: ext.ID,
+			DisplayName: ext.DisplayName,
+			OpenVSX:     ext,
+			Presence:    models.PresenceOpenVSXOnly,
+		})
+	}
+
+	lookupMissing(results.Rows, marketplace, openvsx, mpErr == nil, ovsxErr == nil)
+
+	log.Printf("[Search] %d extensions matching %q across both registries", len(results.Rows), opts.Query)
+	return results, nil
+}
+
+// lookupMissing fetches rows found on one registry's result page from the other registry
+// by ID, so presence and version matching do not depend on how each registry ranks them.
+// A registry whose search failed is not queried; a failed lookup leaves the row as it is.
+func lookupMissing(rows []models.DualSearchRow, marketplace, openvsx Searcher, checkMarketplace, checkOpenVSX bool) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < lookupWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				row := &rows[i]
+				if row.Marketplace == nil {
+					metadata, err := marketplace.FetchMetadata(row.ExtensionID)
+					if err != nil {
+						log.Printf("[Search] Marketplace lookup of %s failed: %v", row.ExtensionID, err)
+						continue
+					}
+					row.Marketplace = metadata
+				} else {
+					metadata, err := openvsx.FetchMetadata(row.ExtensionID)
+					if err != nil {
+						log.Printf("[Search] OpenVSX lookup of %s failed: %v", row.ExtensionID, err)
+						continue
+					}
+					row.OpenVSX = metadata
+				}
+				row.Presence = models.PresenceBoth
+				row.VersionsMatch = row.Marketplace.Version == row.OpenVSX.Version
+			}
+		}()
+	}
+	for i, row := range rows {
+		if (row.Marketplace == nil && checkMarketplace) || (row.OpenVSX == nil && checkOpenVSX) {
+			jobs <- i
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,142 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// fakeSearcher returns fixed results; listed holds extensions found by ID but not on the page
+type fakeSearcher struct {
+	results []*models.ExtensionMetadata
+	listed  []*models.ExtensionMetadata
+	err     error
+}
+
+func (f fakeSearcher) FetchMetadata(extensionID string) (*models.ExtensionMetadata, error) {
+	for _, ext := range append(f.results, f.listed...) {
+		if strings.EqualFold(ext.ID, extensionID) {
+			return ext, nil
+		}
+	}
+	return nil, fmt.Errorf("extension not found: %s", extensionID)
+}
+
+func (f fakeSearcher) Search(opts models.SearchOptions) (*models.SearchResults, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.SearchResults{Extensions: f.results, Total: len(f.results)}, nil
+}
+
+func ext(id, version string) *models.ExtensionMetadata {
+	return &models.ExtensionMetadata{ID: id, Version: version}
+}
+
+func TestDualMergesByID(t *testing.T) {
+	mp := fakeSearcher{results: []*models.ExtensionMetadata{ext("ms-python.python", "2024.2.0"), ext("redhat.java", "1.30.0"), ext("ms-vscode.cpptools", "1.19.0")}}
+	ovsx := fakeSearcher{results: []*models.ExtensionMetadata{ext("RedHat.java", "1.30.0"), ext("ms-python.python", "2024.1.0"), ext("open.only", "0.1.0")}}
+
+	results, err := Dual(models.SearchOptions{Query: "x"}, mp, ovsx)
+	if err != nil {
+		t.Fatalf("Dual() error: %v", err)
+	}
+
+	want := []struct {
+		id       string
+		presence models.RegistryPresence
+		match    bool
+	}{
+		{"ms-python.python", models.PresenceBoth, false},
+		{"redhat.java", models.PresenceBoth, true},
+		{"ms-vscode.cpptools", models.PresenceMarketplaceOnly, false},
+		{"open.only", models.PresenceOpenVSXOnly, false},
+	}
+	if len(results.Rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(results.Rows), len(want))
+	}
+	for i, w := range want {
+		row := results.Rows[i]
+		if row.ExtensionID != w.id || row.Presence != w.presence || row.VersionsMatch != w.match {
+			t.Errorf("row %d = %s %s match=%v, want %s %s match=%v", i, row.ExtensionID, row.Presence, row.VersionsMatch, w.id, w.presence, w.match)
+		}
+	}
+	if results.MarketplaceTotal != 3 || results.OpenVSXTotal != 3 {
+		t.Errorf("totals = %d/%d", results.MarketplaceTotal, results.OpenVSXTotal)
+	}
+}
+
+func TestDualLooksUpMissingByID(t *testing.T) {
+	mp := fakeSearcher{
+		results: []*models.ExtensionMetadata{ext("ms-python.python", "2024.2.0")},
+		listed:  []*models.ExtensionMetadata{ext("open.ranked", "1.0.0")},
+	}
+	ovsx := fakeSearcher{
+		results: []*models.ExtensionMetadata{ext("open.ranked", "1.0.0")},
+		listed:  []*models.ExtensionMetadata{ext("ms-python.python", "2024.1.0")},
+	}
+
+	results, err := Dual(models.SearchOptions{Query: "x"}, mp, ovsx)
+	if err != nil {
+		t.Fatalf("Dual() error: %v", err)
+	}
+	if len(results.Rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(results.Rows))
+	}
+	for _, row := range results.Rows {
+		if row.Presence != models.PresenceBoth {
+			t.Errorf("%s presence = %s, want both", row.ExtensionID, row.Presence)
+		}
+	}
+	if results.Rows[0].VersionsMatch || !results.Rows[1].VersionsMatch {
+		t.Errorf("VersionsMatch = %v/%v, want false/true", results.Rows[0].VersionsMatch, results.Rows[1].VersionsMatch)
+	}
+
+	if _, err := Dual(models.SearchOptions{Query: "x", Tags: []string{"snippets"}}, mp, ovsx); err == nil {
+		t.Error("expected tags to be rejected")
+	}
+}
+
+func TestDualPartialFailure(t *testing.T) {
+	mp := fakeSearcher{err: fmt.Errorf("marketplace down")}
+	ovsx := fakeSearcher{results: []*models.ExtensionMetadata{ext("open.only", "0.1.0")}}
+
+	results, err := Dual(models.SearchOptions{Query: "x"}, mp, ovsx)
+	if err != nil {
+		t.Fatalf("Dual() error: %v", err)
+	}
+	if results.MarketplaceError == "" || len(results.Rows) != 1 || results.Rows[0].Presence != models.PresenceOpenVSXOnly {
+		t.Errorf("results = %+v", results)
+	}
+
+	if _, err := Dual(models.SearchOptions{}, mp, fakeSearcher{err: fmt.Errorf("also down")}); err == nil {
+		t.Error("expected error when both registries fail")
+	}
+}
+
+// fakeDetails returns fixed details
+type fakeDetails struct {
+	details *models.ExtensionDetails
+	err     error
+}
+
+func (f fakeDetails) Details(extensionID string, maxVersions int) (*models.ExtensionDetails, error) {
+	return f.details, f.err
+}
+
+func TestDetailsPartialFailure(t *testing.T) {
+	ovsx := fakeDetails{details: &models.ExtensionDetails{Metadata: ext("open.only", "0.1.0")}}
+	result, err := Details("open.only", 0, fakeDetails{err: fmt.Errorf("not found")}, ovsx)
+	if err != nil {
+		t.Fatalf("Details() error: %v", err)
+	}
+	if result.Marketplace != nil || result.MarketplaceError == "" || result.OpenVSX == nil {
+		t.Errorf("result = %+v", result)
+	}
+
+	if _, err := Details("x.y", 0, fakeDetails{err: fmt.Errorf("a")}, fakeDetails{err: fmt.Errorf("b")}); err == nil {
+		t.Error("expected error when both registries fail")
+	}
+}
@@ -0,0 +1,239 @@
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+)
+
+var (
+	oidSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidRSAPSS        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 10}
+
+	digestAlgorithms = map[string]crypto.Hash{
+		"1.3.14.3.2.26":          crypto.SHA1,
+		"2.16.840.1.101.3.4.2.1": crypto.SHA256,
+		"2.16.840.1.101.3.4.2.2": crypto.SHA384,
+		"2.16.840.1.101.3.4.2.3": crypto.SHA512,
+	}
+)
+
+// contentInfo is the outer CMS structure (RFC 5652)
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+type encapContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type issuerAndSerial struct {
+	Issuer asn1.RawValue
+	Serial *big.Int
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+// verifyPKCS7 checks a detached CMS SignedData signature over content and returns the
+// signer certificate and the other certificates in the bundle. The certificate chain is
+// not checked here.
+func verifyPKCS7(der, content []byte) (*x509.Certificate, []*x509.Certificate, error) {
+	var info contentInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, nil, fmt.Errorf("malformed PKCS#7 signature: %w", err)
+	}
+	if !info.ContentType.Equal(oidSignedData) {
+		return nil, nil, fmt.Errorf("PKCS#7 content is not SignedData")
+	}
+	var sd signedData
+	if _, err := asn1.Unmarshal(info.Content.Bytes, &sd); err != nil {
+		return nil, nil, fmt.Errorf("malformed PKCS#7 SignedData: %w", err)
+	}
+	if len(sd.EncapContentInfo.EContent.Bytes) > 0 {
+		// An attached signature must cover the same content
+		var attached []byte
+		if _, err := asn1.Unmarshal(sd.EncapContentInfo.EContent.Bytes, &attached); err != nil || !bytes.Equal(attached, content) {
+			return nil, nil, fmt.Errorf("PKCS#7 signature covers different content")
+		}
+	}
+
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed PKCS#7 certificates: %w", err)
+	}
+	if len(sd.SignerInfos) == 0 {
+		return nil, nil, fmt.Errorf("PKCS#7 signature has no signers")
+	}
+
+	// Every signer must verify; the first one identifies the package signer
+	var signer *x509.Certificate
+	for i, si := range sd.SignerInfos {
+		cert, err := verifySignerInfo(si, certs, content)
+		if err != nil {
+			return nil, nil, err
+		}
+		if i == 0 {
+			signer = cert
+		}
+	}
+
+	var others []*x509.Certificate
+	for _, cert := range certs {
+		if cert != signer {
+			others = append(others, cert)
+		}
+	}
+	return signer, others, nil
+}
+
+// verifySignerInfo checks one signer's signature and returns its certificate
+func verifySignerInfo(si signerInfo, certs []*x509.Certificate, content []byte) (*x509.Certificate, error) {
+	cert := findSignerCert(si.SID, certs)
+	if cert == nil {
+		return nil, fmt.Errorf("PKCS#7 signer certificate not found")
+	}
+	hash, ok := digestAlgorithms[si.DigestAlgorithm.Algorithm.String()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported PKCS#7 digest algorithm %s", si.DigestAlgorithm.Algorithm)
+	}
+
+	signed := content
+	if len(si.SignedAttrs.Bytes) > 0 {
+		digest, err := messageDigest(si.SignedAttrs.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		h := hash.New()
+		h.Write(content)
+		if !bytes.Equal(h.Sum(nil), digest) {
+			return nil, fmt.Errorf("PKCS#7 message digest does not match the signed content")
+		}
+		// Signed attributes are signed as an explicit SET OF, not with the implicit [0] tag
+		signed = append([]byte{0x31}, si.SignedAttrs.FullBytes[1:]...)
+	}
+
+	algorithm, err := signatureAlgorithm(cert, hash, si.SignatureAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := cert.CheckSignature(algorithm, signed, si.Signature); err != nil {
+		return nil, fmt.Errorf("PKCS#7 signature verification failed: %w", err)
+	}
+	return cert, nil
+}
+
+// findSignerCert finds the certificate named by a SignerIdentifier
+func findSignerCert(sid asn1.RawValue, certs []*x509.Certificate) *x509.Certificate {
+	if sid.Class == asn1.ClassContextSpecific && sid.Tag == 0 {
+		for _, cert := range certs {
+			if bytes.Equal(cert.SubjectKeyId, sid.Bytes) {
+				return cert
+			}
+		}
+		return nil
+	}
+
+	var ias issuerAndSerial
+	if _, err := asn1.Unmarshal(sid.FullBytes, &ias); err != nil {
+		return nil
+	}
+	for _, cert := range certs {
+		if bytes.Equal(cert.RawIssuer, ias.Issuer.FullBytes) && cert.SerialNumber.Cmp(ias.Serial) == 0 {
+			return cert
+		}
+	}
+	return nil
+}
+
+// messageDigest extracts the message-digest attribute from DER signed attributes
+func messageDigest(attrs []byte) ([]byte, error) {
+	var digest []byte
+	hasContentType := false
+	for rest := attrs; len(rest) > 0; {
+		var attr attribute
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &attr); err != nil {
+			return nil, fmt.Errorf("malformed PKCS#7 signed attributes: %w", err)
+		}
+		switch {
+		case attr.Type.Equal(oidMessageDigest):
+			if _, err := asn1.Unmarshal(attr.Values.Bytes, &digest); err != nil {
+				return nil, fmt.Errorf("malformed PKCS#7 message digest: %w", err)
+			}
+		case attr.Type.Equal(oidContentType):
+			hasContentType = true
+		}
+	}
+	if digest == nil || !hasContentType {
+		return nil, fmt.Errorf("PKCS#7 signed attributes lack a content type or message digest")
+	}
+	return digest, nil
+}
+
+// signatureAlgorithm maps a signer's key type and digest to an x509 signature algorithm
+func signatureAlgorithm(cert *x509.Certificate, hash crypto.Hash, sigAlg pkix.AlgorithmIdentifier) (x509.SignatureAlgorithm, error) {
+	pss := sigAlg.Algorithm.Equal(oidRSAPSS)
+	switch cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		switch {
+		case pss && hash == crypto.SHA256:
+			return x509.SHA256WithRSAPSS, nil
+		case pss && hash == crypto.SHA384:
+			return x509.SHA384WithRSAPSS, nil
+		case pss && hash == crypto.SHA512:
+			return x509.SHA512WithRSAPSS, nil
+		case hash == crypto.SHA1:
+			return x509.SHA1WithRSA, nil
+		case hash == crypto.SHA256:
+			return x509.SHA256WithRSA, nil
+		case hash == crypto.SHA384:
+			return x509.SHA384WithRSA, nil
+		case hash == crypto.SHA512:
+			return x509.SHA512WithRSA, nil
+		}
+	case *ecdsa.PublicKey:
+		switch hash {
+		case crypto.SHA1:
+			return x509.ECDSAWithSHA1, nil
+		case crypto.SHA256:
+			return x509.ECDSAWithSHA256, nil
+		case crypto.SHA384:
+			return x509.ECDSAWithSHA384, nil
+		case crypto.SHA512:
+			return x509.ECDSAWithSHA512, nil
+		}
+	}
+	return x509.UnknownSignatureAlgorithm, fmt.Errorf("unsupported PKCS#7 signature algorithm %s", sigAlg.Algorithm)
+}
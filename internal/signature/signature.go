@@ -0,0 +1,276 @@
+package signature
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// Files inside a signature archive (Marketplace .signature.p7s asset and OpenVSX .sigzip)
+const (
+	ManifestFile = ".signature.manifest"
+	PKCS7File    = ".signature.p7s"
+	Ed25519File  = ".signature.sig"
+)
+
+// MarketplaceSigner is the organization that signs Microsoft Marketplace packages
+const MarketplaceSigner = "Microsoft Corporation"
+
+// maxArchiveEntrySize bounds the files read from a signature archive
+const maxArchiveEntrySize = 16 << 20
+
+// Manifest lists the digests of a VSIX package and its files
+type Manifest struct {
+	Package ManifestEntry            `json:"package"`
+	Entries map[string]ManifestEntry `json:"entries"`
+}
+
+// ManifestEntry is the size and digests of the package or one file in it
+type ManifestEntry struct {
+	Size    int64             `json:"size"`
+	Digests map[string]string `json:"digests"`
+}
+
+// VerifyMarketplace verifies a Marketplace signature archive against a VSIX: the manifest
+// must match the package and carry a valid PKCS#7 signature from a certificate issued by
+// one of roots (nil uses the system roots) to the Microsoft Marketplace signer. A chain
+// that does not lead to a trusted root leaves the check unverified rather than invalid,
+// since it only shows that the root is missing on this host.
+func VerifyMarketplace(vsix, archive []byte, roots *x509.CertPool) models.SignatureCheck {
+	check := models.SignatureCheck{Source: "marketplace"}
+	files, err := readArchive(archive)
+	if err != nil {
+		return invalid(check, err)
+	}
+	manifest, p7s := files[ManifestFile], files[PKCS7File]
+	if len(manifest) == 0 || len(p7s) == 0 {
+		check.Status = models.SignatureUnsigned
+		check.Detail = "signature archive has no PKCS#7 signature"
+		return check
+	}
+
+	signer, intermediates, err := verifyPKCS7(p7s, manifest)
+	if err != nil {
+		return invalid(check, err)
+	}
+	check.Signer = signer.Subject.CommonName
+
+	pool := x509.NewCertPool()
+	for _, cert := range intermediates {
+		pool.AddCert(cert)
+	}
+	if _, err := signer.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: pool,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		check.Detail = fmt.Sprintf("could not verify: signing certificate does not chain to a trusted root (%v); pass the Marketplace root with --signature-roots", err)
+		return check
+	}
+	if !containsFold(signer.Subject.Organization, MarketplaceSigner) {
+		return invalid(check, fmt.Errorf("package is signed by %q, not the Marketplace", signer.Subject.String()))
+	}
+
+	if err := checkManifest(manifest, vsix); err != nil {
+		return invalid(check, err)
+	}
+	check.Status = models.SignatureMarketplace
+	return check
+}
+
+// VerifyOpenVSX verifies an OpenVSX .sigzip against a VSIX: the manifest must match the
+// package and carry a valid ed25519 signature from the registry's public key (PEM or base64)
+func VerifyOpenVSX(vsix, sigzip, publicKey []byte) models.SignatureCheck {
+	check := models.SignatureCheck{Source: "openvsx", Signer: "open-vsx.org"}
+	files, err := readArchive(sigzip)
+	if err != nil {
+		return invalid(check, err)
+	}
+	manifest, sig := files[ManifestFile], files[Ed25519File]
+	if len(manifest) == 0 || len(sig) == 0 {
+		check.Status = models.SignatureUnsigned
+		check.Detail = "signature archive has no ed25519 signature"
+		return check
+	}
+
+	key, err := parseEd25519Key(publicKey)
+	if err != nil {
+		return invalid(check, err)
+	}
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil {
+			return invalid(check, fmt.Errorf("malformed ed25519 signature"))
+		}
+		sig = decoded
+	}
+	if !ed25519.Verify(key, manifest, sig) {
+		return invalid(check, fmt.Errorf("ed25519 signature verification failed"))
+	}
+
+	if err := checkManifest(manifest, vsix); err != nil {
+		return invalid(check, err)
+	}
+	check.Status = models.SignatureOpenVSX
+	return check
+}
+
+// Unsigned returns the check for a registry package published without a signature
+func Unsigned(source string) models.SignatureCheck {
+	return models.SignatureCheck{Source: source, Status: models.SignatureUnsigned, Detail: "registry publishes no signature"}
+}
+
+// Combine reduces per-registry checks to one status: any invalid signature wins, then
+// a Marketplace signature, then an OpenVSX signature. Checks that could not run are ignored.
+func Combine(checks []models.SignatureCheck) models.SignatureStatus {
+	var status models.SignatureStatus
+	rank := map[models.SignatureStatus]int{
+		models.SignatureUnsigned:    1,
+		models.SignatureOpenVSX:     2,
+		models.SignatureMarketplace: 3,
+		models.SignatureInvalid:     4,
+	}
+	for _, check := range checks {
+		if rank[check.Status] > rank[status] {
+			status = check.Status
+		}
+	}
+	return status
+}
+
+// checkManifest verifies that a signature manifest describes the VSIX: either the whole
+// package digest matches, or every file in the package is listed with a matching digest
+func checkManifest(data, vsix []byte) error {
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("malformed signature manifest: %w", err)
+	}
+
+	if expected := manifest.Package.Digests["sha256"]; expected != "" {
+		sum := sha256.Sum256(vsix)
+		if !digestMatches(expected, sum[:]) {
+			return fmt.Errorf("signature manifest does not match the package")
+		}
+		return nil
+	}
+
+	if len(manifest.Entries) == 0 {
+		return fmt.Errorf("signature manifest lists no files")
+	}
+	reader, err := zip.NewReader(bytes.NewReader(vsix), int64(len(vsix)))
+	if err != nil {
+		return fmt.Errorf("failed to open package: %w", err)
+	}
+	files := 0
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		files++
+		entry, ok := manifest.Entries[f.Name]
+		if !ok {
+			return fmt.Errorf("package file %s is not covered by the signature", f.Name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		if !digestMatches(entry.Digests["sha256"], h.Sum(nil)) {
+			return fmt.Errorf("package file %s does not match the signature", f.Name)
+		}
+	}
+	if files != len(manifest.Entries) {
+		return fmt.Errorf("signature manifest lists files missing from the package")
+	}
+	return nil
+}
+
+// digestMatches compares a base64 or hex digest with raw bytes
+func digestMatches(expected string, sum []byte) bool {
+	expected = strings.TrimSpace(expected)
+	if expected == "" {
+		return false
+	}
+	if strings.EqualFold(expected, hex.EncodeToString(sum)) {
+		return true
+	}
+	return expected == base64.StdEncoding.EncodeToString(sum)
+}
+
+// readArchive reads the files of a signature archive
+func readArchive(data []byte) (map[string][]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature archive: %w", err)
+	}
+	files := map[string][]byte{}
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("malformed signature archive: %w", err)
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, maxArchiveEntrySize))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("malformed signature archive: %w", err)
+		}
+		files[f.Name] = content
+	}
+	return files, nil
+}
+
+// parseEd25519Key parses a PEM (PKIX) or base64 ed25519 public key
+func parseEd25519Key(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("malformed registry public key: %w", err)
+		}
+		if edKey, ok := key.(ed25519.PublicKey); ok {
+			return edKey, nil
+		}
+		return nil, fmt.Errorf("registry public key is not ed25519")
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("malformed registry public key")
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// invalid marks a check as having a bad signature
+func invalid(check models.SignatureCheck, err error) models.SignatureCheck {
+	check.Status = models.SignatureInvalid
+	check.Detail = err.Error()
+	return check
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,280 @@
+package signature
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+var oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+
+// buildZip creates an in-memory zip archive
+func buildZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		f.Write(content)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// testVSIX returns a small package
+func testVSIX(t *testing.T) []byte {
+	return buildZip(t, map[string][]byte{
+		"extension.vsixmanifest": []byte("<PackageManifest/>"),
+		"extension/package.json": []byte(`{"publisher": "pub", "name": "ext", "version": "1.0.0"}`),
+		"extension/out/index.js": []byte("module.exports = {}"),
+	})
+}
+
+// entryManifest builds a signature manifest listing each file of a package
+func entryManifest(t *testing.T, vsix []byte) []byte {
+	t.Helper()
+	reader, _ := zip.NewReader(bytes.NewReader(vsix), int64(len(vsix)))
+	manifest := Manifest{Entries: map[string]ManifestEntry{}}
+	for _, f := range reader.File {
+		rc, _ := f.Open()
+		var buf bytes.Buffer
+		buf.ReadFrom(rc)
+		rc.Close()
+		sum := sha256.Sum256(buf.Bytes())
+		manifest.Entries[f.Name] = ManifestEntry{
+			Size:    int64(buf.Len()),
+			Digests: map[string]string{"sha256": base64.StdEncoding.EncodeToString(sum[:])},
+		}
+	}
+	data, _ := json.Marshal(manifest)
+	return data
+}
+
+// packageManifest builds a signature manifest with the whole-package digest
+func packageManifest(vsix []byte) []byte {
+	sum := sha256.Sum256(vsix)
+	data, _ := json.Marshal(Manifest{Package: ManifestEntry{
+		Size:    int64(len(vsix)),
+		Digests: map[string]string{"sha256": base64.StdEncoding.EncodeToString(sum[:])},
+	}})
+	return data
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+// issue creates a code signing certificate for an organization
+func (ca *testCA) issue(t *testing.T, organization string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Signer", Organization: []string{organization}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Failed to issue certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert, key
+}
+
+// signPKCS7 creates a detached CMS SignedData signature with signed attributes
+func signPKCS7(t *testing.T, content []byte, cert *x509.Certificate, key crypto.Signer) []byte {
+	t.Helper()
+	marshal := func(v any) []byte {
+		data, err := asn1.Marshal(v)
+		if err != nil {
+			t.Fatalf("Failed to marshal: %v", err)
+		}
+		return data
+	}
+	attr := func(oid asn1.ObjectIdentifier, value []byte) []byte {
+		return marshal(struct {
+			Type   asn1.ObjectIdentifier
+			Values asn1.RawValue
+		}{oid, asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: value}})
+	}
+
+	digest := sha256.Sum256(content)
+	attrs := append(attr(oidContentType, marshal(asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1})),
+		attr(oidMessageDigest, marshal(digest[:]))...)
+	signedAttrs := marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: attrs})
+	attrsDigest := sha256.Sum256(signedAttrs)
+	sig, err := key.Sign(rand.Reader, attrsDigest[:], crypto.SHA256)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+
+	sid := marshal(issuerAndSerial{Issuer: asn1.RawValue{FullBytes: cert.RawIssuer}, Serial: cert.SerialNumber})
+	si := signerInfo{
+		Version:            1,
+		SID:                asn1.RawValue{FullBytes: sid},
+		DigestAlgorithm:    pkix.AlgorithmIdentifier{Algorithm: oidSHA256},
+		SignedAttrs:        asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attrs},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}},
+		Signature:          sig,
+	}
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{{Algorithm: oidSHA256}},
+		EncapContentInfo: encapContentInfo{EContentType: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: cert.Raw},
+		SignerInfos:      []signerInfo{si},
+	}
+	return marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: marshal(sd)},
+	})
+}
+
+func TestVerifyMarketplace(t *testing.T) {
+	ca := newTestCA(t)
+	cert, key := ca.issue(t, MarketplaceSigner)
+	vsix := testVSIX(t)
+	manifest := entryManifest(t, vsix)
+	archive := buildZip(t, map[string][]byte{ManifestFile: manifest, PKCS7File: signPKCS7(t, manifest, cert, key)})
+
+	check := VerifyMarketplace(vsix, archive, ca.pool)
+	if check.Status != models.SignatureMarketplace {
+		t.Fatalf("Status = %s (%s), want signed-marketplace", check.Status, check.Detail)
+	}
+
+	// A modified package no longer matches the manifest
+	tampered := buildZip(t, map[string][]byte{
+		"extension.vsixmanifest": []byte("<PackageManifest/>"),
+		"extension/package.json": []byte(`{"publisher": "pub", "name": "ext", "version": "1.0.0"}`),
+		"extension/out/index.js": []byte("require('child_process').exec('curl evil')"),
+	})
+	if check := VerifyMarketplace(tampered, archive, ca.pool); check.Status != models.SignatureInvalid {
+		t.Errorf("Tampered package status = %s, want invalid", check.Status)
+	}
+
+	// An untrusted root cannot be verified, which is not evidence of tampering
+	if check := VerifyMarketplace(vsix, archive, newTestCA(t).pool); check.Status != "" || !strings.HasPrefix(check.Detail, "could not verify") {
+		t.Errorf("Untrusted root status = %q (%s), want unverified", check.Status, check.Detail)
+	}
+
+	// A valid signature from someone other than the Marketplace
+	otherCert, otherKey := ca.issue(t, "Evil Corp")
+	otherArchive := buildZip(t, map[string][]byte{ManifestFile: manifest, PKCS7File: signPKCS7(t, manifest, otherCert, otherKey)})
+	if check := VerifyMarketplace(vsix, otherArchive, ca.pool); check.Status != models.SignatureInvalid {
+		t.Errorf("Foreign signer status = %s, want invalid", check.Status)
+	}
+
+	// A manifest swapped after signing
+	swapped := buildZip(t, map[string][]byte{ManifestFile: packageManifest(tampered), PKCS7File: signPKCS7(t, manifest, cert, key)})
+	if check := VerifyMarketplace(tampered, swapped, ca.pool); check.Status != models.SignatureInvalid {
+		t.Errorf("Swapped manifest status = %s, want invalid", check.Status)
+	}
+
+	// No signature in the archive
+	unsigned := buildZip(t, map[string][]byte{ManifestFile: manifest})
+	if check := VerifyMarketplace(vsix, unsigned, ca.pool); check.Status != models.SignatureUnsigned {
+		t.Errorf("Unsigned status = %s, want unsigned", check.Status)
+	}
+}
+
+func TestVerifyOpenVSX(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(publicKey)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	vsix := testVSIX(t)
+	manifest := packageManifest(vsix)
+	sigzip := buildZip(t, map[string][]byte{ManifestFile: manifest, Ed25519File: ed25519.Sign(privateKey, manifest)})
+
+	if check := VerifyOpenVSX(vsix, sigzip, publicPEM); check.Status != models.SignatureOpenVSX {
+		t.Fatalf("Status = %s (%s), want signed-openvsx", check.Status, check.Detail)
+	}
+
+	// Base64 signature and key encodings are accepted
+	base64Zip := buildZip(t, map[string][]byte{
+		ManifestFile: manifest,
+		Ed25519File:  []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, manifest))),
+	})
+	if check := VerifyOpenVSX(vsix, base64Zip, []byte(base64.StdEncoding.EncodeToString(publicKey))); check.Status != models.SignatureOpenVSX {
+		t.Errorf("Base64 status = %s (%s), want signed-openvsx", check.Status, check.Detail)
+	}
+
+	otherKey, _, _ := ed25519.GenerateKey(rand.Reader)
+	if check := VerifyOpenVSX(vsix, sigzip, []byte(base64.StdEncoding.EncodeToString(otherKey))); check.Status != models.SignatureInvalid {
+		t.Errorf("Wrong key status = %s, want invalid", check.Status)
+	}
+	if check := VerifyOpenVSX(append([]byte{}, testVSIX(t)[:10]...), sigzip, publicPEM); check.Status != models.SignatureInvalid {
+		t.Errorf("Different package status = %s, want invalid", check.Status)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []models.SignatureCheck
+		want   models.SignatureStatus
+	}{
+		{"None", nil, ""},
+		{"Not run", []models.SignatureCheck{{Source: "marketplace"}}, ""},
+		{"Marketplace", []models.SignatureCheck{{Status: models.SignatureOpenVSX}, {Status: models.SignatureMarketplace}}, models.SignatureMarketplace},
+		{"OpenVSX only", []models.SignatureCheck{{Status: models.SignatureUnsigned}, {Status: models.SignatureOpenVSX}}, models.SignatureOpenVSX},
+		{"Invalid wins", []models.SignatureCheck{{Status: models.SignatureMarketplace}, {Status: models.SignatureInvalid}}, models.SignatureInvalid},
+		{"Unsigned", []models.SignatureCheck{{Status: models.SignatureUnsigned}}, models.SignatureUnsigned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Combine(tt.checks); got != tt.want {
+				t.Errorf("Combine = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
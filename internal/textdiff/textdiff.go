@@ -0,0 +1,165 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change
+const contextLines = 3
+
+// maxCells bounds the size of the LCS table; larger inputs are shown as a full replacement
+const maxCells = 4_000_000
+
+// opKind is the kind of a diff line
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+type op struct {
+	kind       opKind
+	text       string
+	oldN, newN int // 1-based line numbers in the old and new text
+}
+
+// Unified returns a unified diff between two texts, or "" when they are equal
+func Unified(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for _, hunk := range hunks(ops) {
+		writeHunk(&b, ops[hunk[0]:hunk[1]])
+	}
+	return b.String()
+}
+
+// splitLines splits text into lines without their line endings
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes line operations from a longest common subsequence
+func diffLines(a, b []string) []op {
+	var ops []op
+	if len(a)*len(b) > maxCells {
+		for i, line := range a {
+			ops = append(ops, op{kind: opDelete, text: line, oldN: i + 1})
+		}
+		for j, line := range b {
+			ops = append(ops, op{kind: opInsert, text: line, newN: j + 1})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, op{kind: opEqual, text: a[i], oldN: i + 1, newN: j + 1})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{kind: opDelete, text: a[i], oldN: i + 1, newN: j})
+			i++
+		default:
+			ops = append(ops, op{kind: opInsert, text: b[j], oldN: i, newN: j + 1})
+			j++
+		}
+	}
+	return ops
+}
+
+// hunks groups changes with their surrounding context into [start, end) ranges of ops
+func hunks(ops []op) [][2]int {
+	var result [][2]int
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == opEqual {
+			continue
+		}
+		start := max(i-contextLines, 0)
+		end := i
+		// Extend while the next change is within two context blocks
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == opEqual {
+				next++
+			}
+			if next == len(ops) || next-end > 2*contextLines {
+				end = min(end+contextLines, len(ops))
+				break
+			}
+			end = next
+		}
+		if len(result) > 0 && start <= result[len(result)-1][1] {
+			result[len(result)-1][1] = end
+		} else {
+			result = append(result, [2]int{start, end})
+		}
+		i = end - 1
+	}
+	return result
+}
+
+// writeHunk writes one hunk with its @@ header
+func writeHunk(b *strings.Builder, ops []op) {
+	oldStart, newStart, oldCount, newCount := 0, 0, 0, 0
+	for _, o := range ops {
+		if o.kind != opInsert {
+			if oldCount == 0 {
+				oldStart = o.oldN
+			}
+			oldCount++
+		}
+		if o.kind != opDelete {
+			if newCount == 0 {
+				newStart = o.newN
+			}
+			newCount++
+		}
+	}
+	if oldCount == 0 {
+		oldStart = ops[0].oldN
+	}
+	if newCount == 0 {
+		newStart = ops[0].newN
+	}
+
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, o := range ops {
+		fmt.Fprintf(b, "%c%s\n", o.kind, o.text)
+	}
+}
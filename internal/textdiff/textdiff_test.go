@@ -0,0 +1,41 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	oldText := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	newText := "a\nb\nC\nd\ne\nf\ng\nh\ni\nj\nk\n"
+
+	want := `--- old
++++ new
+@@ -1,6 +1,6 @@
+ a
+ b
+-c
++C
+ d
+ e
+ f
+@@ -8,3 +8,4 @@
+ h
+ i
+ j
++k
+`
+	if got := Unified("old", "new", oldText, newText); got != want {
+		t.Errorf("Unified:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnifiedNewFile(t *testing.T) {
+	want := "--- /dev/null\n+++ new\n@@ -0,0 +1,2 @@\n+x\n+y\n"
+	if got := Unified("/dev/null", "new", "", "x\ny\n"); got != want {
+		t.Errorf("Unified:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestUnifiedEqual(t *testing.T) {
+	if got := Unified("a", "b", "same\n", "same\n"); got != "" {
+		t.Errorf("Expected empty diff, got %q", got)
+	}
+}
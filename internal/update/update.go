@@ -0,0 +1,452 @@
+package update
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/download"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/vsixdiff"
+)
+
+// Registry lists the published versions of an extension, newest first;
+// marketplace.Client and openvsx.Client implement it
+type Registry interface {
+	ListVersions(extensionID string) ([]models.ExtensionVersion, error)
+}
+
+// VersionDetailer looks up the engine range, pre-release flag and platform of a version
+// that the registry's version list leaves out; openvsx.Client implements it
+type VersionDetailer interface {
+	VersionDetails(extensionID, version, targetPlatform string) (*models.ExtensionVersion, error)
+}
+
+// Fetcher resolves and downloads extension packages; download.Downloader implements it
+type Fetcher interface {
+	Resolve(extensionID, version, source, targetPlatform string) (*models.DownloadInfo, error)
+	Download(info *models.DownloadInfo, dir string) error
+}
+
+// Validator classifies an extension; validation.Validator implements it
+type Validator interface {
+	ValidateExtension(extensionID string) (*models.ValidationResult, error)
+}
+
+// maxDetailedCandidates bounds the versions looked up one by one on a VersionDetailer
+const maxDetailedCandidates = 10
+
+// checkWorkers is the number of extensions checked concurrently
+const checkWorkers = 4
+
+// Options selects what Check compares installed extensions against
+type Options struct {
+	EngineVersion string   // VS Code version of the editor; empty skips engine checks
+	Platform      string   // target platform of the editor, e.g. "linux-x64"
+	ExtensionIDs  []string // only check these extensions; empty checks all
+}
+
+// Checker finds updates for installed extensions, remembering registry answers so that
+// several editors can be checked without asking twice
+type Checker struct {
+	registries map[string]Registry
+	mu         sync.Mutex
+	cache      map[string]latestResult
+}
+
+// latestResult is the newest compatible version of an extension on one registry
+type latestResult struct {
+	version *models.ExtensionVersion
+	err     error
+}
+
+// installedExtension is an extension found in an editor's extensions directory
+type installedExtension struct {
+	id         string
+	version    string
+	preRelease bool // the user follows the pre-release channel
+}
+
+// NewChecker creates a checker over the Marketplace and OpenVSX
+func NewChecker(marketplace, openvsx Registry) *Checker {
+	return &Checker{
+		registries: map[string]Registry{"marketplace": marketplace, "openvsx": openvsx},
+		cache:      map[string]latestResult{},
+	}
+}
+
+// Check lists the installed extensions of an editor with the newest version on each
+// registry that suits the editor's VS Code version, platform and the extension's release
+// channel. An extension is outdated when either registry has a newer such version.
+func (c *Checker) Check(profile models.EditorProfile, opts Options) (*models.OutdatedReport, error) {
+	if opts.Platform == "" {
+		opts.Platform = download.CurrentPlatform()
+	}
+
+	installed, err := installedExtensions(profile.ExtensionsDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.ExtensionIDs) > 0 {
+		wanted := map[string]bool{}
+		for _, id := range opts.ExtensionIDs {
+			wanted[strings.ToLower(id)] = true
+		}
+		var selected []installedExtension
+		for _, ext := range installed {
+			if wanted[ext.id] {
+				selected = append(selected, ext)
+				delete(wanted, ext.id)
+			}
+		}
+		for id := range wanted {
+			return nil, fmt.Errorf("%s is not installed in %s", id, profile.Name)
+		}
+		installed = selected
+	}
+
+	report := &models.OutdatedReport{
+		Editor:        profile.ID,
+		EditorName:    profile.Name,
+		EngineVersion: opts.EngineVersion,
+		Platform:      opts.Platform,
+		Extensions:    make([]models.OutdatedExtension, len(installed)),
+		CheckedAt:     time.Now(),
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < checkWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				report.Extensions[i] = c.checkExtension(installed[i], opts)
+			}
+		}()
+	}
+	for i := range installed {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, ext := range report.Extensions {
+		if ext.Outdated {
+			report.OutdatedCount++
+		}
+	}
+	log.Printf("[Update] %d of %d extensions outdated in %s", report.OutdatedCount, len(installed), profile.Name)
+	return report, nil
+}
+
+// checkExtension compares one installed extension with both registries
+func (c *Checker) checkExtension(ext installedExtension, opts Options) models.OutdatedExtension {
+	result := models.OutdatedExtension{
+		ExtensionID:       ext.id,
+		InstalledVersion:  ext.version,
+		PreReleaseChannel: ext.preRelease,
+	}
+
+	// The Marketplace goes first so it wins a tie
+	for _, source := range []string{"marketplace", "openvsx"} {
+		latest := c.latest(source, ext, opts)
+		if latest.err != nil {
+			if source == "marketplace" {
+				result.MarketplaceError = latest.err.Error()
+			} else {
+				result.OpenVSXError = latest.err.Error()
+			}
+			continue
+		}
+		if latest.version == nil {
+			continue
+		}
+		if source == "marketplace" {
+			result.MarketplaceLatest = latest.version.Version
+		} else {
+			result.OpenVSXLatest = latest.version.Version
+		}
+
+		if editor.CompareVersions(latest.version.Version, ext.version) <= 0 {
+			continue
+		}
+		if result.UpdateVersion != "" && editor.CompareVersions(latest.version.Version, result.UpdateVersion) <= 0 {
+			continue
+		}
+		result.Outdated = true
+		result.UpdateVersion = latest.version.Version
+		result.UpdateSource = source
+		result.UpdatePlatform = download.UniversalPlatform
+		if containsPlatform(latest.version.TargetPlatforms, opts.Platform) {
+			result.UpdatePlatform = opts.Platform
+		}
+	}
+	return result
+}
+
+// latest returns the newest suitable version of an extension on a registry, cached
+func (c *Checker) latest(source string, ext installedExtension, opts Options) latestResult {
+	key := strings.Join([]string{source, ext.id, ext.version, fmt.Sprint(ext.preRelease), opts.EngineVersion, opts.Platform}, "|")
+	c.mu.Lock()
+	cached, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok {
+		return cached
+	}
+
+	version, err := LatestCompatible(c.registries[source], ext.id, ext.version, opts.EngineVersion, opts.Platform, ext.preRelease)
+	result := latestResult{version: version, err: err}
+	c.mu.Lock()
+	c.cache[key] = result
+	c.mu.Unlock()
+	return result
+}
+
+// LatestCompatible returns the newest version of an extension on a registry that is built
+// for platform (or universal), supports the editor's VS Code version and is a release
+// unless preRelease is set. The search stops at the installed version, which is returned
+// when nothing newer fits. It returns nil when no listed version fits.
+func LatestCompatible(registry Registry, extensionID, installedVersion, engineVersion, platform string, preRelease bool) (*models.ExtensionVersion, error) {
+	versions, err := registry.ListVersions(extensionID)
+	if err != nil {
+		return nil, err
+	}
+	detailer, needsDetails := registry.(VersionDetailer)
+
+	looked := 0
+	for _, v := range versions {
+		if installedVersion != "" && editor.CompareVersions(v.Version, installedVersion) <= 0 {
+			return &models.ExtensionVersion{Version: installedVersion}, nil
+		}
+
+		if needsDetails {
+			if looked == maxDetailedCandidates {
+				break
+			}
+			looked++
+			details, err := versionDetails(detailer, extensionID, v.Version, platform)
+			if err != nil {
+				log.Printf("[Update] Skipping %s@%s: %v", extensionID, v.Version, err)
+				continue
+			}
+			v = *details
+		}
+
+		if v.PreRelease && !preRelease {
+			continue
+		}
+		if len(v.TargetPlatforms) > 0 && !containsPlatform(v.TargetPlatforms, platform) {
+			continue
+		}
+		if !editor.EngineCompatible(v.EngineVSCode, engineVersion) {
+			continue
+		}
+		return &v, nil
+	}
+	return nil, nil
+}
+
+// versionDetails looks up a version built for platform, falling back to its universal package
+func versionDetails(detailer VersionDetailer, extensionID, version, platform string) (*models.ExtensionVersion, error) {
+	if platform != "" && platform != download.UniversalPlatform {
+		if details, err := detailer.VersionDetails(extensionID, version, platform); err == nil {
+			return details, nil
+		}
+	}
+	return detailer.VersionDetails(extensionID, version, "")
+}
+
+// containsPlatform reports whether platforms lists platform
+func containsPlatform(platforms []string, platform string) bool {
+	for _, p := range platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// installedExtensions lists the extensions in an extensions directory, sorted by ID, with
+// the release channel recorded in extensions.json
+func installedExtensions(extensionsDir string) ([]installedExtension, error) {
+	versions, err := editor.InstalledVersions(extensionsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := map[string]bool{}
+	if index, err := editor.ReadExtensionsIndex(extensionsDir); err == nil {
+		for _, entry := range index {
+			preRelease, _ := entry.Metadata["preRelease"].(bool)
+			preReleaseVersion, _ := entry.Metadata["isPreReleaseVersion"].(bool)
+			if preRelease || preReleaseVersion {
+				channels[strings.ToLower(entry.Identifier.ID)] = true
+			}
+		}
+	}
+
+	installed := make([]installedExtension, 0, len(versions))
+	for id, version := range versions {
+		installed = append(installed, installedExtension{id: id, version: version, preRelease: channels[id]})
+	}
+	sort.Slice(installed, func(i, j int) bool { return installed[i].id < installed[j].id })
+	return installed, nil
+}
+
+// UpdateOptions controls how Update installs new versions
+type UpdateOptions struct {
+	EngineVersion   string // VS Code version of the editor; empty skips the package's engine check
+	Native          bool   // unpack the VSIX even when the editor has a CLI
+	AllowSuspicious bool   // install updates of extensions classified as Suspicious
+
+	// Review compares each update with the installed version before installing it. An
+	// update whose risk is medium or higher, or that cannot be compared, is only installed
+	// if Approve returns true; a nil Approve refuses it.
+	Review  bool
+	Approve func(ext models.OutdatedExtension, diff *models.PackageDiff) bool
+}
+
+// Update installs the available update of each outdated extension. Each extension is
+// validated first: Malicious extensions are refused and Suspicious ones skipped unless
+// allowed. The new package is downloaded, verified to be the expected extension, version
+// and VS Code engine, optionally reviewed against the installed version, and then
+// installed natively or through the editor CLI.
+func Update(profile models.EditorProfile, outdated []models.OutdatedExtension, opts UpdateOptions, fetcher Fetcher, validator Validator) *models.UpdateReport {
+	report := &models.UpdateReport{
+		Editor:    profile.ID,
+		Results:   []models.UpdateResult{},
+		UpdatedAt: time.Now(),
+	}
+
+	for _, ext := range outdated {
+		if !ext.Outdated {
+			continue
+		}
+		result := models.UpdateResult{
+			ExtensionID: ext.ExtensionID,
+			FromVersion: ext.InstalledVersion,
+			ToVersion:   ext.UpdateVersion,
+			Source:      ext.UpdateSource,
+		}
+
+		status, err := updateExtension(profile, ext, opts, fetcher, validator, &result)
+		result.Status = status
+		if err != nil {
+			result.Error = err.Error()
+		}
+		switch status {
+		case models.UpdateStatusUpdated:
+			report.UpdatedCount++
+		case models.UpdateStatusSkipped:
+			report.SkippedCount++
+		default:
+			report.FailedCount++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+// updateExtension validates, downloads, verifies and installs one update
+func updateExtension(profile models.EditorProfile, ext models.OutdatedExtension, opts UpdateOptions, fetcher Fetcher, validator Validator, result *models.UpdateResult) (models.UpdateStatus, error) {
+	validation, err := validator.ValidateExtension(ext.ExtensionID)
+	if err != nil {
+		return models.UpdateStatusFailed, fmt.Errorf("validation failed: %w", err)
+	}
+	result.TrustLevel = validation.TrustLevel
+	switch validation.TrustLevel {
+	case models.TrustLevelMalicious:
+		return models.UpdateStatusFailed, fmt.Errorf("refusing to install a Malicious extension")
+	case models.TrustLevelSuspicious:
+		if !opts.AllowSuspicious {
+			return models.UpdateStatusSkipped, fmt.Errorf("extension is Suspicious; review it with 'vsynx validate %s'", ext.ExtensionID)
+		}
+	}
+
+	info, err := fetcher.Resolve(ext.ExtensionID, ext.UpdateVersion, ext.UpdateSource, ext.UpdatePlatform)
+	if err != nil {
+		return models.UpdateStatusFailed, err
+	}
+	dir, err := os.MkdirTemp("", "vsynx-update-")
+	if err != nil {
+		return models.UpdateStatusFailed, fmt.Errorf("failed to create download directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := fetcher.Download(info, dir); err != nil {
+		return models.UpdateStatusFailed, err
+	}
+
+	data, err := os.ReadFile(info.File)
+	if err != nil {
+		return models.UpdateStatusFailed, fmt.Errorf("failed to read download: %w", err)
+	}
+	pkg, err := editor.ReadVSIX(data)
+	if err != nil {
+		return models.UpdateStatusFailed, err
+	}
+	if !strings.EqualFold(pkg.ID(), ext.ExtensionID) {
+		return models.UpdateStatusFailed, fmt.Errorf("package contains %s, expected %s", pkg.ID(), ext.ExtensionID)
+	}
+	if pkg.Version != ext.UpdateVersion {
+		return models.UpdateStatusFailed, fmt.Errorf("package is version %s, expected %s", pkg.Version, ext.UpdateVersion)
+	}
+	if !editor.EngineCompatible(pkg.EngineVSCode, opts.EngineVersion) {
+		return models.UpdateStatusFailed, fmt.Errorf("package requires VS Code %s, editor is %s", pkg.EngineVSCode, opts.EngineVersion)
+	}
+	result.SHA256 = pkg.SHA256
+
+	if opts.Review {
+		result.Review = reviewUpdate(profile, ext, data)
+		if result.Review.RequiresApproval && (opts.Approve == nil || !opts.Approve(ext, result.Review)) {
+			return models.UpdateStatusSkipped, fmt.Errorf("update not approved (risk %s)", result.Review.Risk)
+		}
+	}
+
+	if opts.Native || profile.CLICommand == "" {
+		result.Method = "native"
+		if _, err := editor.InstallVSIX(profile, data, "gallery", ext.ExtensionID); err != nil {
+			return models.UpdateStatusFailed, err
+		}
+	} else {
+		// The CLI installs the verified file, not whatever the gallery serves next
+		result.Method = profile.CLICommand
+		if err := editor.InstallExtensionViaCLI(profile.CLICommand, info.File); err != nil {
+			return models.UpdateStatusFailed, err
+		}
+	}
+
+	log.Printf("[Update] Updated %s %s -> %s in %s (%s)", ext.ExtensionID, ext.InstalledVersion, pkg.Version, profile.Name, result.Method)
+	return models.UpdateStatusUpdated, nil
+}
+
+// reviewUpdate compares the installed extension folder with the new package, so the diff
+// covers what is actually installed whichever registry it came from. Versions that cannot
+// be compared require approval.
+func reviewUpdate(profile models.EditorProfile, ext models.OutdatedExtension, newData []byte) *models.PackageDiff {
+	diff, err := func() (*models.PackageDiff, error) {
+		folder, err := editor.InstalledExtensionFolder(profile.ExtensionsDir, ext.ExtensionID)
+		if err != nil {
+			return nil, err
+		}
+		return vsixdiff.CompareInstalled(folder, newData)
+	}()
+	if err != nil {
+		log.Printf("[Update] Could not review %s %s -> %s: %v", ext.ExtensionID, ext.InstalledVersion, ext.UpdateVersion, err)
+		return &models.PackageDiff{
+			ExtensionID:      ext.ExtensionID,
+			OldVersion:       ext.InstalledVersion,
+			NewVersion:       ext.UpdateVersion,
+			Changes:          []models.PackageChange{},
+			Risk:             models.RiskUnknown,
+			RequiresApproval: true,
+			Error:            fmt.Sprintf("could not compare with the installed version: %v", err),
+		}
+	}
+	return diff
+}
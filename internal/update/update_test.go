@@ -0,0 +1,316 @@
+package update
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// buildTestVSIX creates a minimal VSIX package in memory
+func buildTestVSIX(t *testing.T, extensionID, version, engine string) []byte {
+	t.Helper()
+	return buildTestVSIXWithManifest(t, extensionID, version, engine, "")
+}
+
+// buildTestVSIXWithManifest creates a minimal VSIX package whose package.json has extra fields
+func buildTestVSIXWithManifest(t *testing.T, extensionID, version, engine, extraFields string) []byte {
+	t.Helper()
+	publisher, name, _ := strings.Cut(extensionID, ".")
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string]string{
+		"extension.vsixmanifest": fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<PackageManifest Version="2.0.0" xmlns="http://schemas.microsoft.com/developer/vsx-schema/2011">
+  <Metadata>
+    <Identity Language="en-US" Id="%s" Version="%s" Publisher="%s" />
+  </Metadata>
+</PackageManifest>`, name, version, publisher),
+		"extension/package.json": fmt.Sprintf(`{"publisher": %q, "name": %q, "version": %q, "engines": {"vscode": %q}%s}`, publisher, name, version, engine, extraFields),
+	}
+	for fileName, content := range files {
+		f, err := w.Create(fileName)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// fakeRegistry lists fixed versions
+type fakeRegistry map[string][]models.ExtensionVersion
+
+func (r fakeRegistry) ListVersions(extensionID string) ([]models.ExtensionVersion, error) {
+	versions, ok := r[extensionID]
+	if !ok {
+		return nil, fmt.Errorf("extension not found: %s", extensionID)
+	}
+	return versions, nil
+}
+
+// fakeDetailRegistry lists bare versions and reports their details one at a time
+type fakeDetailRegistry struct {
+	versions map[string][]models.ExtensionVersion
+	lookups  int
+}
+
+func (r *fakeDetailRegistry) ListVersions(extensionID string) ([]models.ExtensionVersion, error) {
+	var bare []models.ExtensionVersion
+	for _, v := range r.versions[extensionID] {
+		bare = append(bare, models.ExtensionVersion{Version: v.Version})
+	}
+	return bare, nil
+}
+
+func (r *fakeDetailRegistry) VersionDetails(extensionID, version, targetPlatform string) (*models.ExtensionVersion, error) {
+	r.lookups++
+	for _, v := range r.versions[extensionID] {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+func TestLatestCompatible(t *testing.T) {
+	registry := fakeRegistry{"pub.ext": {
+		{Version: "4.0.0", EngineVSCode: "^1.95.0"},
+		{Version: "3.1.0", PreRelease: true, EngineVSCode: "^1.80.0"},
+		{Version: "3.0.0", TargetPlatforms: []string{"win32-x64"}},
+		{Version: "2.0.0", EngineVSCode: "^1.80.0"},
+		{Version: "1.0.0"},
+	}}
+
+	tests := []struct {
+		name       string
+		installed  string
+		preRelease bool
+		want       string
+	}{
+		{"release channel skips engine, platform and pre-release", "1.0.0", false, "2.0.0"},
+		{"pre-release channel", "1.0.0", true, "3.1.0"},
+		{"up to date", "2.0.0", false, "2.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LatestCompatible(registry, "pub.ext", tt.installed, "1.86.0", "linux-x64", tt.preRelease)
+			if err != nil {
+				t.Fatalf("LatestCompatible() error: %v", err)
+			}
+			if got == nil || got.Version != tt.want {
+				t.Errorf("LatestCompatible() = %+v, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := LatestCompatible(registry, "pub.missing", "1.0.0", "", "linux-x64", false); err == nil {
+		t.Error("expected error for unknown extension")
+	}
+}
+
+func TestLatestCompatibleLooksUpDetailsLazily(t *testing.T) {
+	registry := &fakeDetailRegistry{versions: map[string][]models.ExtensionVersion{"pub.ext": {
+		{Version: "3.0.0", PreRelease: true},
+		{Version: "2.0.0", EngineVSCode: "^1.80.0"},
+		{Version: "1.0.0"},
+	}}}
+
+	got, err := LatestCompatible(registry, "pub.ext", "1.0.0", "1.86.0", "universal", false)
+	if err != nil || got == nil || got.Version != "2.0.0" {
+		t.Fatalf("LatestCompatible() = %+v, %v; want 2.0.0", got, err)
+	}
+	if registry.lookups != 2 {
+		t.Errorf("looked up %d versions, want 2", registry.lookups)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	index := []models.ExtensionIndexEntry{
+		{Identifier: models.ExtensionIdentifier{ID: "pub.old"}, Version: "1.0.0", RelativeLocation: "pub.old-1.0.0"},
+		{Identifier: models.ExtensionIdentifier{ID: "pub.beta"}, Version: "2.0.0-beta", RelativeLocation: "pub.beta-2.0.0-beta",
+			Metadata: map[string]any{"preRelease": true}},
+		{Identifier: models.ExtensionIdentifier{ID: "pub.current"}, Version: "5.0.0", RelativeLocation: "pub.current-5.0.0"},
+	}
+	if err := editor.WriteExtensionsIndex(dir, index); err != nil {
+		t.Fatalf("WriteExtensionsIndex() error: %v", err)
+	}
+
+	marketplace := fakeRegistry{
+		"pub.old":     {{Version: "1.5.0"}, {Version: "1.0.0"}},
+		"pub.beta":    {{Version: "2.1.0-beta", PreRelease: true}, {Version: "2.0.0-beta", PreRelease: true}},
+		"pub.current": {{Version: "5.0.0"}},
+	}
+	openvsx := fakeRegistry{
+		"pub.old": {{Version: "2.0.0"}, {Version: "1.0.0"}},
+	}
+
+	checker := NewChecker(marketplace, openvsx)
+	report, err := checker.Check(models.EditorProfile{ID: models.EditorVSCode, Name: "VS Code", ExtensionsDir: dir}, Options{Platform: "linux-x64"})
+	if err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+	if report.OutdatedCount != 2 || len(report.Extensions) != 3 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	byID := map[string]models.OutdatedExtension{}
+	for _, ext := range report.Extensions {
+		byID[ext.ExtensionID] = ext
+	}
+	old := byID["pub.old"]
+	if old.MarketplaceLatest != "1.5.0" || old.OpenVSXLatest != "2.0.0" || old.UpdateVersion != "2.0.0" || old.UpdateSource != "openvsx" {
+		t.Errorf("pub.old = %+v", old)
+	}
+	beta := byID["pub.beta"]
+	if !beta.PreReleaseChannel || beta.UpdateVersion != "2.1.0-beta" || beta.OpenVSXError == "" {
+		t.Errorf("pub.beta = %+v", beta)
+	}
+	if byID["pub.current"].Outdated {
+		t.Errorf("pub.current = %+v", byID["pub.current"])
+	}
+
+	if _, err := checker.Check(models.EditorProfile{Name: "VS Code", ExtensionsDir: dir}, Options{ExtensionIDs: []string{"pub.none"}}); err == nil {
+		t.Error("expected error for an extension that is not installed")
+	}
+}
+
+// fakeFetcher serves a package from memory
+type fakeFetcher struct {
+	t        *testing.T
+	version  string // version inside the served package
+	engine   string
+	packages map[string][]byte // packages served by requested version instead
+}
+
+func (f *fakeFetcher) Resolve(extensionID, version, source, targetPlatform string) (*models.DownloadInfo, error) {
+	return &models.DownloadInfo{ExtensionID: extensionID, Version: version, Source: source, TargetPlatform: targetPlatform, URL: "memory"}, nil
+}
+
+func (f *fakeFetcher) Download(info *models.DownloadInfo, dir string) error {
+	info.File = filepath.Join(dir, "package.vsix")
+	if data, ok := f.packages[info.Version]; ok {
+		return os.WriteFile(info.File, data, 0644)
+	}
+	return os.WriteFile(info.File, buildTestVSIX(f.t, info.ExtensionID, f.version, f.engine), 0644)
+}
+
+type fakeValidator struct{ trust models.TrustLevel }
+
+func (v fakeValidator) ValidateExtension(extensionID string) (*models.ValidationResult, error) {
+	return &models.ValidationResult{ExtensionID: extensionID, TrustLevel: v.trust}, nil
+}
+
+func TestUpdate(t *testing.T) {
+	outdated := []models.OutdatedExtension{{
+		ExtensionID:      "pub.ext",
+		InstalledVersion: "1.0.0",
+		Outdated:         true,
+		UpdateVersion:    "2.0.0",
+		UpdateSource:     "marketplace",
+		UpdatePlatform:   "universal",
+	}}
+	opts := UpdateOptions{EngineVersion: "1.86.0"}
+
+	tests := []struct {
+		name    string
+		trust   models.TrustLevel
+		version string
+		engine  string
+		want    models.UpdateStatus
+	}{
+		{"legitimate", models.TrustLevelLegitimate, "2.0.0", "^1.80.0", models.UpdateStatusUpdated},
+		{"suspicious", models.TrustLevelSuspicious, "2.0.0", "^1.80.0", models.UpdateStatusSkipped},
+		{"malicious", models.TrustLevelMalicious, "2.0.0", "^1.80.0", models.UpdateStatusFailed},
+		{"wrong version", models.TrustLevelLegitimate, "2.0.1", "^1.80.0", models.UpdateStatusFailed},
+		{"incompatible engine", models.TrustLevelLegitimate, "2.0.0", "^1.90.0", models.UpdateStatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := models.EditorProfile{ID: models.EditorCursor, Name: "Cursor", ExtensionsDir: t.TempDir()}
+			fetcher := &fakeFetcher{t: t, version: tt.version, engine: tt.engine}
+
+			report := Update(profile, outdated, opts, fetcher, fakeValidator{trust: tt.trust})
+			if len(report.Results) != 1 || report.Results[0].Status != tt.want {
+				t.Fatalf("results = %+v, want %s", report.Results, tt.want)
+			}
+
+			versions, _ := editor.InstalledVersions(profile.ExtensionsDir)
+			installed := versions["pub.ext"] == "2.0.0"
+			if installed != (tt.want == models.UpdateStatusUpdated) {
+				t.Errorf("installed versions = %v", versions)
+			}
+			if tt.want == models.UpdateStatusUpdated && report.Results[0].Method != "native" {
+				t.Errorf("method = %s, want native for an editor without CLI", report.Results[0].Method)
+			}
+		})
+	}
+}
+
+func TestUpdateReview(t *testing.T) {
+	outdated := []models.OutdatedExtension{{
+		ExtensionID:      "pub.ext",
+		InstalledVersion: "1.0.0",
+		Outdated:         true,
+		UpdateVersion:    "2.0.0",
+		UpdateSource:     "openvsx",
+		UpdatePlatform:   "universal",
+	}}
+	harmless := buildTestVSIX(t, "pub.ext", "2.0.0", "^1.80.0")
+	risky := buildTestVSIXWithManifest(t, "pub.ext", "2.0.0", "^1.80.0", `, "activationEvents": ["*"]`)
+
+	tests := []struct {
+		name     string
+		packages map[string][]byte
+		approve  bool
+		want     models.UpdateStatus
+		risk     models.RiskLevel
+		asked    bool
+	}{
+		{"harmless", map[string][]byte{"2.0.0": harmless}, false, models.UpdateStatusUpdated, models.RiskNone, false},
+		{"activation on startup refused", map[string][]byte{"2.0.0": risky}, false, models.UpdateStatusSkipped, models.RiskHigh, true},
+		{"activation on startup approved", map[string][]byte{"2.0.0": risky}, true, models.UpdateStatusUpdated, models.RiskHigh, true},
+		// The registry's 1.0.0 matches the update, but the installed copy does not
+		{"registry copy differs from installed", map[string][]byte{"1.0.0": buildTestVSIXWithManifest(t, "pub.ext", "1.0.0", "^1.80.0", `, "activationEvents": ["*"]`), "2.0.0": risky}, false, models.UpdateStatusSkipped, models.RiskHigh, true},
+		{"installed version unavailable", map[string][]byte{"2.0.0": harmless}, false, models.UpdateStatusSkipped, models.RiskUnknown, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := models.EditorProfile{ID: models.EditorCursor, Name: "Cursor", ExtensionsDir: t.TempDir()}
+			if tt.name != "installed version unavailable" {
+				if _, err := editor.InstallVSIX(profile, buildTestVSIX(t, "pub.ext", "1.0.0", "^1.80.0"), "gallery", "pub.ext"); err != nil {
+					t.Fatalf("Failed to install 1.0.0: %v", err)
+				}
+			}
+			fetcher := &fakeFetcher{t: t, packages: tt.packages}
+
+			asked := false
+			opts := UpdateOptions{EngineVersion: "1.86.0", Review: true, Approve: func(ext models.OutdatedExtension, diff *models.PackageDiff) bool {
+				asked = true
+				return tt.approve
+			}}
+			report := Update(profile, outdated, opts, fetcher, fakeValidator{trust: models.TrustLevelLegitimate})
+			if len(report.Results) != 1 {
+				t.Fatalf("results = %+v", report.Results)
+			}
+			result := report.Results[0]
+			if result.Status != tt.want || result.Review == nil || result.Review.Risk != tt.risk {
+				t.Errorf("result = %+v (review %+v), want %s with risk %s", result, result.Review, tt.want, tt.risk)
+			}
+			if asked != tt.asked {
+				t.Errorf("approval asked = %v, want %v", asked, tt.asked)
+			}
+		})
+	}
+}
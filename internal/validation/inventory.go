@@ -0,0 +1,101 @@
+package validation
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// BuildInventory scans every given editor and builds a matrix of extension ID by editor.
+// Editors whose extensions directory does not exist are skipped. With checkTrust set,
+// each distinct extension is validated once to fill in its trust level.
+func (s *Scanner) BuildInventory(profiles []models.EditorProfile, checkTrust bool) (*models.Inventory, error) {
+	log.Printf("[Scanner] Building inventory for %d editors (trust=%v)", len(profiles), checkTrust)
+
+	scans := map[models.EditorType][]models.InstalledExtension{}
+	var editors []models.EditorType
+	for _, profile := range profiles {
+		if !editor.CheckEditorStatus(profile).IsAvailable {
+			continue
+		}
+		extensions, err := s.ScanEditorExtensions(profile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", profile.ID, err)
+		}
+		editors = append(editors, profile.ID)
+		scans[profile.ID] = extensions
+	}
+
+	inventory := newInventory(editors, scans)
+
+	if checkTrust {
+		for i := range inventory.Rows {
+			row := &inventory.Rows[i]
+			result, err := s.validator.ValidateExtension(row.ExtensionID)
+			if err != nil {
+				row.TrustLevel = models.TrustLevelUnknown
+				continue
+			}
+			row.TrustLevel = result.TrustLevel
+		}
+		inventory.TrustChecked = true
+	}
+
+	return inventory, nil
+}
+
+// newInventory merges per-editor scans into inventory rows sorted by extension ID
+func newInventory(editors []models.EditorType, scans map[models.EditorType][]models.InstalledExtension) *models.Inventory {
+	inventory := &models.Inventory{
+		Editors:     editors,
+		Rows:        []models.InventoryRow{},
+		GeneratedAt: time.Now(),
+	}
+	if inventory.Editors == nil {
+		inventory.Editors = []models.EditorType{}
+	}
+
+	rows := map[string]*models.InventoryRow{}
+	for _, editorID := range editors {
+		for _, ext := range scans[editorID] {
+			key := strings.ToLower(ext.ID)
+			row, ok := rows[key]
+			if !ok {
+				row = &models.InventoryRow{
+					ExtensionID: ext.ID,
+					Editors:     map[models.EditorType]models.InventoryCell{},
+				}
+				rows[key] = row
+			}
+			row.Editors[editorID] = models.InventoryCell{Version: ext.Version, State: ext.State()}
+		}
+	}
+
+	for _, row := range rows {
+		versions := map[string]bool{}
+		for _, cell := range row.Editors {
+			versions[cell.Version] = true
+		}
+		row.VersionSkew = len(versions) > 1
+		row.SingleEditor = len(row.Editors) == 1 && len(editors) > 1
+
+		if row.VersionSkew {
+			inventory.VersionSkewCount++
+		}
+		if row.SingleEditor {
+			inventory.SingleEditorCount++
+		}
+		inventory.Rows = append(inventory.Rows, *row)
+	}
+
+	sort.Slice(inventory.Rows, func(i, j int) bool {
+		return strings.ToLower(inventory.Rows[i].ExtensionID) < strings.ToLower(inventory.Rows[j].ExtensionID)
+	})
+	inventory.TotalExtensions = len(inventory.Rows)
+	return inventory
+}
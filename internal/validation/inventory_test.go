@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+func TestNewInventory(t *testing.T) {
+	editors := []models.EditorType{models.EditorVSCode, models.EditorCursor}
+	scans := map[models.EditorType][]models.InstalledExtension{
+		models.EditorVSCode: {
+			{ID: "ms-python.python", Version: "2024.1.0", IsEnabled: true},
+			{ID: "esbenp.prettier-vscode", Version: "10.1.0", IsEnabled: true},
+			{ID: "only.vscode", Version: "1.0.0", IsEnabled: false},
+		},
+		models.EditorCursor: {
+			{ID: "MS-Python.python", Version: "2024.2.0", IsEnabled: true},
+			{ID: "esbenp.prettier-vscode", Version: "10.1.0", IsEnabled: true},
+		},
+	}
+
+	inventory := newInventory(editors, scans)
+
+	if inventory.TotalExtensions != 3 {
+		t.Fatalf("TotalExtensions = %d, want 3", inventory.TotalExtensions)
+	}
+	if inventory.VersionSkewCount != 1 {
+		t.Errorf("VersionSkewCount = %d, want 1", inventory.VersionSkewCount)
+	}
+	if inventory.SingleEditorCount != 1 {
+		t.Errorf("SingleEditorCount = %d, want 1", inventory.SingleEditorCount)
+	}
+
+	rows := map[string]models.InventoryRow{}
+	for _, row := range inventory.Rows {
+		rows[row.ExtensionID] = row
+	}
+
+	python := rows["ms-python.python"]
+	if !python.VersionSkew || len(python.Editors) != 2 {
+		t.Errorf("Expected ms-python.python in both editors with skew, got %+v", python)
+	}
+	if python.Editors[models.EditorCursor].Version != "2024.2.0" {
+		t.Errorf("Cursor version = %s, want 2024.2.0", python.Editors[models.EditorCursor].Version)
+	}
+
+	only := rows["only.vscode"]
+	if !only.SingleEditor || only.Editors[models.EditorVSCode].State != models.InstallStateDisabled {
+		t.Errorf("Expected only.vscode to be a disabled single-editor extension, got %+v", only)
+	}
+
+	if rows["esbenp.prettier-vscode"].VersionSkew {
+		t.Error("Matching versions should not be reported as skew")
+	}
+}
+
+func TestNewInventorySingleEditorScan(t *testing.T) {
+	scans := map[models.EditorType][]models.InstalledExtension{
+		models.EditorVSCode: {{ID: "pub.ext", Version: "1.0.0", IsEnabled: true}},
+	}
+	inventory := newInventory([]models.EditorType{models.EditorVSCode}, scans)
+
+	// With only one editor scanned, nothing is "only in one editor"
+	if inventory.SingleEditorCount != 0 {
+		t.Errorf("SingleEditorCount = %d, want 0", inventory.SingleEditorCount)
+	}
+}
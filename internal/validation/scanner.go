@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/yourusername/secureopenvsx/internal/editor"
 	"github.com/yourusername/secureopenvsx/internal/models"
 )
 
@@ -36,74 +37,28 @@ type packageJSON struct {
 	} `json:"repository"`
 }
 
-// GetExtensionsPath returns the path to the VS Code extensions directory
+// GetExtensionsPath returns the path to the VS Code extensions directory.
+// Every extensions root discovered for VS Code is tried first (including remote server
+// and WSL host installs), then the roots of the other known editors.
 func GetExtensionsPath() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
-	}
-
-	log.Printf("[Scanner] Home directory: %s", homeDir)
-
-	// Default VS Code extensions path
-	var extensionsPath string
-
-	// Check for different editors and locations
-	paths := []string{
-		// Linux/WSL standard locations
-		filepath.Join(homeDir, ".vscode", "extensions"),
-		filepath.Join(homeDir, ".vscode-server", "extensions"),
-		filepath.Join(homeDir, ".vscode-oss", "extensions"),
-		filepath.Join(homeDir, ".vscodium", "extensions"),
-		filepath.Join(homeDir, ".config", "Code", "User", "extensions"),
-		// Windows paths (native)
-		filepath.Join(homeDir, "AppData", "Local", "Programs", "Microsoft VS Code", "resources", "app", "extensions"),
-		// Windows user extensions
-		filepath.Join(homeDir, ".vscode", "extensions"),
-		// macOS
-		filepath.Join(homeDir, "Library", "Application Support", "Code", "User", "extensions"),
-		// WSL accessing Windows paths
-		"/mnt/c/Users/" + getWindowsUsername() + "/.vscode/extensions",
-		"/mnt/c/Users/" + getWindowsUsername() + "/AppData/Local/Programs/Microsoft VS Code/resources/app/extensions",
-	}
-
-	log.Printf("[Scanner] Checking %d possible paths", len(paths))
-	for i, path := range paths {
-		log.Printf("[Scanner] Checking path %d: %s", i+1, path)
-		if info, err := os.Stat(path); err == nil && info.IsDir() {
-			log.Printf("[Scanner] Found extensions directory: %s", path)
-			extensionsPath = path
-			break
-		} else if err != nil {
-			log.Printf("[Scanner] Path does not exist: %s", path)
+	profiles := editor.GetEditorProfiles()
+
+	var checked []string
+	for _, profile := range profiles {
+		discovery := editor.DiscoverEditorRoots(profile)
+		for _, root := range discovery.Roots {
+			log.Printf("[Scanner] Checking %s (%s, %s)", root.Path, profile.ID, root.Source)
+			if root.Exists {
+				log.Printf("[Scanner] Found extensions directory: %s", root.Path)
+				return root.Path, nil
+			}
+			checked = append(checked, root.Path)
 		}
 	}
 
-	if extensionsPath == "" {
-		log.Printf("[Scanner] Could not find VS Code extensions directory in any standard location")
-		return "", fmt.Errorf("could not find VS Code extensions directory. Please use 'Change Path' to select manually. Checked locations: %s, %s, %s",
-			filepath.Join(homeDir, ".vscode", "extensions"),
-			filepath.Join(homeDir, ".vscode-server", "extensions"),
-			filepath.Join(homeDir, ".config", "Code", "User", "extensions"))
-	}
-
-	return extensionsPath, nil
-}
-
-// getWindowsUsername extracts Windows username from WSL home path or returns current user
-func getWindowsUsername() string {
-	// Try to get from environment or current user
-	if username := os.Getenv("USER"); username != "" {
-		return username
-	}
-	if homeDir, err := os.UserHomeDir(); err == nil {
-		// Extract username from /home/username
-		parts := strings.Split(homeDir, string(filepath.Separator))
-		if len(parts) > 2 {
-			return parts[2]
-		}
-	}
-	return "*"
+	log.Printf("[Scanner] Could not find an extensions directory in any known location")
+	return "", fmt.Errorf("could not find VS Code extensions directory. Please use 'Change Path' to select manually. Checked locations: %s",
+		strings.Join(checked, ", "))
 }
 
 // ScanInstalledExtensions scans for all installed extensions
@@ -126,11 +81,42 @@ func (s *Scanner) ScanInstalledExtensions(extensionsPath string) ([]models.Insta
 		}
 	}
 
+	// Known editors also have user-data state (disabled extensions) to apply
+	return s.scanDirectory(extensionsPath, editor.FindProfileByExtensionsDir(extensionsPath))
+}
+
+// ScanEditorExtensions scans an editor's extensions and applies its enabled/disabled state
+func (s *Scanner) ScanEditorExtensions(profile models.EditorProfile) ([]models.InstalledExtension, error) {
+	log.Printf("[Scanner] Scanning extensions for editor: %s", profile.ID)
+	return s.scanDirectory(profile.ExtensionsDir, &profile)
+}
+
+// scanDirectory reads every extension folder in extensionsPath. Folders listed in the
+// .obsolete marker file are reported as pending removal, and if a profile is given its
+// global storage decides which extensions are disabled.
+func (s *Scanner) scanDirectory(extensionsPath string, profile *models.EditorProfile) ([]models.InstalledExtension, error) {
 	entries, err := os.ReadDir(extensionsPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read extensions directory: %w", err)
 	}
 
+	obsolete, err := editor.ReadObsoleteExtensions(extensionsPath)
+	if err != nil {
+		log.Printf("[Scanner] Ignoring unreadable .obsolete file: %v", err)
+		obsolete = map[string]bool{}
+	}
+
+	disabled := map[string]bool{}
+	if profile != nil {
+		ids, err := editor.ReadDisabledExtensions(*profile)
+		if err != nil {
+			log.Printf("[Scanner] Could not read disabled extensions for %s: %v", profile.ID, err)
+		}
+		for _, id := range ids {
+			disabled[strings.ToLower(id.ID)] = true
+		}
+	}
+
 	var extensions []models.InstalledExtension
 	log.Printf("[Scanner] Found %d entries in directory", len(entries))
 
@@ -169,23 +155,77 @@ func (s *Scanner) ScanInstalledExtensions(extensionsPath string) ([]models.Insta
 			lastModified = info.ModTime()
 		}
 
+		extensionID := fmt.Sprintf("%s.%s", pkg.Publisher, pkg.Name)
 		extension := models.InstalledExtension{
-			ID:           fmt.Sprintf("%s.%s", pkg.Publisher, pkg.Name),
-			Path:         extPath,
-			Publisher:    pkg.Publisher,
-			Name:         pkg.Name,
-			Version:      pkg.Version,
-			IsEnabled:    true, // Assume enabled if installed
-			LastModified: lastModified,
+			ID:               extensionID,
+			Path:             extPath,
+			Publisher:        pkg.Publisher,
+			Name:             pkg.Name,
+			Version:          pkg.Version,
+			IsEnabled:        !disabled[strings.ToLower(extensionID)],
+			IsPendingRemoval: editor.IsObsolete(obsolete, entry.Name()),
+			LastModified:     lastModified,
 		}
 
 		extensions = append(extensions, extension)
 	}
 
+	// Report one entry per extension even when several versions are on disk
+	activeFolders, err := editor.ActiveExtensionFolders(extensionsPath)
+	if err != nil {
+		log.Printf("[Scanner] Could not reconcile extensions index: %v", err)
+		activeFolders = map[string]string{}
+	}
+	extensions = selectActiveVersions(extensions, activeFolders)
+
 	log.Printf("[Scanner] Successfully scanned %d extensions", len(extensions))
 	return extensions, nil
 }
 
+// selectActiveVersions collapses multiple installed versions of an extension into the
+// active one (the folder referenced by extensions.json, otherwise the highest version),
+// recording the remaining versions in OtherVersions
+func selectActiveVersions(extensions []models.InstalledExtension, activeFolders map[string]string) []models.InstalledExtension {
+	byID := map[string][]models.InstalledExtension{}
+	order := []string{}
+	for _, ext := range extensions {
+		id := strings.ToLower(ext.ID)
+		if _, seen := byID[id]; !seen {
+			order = append(order, id)
+		}
+		byID[id] = append(byID[id], ext)
+	}
+
+	result := make([]models.InstalledExtension, 0, len(order))
+	for _, id := range order {
+		versions := byID[id]
+		active := -1
+		for i, ext := range versions {
+			if strings.EqualFold(filepath.Base(ext.Path), activeFolders[id]) {
+				active = i
+				break
+			}
+		}
+		if active < 0 {
+			active = 0
+			for i, ext := range versions {
+				if editor.CompareVersions(ext.Version, versions[active].Version) > 0 {
+					active = i
+				}
+			}
+		}
+
+		selected := versions[active]
+		for i, ext := range versions {
+			if i != active {
+				selected.OtherVersions = append(selected.OtherVersions, ext.Version)
+			}
+		}
+		result = append(result, selected)
+	}
+	return result
+}
+
 // AuditExtensions performs a full audit of all installed extensions
 func (s *Scanner) AuditExtensions(extensionsPath string) (*models.AuditReport, error) {
 	log.Printf("[Scanner] Starting audit of extensions at: %s", extensionsPath)
@@ -212,8 +252,17 @@ func (s *Scanner) AuditExtensions(extensionsPath string) (*models.AuditReport, e
 			}
 		}
 
+		result.InstallState = ext.State()
 		report.Results = append(report.Results, *result)
 
+		// Disabled and pending-removal extensions are counted separately from trust levels
+		switch result.InstallState {
+		case models.InstallStateDisabled:
+			report.DisabledCount++
+		case models.InstallStatePendingRemoval:
+			report.PendingRemovalCount++
+		}
+
 		// Update counters
 		switch result.TrustLevel {
 		case models.TrustLevelLegitimate:
@@ -227,7 +276,8 @@ func (s *Scanner) AuditExtensions(extensionsPath string) (*models.AuditReport, e
 		}
 	}
 
-	log.Printf("[Scanner] Audit complete: %d total, %d legitimate, %d suspicious, %d malicious, %d unknown",
-		report.TotalExtensions, report.LegitimateCount, report.SuspiciousCount, report.MaliciousCount, report.UnknownCount)
+	log.Printf("[Scanner] Audit complete: %d total, %d legitimate, %d suspicious, %d malicious, %d unknown, %d disabled, %d pending removal",
+		report.TotalExtensions, report.LegitimateCount, report.SuspiciousCount, report.MaliciousCount, report.UnknownCount,
+		report.DisabledCount, report.PendingRemovalCount)
 	return report, nil
 }
@@ -5,6 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
 )
 
 func TestNewScanner(t *testing.T) {
@@ -98,3 +101,80 @@ func TestAuditExtensionsEmptyDirectory(t *testing.T) {
 		t.Error("AuditTime should be set")
 	}
 }
+
+func TestScanEditorExtensionsInstallState(t *testing.T) {
+	scanner := NewScanner()
+	profile := models.EditorProfile{
+		ID:            models.EditorVSCode,
+		ExtensionsDir: t.TempDir(),
+		UserDataDir:   t.TempDir(),
+	}
+
+	for _, name := range []string{"active", "off", "gone"} {
+		extDir := filepath.Join(profile.ExtensionsDir, "publisher."+name+"-1.0.0")
+		if err := os.MkdirAll(extDir, 0755); err != nil {
+			t.Fatalf("Failed to create extension directory: %v", err)
+		}
+		data, _ := json.Marshal(map[string]string{"publisher": "publisher", "name": name, "version": "1.0.0"})
+		os.WriteFile(filepath.Join(extDir, "package.json"), data, 0644)
+	}
+
+	// Mark one folder for removal and disable another
+	os.WriteFile(filepath.Join(profile.ExtensionsDir, ".obsolete"), []byte(`{"publisher.gone-1.0.0":true}`), 0644)
+	if err := editor.SetExtensionEnabled(profile, "publisher.off", false); err != nil {
+		t.Fatalf("SetExtensionEnabled failed: %v", err)
+	}
+
+	extensions, err := scanner.ScanEditorExtensions(profile)
+	if err != nil {
+		t.Fatalf("ScanEditorExtensions failed: %v", err)
+	}
+
+	states := map[string]models.InstallState{}
+	for _, ext := range extensions {
+		states[ext.ID] = ext.State()
+	}
+
+	expected := map[string]models.InstallState{
+		"publisher.active": models.InstallStateEnabled,
+		"publisher.off":    models.InstallStateDisabled,
+		"publisher.gone":   models.InstallStatePendingRemoval,
+	}
+	for id, want := range expected {
+		if states[id] != want {
+			t.Errorf("State(%s) = %s, want %s", id, states[id], want)
+		}
+	}
+}
+
+func TestScanInstalledExtensionsMultipleVersions(t *testing.T) {
+	scanner := NewScanner()
+	tempDir := t.TempDir()
+
+	for _, version := range []string{"1.2.0", "1.3.0"} {
+		extDir := filepath.Join(tempDir, "publisher.name-"+version)
+		os.MkdirAll(extDir, 0755)
+		data, _ := json.Marshal(map[string]string{"publisher": "publisher", "name": "name", "version": version})
+		os.WriteFile(filepath.Join(extDir, "package.json"), data, 0644)
+	}
+
+	// The index points at the older version, which makes it the active one
+	editor.WriteExtensionsIndex(tempDir, []models.ExtensionIndexEntry{
+		{Identifier: models.ExtensionIdentifier{ID: "publisher.name"}, Version: "1.2.0", RelativeLocation: "publisher.name-1.2.0"},
+	})
+
+	extensions, err := scanner.ScanInstalledExtensions(tempDir)
+	if err != nil {
+		t.Fatalf("ScanInstalledExtensions failed: %v", err)
+	}
+
+	if len(extensions) != 1 {
+		t.Fatalf("Expected 1 extension, got %d", len(extensions))
+	}
+	if extensions[0].Version != "1.2.0" {
+		t.Errorf("Version = %s, want 1.2.0", extensions[0].Version)
+	}
+	if len(extensions[0].OtherVersions) != 1 || extensions[0].OtherVersions[0] != "1.3.0" {
+		t.Errorf("OtherVersions = %v, want [1.3.0]", extensions[0].OtherVersions)
+	}
+}
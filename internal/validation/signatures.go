@@ -0,0 +1,180 @@
+package validation
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/signature"
+)
+
+// DefaultOpenVSXKeyURL is where open-vsx.org publishes its package signing keys
+const DefaultOpenVSXKeyURL = "https://open-vsx.org/api/-/public-key/"
+
+// SetSignatureVerification turns package signature checks on or off. Marketplace
+// signatures must chain to roots; nil uses the system roots.
+func (v *Validator) SetSignatureVerification(enabled bool, roots *x509.CertPool) {
+	v.verifySignatures = enabled
+	v.signatureRoots = roots
+}
+
+// SetOpenVSXKey pins the key OpenVSX signatures are verified with. Without a pinned key,
+// the key named by the registry is only fetched if its URL lies below keyURL, so that the
+// response carrying a signature cannot also supply the key; empty keeps the current URL.
+func (v *Validator) SetOpenVSXKey(key []byte, keyURL string) {
+	v.openvsxKey = key
+	if keyURL != "" {
+		v.openvsxKeyURL = keyURL
+	}
+}
+
+// SetSignatureVerification turns package signature checks on or off for audits
+func (s *Scanner) SetSignatureVerification(enabled bool, roots *x509.CertPool) {
+	s.validator.SetSignatureVerification(enabled, roots)
+}
+
+// SetOpenVSXKey sets the OpenVSX signing key used by audits
+func (s *Scanner) SetOpenVSXKey(key []byte, keyURL string) {
+	s.validator.SetOpenVSXKey(key, keyURL)
+}
+
+// LoadSignatureRoots reads a PEM bundle of trusted roots for Marketplace signatures
+func LoadSignatureRoots(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature roots: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// checkSignatures downloads each registry's latest package and signature and verifies them
+func (v *Validator) checkSignatures(result *models.ValidationResult) []models.SignatureCheck {
+	checks := []models.SignatureCheck{}
+
+	if m := result.MarketplaceData; m != nil {
+		check := signature.Unsigned("marketplace")
+		if m.SignatureURL != "" {
+			check = models.SignatureCheck{Source: "marketplace"}
+			vsix, err := v.marketplaceClient.DownloadExtension(m.DownloadURL)
+			var archive []byte
+			if err == nil {
+				archive, err = v.marketplaceClient.DownloadExtension(m.SignatureURL)
+			}
+			if err != nil {
+				check.Detail = fmt.Sprintf("could not verify: %v", err)
+			} else {
+				check = signature.VerifyMarketplace(vsix, archive, v.signatureRoots)
+			}
+		}
+		check.Version = m.Version
+		checks = append(checks, check)
+	}
+
+	if o := result.OpenVSXData; o != nil {
+		check := signature.Unsigned("openvsx")
+		if o.SignatureURL != "" {
+			check = models.SignatureCheck{Source: "openvsx"}
+			var vsix, sigzip []byte
+			publicKey, err := v.openvsxPublicKey(o.PublicKeyURL)
+			if err == nil {
+				vsix, err = v.openvsxClient.DownloadExtension(o.DownloadURL)
+			}
+			if err == nil {
+				sigzip, err = v.openvsxClient.DownloadExtension(o.SignatureURL)
+			}
+			if err != nil {
+				check.Detail = fmt.Sprintf("could not verify: %v", err)
+			} else {
+				check = signature.VerifyOpenVSX(vsix, sigzip, publicKey)
+			}
+		}
+		check.Version = o.Version
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+// DownloadSignedExtension downloads the Marketplace package described by metadata and
+// verifies its Marketplace signature. Anything short of a verified signature, including
+// a missing signature or an untrusted root, is an error.
+func (v *Validator) DownloadSignedExtension(metadata *models.ExtensionMetadata) ([]byte, string, error) {
+	if metadata.DownloadURL == "" {
+		return nil, "", fmt.Errorf("no download URL available for extension")
+	}
+	if metadata.SignatureURL == "" {
+		return nil, "", fmt.Errorf("the Marketplace publishes no signature for %s %s", metadata.ID, metadata.Version)
+	}
+	data, err := v.marketplaceClient.DownloadExtension(metadata.DownloadURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download extension: %w", err)
+	}
+	archive, err := v.marketplaceClient.DownloadExtension(metadata.SignatureURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download signature: %w", err)
+	}
+	check := signature.VerifyMarketplace(data, archive, v.signatureRoots)
+	if check.Status != models.SignatureMarketplace {
+		return nil, "", fmt.Errorf("signature not verified: %s", check.Detail)
+	}
+	return data, ComputeSHA256(data), nil
+}
+
+// openvsxPublicKey returns the pinned OpenVSX key, or fetches the key the registry names
+// if it is published at the trusted key location
+func (v *Validator) openvsxPublicKey(keyURL string) ([]byte, error) {
+	if len(v.openvsxKey) > 0 {
+		return v.openvsxKey, nil
+	}
+	if keyURL == "" {
+		return nil, fmt.Errorf("registry publishes no public key")
+	}
+	if !strings.HasPrefix(keyURL, v.openvsxKeyURL) {
+		return nil, fmt.Errorf("public key %s is not published at the trusted key location %s", keyURL, v.openvsxKeyURL)
+	}
+	return v.openvsxClient.DownloadExtension(keyURL)
+}
+
+// LoadOpenVSXKey reads a pinned OpenVSX signing key (PEM or base64)
+func LoadOpenVSXKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenVSX key: %w", err)
+	}
+	return data, nil
+}
+
+// applySignatureChecks records signature checks on a result and adjusts its trust level:
+// an invalid signature is malicious and an unsigned package cannot be legitimate
+func applySignatureChecks(result *models.ValidationResult, checks []models.SignatureCheck) {
+	result.SignatureChecks = checks
+	result.SignatureStatus = signature.Combine(checks)
+
+	switch result.SignatureStatus {
+	case models.SignatureMarketplace:
+		result.Differences = append(result.Differences, "✓ Package signed by Microsoft Marketplace")
+	case models.SignatureOpenVSX:
+		result.Differences = append(result.Differences, "✓ Package signed by OpenVSX")
+	case models.SignatureUnsigned:
+		result.Differences = append(result.Differences, "Package is not signed by any registry")
+		if result.TrustLevel == models.TrustLevelLegitimate {
+			result.TrustLevel = models.TrustLevelSuspicious
+			result.Recommendation = "Warning: metadata matches but the package is not signed - verify before use"
+		}
+	case models.SignatureInvalid:
+		for _, check := range checks {
+			if check.Status == models.SignatureInvalid {
+				result.Differences = append(result.Differences,
+					fmt.Sprintf("⚠ Invalid %s package signature: %s", check.Source, check.Detail))
+			}
+		}
+		result.TrustLevel = models.TrustLevelMalicious
+		result.Recommendation = "DANGER: package signature verification failed - the package may have been tampered with"
+	}
+}
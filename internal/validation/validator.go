@@ -2,6 +2,7 @@ package validation
 
 import (
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/hex"
 	"fmt"
 	"log"
@@ -17,6 +18,10 @@ import (
 type Validator struct {
 	marketplaceClient *marketplace.Client
 	openvsxClient     *openvsx.Client
+	verifySignatures  bool
+	signatureRoots    *x509.CertPool
+	openvsxKey        []byte // pinned OpenVSX signing key
+	openvsxKeyURL     string // location OpenVSX signing keys are fetched from
 }
 
 // NewValidator creates a new validator instance
@@ -24,11 +29,23 @@ func NewValidator() *Validator {
 	return &Validator{
 		marketplaceClient: marketplace.NewClient(),
 		openvsxClient:     openvsx.NewClient(),
+		openvsxKeyURL:     DefaultOpenVSXKeyURL,
 	}
 }
 
-// ValidateExtension validates an extension by comparing marketplace and OpenVSX metadata
+// ValidateExtension validates an extension by comparing marketplace and OpenVSX metadata.
+// With signature verification enabled, the registry package signatures are checked too.
 func (v *Validator) ValidateExtension(extensionID string) (*models.ValidationResult, error) {
+	result := v.validateMetadata(extensionID)
+	if v.verifySignatures && (result.MarketplaceData != nil || result.OpenVSXData != nil) {
+		applySignatureChecks(result, v.checkSignatures(result))
+		log.Printf("[Validator] Signature status for %s: %s", extensionID, result.SignatureStatus)
+	}
+	return result, nil
+}
+
+// validateMetadata compares marketplace and OpenVSX metadata and classifies the trust level
+func (v *Validator) validateMetadata(extensionID string) *models.ValidationResult {
 	log.Printf("[Validator] Starting validation for extension: %s", extensionID)
 	result := &models.ValidationResult{
 		ExtensionID:    extensionID,
@@ -59,7 +76,7 @@ func (v *Validator) ValidateExtension(extensionID string) (*models.ValidationRes
 	// If both failed, return early
 	if marketplaceErr != nil && openvsxErr != nil {
 		result.Recommendation = "Cannot validate: both sources unavailable"
-		return result, nil
+		return result
 	}
 
 	// If only one source is available, mark as suspicious
@@ -67,21 +84,21 @@ func (v *Validator) ValidateExtension(extensionID string) (*models.ValidationRes
 		result.TrustLevel = models.TrustLevelSuspicious
 		result.Differences = append(result.Differences, "Extension not found in Microsoft Marketplace")
 		result.Recommendation = "Extension only exists in OpenVSX - verify authenticity manually"
-		return result, nil
+		return result
 	}
 
 	if openvsxErr != nil {
 		result.TrustLevel = models.TrustLevelLegitimate
 		result.Differences = append(result.Differences, "Extension not found in OpenVSX")
 		result.Recommendation = "Extension verified from Microsoft Marketplace (OpenVSX unavailable)"
-		return result, nil
+		return result
 	}
 
 	// Compare metadata and classify trust level
 	v.compareMetadata(result, marketplaceData, openvsxData)
 
 	log.Printf("[Validator] Validation complete for %s: %s", extensionID, result.TrustLevel)
-	return result, nil
+	return result
 }
 
 // compareMetadata compares marketplace and OpenVSX metadata and determines trust level
@@ -212,3 +229,41 @@ func (v *Validator) DownloadOfficialExtension(extensionID string) ([]byte, strin
 	hash := ComputeSHA256(data)
 	return data, hash, nil
 }
+
+// DownloadExtensionVersion downloads a specific version of an extension from the given
+// source registry ("marketplace" or "openvsx"). An empty version downloads the latest.
+func (v *Validator) DownloadExtensionVersion(extensionID, version, source string) ([]byte, string, error) {
+	var data []byte
+	var err error
+
+	switch source {
+	case "", "marketplace":
+		if version == "" {
+			return v.DownloadOfficialExtension(extensionID)
+		}
+		var downloadURL string
+		downloadURL, err = marketplace.VSIXPackageURL(extensionID, version)
+		if err != nil {
+			return nil, "", err
+		}
+		data, err = v.marketplaceClient.DownloadExtension(downloadURL)
+	case "openvsx":
+		var metadata *models.ExtensionMetadata
+		if version == "" {
+			metadata, err = v.openvsxClient.FetchMetadata(extensionID)
+		} else {
+			metadata, err = v.openvsxClient.FetchVersionMetadata(extensionID, version)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to fetch metadata: %w", err)
+		}
+		data, err = v.openvsxClient.DownloadExtension(metadata.DownloadURL)
+	default:
+		return nil, "", fmt.Errorf("unknown source registry: %s", source)
+	}
+
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download extension: %w", err)
+	}
+	return data, ComputeSHA256(data), nil
+}
@@ -352,3 +352,86 @@ func TestCompareMetadataURLNormalization(t *testing.T) {
 		t.Errorf("URLs should match after normalization, but got difference: %v", result.Differences)
 	}
 }
+
+func TestApplySignatureChecks(t *testing.T) {
+	tests := []struct {
+		name      string
+		trust     models.TrustLevel
+		checks    []models.SignatureCheck
+		wantTrust models.TrustLevel
+		wantSig   models.SignatureStatus
+	}{
+		{
+			name:  "marketplace signature keeps legitimate",
+			trust: models.TrustLevelLegitimate,
+			checks: []models.SignatureCheck{
+				{Source: "marketplace", Status: models.SignatureMarketplace},
+				{Source: "openvsx", Status: models.SignatureUnsigned},
+			},
+			wantTrust: models.TrustLevelLegitimate,
+			wantSig:   models.SignatureMarketplace,
+		},
+		{
+			name:      "unsigned downgrades legitimate",
+			trust:     models.TrustLevelLegitimate,
+			checks:    []models.SignatureCheck{{Source: "marketplace", Status: models.SignatureUnsigned}},
+			wantTrust: models.TrustLevelSuspicious,
+			wantSig:   models.SignatureUnsigned,
+		},
+		{
+			name:  "invalid signature is malicious",
+			trust: models.TrustLevelLegitimate,
+			checks: []models.SignatureCheck{
+				{Source: "marketplace", Status: models.SignatureMarketplace},
+				{Source: "openvsx", Status: models.SignatureInvalid, Detail: "digest mismatch"},
+			},
+			wantTrust: models.TrustLevelMalicious,
+			wantSig:   models.SignatureInvalid,
+		},
+		{
+			name:      "unverifiable checks leave trust alone",
+			trust:     models.TrustLevelLegitimate,
+			checks:    []models.SignatureCheck{{Source: "marketplace", Detail: "could not verify: timeout"}},
+			wantTrust: models.TrustLevelLegitimate,
+			wantSig:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &models.ValidationResult{TrustLevel: tt.trust}
+			applySignatureChecks(result, tt.checks)
+
+			if result.TrustLevel != tt.wantTrust {
+				t.Errorf("TrustLevel = %s, want %s", result.TrustLevel, tt.wantTrust)
+			}
+			if result.SignatureStatus != tt.wantSig {
+				t.Errorf("SignatureStatus = %q, want %q", result.SignatureStatus, tt.wantSig)
+			}
+			if len(result.SignatureChecks) != len(tt.checks) {
+				t.Errorf("SignatureChecks has %d entries, want %d", len(result.SignatureChecks), len(tt.checks))
+			}
+		})
+	}
+}
+
+func TestOpenVSXPublicKey(t *testing.T) {
+	v := NewValidator()
+
+	// A key named by the registry response outside the trusted location is not fetched
+	if _, err := v.openvsxPublicKey("https://evil.example/key.pem"); err == nil {
+		t.Error("Expected an error for a key outside the trusted key location")
+	}
+	if _, err := v.openvsxPublicKey(""); err == nil {
+		t.Error("Expected an error when no key is published")
+	}
+
+	v.SetOpenVSXKey([]byte("pinned"), "")
+	key, err := v.openvsxPublicKey("https://evil.example/key.pem")
+	if err != nil || string(key) != "pinned" {
+		t.Errorf("openvsxPublicKey() = %q, %v; want the pinned key", key, err)
+	}
+	if v.openvsxKeyURL != DefaultOpenVSXKeyURL {
+		t.Errorf("openvsxKeyURL = %s, want the default", v.openvsxKeyURL)
+	}
+}
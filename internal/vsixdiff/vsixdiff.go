@@ -0,0 +1,468 @@
+package vsixdiff
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+	"github.com/yourusername/secureopenvsx/internal/textdiff"
+)
+
+// packageJSONPath is the extension manifest inside a VSIX
+const packageJSONPath = "extension/package.json"
+
+// Size limits for the files read from a package, so a zip bomb cannot exhaust memory
+const (
+	maxEntrySize = 256 << 20
+	maxTotalSize = 1 << 30
+)
+
+// Minified JavaScript is recognised by its long lines
+const (
+	minifiedMinSize     = 4096
+	minifiedLongLine    = 5000
+	minifiedAverageLine = 300
+)
+
+// nativeExtensions are file extensions of native code
+var nativeExtensions = map[string]bool{
+	".node": true, ".exe": true, ".dll": true, ".so": true, ".dylib": true,
+}
+
+// nativeMagic are the leading bytes of executables: ELF, PE, Mach-O and WebAssembly
+var nativeMagic = [][]byte{
+	[]byte("\x7fELF"),
+	[]byte("MZ"),
+	{0xfe, 0xed, 0xfa, 0xce}, {0xfe, 0xed, 0xfa, 0xcf},
+	{0xce, 0xfa, 0xed, 0xfe}, {0xcf, 0xfa, 0xed, 0xfe},
+	{0xca, 0xfe, 0xba, 0xbe},
+	[]byte("\x00asm"),
+}
+
+// broadActivationEvents start an extension without any user action
+var broadActivationEvents = map[string]bool{"*": true, "onStartupFinished": true}
+
+// manifest holds the package.json fields that are compared
+type manifest struct {
+	Publisher             string                     `json:"publisher"`
+	Main                  string                     `json:"main"`
+	Browser               string                     `json:"browser"`
+	ActivationEvents      []string                   `json:"activationEvents"`
+	Dependencies          map[string]string          `json:"dependencies"`
+	ExtensionDependencies []string                   `json:"extensionDependencies"`
+	ExtensionPack         []string                   `json:"extensionPack"`
+	Contributes           map[string]json.RawMessage `json:"contributes"`
+}
+
+// packageFile summarizes one file of a package
+type packageFile struct {
+	hash     [sha256.Size]byte
+	native   bool
+	minified bool
+}
+
+// vsixContents is a read package
+type vsixContents struct {
+	pkg      *editor.VSIXPackage
+	raw      []byte // package.json as packaged
+	manifest manifest
+	files    map[string]packageFile
+	size     int64 // bytes read so far
+}
+
+// Compare diffs two versions of an extension package. It reports a changed publisher, new
+// activation events, dependencies and contribution points, a changed entry point, and
+// newly added native binaries or minified files, each rated by the risk it adds. An update
+// requires explicit approval when any change is rated medium or higher.
+func Compare(oldData, newData []byte) (*models.PackageDiff, error) {
+	oldPkg, err := readContents(oldData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read old package: %w", err)
+	}
+	newPkg, err := readContents(newData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read new package: %w", err)
+	}
+	return compareContents(oldPkg, newPkg)
+}
+
+// CompareInstalled diffs an installed extension folder, which holds the unpacked extension/
+// folder of its package, with a new package
+func CompareInstalled(folder string, newData []byte) (*models.PackageDiff, error) {
+	oldPkg, err := readFolder(folder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read installed extension: %w", err)
+	}
+	newPkg, err := readContents(newData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read new package: %w", err)
+	}
+	return compareContents(oldPkg, newPkg)
+}
+
+// compareContents diffs two read packages
+func compareContents(oldPkg, newPkg *vsixContents) (*models.PackageDiff, error) {
+	if !strings.EqualFold(oldPkg.pkg.Name, newPkg.pkg.Name) {
+		return nil, fmt.Errorf("packages are different extensions: %s and %s", oldPkg.pkg.ID(), newPkg.pkg.ID())
+	}
+
+	diff := &models.PackageDiff{
+		ExtensionID:  newPkg.pkg.ID(),
+		OldVersion:   oldPkg.pkg.Version,
+		NewVersion:   newPkg.pkg.Version,
+		OldPublisher: oldPkg.pkg.Publisher,
+		NewPublisher: newPkg.pkg.Publisher,
+		Changes:      []models.PackageChange{},
+		ManifestDiff: textdiff.Unified("a/"+packageJSONPath, "b/"+packageJSONPath, string(oldPkg.raw), string(newPkg.raw)),
+	}
+	add := func(kind models.PackageChangeKind, action, detail string, risk models.RiskLevel) {
+		diff.Changes = append(diff.Changes, models.PackageChange{Kind: kind, Action: action, Detail: detail, Risk: risk})
+	}
+
+	if !strings.EqualFold(oldPkg.pkg.Publisher, newPkg.pkg.Publisher) {
+		add(models.ChangePublisher, "changed", fmt.Sprintf("publisher changed from %s to %s", oldPkg.pkg.Publisher, newPkg.pkg.Publisher), models.RiskHigh)
+	}
+
+	oldManifest, newManifest := oldPkg.manifest, newPkg.manifest
+	added, removed := compareLists(oldManifest.ActivationEvents, newManifest.ActivationEvents)
+	for _, event := range added {
+		risk := models.RiskLow
+		if broadActivationEvents[event] {
+			risk = models.RiskHigh
+		}
+		add(models.ChangeActivationEvent, "added", "activates on "+event, risk)
+	}
+	for _, event := range removed {
+		add(models.ChangeActivationEvent, "removed", "no longer activates on "+event, models.RiskNone)
+	}
+
+	for _, name := range sortedKeys(newManifest.Dependencies) {
+		oldVersion, existed := oldManifest.Dependencies[name]
+		switch {
+		case !existed:
+			add(models.ChangeDependency, "added", fmt.Sprintf("new dependency %s@%s", name, newManifest.Dependencies[name]), models.RiskMedium)
+		case oldVersion != newManifest.Dependencies[name]:
+			add(models.ChangeDependency, "changed", fmt.Sprintf("%s %s -> %s", name, oldVersion, newManifest.Dependencies[name]), models.RiskLow)
+		}
+	}
+	for _, name := range sortedKeys(oldManifest.Dependencies) {
+		if _, kept := newManifest.Dependencies[name]; !kept {
+			add(models.ChangeDependency, "removed", "dependency "+name+" removed", models.RiskNone)
+		}
+	}
+
+	added, _ = compareLists(
+		append(oldManifest.ExtensionDependencies, oldManifest.ExtensionPack...),
+		append(newManifest.ExtensionDependencies, newManifest.ExtensionPack...))
+	for _, id := range added {
+		add(models.ChangeExtensionDependency, "added", "installs or requires extension "+id, models.RiskMedium)
+	}
+
+	for _, point := range sortedKeys(newManifest.Contributes) {
+		oldValue, existed := oldManifest.Contributes[point]
+		switch {
+		case !existed:
+			add(models.ChangeContributes, "added", "new contribution point contributes."+point, models.RiskLow)
+		case !sameJSON(oldValue, newManifest.Contributes[point]):
+			add(models.ChangeContributes, "changed", "contributes."+point+" changed", models.RiskLow)
+		}
+	}
+
+	if oldManifest.Main != newManifest.Main {
+		add(models.ChangeEntryPoint, "changed", fmt.Sprintf("main entry point %q -> %q", oldManifest.Main, newManifest.Main), models.RiskMedium)
+	}
+	if oldManifest.Browser != newManifest.Browser {
+		add(models.ChangeEntryPoint, "changed", fmt.Sprintf("browser entry point %q -> %q", oldManifest.Browser, newManifest.Browser), models.RiskMedium)
+	}
+
+	for _, name := range sortedKeys(newPkg.files) {
+		file := newPkg.files[name]
+		old, existed := oldPkg.files[name]
+		switch {
+		case !existed:
+			diff.FilesAdded++
+		case old.hash != file.hash:
+			diff.FilesChanged++
+		}
+		if file.native && (!existed || !old.native) {
+			add(models.ChangeNativeBinary, "added", "native binary "+name, models.RiskHigh)
+		} else if file.native && old.hash != file.hash {
+			add(models.ChangeNativeBinary, "changed", "native binary "+name+" changed", models.RiskHigh)
+		} else if file.minified && (!existed || !old.minified) {
+			add(models.ChangeMinifiedFile, "added", "minified file "+name, models.RiskMedium)
+		}
+	}
+	for name := range oldPkg.files {
+		if _, kept := newPkg.files[name]; !kept {
+			diff.FilesRemoved++
+		}
+	}
+
+	diff.Risk = models.RiskNone
+	for _, change := range diff.Changes {
+		if riskRank(change.Risk) > riskRank(diff.Risk) {
+			diff.Risk = change.Risk
+		}
+	}
+	diff.RequiresApproval = riskRank(diff.Risk) >= riskRank(models.RiskMedium)
+	return diff, nil
+}
+
+// readContents reads the manifest and summarizes the files of a VSIX
+func readContents(data []byte) (*vsixContents, error) {
+	pkg, err := editor.ReadVSIX(data)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid VSIX archive: %w", err)
+	}
+
+	contents := &vsixContents{pkg: pkg, files: map[string]packageFile{}}
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || !strings.HasPrefix(file.Name, "extension/") {
+			continue
+		}
+		// Check the declared size first; the limited read below catches a false one
+		if err := contents.reserve(file.Name, file.UncompressedSize64); err != nil {
+			return nil, err
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", file.Name, err)
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, maxEntrySize+1))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+		if err := contents.add(file.Name, content); err != nil {
+			return nil, err
+		}
+	}
+	return contents, nil
+}
+
+// readFolder reads the manifest and summarizes the files of an installed extension folder,
+// naming them as they are packaged
+func readFolder(folder string) (*vsixContents, error) {
+	contents := &vsixContents{files: map[string]packageFile{}}
+	err := filepath.WalkDir(folder, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(folder, filePath)
+		if err != nil {
+			return err
+		}
+		// The editor keeps the package's extension.vsixmanifest as .vsixmanifest
+		if rel == ".vsixmanifest" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := contents.reserve(rel, uint64(info.Size())); err != nil {
+			return err
+		}
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		return contents.add("extension/"+filepath.ToSlash(rel), content)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if contents.raw == nil {
+		return nil, fmt.Errorf("no package.json in %s", folder)
+	}
+
+	var identity struct {
+		Publisher string `json:"publisher"`
+		Name      string `json:"name"`
+		Version   string `json:"version"`
+	}
+	json.Unmarshal(contents.raw, &identity)
+	contents.pkg = &editor.VSIXPackage{Publisher: identity.Publisher, Name: identity.Name, Version: identity.Version}
+	return contents, nil
+}
+
+// reserve checks that a file of the given size fits the size limits
+func (c *vsixContents) reserve(name string, size uint64) error {
+	if size > maxEntrySize {
+		return fmt.Errorf("%s is larger than %d bytes", name, maxEntrySize)
+	}
+	if uint64(c.size)+size > maxTotalSize {
+		return fmt.Errorf("package contents are larger than %d bytes", maxTotalSize)
+	}
+	return nil
+}
+
+// add records one file of a package, parsing package.json
+func (c *vsixContents) add(name string, content []byte) error {
+	if err := c.reserve(name, uint64(len(content))); err != nil {
+		return err
+	}
+	c.size += int64(len(content))
+	if name == packageJSONPath {
+		c.raw = content
+		if err := json.Unmarshal(content, &c.manifest); err != nil {
+			return fmt.Errorf("invalid package.json: %w", err)
+		}
+	}
+	c.files[name] = packageFile{
+		hash:     sha256.Sum256(content),
+		native:   isNative(name, content),
+		minified: isMinified(name, content),
+	}
+	return nil
+}
+
+// isNative reports whether a file is native code, by extension or executable header
+func isNative(name string, content []byte) bool {
+	if nativeExtensions[strings.ToLower(path.Ext(name))] {
+		return true
+	}
+	for _, magic := range nativeMagic {
+		if bytes.HasPrefix(content, magic) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMinified reports whether a JavaScript file is minified or packed into long lines
+func isMinified(name string, content []byte) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".js", ".mjs", ".cjs":
+	default:
+		return false
+	}
+	if len(content) < minifiedMinSize {
+		return false
+	}
+
+	lines := bytes.Split(content, []byte("\n"))
+	for _, line := range lines {
+		if len(line) > minifiedLongLine {
+			return true
+		}
+	}
+	return len(content)/len(lines) > minifiedAverageLine
+}
+
+// compareLists returns the values only in b (added) and only in a (removed)
+func compareLists(a, b []string) (added, removed []string) {
+	inA := map[string]bool{}
+	for _, v := range a {
+		inA[v] = true
+	}
+	inB := map[string]bool{}
+	for _, v := range b {
+		inB[v] = true
+		if !inA[v] {
+			added = append(added, v)
+		}
+	}
+	for _, v := range a {
+		if !inB[v] {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
+
+// sameJSON reports whether two JSON values are equal regardless of formatting
+func sameJSON(a, b json.RawMessage) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// riskRank orders risk levels
+func riskRank(risk models.RiskLevel) int {
+	switch risk {
+	case models.RiskLow:
+		return 1
+	case models.RiskMedium:
+		return 2
+	case models.RiskHigh:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Fetcher resolves and downloads extension packages; download.Downloader implements it
+type Fetcher interface {
+	Resolve(extensionID, version, source, targetPlatform string) (*models.DownloadInfo, error)
+	Download(info *models.DownloadInfo, dir string) error
+}
+
+// FetchPackage downloads one version of an extension into dir and returns the package
+func FetchPackage(fetcher Fetcher, extensionID, version, source, targetPlatform, dir string) ([]byte, error) {
+	info, err := fetcher.Resolve(extensionID, version, source, targetPlatform)
+	if err != nil {
+		return nil, err
+	}
+	if err := fetcher.Download(info, dir); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(info.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read download: %w", err)
+	}
+	return data, nil
+}
+
+// CompareVersions downloads two versions of an extension from a registry and diffs them
+func CompareVersions(fetcher Fetcher, extensionID, oldVersion, newVersion, source, targetPlatform string) (*models.PackageDiff, error) {
+	dir, err := os.MkdirTemp("", "vsynx-diff-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var packages [2][]byte
+	for i, version := range []string{oldVersion, newVersion} {
+		versionDir := filepath.Join(dir, strconv.Itoa(i))
+		if err := os.Mkdir(versionDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create download directory: %w", err)
+		}
+		if packages[i], err = FetchPackage(fetcher, extensionID, version, source, targetPlatform, versionDir); err != nil {
+			return nil, fmt.Errorf("failed to download %s %s: %w", extensionID, version, err)
+		}
+	}
+	return Compare(packages[0], packages[1])
+}
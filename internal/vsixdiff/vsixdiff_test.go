@@ -0,0 +1,204 @@
+package vsixdiff
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// buildTestVSIX creates a VSIX package in memory from a package.json and extra files
+func buildTestVSIX(t *testing.T, packageJSON map[string]any, files map[string][]byte) []byte {
+	t.Helper()
+	manifest, err := json.MarshalIndent(packageJSON, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal package.json: %v", err)
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	all := map[string][]byte{"extension/package.json": manifest}
+	for name, content := range files {
+		all[name] = content
+	}
+	for name, content := range all {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		f.Write(content)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func basePackage(version string) map[string]any {
+	return map[string]any{
+		"publisher":        "pub",
+		"name":             "ext",
+		"version":          version,
+		"main":             "./out/extension.js",
+		"activationEvents": []string{"onLanguage:python"},
+		"dependencies":     map[string]string{"semver": "^7.5.0"},
+		"contributes":      map[string]any{"commands": []map[string]string{{"command": "ext.run", "title": "Run"}}},
+	}
+}
+
+func findChange(diff *models.PackageDiff, kind models.PackageChangeKind, detail string) *models.PackageChange {
+	for i, change := range diff.Changes {
+		if change.Kind == kind && strings.Contains(change.Detail, detail) {
+			return &diff.Changes[i]
+		}
+	}
+	return nil
+}
+
+func TestCompareHarmlessUpdate(t *testing.T) {
+	source := []byte("function run() {\n  return 1;\n}\n")
+	oldData := buildTestVSIX(t, basePackage("1.0.0"), map[string][]byte{"extension/out/extension.js": source})
+	newPkg := basePackage("1.1.0")
+	newPkg["dependencies"] = map[string]string{"semver": "^7.6.0"}
+	newData := buildTestVSIX(t, newPkg, map[string][]byte{
+		"extension/out/extension.js": append(source, []byte("// fixed\n")...),
+		"extension/README.md":        []byte("# Ext"),
+	})
+
+	diff, err := Compare(oldData, newData)
+	if err != nil {
+		t.Fatalf("Compare() error: %v", err)
+	}
+	if diff.RequiresApproval || diff.Risk != models.RiskLow {
+		t.Errorf("risk = %s (approval %v), want low without approval: %+v", diff.Risk, diff.RequiresApproval, diff.Changes)
+	}
+	if findChange(diff, models.ChangeDependency, "semver ^7.5.0 -> ^7.6.0") == nil {
+		t.Errorf("missing dependency change in %+v", diff.Changes)
+	}
+	if diff.FilesAdded != 1 || diff.FilesChanged != 2 || diff.FilesRemoved != 0 {
+		t.Errorf("files added/changed/removed = %d/%d/%d", diff.FilesAdded, diff.FilesChanged, diff.FilesRemoved)
+	}
+	if !strings.Contains(diff.ManifestDiff, `+  "version": "1.1.0"`) {
+		t.Errorf("manifest diff = %s", diff.ManifestDiff)
+	}
+}
+
+func TestCompareRiskyUpdate(t *testing.T) {
+	oldData := buildTestVSIX(t, basePackage("1.0.0"), nil)
+
+	newPkg := basePackage("1.0.1")
+	newPkg["publisher"] = "pub2"
+	newPkg["activationEvents"] = []string{"onLanguage:python", "*"}
+	newPkg["dependencies"] = map[string]string{"semver": "^7.5.0", "node-fetch": "^3.0.0"}
+	newPkg["extensionDependencies"] = []string{"other.helper"}
+	newPkg["contributes"] = map[string]any{
+		"commands": []map[string]string{{"command": "ext.run", "title": "Run"}},
+		"terminal": map[string]any{"profiles": []string{}},
+	}
+	minified := []byte("var a=" + strings.Repeat("1+", 4000) + "1;")
+	newData := buildTestVSIX(t, newPkg, map[string][]byte{
+		"extension/bin/helper":      append([]byte("\x7fELF"), make([]byte, 64)...),
+		"extension/dist/payload.js": minified,
+	})
+
+	diff, err := Compare(oldData, newData)
+	if err != nil {
+		t.Fatalf("Compare() error: %v", err)
+	}
+	if diff.Risk != models.RiskHigh || !diff.RequiresApproval {
+		t.Errorf("risk = %s (approval %v), want high with approval", diff.Risk, diff.RequiresApproval)
+	}
+
+	want := []struct {
+		kind   models.PackageChangeKind
+		detail string
+		risk   models.RiskLevel
+	}{
+		{models.ChangePublisher, "from pub to pub2", models.RiskHigh},
+		{models.ChangeActivationEvent, "activates on *", models.RiskHigh},
+		{models.ChangeDependency, "new dependency node-fetch", models.RiskMedium},
+		{models.ChangeExtensionDependency, "other.helper", models.RiskMedium},
+		{models.ChangeContributes, "contributes.terminal", models.RiskLow},
+		{models.ChangeNativeBinary, "extension/bin/helper", models.RiskHigh},
+		{models.ChangeMinifiedFile, "extension/dist/payload.js", models.RiskMedium},
+	}
+	for _, w := range want {
+		change := findChange(diff, w.kind, w.detail)
+		if change == nil {
+			t.Errorf("missing %s change %q in %+v", w.kind, w.detail, diff.Changes)
+			continue
+		}
+		if change.Risk != w.risk {
+			t.Errorf("%s change %q risk = %s, want %s", w.kind, w.detail, change.Risk, w.risk)
+		}
+	}
+	if findChange(diff, models.ChangeContributes, "contributes.commands") != nil {
+		t.Error("unchanged contributes.commands reported as changed")
+	}
+}
+
+func TestCompareChangedNativeBinary(t *testing.T) {
+	binary := append([]byte("\x7fELF"), make([]byte, 64)...)
+	oldData := buildTestVSIX(t, basePackage("1.0.0"), map[string][]byte{"extension/bin/helper": binary})
+	unchanged := buildTestVSIX(t, basePackage("1.0.1"), map[string][]byte{"extension/bin/helper": binary})
+	replaced := buildTestVSIX(t, basePackage("1.0.1"), map[string][]byte{"extension/bin/helper": append(binary, 1)})
+
+	diff, err := Compare(oldData, unchanged)
+	if err != nil {
+		t.Fatalf("Compare() error: %v", err)
+	}
+	if findChange(diff, models.ChangeNativeBinary, "extension/bin/helper") != nil {
+		t.Errorf("unchanged native binary reported in %+v", diff.Changes)
+	}
+
+	diff, err = Compare(oldData, replaced)
+	if err != nil {
+		t.Fatalf("Compare() error: %v", err)
+	}
+	change := findChange(diff, models.ChangeNativeBinary, "extension/bin/helper changed")
+	if change == nil || change.Risk != models.RiskHigh {
+		t.Fatalf("missing high-risk native binary change in %+v", diff.Changes)
+	}
+	if diff.Risk != models.RiskHigh || !diff.RequiresApproval {
+		t.Errorf("risk = %s (approval %v), want high with approval", diff.Risk, diff.RequiresApproval)
+	}
+}
+
+func TestCompareDifferentExtensions(t *testing.T) {
+	other := basePackage("1.0.0")
+	other["name"] = "other"
+	if _, err := Compare(buildTestVSIX(t, basePackage("1.0.0"), nil), buildTestVSIX(t, other, nil)); err == nil {
+		t.Error("expected error comparing different extensions")
+	}
+}
+
+func TestCompareRejectsOversizedEntry(t *testing.T) {
+	manifest, _ := json.Marshal(basePackage("2.0.0"))
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, _ := w.Create("extension/package.json")
+	f.Write(manifest)
+	// A stored entry that claims to unpack to far more than the limit
+	raw, err := w.CreateRaw(&zip.FileHeader{
+		Name:               "extension/bomb.bin",
+		Method:             zip.Store,
+		CompressedSize64:   4,
+		UncompressedSize64: 1 << 40,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create raw entry: %v", err)
+	}
+	raw.Write([]byte("boom"))
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+
+	_, err = Compare(buildTestVSIX(t, basePackage("1.0.0"), nil), buf.Bytes())
+	if err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("Compare() error = %v, want a size limit error", err)
+	}
+}
@@ -0,0 +1,89 @@
+package watch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// WebhookNotifier posts events as JSON to a URL
+type WebhookNotifier struct {
+	URL        string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a notifier that posts to url
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify posts the event to the webhook
+func (n *WebhookNotifier) Notify(event models.WatchEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	resp, err := n.httpClient.Post(n.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// DesktopNotifier shows events as desktop notifications using the platform's notifier
+// (notify-send on Linux, osascript on macOS, PowerShell on Windows)
+type DesktopNotifier struct{}
+
+// Notify shows a desktop notification for the event
+func (DesktopNotifier) Notify(event models.WatchEvent) error {
+	title := fmt.Sprintf("vsynx: %s extension detected", event.TrustLevel)
+	message := fmt.Sprintf("%s %s in %s", event.ExtensionID, event.Version, event.Editor)
+	if event.Action == models.WatchActionQuarantined {
+		message += " was quarantined"
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := fmt.Sprintf(`[void][System.Reflection.Assembly]::LoadWithPartialName('System.Windows.Forms');`+
+			`$n = New-Object System.Windows.Forms.NotifyIcon; $n.Icon = [System.Drawing.SystemIcons]::Warning; $n.Visible = $true;`+
+			`$n.ShowBalloonTip(10000, '%s', '%s', 'Warning'); Start-Sleep -Seconds 10; $n.Dispose()`,
+			powerShellString(title), powerShellString(message))
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+	default:
+		cmd = exec.Command("notify-send", "--urgency=critical", title, message)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to show desktop notification: %w", err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// powerShellString escapes s for a single-quoted PowerShell string
+func powerShellString(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
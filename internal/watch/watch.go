@@ -0,0 +1,237 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// DefaultSettle is how long a directory must be quiet before its changes are validated,
+// so that an install has finished writing the extension folder
+const DefaultSettle = 2 * time.Second
+
+// Validator checks the trust level of an extension
+type Validator interface {
+	ValidateExtension(extensionID string) (*models.ValidationResult, error)
+}
+
+// Notifier is told about every event for a suspicious or malicious extension
+type Notifier interface {
+	Notify(event models.WatchEvent) error
+}
+
+// Options configures a Watcher
+type Options struct {
+	Settle     time.Duration
+	Quarantine bool // move suspicious and malicious extensions into the vsynx quarantine
+	Notifiers  []Notifier
+}
+
+// folderState identifies a version of an extension folder by its package.json
+type folderState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher validates extension folders as they appear in editors' extensions directories
+type Watcher struct {
+	profiles  []models.EditorProfile
+	validator Validator
+	options   Options
+	snapshots map[string]map[string]folderState // extensions dir -> folder -> state
+}
+
+// New creates a Watcher for the given editors
+func New(profiles []models.EditorProfile, validator Validator, options Options) *Watcher {
+	if options.Settle <= 0 {
+		options.Settle = DefaultSettle
+	}
+	return &Watcher{
+		profiles:  profiles,
+		validator: validator,
+		options:   options,
+		snapshots: map[string]map[string]folderState{},
+	}
+}
+
+// Baseline records the extensions currently installed so that only later changes are validated
+func (w *Watcher) Baseline() {
+	for _, profile := range w.profiles {
+		w.snapshots[profile.ExtensionsDir] = snapshot(profile.ExtensionsDir)
+	}
+}
+
+// Run watches the extensions directories until ctx is cancelled, sending an event for every
+// new or changed extension. Directories that do not exist are skipped.
+func (w *Watcher) Run(ctx context.Context, events chan<- models.WatchEvent) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer fsWatcher.Close()
+
+	profilesByDir := map[string]models.EditorProfile{}
+	for _, profile := range w.profiles {
+		dir := filepath.Clean(profile.ExtensionsDir)
+		if _, seen := profilesByDir[dir]; seen {
+			continue
+		}
+		if err := fsWatcher.Add(dir); err != nil {
+			log.Printf("[Watch] Not watching %s (%s): %v", profile.Name, dir, err)
+			continue
+		}
+		profilesByDir[dir] = profile
+		if _, ok := w.snapshots[profile.ExtensionsDir]; !ok {
+			w.snapshots[profile.ExtensionsDir] = snapshot(profile.ExtensionsDir)
+		}
+		log.Printf("[Watch] Watching %s: %s", profile.Name, dir)
+	}
+	if len(profilesByDir) == 0 {
+		return fmt.Errorf("no extensions directory could be watched")
+	}
+
+	// Each directory is checked once it has been quiet for the settle time
+	pending := map[string]time.Time{}
+	ticker := time.NewTicker(w.options.Settle / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return nil
+			}
+			dir := filepath.Dir(event.Name)
+			if _, watched := profilesByDir[dir]; watched {
+				pending[dir] = time.Now().Add(w.options.Settle)
+			}
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("[Watch] File watcher error: %v", err)
+		case now := <-ticker.C:
+			for dir, due := range pending {
+				if now.Before(due) {
+					continue
+				}
+				delete(pending, dir)
+				for _, event := range w.Check(profilesByDir[dir]) {
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return nil
+					}
+				}
+			}
+		}
+	}
+}
+
+// Check validates the extension folders of an editor that appeared or changed since the
+// last check, quarantining and notifying as configured
+func (w *Watcher) Check(profile models.EditorProfile) []models.WatchEvent {
+	previous := w.snapshots[profile.ExtensionsDir]
+	current := snapshot(profile.ExtensionsDir)
+
+	var events []models.WatchEvent
+	validated := map[string]bool{}
+	for folder, state := range current {
+		if old, ok := previous[folder]; ok && old == state {
+			continue
+		}
+		extensionID, version, err := editor.ReadExtensionIdentity(filepath.Join(profile.ExtensionsDir, folder))
+		if err != nil {
+			// Still being written; look at it again on the next change
+			delete(current, folder)
+			continue
+		}
+		if validated[strings.ToLower(extensionID)] {
+			continue
+		}
+		validated[strings.ToLower(extensionID)] = true
+
+		event := w.handle(profile, folder, extensionID, version)
+		if event.Action == models.WatchActionQuarantined {
+			// The folder is gone; forget it so a reinstall is validated again
+			delete(current, folder)
+		}
+		events = append(events, event)
+	}
+
+	w.snapshots[profile.ExtensionsDir] = current
+	return events
+}
+
+// handle validates one extension and reacts to the result
+func (w *Watcher) handle(profile models.EditorProfile, folder, extensionID, version string) models.WatchEvent {
+	event := models.WatchEvent{
+		Time:        time.Now(),
+		Editor:      profile.ID,
+		ExtensionID: extensionID,
+		Version:     version,
+		Folder:      folder,
+		Action:      models.WatchActionValidated,
+	}
+
+	result, err := w.validator.ValidateExtension(extensionID)
+	if err != nil {
+		event.TrustLevel = models.TrustLevelUnknown
+		event.Error = err.Error()
+	} else {
+		event.TrustLevel = result.TrustLevel
+		event.Issues = result.Differences
+	}
+
+	if !event.IsAlert() {
+		return event
+	}
+
+	if w.options.Quarantine {
+		reason := fmt.Sprintf("vsynx watch: %s", event.TrustLevel)
+		record, err := editor.QuarantineExtension(profile, extensionID, reason, event.TrustLevel)
+		if err != nil {
+			event.Error = fmt.Sprintf("quarantine failed: %v", err)
+		} else {
+			event.Action = models.WatchActionQuarantined
+			event.QuarantineID = record.ID
+		}
+	}
+
+	for _, notifier := range w.options.Notifiers {
+		if err := notifier.Notify(event); err != nil {
+			log.Printf("[Watch] Notification failed: %v", err)
+		}
+	}
+	return event
+}
+
+// snapshot records the package.json state of every extension folder in a directory
+func snapshot(extensionsDir string) map[string]folderState {
+	states := map[string]folderState{}
+	entries, err := os.ReadDir(extensionsDir)
+	if err != nil {
+		return states
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(extensionsDir, entry.Name(), "package.json"))
+		if err != nil {
+			continue
+		}
+		states[entry.Name()] = folderState{modTime: info.ModTime(), size: info.Size()}
+	}
+	return states
+}
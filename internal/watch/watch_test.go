@@ -0,0 +1,148 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/secureopenvsx/internal/config"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// writeExtensionFolder creates an extension folder with a package.json
+func writeExtensionFolder(t *testing.T, extensionsDir, publisher, name, version string) string {
+	t.Helper()
+	folder := fmt.Sprintf("%s.%s-%s", publisher, name, version)
+	dir := filepath.Join(extensionsDir, folder)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create folder: %v", err)
+	}
+	pkg := fmt.Sprintf(`{"publisher": %q, "name": %q, "version": %q}`, publisher, name, version)
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(pkg), 0644); err != nil {
+		t.Fatalf("Failed to write package.json: %v", err)
+	}
+	return folder
+}
+
+// fakeValidator returns a fixed trust level per extension
+type fakeValidator map[string]models.TrustLevel
+
+func (f fakeValidator) ValidateExtension(extensionID string) (*models.ValidationResult, error) {
+	level, ok := f[extensionID]
+	if !ok {
+		return nil, fmt.Errorf("not found: %s", extensionID)
+	}
+	return &models.ValidationResult{ExtensionID: extensionID, TrustLevel: level}, nil
+}
+
+// recordingNotifier remembers the events it was told about
+type recordingNotifier struct {
+	events []models.WatchEvent
+}
+
+func (n *recordingNotifier) Notify(event models.WatchEvent) error {
+	n.events = append(n.events, event)
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+	extensionsDir := t.TempDir()
+	profile := models.EditorProfile{ID: models.EditorVSCode, Name: "VS Code", ExtensionsDir: extensionsDir}
+	writeExtensionFolder(t, extensionsDir, "pub", "existing", "1.0.0")
+
+	notifier := &recordingNotifier{}
+	validator := fakeValidator{"pub.good": models.TrustLevelLegitimate, "evil.ext": models.TrustLevelMalicious}
+	w := New([]models.EditorProfile{profile}, validator, Options{Quarantine: true, Notifiers: []Notifier{notifier}})
+	w.Baseline()
+
+	if events := w.Check(profile); len(events) != 0 {
+		t.Fatalf("Expected no events without changes, got %+v", events)
+	}
+
+	writeExtensionFolder(t, extensionsDir, "pub", "good", "1.0.0")
+	evilFolder := writeExtensionFolder(t, extensionsDir, "evil", "ext", "6.6.6")
+	os.MkdirAll(filepath.Join(extensionsDir, "pub.partial-1.0.0"), 0755) // no package.json yet
+
+	events := w.Check(profile)
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %+v", events)
+	}
+	byID := map[string]models.WatchEvent{}
+	for _, event := range events {
+		byID[event.ExtensionID] = event
+	}
+	if byID["pub.good"].TrustLevel != models.TrustLevelLegitimate || byID["pub.good"].Action != models.WatchActionValidated {
+		t.Errorf("pub.good event = %+v", byID["pub.good"])
+	}
+	evil := byID["evil.ext"]
+	if evil.Action != models.WatchActionQuarantined || evil.QuarantineID == "" {
+		t.Errorf("evil.ext should be quarantined, got %+v", evil)
+	}
+	if _, err := os.Stat(filepath.Join(extensionsDir, evilFolder)); !os.IsNotExist(err) {
+		t.Error("Quarantined folder is still in the extensions directory")
+	}
+	if len(notifier.events) != 1 || notifier.events[0].ExtensionID != "evil.ext" {
+		t.Errorf("Only evil.ext should be notified, got %+v", notifier.events)
+	}
+
+	// Reinstalling the quarantined extension is caught again
+	writeExtensionFolder(t, extensionsDir, "evil", "ext", "6.6.6")
+	if events := w.Check(profile); len(events) != 1 || events[0].ExtensionID != "evil.ext" {
+		t.Errorf("Expected the reinstall to be validated, got %+v", events)
+	}
+}
+
+func TestRun(t *testing.T) {
+	extensionsDir := t.TempDir()
+	profile := models.EditorProfile{ID: models.EditorCursor, Name: "Cursor", ExtensionsDir: extensionsDir}
+	missing := models.EditorProfile{ID: models.EditorKiro, Name: "Kiro", ExtensionsDir: filepath.Join(extensionsDir, "missing")}
+
+	w := New([]models.EditorProfile{profile, missing}, fakeValidator{"pub.new": models.TrustLevelSuspicious}, Options{Settle: 40 * time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan models.WatchEvent, 1)
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx, events) }()
+
+	// Give the watcher time to start before installing
+	time.Sleep(100 * time.Millisecond)
+	writeExtensionFolder(t, extensionsDir, "pub", "new", "1.0.0")
+
+	select {
+	case event := <-events:
+		if event.ExtensionID != "pub.new" || event.TrustLevel != models.TrustLevelSuspicious || event.Editor != models.EditorCursor {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for a watch event")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run returned %v", err)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received models.WatchEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	event := models.WatchEvent{ExtensionID: "evil.ext", TrustLevel: models.TrustLevelMalicious}
+	if err := NewWebhookNotifier(server.URL).Notify(event); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if received.ExtensionID != "evil.ext" || received.TrustLevel != models.TrustLevelMalicious {
+		t.Errorf("Webhook received %+v", received)
+	}
+}
@@ -0,0 +1,317 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/secureopenvsx/internal/editor"
+	"github.com/yourusername/secureopenvsx/internal/jsonc"
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// Validator checks the trust level of an extension
+type Validator interface {
+	ValidateExtension(extensionID string) (*models.ValidationResult, error)
+}
+
+// Downloader validates and downloads extension packages for InstallMissing;
+// validation.Validator implements it
+type Downloader interface {
+	Validator
+	DownloadExtensionVersion(extensionID, version, source string) ([]byte, string, error)
+}
+
+// RecommendationsPath returns the .vscode/extensions.json path of a workspace folder
+func RecommendationsPath(dir string) string {
+	return filepath.Join(dir, ".vscode", "extensions.json")
+}
+
+// LoadRecommendations reads workspace recommendations from a folder (its .vscode/extensions.json),
+// an extensions.json file or a .code-workspace file. It also returns the file that was read.
+func LoadRecommendations(path string) (*models.WorkspaceRecommendations, string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to access %s: %w", path, err)
+	}
+	if info.IsDir() {
+		path = RecommendationsPath(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, path, fmt.Errorf("failed to read recommendations: %w", err)
+	}
+	data = jsonc.Standardize(data)
+
+	var recs models.WorkspaceRecommendations
+	if strings.HasSuffix(strings.ToLower(path), ".code-workspace") {
+		var workspaceFile struct {
+			Extensions models.WorkspaceRecommendations `json:"extensions"`
+		}
+		if err := json.Unmarshal(data, &workspaceFile); err != nil {
+			return nil, path, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		recs = workspaceFile.Extensions
+	} else if err := json.Unmarshal(data, &recs); err != nil {
+		return nil, path, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &recs, path, nil
+}
+
+// SaveRecommendations writes .vscode/extensions.json in a workspace folder
+func SaveRecommendations(dir string, recs *models.WorkspaceRecommendations) error {
+	path := RecommendationsPath(dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create .vscode folder: %w", err)
+	}
+	if recs.Recommendations == nil {
+		recs.Recommendations = []string{}
+	}
+	data, err := json.MarshalIndent(recs, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recommendations: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write recommendations: %w", err)
+	}
+	return nil
+}
+
+// RecommendationsFromInstalled recommends every enabled installed extension, keeping the
+// unwanted list of an existing recommendations file
+func RecommendationsFromInstalled(installed []models.InstalledExtension, existing *models.WorkspaceRecommendations) *models.WorkspaceRecommendations {
+	recs := &models.WorkspaceRecommendations{}
+	unwanted := map[string]bool{}
+	if existing != nil {
+		recs.UnwantedRecommendations = existing.UnwantedRecommendations
+		for _, id := range existing.UnwantedRecommendations {
+			unwanted[strings.ToLower(id)] = true
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, ext := range installed {
+		key := strings.ToLower(ext.ID)
+		if !ext.IsEnabled || ext.IsPendingRemoval || unwanted[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		recs.Recommendations = append(recs.Recommendations, ext.ID)
+	}
+	sort.Slice(recs.Recommendations, func(i, j int) bool {
+		return strings.ToLower(recs.Recommendations[i]) < strings.ToLower(recs.Recommendations[j])
+	})
+	return recs
+}
+
+// LoadCodeProfile reads the extensions of a .code-profile export. VS Code stores the
+// extension list as a JSON-encoded string; a plain array is accepted as well.
+func LoadCodeProfile(path string) (*models.CodeProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+
+	var raw struct {
+		Name       string          `json:"name"`
+		Extensions json.RawMessage `json:"extensions"`
+	}
+	if err := json.Unmarshal(jsonc.Standardize(data), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %s: %w", path, err)
+	}
+
+	profile := &models.CodeProfile{Name: raw.Name, Extensions: []models.ProfileExtension{}}
+	extensions := []byte(raw.Extensions)
+	var encoded string
+	if json.Unmarshal(extensions, &encoded) == nil {
+		extensions = []byte(encoded)
+	}
+	if len(extensions) > 0 && string(extensions) != "null" {
+		if err := json.Unmarshal(extensions, &profile.Extensions); err != nil {
+			return nil, fmt.Errorf("failed to parse profile extensions: %w", err)
+		}
+	}
+	return profile, nil
+}
+
+// SaveCodeProfile writes a .code-profile file that VS Code can import
+func SaveCodeProfile(path string, profile *models.CodeProfile) error {
+	extensions := profile.Extensions
+	if extensions == nil {
+		extensions = []models.ProfileExtension{}
+	}
+	encoded, err := json.Marshal(extensions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal profile extensions: %w", err)
+	}
+
+	data, err := json.MarshalIndent(map[string]string{
+		"name":       profile.Name,
+		"extensions": string(encoded),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal profile: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write profile: %w", err)
+	}
+	return nil
+}
+
+// ProfileFromInstalled builds a profile from an editor's installed extensions
+func ProfileFromInstalled(name string, installed []models.InstalledExtension) *models.CodeProfile {
+	profile := &models.CodeProfile{Name: name, Extensions: []models.ProfileExtension{}}
+	for _, ext := range installed {
+		if ext.IsPendingRemoval {
+			continue
+		}
+		profile.Extensions = append(profile.Extensions, models.ProfileExtension{
+			Identifier: models.ExtensionIdentifier{ID: ext.ID},
+			Version:    ext.Version,
+			Disabled:   !ext.IsEnabled,
+		})
+	}
+	sort.Slice(profile.Extensions, func(i, j int) bool {
+		return strings.ToLower(profile.Extensions[i].Identifier.ID) < strings.ToLower(profile.Extensions[j].Identifier.ID)
+	})
+	return profile
+}
+
+// CheckRecommendations compares workspace recommendations with installed extensions
+// (lowercase ID -> version). Unwanted wins when an ID appears in both lists.
+// A nil validator skips the trust check.
+func CheckRecommendations(recs *models.WorkspaceRecommendations, installed map[string]string, validator Validator) *models.WorkspaceCheckReport {
+	unwanted := map[string]bool{}
+	for _, id := range recs.UnwantedRecommendations {
+		unwanted[strings.ToLower(id)] = true
+	}
+
+	var checks []models.WorkspaceExtensionCheck
+	for _, id := range recs.Recommendations {
+		if !unwanted[strings.ToLower(id)] {
+			checks = append(checks, models.WorkspaceExtensionCheck{ExtensionID: id})
+		}
+	}
+	for _, id := range recs.UnwantedRecommendations {
+		checks = append(checks, models.WorkspaceExtensionCheck{ExtensionID: id, Unwanted: true})
+	}
+	return check(checks, installed, validator)
+}
+
+// CheckProfile compares the extensions of a profile with installed extensions
+func CheckProfile(profile *models.CodeProfile, installed map[string]string, validator Validator) *models.WorkspaceCheckReport {
+	var checks []models.WorkspaceExtensionCheck
+	for _, ext := range profile.Extensions {
+		checks = append(checks, models.WorkspaceExtensionCheck{ExtensionID: ext.Identifier.ID, Version: ext.Version})
+	}
+	return check(checks, installed, validator)
+}
+
+// check fills in install state and trust for each extension, dropping duplicate IDs
+func check(checks []models.WorkspaceExtensionCheck, installed map[string]string, validator Validator) *models.WorkspaceCheckReport {
+	report := &models.WorkspaceCheckReport{
+		Extensions:   []models.WorkspaceExtensionCheck{},
+		TrustChecked: validator != nil,
+	}
+
+	seen := map[string]bool{}
+	for _, item := range checks {
+		key := strings.ToLower(item.ExtensionID)
+		if item.ExtensionID == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		item.InstalledVersion, item.Installed = installed[key]
+
+		if item.Unwanted {
+			if item.Installed {
+				report.UnwantedInstalled++
+				report.Extensions = append(report.Extensions, item)
+			}
+			continue
+		}
+
+		if !item.Installed {
+			report.MissingCount++
+		}
+		if validator != nil {
+			result, err := validator.ValidateExtension(item.ExtensionID)
+			if err != nil {
+				item.TrustLevel = models.TrustLevelUnknown
+				item.Error = err.Error()
+			} else {
+				item.TrustLevel = result.TrustLevel
+			}
+			if isUntrusted(item.TrustLevel) {
+				report.UntrustedCount++
+			}
+		}
+		report.Extensions = append(report.Extensions, item)
+	}
+	return report
+}
+
+// InstallMissing installs the missing extensions of a report into an editor. Every
+// extension is validated first, also when the report skipped trust checks; only Legitimate
+// extensions are installed, and Unknown ones only when allowUnknown is set.
+func InstallMissing(profile models.EditorProfile, report *models.WorkspaceCheckReport, downloader Downloader, allowUnknown bool) []models.InstallResult {
+	var results []models.InstallResult
+	for i := range report.Extensions {
+		item := &report.Extensions[i]
+		if item.Unwanted || item.Installed {
+			continue
+		}
+
+		result := models.InstallResult{ExtensionID: item.ExtensionID}
+		if item.TrustLevel == "" {
+			validation, err := downloader.ValidateExtension(item.ExtensionID)
+			if err != nil {
+				result.Error = fmt.Sprintf("skipped: validation failed: %v", err)
+				results = append(results, result)
+				continue
+			}
+			item.TrustLevel = validation.TrustLevel
+		}
+		if !installable(item.TrustLevel, allowUnknown) {
+			result.Error = fmt.Sprintf("skipped: trust level is %s", item.TrustLevel)
+			if item.TrustLevel == models.TrustLevelUnknown {
+				result.Error += " (install Unknown extensions with --allow-unknown)"
+			}
+			results = append(results, result)
+			continue
+		}
+
+		data, _, err := downloader.DownloadExtensionVersion(item.ExtensionID, item.Version, "")
+		var installed *models.VSIXInstallResult
+		if err == nil {
+			installed, err = editor.InstallVSIX(profile, data, "gallery", item.ExtensionID)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+			result.Message = fmt.Sprintf("Installed %s to %s", installed.Version, installed.InstallPath)
+			item.Installed = true
+			item.InstalledVersion = installed.Version
+			report.MissingCount--
+		}
+		results = append(results, result)
+	}
+	report.Installs = results
+	return results
+}
+
+// installable reports whether an extension with a trust level may be installed
+func installable(level models.TrustLevel, allowUnknown bool) bool {
+	return level == models.TrustLevelLegitimate || (allowUnknown && level == models.TrustLevelUnknown)
+}
+
+// isUntrusted reports whether a trust level should block installation
+func isUntrusted(level models.TrustLevel) bool {
+	return level == models.TrustLevelSuspicious || level == models.TrustLevelMalicious
+}
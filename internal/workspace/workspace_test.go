@@ -0,0 +1,232 @@
+package workspace
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/secureopenvsx/internal/models"
+)
+
+// buildTestVSIX creates an in-memory VSIX archive for an extension version
+func buildTestVSIX(t *testing.T, id, version string) []byte {
+	t.Helper()
+	publisher, name, _ := strings.Cut(id, ".")
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string]string{
+		"extension.vsixmanifest": fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<PackageManifest Version="2.0.0" xmlns="http://schemas.microsoft.com/developer/vsx-schema/2011">
+  <Metadata>
+    <Identity Language="en-US" Id="%s" Version="%s" Publisher="%s" />
+  </Metadata>
+</PackageManifest>`, name, version, publisher),
+		"extension/package.json": fmt.Sprintf(`{"publisher": %q, "name": %q, "version": %q}`, publisher, name, version),
+	}
+	for fileName, content := range files {
+		f, _ := w.Create(fileName)
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// fakeValidator returns a fixed trust level per extension
+type fakeValidator map[string]models.TrustLevel
+
+func (f fakeValidator) ValidateExtension(extensionID string) (*models.ValidationResult, error) {
+	level, ok := f[extensionID]
+	if !ok {
+		return nil, fmt.Errorf("not found: %s", extensionID)
+	}
+	return &models.ValidationResult{ExtensionID: extensionID, TrustLevel: level}, nil
+}
+
+// fakeDownloader serves the latest test package of any extension
+type fakeDownloader struct {
+	fakeValidator
+	t         *testing.T
+	downloads []string
+}
+
+func (f *fakeDownloader) DownloadExtensionVersion(extensionID, version, source string) ([]byte, string, error) {
+	f.downloads = append(f.downloads, extensionID)
+	if version == "" {
+		version = "1.0.0"
+	}
+	return buildTestVSIX(f.t, extensionID, version), "", nil
+}
+
+func TestLoadRecommendations(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".vscode"), 0755)
+	content := `{
+		// See https://go.microsoft.com/fwlink/?LinkId=827846
+		"recommendations": ["golang.go", "ms-python.python",],
+		"unwantedRecommendations": ["bad.ext"]
+	}`
+	os.WriteFile(RecommendationsPath(dir), []byte(content), 0644)
+
+	recs, path, err := LoadRecommendations(dir)
+	if err != nil {
+		t.Fatalf("LoadRecommendations failed: %v", err)
+	}
+	if path != RecommendationsPath(dir) {
+		t.Errorf("path = %s, want %s", path, RecommendationsPath(dir))
+	}
+	if len(recs.Recommendations) != 2 || len(recs.UnwantedRecommendations) != 1 {
+		t.Errorf("Unexpected recommendations: %+v", recs)
+	}
+
+	workspaceFile := filepath.Join(dir, "team.code-workspace")
+	os.WriteFile(workspaceFile, []byte(`{"folders": [{"path": "."}], "extensions": {"recommendations": ["golang.go"]}}`), 0644)
+	recs, _, err = LoadRecommendations(workspaceFile)
+	if err != nil {
+		t.Fatalf("LoadRecommendations(.code-workspace) failed: %v", err)
+	}
+	if len(recs.Recommendations) != 1 || recs.Recommendations[0] != "golang.go" {
+		t.Errorf("Unexpected workspace recommendations: %+v", recs)
+	}
+}
+
+func TestRecommendationsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	installed := []models.InstalledExtension{
+		{ID: "pub.b", IsEnabled: true},
+		{ID: "pub.a", IsEnabled: true},
+		{ID: "pub.off", IsEnabled: false},
+		{ID: "bad.ext", IsEnabled: true},
+	}
+	recs := RecommendationsFromInstalled(installed, &models.WorkspaceRecommendations{UnwantedRecommendations: []string{"bad.ext"}})
+	if err := SaveRecommendations(dir, recs); err != nil {
+		t.Fatalf("SaveRecommendations failed: %v", err)
+	}
+
+	loaded, _, err := LoadRecommendations(dir)
+	if err != nil {
+		t.Fatalf("LoadRecommendations failed: %v", err)
+	}
+	if strings.Join(loaded.Recommendations, ",") != "pub.a,pub.b" {
+		t.Errorf("Recommendations = %v, want [pub.a pub.b]", loaded.Recommendations)
+	}
+	if len(loaded.UnwantedRecommendations) != 1 {
+		t.Errorf("Unwanted list was not kept: %v", loaded.UnwantedRecommendations)
+	}
+}
+
+func TestCodeProfileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "work.code-profile")
+	exported := `{"name": "Work", "settings": "{}", "extensions": "[{\"identifier\":{\"id\":\"golang.go\",\"uuid\":\"abc\"},\"displayName\":\"Go\",\"disabled\":true},{\"identifier\":{\"id\":\"pub.ext\"},\"version\":\"1.2.0\"}]"}`
+	os.WriteFile(path, []byte(exported), 0644)
+
+	profile, err := LoadCodeProfile(path)
+	if err != nil {
+		t.Fatalf("LoadCodeProfile failed: %v", err)
+	}
+	if profile.Name != "Work" || len(profile.Extensions) != 2 {
+		t.Fatalf("Unexpected profile: %+v", profile)
+	}
+	if !profile.Extensions[0].Disabled || profile.Extensions[1].Version != "1.2.0" {
+		t.Errorf("Extension fields not parsed: %+v", profile.Extensions)
+	}
+
+	if err := SaveCodeProfile(path, profile); err != nil {
+		t.Fatalf("SaveCodeProfile failed: %v", err)
+	}
+	reloaded, err := LoadCodeProfile(path)
+	if err != nil {
+		t.Fatalf("LoadCodeProfile after save failed: %v", err)
+	}
+	if len(reloaded.Extensions) != 2 || reloaded.Extensions[0].Identifier.UUID != "abc" {
+		t.Errorf("Profile did not round-trip: %+v", reloaded)
+	}
+}
+
+func TestCheckAndInstall(t *testing.T) {
+	recs := &models.WorkspaceRecommendations{
+		Recommendations:         []string{"pub.present", "pub.missing", "evil.ext", "Pub.Missing", "pub.both"},
+		UnwantedRecommendations: []string{"pub.unwanted", "pub.both", "pub.absent"},
+	}
+	installed := map[string]string{"pub.present": "1.0.0", "pub.unwanted": "0.1.0", "pub.both": "2.0.0"}
+	validator := fakeValidator{
+		"pub.present": models.TrustLevelLegitimate,
+		"pub.missing": models.TrustLevelLegitimate,
+		"evil.ext":    models.TrustLevelMalicious,
+	}
+
+	report := CheckRecommendations(recs, installed, validator)
+	if report.MissingCount != 2 {
+		t.Errorf("MissingCount = %d, want 2", report.MissingCount)
+	}
+	if report.UnwantedInstalled != 2 {
+		t.Errorf("UnwantedInstalled = %d, want 2", report.UnwantedInstalled)
+	}
+	if report.UntrustedCount != 1 {
+		t.Errorf("UntrustedCount = %d, want 1", report.UntrustedCount)
+	}
+	if len(report.Extensions) != 5 {
+		t.Errorf("Expected 5 checked extensions, got %+v", report.Extensions)
+	}
+
+	profile := models.EditorProfile{
+		ID:            models.EditorWindsurf,
+		Name:          "Test Editor",
+		ExtensionsDir: t.TempDir(),
+		IndexFile:     "extensions.json",
+	}
+	downloader := &fakeDownloader{fakeValidator: validator, t: t}
+	results := InstallMissing(profile, report, downloader, false)
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 install results, got %+v", results)
+	}
+	if strings.Join(downloader.downloads, ",") != "pub.missing" {
+		t.Errorf("Only pub.missing should be downloaded, got %v", downloader.downloads)
+	}
+	for _, result := range results {
+		if result.ExtensionID == "evil.ext" && result.Success {
+			t.Error("Malicious extension must not be installed")
+		}
+		if result.ExtensionID == "pub.missing" && !result.Success {
+			t.Errorf("pub.missing install failed: %s", result.Error)
+		}
+	}
+	if report.MissingCount != 1 {
+		t.Errorf("MissingCount after install = %d, want 1", report.MissingCount)
+	}
+	if _, err := os.Stat(filepath.Join(profile.ExtensionsDir, "pub.missing-1.0.0")); err != nil {
+		t.Errorf("pub.missing was not extracted: %v", err)
+	}
+}
+
+func TestInstallMissingValidatesWithoutTrustCheck(t *testing.T) {
+	recs := &models.WorkspaceRecommendations{Recommendations: []string{"pub.good", "pub.unknown", "evil.ext"}}
+	trust := fakeValidator{
+		"pub.good":    models.TrustLevelLegitimate,
+		"pub.unknown": models.TrustLevelUnknown,
+		"evil.ext":    models.TrustLevelSuspicious,
+	}
+
+	for _, allowUnknown := range []bool{false, true} {
+		// The report skipped trust checks (--skip-trust); installs still validate
+		report := CheckRecommendations(recs, map[string]string{}, nil)
+		profile := models.EditorProfile{ID: models.EditorWindsurf, Name: "Test Editor", ExtensionsDir: t.TempDir(), IndexFile: "extensions.json"}
+		downloader := &fakeDownloader{fakeValidator: trust, t: t}
+		InstallMissing(profile, report, downloader, allowUnknown)
+
+		want := "pub.good"
+		if allowUnknown {
+			want = "pub.good,pub.unknown"
+		}
+		if got := strings.Join(downloader.downloads, ","); got != want {
+			t.Errorf("allowUnknown=%v: downloaded %q, want %q", allowUnknown, got, want)
+		}
+	}
+}